@@ -0,0 +1,167 @@
+// Package abi decodes calldata, call results and events of Starknet contracts using the ABI of their class.
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrUnknownFunction = errors.New("function not found in abi")
+	ErrUnknownEvent    = errors.New("event not found in abi")
+	ErrNotEnoughData   = errors.New("not enough data to decode")
+	ErrTrailingData    = errors.New("data left after decoding")
+)
+
+// ABI is the parsed ABI of a contract class
+type ABI struct {
+	cairo1    bool
+	functions map[felt.Felt]*function
+	// structs and enums by name
+	structs map[string]*typeDef
+	enums   map[string]*typeDef
+	events  *events
+}
+
+type function struct {
+	name    string
+	inputs  []param
+	outputs []param
+}
+
+type param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Kind is the kind of a Cairo 1 event member or variant: key, data, nested or flat
+	Kind string `json:"kind"`
+}
+
+type typeDef struct {
+	name    string
+	members []param
+	// selectors of the variant names of an event enum
+	selectors []*felt.Felt
+}
+
+// Call is a decoded call to a function
+type Call struct {
+	Function  string `json:"function"`
+	Arguments Struct `json:"arguments"`
+}
+
+// Event is a decoded event
+type Event struct {
+	Name   string `json:"name"`
+	Fields Struct `json:"fields"`
+}
+
+// New parses the ABI of the given class
+func New(class core.Class) (*ABI, error) {
+	switch c := class.(type) {
+	case *core.Cairo0Class:
+		return newCairo0(c.Abi)
+	case *core.Cairo1Class:
+		return newCairo1(json.RawMessage(c.Abi))
+	default:
+		return nil, fmt.Errorf("unsupported class type %T", class)
+	}
+}
+
+func newABI(cairo1 bool) *ABI {
+	return &ABI{
+		cairo1:    cairo1,
+		functions: make(map[felt.Felt]*function),
+		structs:   make(map[string]*typeDef),
+		enums:     make(map[string]*typeDef),
+		events:    newEvents(),
+	}
+}
+
+// Selector returns the selector of the entry point or event with the given name
+func Selector(name string) *felt.Felt {
+	selector, err := crypto.StarknetKeccak([]byte(name))
+	if err != nil {
+		// the keccak hash writer never fails
+		panic(err)
+	}
+	return selector
+}
+
+func (a *ABI) addFunction(name string, inputs, outputs []param) {
+	a.functions[*Selector(name)] = &function{name: name, inputs: inputs, outputs: outputs}
+}
+
+// DecodeCall decodes the calldata of a call to the entry point with the given selector
+func (a *ABI) DecodeCall(selector *felt.Felt, calldata []*felt.Felt) (*Call, error) {
+	fn, ok := a.functions[*selector]
+	if !ok {
+		return nil, ErrUnknownFunction
+	}
+
+	r := newReader(calldata)
+	args, err := a.decodeParams(fn.inputs, r)
+	if err != nil {
+		return nil, err
+	}
+	if !r.done() {
+		return nil, ErrTrailingData
+	}
+	return &Call{Function: fn.name, Arguments: args}, nil
+}
+
+// DecodeResult decodes the values returned by a call to the entry point with the given selector. Cairo 1
+// outputs are not named, so the values are returned in a list, while Cairo 0 outputs are returned in a
+// [Struct].
+func (a *ABI) DecodeResult(selector *felt.Felt, result []*felt.Felt) (any, error) {
+	fn, ok := a.functions[*selector]
+	if !ok {
+		return nil, ErrUnknownFunction
+	}
+
+	r := newReader(result)
+	outputs, err := a.decodeParams(fn.outputs, r)
+	if err != nil {
+		return nil, err
+	}
+	if !r.done() {
+		return nil, ErrTrailingData
+	}
+
+	if !a.cairo1 {
+		return outputs, nil
+	}
+	values := make([]any, 0, len(outputs))
+	for _, output := range outputs {
+		values = append(values, output.Value)
+	}
+	return values, nil
+}
+
+// DecodeEvent decodes an event emitted by a contract of the class
+func (a *ABI) DecodeEvent(keys, data []*felt.Felt) (*Event, error) {
+	if len(keys) == 0 {
+		return nil, ErrUnknownEvent
+	}
+	return a.events.decode(a, keys, data)
+}
+
+func (a *ABI) decodeParams(params []param, r *reader) (Struct, error) {
+	if !a.cairo1 {
+		return a.decodeCairo0Members(params, r)
+	}
+
+	values := make(Struct, 0, len(params))
+	for _, p := range params {
+		value, err := a.decodeCairo1(p.Type, r)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", p.Name, err)
+		}
+		values = append(values, Field{Name: p.Name, Value: value})
+	}
+	return values, nil
+}
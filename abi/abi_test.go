@@ -0,0 +1,241 @@
+package abi_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/abi"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func felts(t *testing.T, hexes ...string) []*felt.Felt {
+	t.Helper()
+	values := make([]*felt.Felt, 0, len(hexes))
+	for _, hex := range hexes {
+		values = append(values, utils.HexToFelt(t, hex))
+	}
+	return values
+}
+
+func TestCairo0(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Goerli))
+	class, err := gw.Class(context.Background(), utils.HexToFelt(t, "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8"))
+	require.NoError(t, err)
+
+	classABI, err := abi.New(class)
+	require.NoError(t, err)
+
+	t.Run("pointer arguments", func(t *testing.T) {
+		call, err := classABI.DecodeCall(abi.Selector("advance_counter"), felts(t, "0x1", "0x2", "0x3", "0x4"))
+		require.NoError(t, err)
+		assert.Equal(t, "advance_counter", call.Function)
+		assert.Equal(t, abi.Struct{
+			{Name: "index", Value: utils.HexToFelt(t, "0x1")},
+			{Name: "diffs_len", Value: utils.HexToFelt(t, "0x2")},
+			{Name: "diffs", Value: []any{utils.HexToFelt(t, "0x3"), utils.HexToFelt(t, "0x4")}},
+		}, call.Arguments)
+
+		_, err = classABI.DecodeCall(abi.Selector("advance_counter"), felts(t, "0x1", "0x3", "0x3", "0x4"))
+		require.ErrorIs(t, err, abi.ErrNotEnoughData)
+		_, err = classABI.DecodeCall(abi.Selector("advance_counter"), felts(t, "0x1", "0x1", "0x3", "0x4"))
+		require.ErrorIs(t, err, abi.ErrTrailingData)
+	})
+
+	t.Run("struct with a tuple", func(t *testing.T) {
+		call, err := classABI.DecodeCall(abi.Selector("xor_counters"), felts(t, "0x1", "0x2", "0x3"))
+		require.NoError(t, err)
+		assert.Equal(t, abi.Struct{{Name: "index_and_x", Value: abi.Struct{
+			{Name: "index", Value: utils.HexToFelt(t, "0x1")},
+			{Name: "values", Value: []any{utils.HexToFelt(t, "0x2"), utils.HexToFelt(t, "0x3")}},
+		}}}, call.Arguments)
+	})
+
+	t.Run("result", func(t *testing.T) {
+		result, err := classABI.DecodeResult(abi.Selector("get_value"), felts(t, "0x7"))
+		require.NoError(t, err)
+		assert.Equal(t, abi.Struct{{Name: "res", Value: utils.HexToFelt(t, "0x7")}}, result)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := classABI.DecodeCall(abi.Selector("transfer"), nil)
+		require.ErrorIs(t, err, abi.ErrUnknownFunction)
+	})
+}
+
+func TestCairo0Events(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	class, err := gw.Class(context.Background(), utils.HexToFelt(t, "0x1efa8f84fd4dff9e2902ec88717cf0dafc8c188f80c3450615944a469428f7f"))
+	require.NoError(t, err)
+
+	classABI, err := abi.New(class)
+	require.NoError(t, err)
+
+	event, err := classABI.DecodeEvent([]*felt.Felt{abi.Selector("AdminChanged")}, felts(t, "0x1", "0x2"))
+	require.NoError(t, err)
+	assert.Equal(t, &abi.Event{Name: "AdminChanged", Fields: abi.Struct{
+		{Name: "previousAdmin", Value: utils.HexToFelt(t, "0x1")},
+		{Name: "newAdmin", Value: utils.HexToFelt(t, "0x2")},
+	}}, event)
+
+	_, err = classABI.DecodeEvent([]*felt.Felt{abi.Selector("Transfer")}, nil)
+	require.ErrorIs(t, err, abi.ErrUnknownEvent)
+}
+
+const cairo1ABI = `[
+  {"type": "impl", "name": "TokenImpl", "interface_name": "token::IToken"},
+  {"type": "struct", "name": "core::integer::u256", "members": [
+    {"name": "low", "type": "core::integer::u128"}, {"name": "high", "type": "core::integer::u128"}]},
+  {"type": "struct", "name": "core::array::Span::<core::felt252>", "members": [
+    {"name": "snapshot", "type": "@core::array::Array::<core::felt252>"}]},
+  {"type": "enum", "name": "core::bool", "variants": [{"name": "False", "type": "()"}, {"name": "True", "type": "()"}]},
+  {"type": "enum", "name": "core::option::Option::<core::integer::u32>", "variants": [
+    {"name": "Some", "type": "core::integer::u32"}, {"name": "None", "type": "()"}]},
+  {"type": "struct", "name": "token::Metadata", "members": [
+    {"name": "name", "type": "core::byte_array::ByteArray"},
+    {"name": "decimals", "type": "core::option::Option::<core::integer::u32>"}]},
+  {"type": "interface", "name": "token::IToken", "items": [
+    {"type": "function", "name": "transfer", "inputs": [
+      {"name": "recipient", "type": "core::starknet::contract_address::ContractAddress"},
+      {"name": "amount", "type": "core::integer::u256"},
+      {"name": "data", "type": "core::array::Span::<core::felt252>"}],
+     "outputs": [{"type": "core::bool"}], "state_mutability": "external"},
+    {"type": "function", "name": "metadata", "inputs": [],
+     "outputs": [{"type": "token::Metadata"}, {"type": "(core::integer::i8, core::felt252)"}], "state_mutability": "view"}]},
+  {"type": "constructor", "name": "constructor", "inputs": [{"name": "owner", "type": "core::starknet::contract_address::ContractAddress"}]},
+  {"type": "event", "name": "token::Transfer", "kind": "struct", "members": [
+    {"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+    {"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+    {"name": "value", "type": "core::integer::u256", "kind": "data"}]},
+  {"type": "event", "name": "ownable::OwnershipTransferred", "kind": "struct", "members": [
+    {"name": "new_owner", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}]},
+  {"type": "event", "name": "ownable::Event", "kind": "enum", "variants": [
+    {"name": "OwnershipTransferred", "type": "ownable::OwnershipTransferred", "kind": "nested"}]},
+  {"type": "event", "name": "token::Event", "kind": "enum", "variants": [
+    {"name": "Transfer", "type": "token::Transfer", "kind": "nested"},
+    {"name": "OwnableEvent", "type": "ownable::Event", "kind": "flat"}]}
+]`
+
+func TestCairo1(t *testing.T) {
+	classABI, err := abi.New(&core.Cairo1Class{Abi: cairo1ABI})
+	require.NoError(t, err)
+
+	t.Run("call", func(t *testing.T) {
+		call, err := classABI.DecodeCall(abi.Selector("transfer"), felts(t, "0xabc", "0x1", "0x2", "0x2", "0x5", "0x6"))
+		require.NoError(t, err)
+		assert.Equal(t, &abi.Call{Function: "transfer", Arguments: abi.Struct{
+			{Name: "recipient", Value: utils.HexToFelt(t, "0xabc")},
+			{Name: "amount", Value: "0x200000000000000000000000000000001"},
+			{Name: "data", Value: []any{utils.HexToFelt(t, "0x5"), utils.HexToFelt(t, "0x6")}},
+		}}, call)
+
+		_, err = classABI.DecodeCall(abi.Selector("transfer"), felts(t, "0xabc", "0x1", "0x1"+"00000000000000000000000000000000", "0x0"))
+		require.Error(t, err)
+	})
+
+	t.Run("result", func(t *testing.T) {
+		result, err := classABI.DecodeResult(abi.Selector("transfer"), felts(t, "0x1"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{true}, result)
+
+		// "Juno" as a ByteArray with no full words, Some(18), -1 and 0x7
+		result, err = classABI.DecodeResult(abi.Selector("metadata"), felts(t,
+			"0x0", "0x4a756e6f", "0x4", "0x0", "0x12",
+			"0x800000000000011000000000000000000000000000000000000000000000000", "0x7"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{
+			abi.Struct{
+				{Name: "name", Value: "Juno"},
+				{Name: "decimals", Value: abi.Enum{Variant: "Some", Value: utils.HexToFelt(t, "0x12")}},
+			},
+			[]any{"-0x1", utils.HexToFelt(t, "0x7")},
+		}, result)
+
+		encoded, err := json.Marshal(result)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name": "Juno", "decimals": {"Some": "0x12"}}, ["-0x1", "0x7"]]`, string(encoded))
+	})
+
+	t.Run("nested event", func(t *testing.T) {
+		event, err := classABI.DecodeEvent([]*felt.Felt{abi.Selector("Transfer"), utils.HexToFelt(t, "0x1"), utils.HexToFelt(t, "0x2")},
+			felts(t, "0x3", "0x0"))
+		require.NoError(t, err)
+		assert.Equal(t, &abi.Event{Name: "token::Transfer", Fields: abi.Struct{
+			{Name: "from", Value: utils.HexToFelt(t, "0x1")},
+			{Name: "to", Value: utils.HexToFelt(t, "0x2")},
+			{Name: "value", Value: "0x3"},
+		}}, event)
+	})
+
+	t.Run("flat event", func(t *testing.T) {
+		event, err := classABI.DecodeEvent([]*felt.Felt{abi.Selector("OwnershipTransferred")}, felts(t, "0x1"))
+		require.NoError(t, err)
+		assert.Equal(t, &abi.Event{Name: "ownable::OwnershipTransferred", Fields: abi.Struct{
+			{Name: "new_owner", Value: utils.HexToFelt(t, "0x1")},
+		}}, event)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := classABI.DecodeEvent([]*felt.Felt{abi.Selector("Approval")}, felts(t, "0x1"))
+		require.ErrorIs(t, err, abi.ErrUnknownEvent)
+
+		// trailing data
+		_, err = classABI.DecodeEvent([]*felt.Felt{abi.Selector("OwnershipTransferred")}, felts(t, "0x1", "0x2"))
+		require.ErrorIs(t, err, abi.ErrUnknownEvent)
+	})
+}
+
+func TestCairo1FirstABIVersion(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Goerli))
+	class, err := gw.Class(context.Background(), utils.HexToFelt(t, "0x1338d85d3e579f6944ba06c005238d145920afeb32f94e3a1e234d21e1e9292"))
+	require.NoError(t, err)
+
+	classABI, err := abi.New(class)
+	require.NoError(t, err)
+
+	call, err := classABI.DecodeCall(abi.Selector("test_emit_simple_event"), felts(t, "0x1", "0x1", "0x2", "0x3"))
+	require.NoError(t, err)
+	assert.Equal(t, abi.Struct{
+		{Name: "argument", Value: utils.HexToFelt(t, "0x1")},
+		{Name: "my_array", Value: []any{utils.HexToFelt(t, "0x2")}},
+		{Name: "another_argument", Value: utils.HexToFelt(t, "0x3")},
+	}, call.Arguments)
+
+	event, err := classABI.DecodeEvent([]*felt.Felt{abi.Selector("simple_event")}, felts(t, "0x1", "0x1", "0x2"))
+	require.NoError(t, err)
+	assert.Equal(t, &abi.Event{Name: "simple_event", Fields: call.Arguments[:2]}, event)
+}
+
+func TestDecodeMulticall(t *testing.T) {
+	to, selector := utils.HexToFelt(t, "0xabc"), utils.HexToFelt(t, "0xdef")
+
+	t.Run("cairo 1 layout", func(t *testing.T) {
+		calls, err := abi.DecodeMulticall(felts(t, "0x2", "0xabc", "0xdef", "0x1", "0x5", "0xabc", "0xdef", "0x0"))
+		require.NoError(t, err)
+		assert.Equal(t, []abi.AccountCall{
+			{To: to, Selector: selector, Calldata: felts(t, "0x5")},
+			{To: to, Selector: selector, Calldata: []*felt.Felt{}},
+		}, calls)
+	})
+
+	t.Run("cairo 0 layout", func(t *testing.T) {
+		calls, err := abi.DecodeMulticall(felts(t, "0x2", "0xabc", "0xdef", "0x0", "0x1", "0xabc", "0xdef", "0x1", "0x2",
+			"0x3", "0x5", "0x6", "0x7"))
+		require.NoError(t, err)
+		assert.Equal(t, []abi.AccountCall{
+			{To: to, Selector: selector, Calldata: felts(t, "0x5")},
+			{To: to, Selector: selector, Calldata: felts(t, "0x6", "0x7")},
+		}, calls)
+	})
+
+	t.Run("not a multicall", func(t *testing.T) {
+		_, err := abi.DecodeMulticall(felts(t, "0x2", "0xabc"))
+		require.ErrorIs(t, err, abi.ErrNotMulticall)
+	})
+}
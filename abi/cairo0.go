@@ -0,0 +1,182 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+type cairo0Entry struct {
+	Type    string  `json:"type"`
+	Name    string  `json:"name"`
+	Inputs  []param `json:"inputs"`
+	Outputs []param `json:"outputs"`
+	Members []param `json:"members"`
+	Keys    []param `json:"keys"`
+	Data    []param `json:"data"`
+}
+
+func newCairo0(raw json.RawMessage) (*ABI, error) {
+	var entries []cairo0Entry
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("unmarshal cairo 0 abi: %v", err)
+		}
+	}
+
+	a := newABI(false)
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function", "constructor", "l1_handler":
+			a.addFunction(entry.Name, entry.Inputs, entry.Outputs)
+		case "struct":
+			// members are listed in offset order
+			a.structs[entry.Name] = &typeDef{name: entry.Name, members: entry.Members}
+		case "event":
+			a.events.addFlat(&eventDef{name: entry.Name, keys: entry.Keys, data: entry.Data})
+		}
+	}
+	return a, nil
+}
+
+func (a *ABI) decodeCairo0(typ string, r *reader) (any, error) {
+	switch {
+	case typ == "felt":
+		return r.next()
+	case strings.HasSuffix(typ, "*"):
+		return nil, fmt.Errorf("pointer type %s without a length", typ)
+	case strings.HasPrefix(typ, "("):
+		return a.decodeCairo0Tuple(typ, r)
+	}
+
+	def, ok := a.structs[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+	return a.decodeCairo0Members(def.members, r)
+}
+
+// decodeCairo0Members decodes a list of members, where each pointer is an array whose length is the value
+// of the member right before it.
+func (a *ABI) decodeCairo0Members(members []param, r *reader) (Struct, error) {
+	values := make(Struct, 0, len(members))
+	for i, member := range members {
+		var (
+			value any
+			err   error
+		)
+		if elemType, isPointer := strings.CutSuffix(member.Type, "*"); isPointer {
+			var length *felt.Felt
+			if i > 0 {
+				length, _ = values[i-1].Value.(*felt.Felt)
+			}
+			if length == nil {
+				return nil, fmt.Errorf("decode %s: pointer without a length", member.Name)
+			}
+			value, err = decodeArray(length, r, func(r *reader) (any, error) {
+				return a.decodeCairo0(elemType, r)
+			})
+		} else {
+			value, err = a.decodeCairo0(member.Type, r)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", member.Name, err)
+		}
+		values = append(values, Field{Name: member.Name, Value: value})
+	}
+	return values, nil
+}
+
+// decodeCairo0Tuple decodes tuples such as (felt, felt) or (x: felt, y: felt). Named tuples are decoded
+// into a [Struct].
+func (a *ABI) decodeCairo0Tuple(typ string, r *reader) (any, error) {
+	elems, err := tupleElems(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]param, 0, len(elems))
+	named := len(elems) > 0
+	for _, elem := range elems {
+		member := param{Type: elem}
+		if name, elemType, found := strings.Cut(elem, ":"); found && !strings.Contains(name, "(") {
+			member = param{Name: strings.TrimSpace(name), Type: strings.TrimSpace(elemType)}
+		} else {
+			named = false
+		}
+		members = append(members, member)
+	}
+
+	values, err := a.decodeCairo0Members(members, r)
+	if err != nil {
+		return nil, err
+	}
+	if named {
+		return values, nil
+	}
+
+	tuple := make([]any, 0, len(values))
+	for _, value := range values {
+		tuple = append(tuple, value.Value)
+	}
+	return tuple, nil
+}
+
+// decodeArray decodes as many elements as the given length
+func decodeArray(length *felt.Felt, r *reader, decodeElem func(*reader) (any, error)) ([]any, error) {
+	n, err := r.checkLength(length)
+	if err != nil {
+		return nil, err
+	}
+
+	elems := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		elem, err := decodeElem(r)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+// tupleElems splits a tuple type into the types of its elements
+func tupleElems(typ string) ([]string, error) {
+	if !strings.HasPrefix(typ, "(") || !strings.HasSuffix(typ, ")") {
+		return nil, fmt.Errorf("malformed tuple type %s", typ)
+	}
+
+	inner := strings.TrimSpace(typ[1 : len(typ)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var (
+		elems []string
+		depth int
+		start int
+	)
+	for i, c := range inner {
+		switch c {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced tuple type " + typ)
+			}
+		case ',':
+			if depth == 0 {
+				elems = append(elems, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced tuple type " + typ)
+	}
+	return append(elems, strings.TrimSpace(inner[start:])), nil
+}
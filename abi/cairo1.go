@@ -0,0 +1,266 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+type cairo1Entry struct {
+	Type     string        `json:"type"`
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"`
+	Inputs   []param       `json:"inputs"`
+	Outputs  []param       `json:"outputs"`
+	Members  []param       `json:"members"`
+	Variants []param       `json:"variants"`
+	Items    []cairo1Entry `json:"items"`
+}
+
+func newCairo1(raw json.RawMessage) (*ABI, error) {
+	var entries []cairo1Entry
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("unmarshal cairo 1 abi: %v", err)
+		}
+	}
+
+	a := newABI(true)
+	a.addCairo1Entries(entries)
+	a.events.findRoots()
+	return a, nil
+}
+
+func (a *ABI) addCairo1Entries(entries []cairo1Entry) {
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function", "constructor", "l1_handler":
+			a.addFunction(entry.Name, entry.Inputs, entry.Outputs)
+		case "interface":
+			a.addCairo1Entries(entry.Items)
+		case "struct":
+			a.structs[entry.Name] = &typeDef{name: entry.Name, members: entry.Members}
+		case "enum":
+			a.enums[entry.Name] = &typeDef{name: entry.Name, members: entry.Variants}
+		case "event":
+			switch entry.Kind {
+			case "struct":
+				a.events.structs[entry.Name] = &typeDef{name: entry.Name, members: entry.Members}
+			case "enum":
+				a.events.addEnum(&typeDef{name: entry.Name, members: entry.Variants})
+			default:
+				// the first ABI version lists the event data as inputs
+				a.events.addFlat(&eventDef{name: entry.Name, data: entry.Inputs})
+			}
+		}
+	}
+}
+
+const (
+	arrayPrefix = "core::array::Array::<"
+	spanPrefix  = "core::array::Span::<"
+)
+
+var (
+	uintBits = map[string]int{
+		"core::integer::u8":    8,
+		"core::integer::u16":   16,
+		"core::integer::u32":   32,
+		"core::integer::u64":   64,
+		"core::integer::u128":  128,
+		"core::integer::usize": 32,
+	}
+	intBits = map[string]int{
+		"core::integer::i8":   8,
+		"core::integer::i16":  16,
+		"core::integer::i32":  32,
+		"core::integer::i64":  64,
+		"core::integer::i128": 128,
+	}
+	feltTypes = map[string]bool{
+		"core::felt252": true,
+		"core::starknet::contract_address::ContractAddress": true,
+		"core::starknet::class_hash::ClassHash":             true,
+		"core::starknet::eth_address::EthAddress":           true,
+		"core::starknet::storage_access::StorageAddress":    true,
+		"core::bytes_31::bytes31":                           true,
+	}
+)
+
+// decodeCairo1 decodes a value of the given type as serialised by Cairo 1's Serde
+func (a *ABI) decodeCairo1(typ string, r *reader) (any, error) {
+	typ = strings.TrimPrefix(typ, "@")
+	if feltTypes[typ] {
+		return r.next()
+	} else if bits, ok := uintBits[typ]; ok {
+		return decodeUint(bits, r)
+	} else if bits, ok := intBits[typ]; ok {
+		return decodeInt(bits, r)
+	}
+
+	switch {
+	case typ == "()":
+		return nil, nil
+	case typ == "core::bool":
+		return decodeBool(r)
+	case typ == "core::integer::u256":
+		return decodeU256(r)
+	case typ == "core::byte_array::ByteArray":
+		return decodeByteArray(r)
+	case strings.HasPrefix(typ, "("):
+		return a.decodeCairo1Tuple(typ, r)
+	case strings.HasPrefix(typ, arrayPrefix), strings.HasPrefix(typ, spanPrefix):
+		elemType := typ[strings.Index(typ, "<")+1 : len(typ)-1]
+		length, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(length, r, func(r *reader) (any, error) {
+			return a.decodeCairo1(elemType, r)
+		})
+	}
+
+	if def, ok := a.structs[typ]; ok {
+		return a.decodeParams(def.members, r)
+	} else if def, ok := a.enums[typ]; ok {
+		return a.decodeEnum(def, r)
+	}
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+func (a *ABI) decodeEnum(def *typeDef, r *reader) (any, error) {
+	index, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	if index.Cmp(new(felt.Felt).SetUint64(uint64(len(def.members)))) >= 0 {
+		return nil, fmt.Errorf("invalid %s variant %s", def.name, index)
+	}
+
+	variant := def.members[index.Uint64()]
+	value, err := a.decodeCairo1(variant.Type, r)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", variant.Name, err)
+	}
+	return Enum{Variant: variant.Name, Value: value}, nil
+}
+
+func (a *ABI) decodeCairo1Tuple(typ string, r *reader) (any, error) {
+	elems, err := tupleElems(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	tuple := make([]any, 0, len(elems))
+	for _, elemType := range elems {
+		elem, err := a.decodeCairo1(elemType, r)
+		if err != nil {
+			return nil, err
+		}
+		tuple = append(tuple, elem)
+	}
+	return tuple, nil
+}
+
+func decodeUint(bits int, r *reader) (*felt.Felt, error) {
+	f, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	if f.BigInt(new(big.Int)).BitLen() > bits {
+		return nil, fmt.Errorf("%s overflows u%d", f, bits)
+	}
+	return f, nil
+}
+
+// decodeInt decodes a signed integer into its hex representation, prefixed by a minus sign if negative.
+// Negative values are serialised as the field element p - |x|.
+func decodeInt(bits int, r *reader) (string, error) {
+	f, err := r.next()
+	if err != nil {
+		return "", err
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if f.BigInt(new(big.Int)).Cmp(limit) < 0 {
+		return f.String(), nil
+	}
+
+	abs := new(felt.Felt).Sub(&felt.Zero, f)
+	if abs.BigInt(new(big.Int)).Cmp(limit) > 0 {
+		return "", fmt.Errorf("%s overflows i%d", f, bits)
+	}
+	return "-" + abs.String(), nil
+}
+
+func decodeBool(r *reader) (bool, error) {
+	f, err := r.next()
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case f.IsZero():
+		return false, nil
+	case f.IsOne():
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid bool %s", f)
+	}
+}
+
+// decodeU256 decodes the low and high u128 words of a u256 into its hex representation
+func decodeU256(r *reader) (string, error) {
+	low, err := decodeUint(128, r)
+	if err != nil {
+		return "", err
+	}
+	high, err := decodeUint(128, r)
+	if err != nil {
+		return "", err
+	}
+
+	value := high.BigInt(new(big.Int))
+	value.Lsh(value, 128).Or(value, low.BigInt(new(big.Int)))
+	return fmt.Sprintf("0x%x", value), nil
+}
+
+// decodeByteArray decodes a ByteArray, which is serialised as its full 31-byte words followed by the
+// pending word and its length
+func decodeByteArray(r *reader) (string, error) {
+	const wordSize = 31
+
+	length, err := r.length()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		word, err := r.next()
+		if err != nil {
+			return "", err
+		}
+		wordBytes := word.Bytes()
+		b.Write(wordBytes[len(wordBytes)-wordSize:])
+	}
+
+	pendingWord, err := r.next()
+	if err != nil {
+		return "", err
+	}
+	pendingLen, err := r.next()
+	if err != nil {
+		return "", err
+	}
+	if pendingLen.Cmp(new(felt.Felt).SetUint64(wordSize)) >= 0 {
+		return "", fmt.Errorf("invalid pending word length %s", pendingLen)
+	}
+
+	pendingBytes := pendingWord.Bytes()
+	b.Write(pendingBytes[len(pendingBytes)-int(pendingLen.Uint64()):])
+	return b.String(), nil
+}
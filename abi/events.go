@@ -0,0 +1,143 @@
+package abi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// eventDef is an event identified by the selector of its name in the first key, which is how Cairo 0
+// events and Cairo 1 events of the first ABI version are emitted
+type eventDef struct {
+	name string
+	keys []param
+	data []param
+}
+
+// events holds the events of an ABI.
+//
+// Cairo 1 events of the current ABI version are structs and enums whose members carry a kind. The
+// contract emits the variants of a root enum: the first key is the selector of the variant name, unless
+// the variant is flat, in which case it is the selector of the inner enum's variant. Struct members are
+// either keys or data.
+type events struct {
+	bySelector map[felt.Felt]*eventDef
+	structs    map[string]*typeDef
+	enums      map[string]*typeDef
+	// enums that are not variants of other enums
+	roots []*typeDef
+}
+
+func newEvents() *events {
+	return &events{
+		bySelector: make(map[felt.Felt]*eventDef),
+		structs:    make(map[string]*typeDef),
+		enums:      make(map[string]*typeDef),
+	}
+}
+
+func (e *events) addFlat(def *eventDef) {
+	e.bySelector[*Selector(def.name)] = def
+}
+
+func (e *events) addEnum(def *typeDef) {
+	def.selectors = make([]*felt.Felt, 0, len(def.members))
+	for _, variant := range def.members {
+		def.selectors = append(def.selectors, Selector(variant.Name))
+	}
+	e.enums[def.name] = def
+}
+
+// findRoots must be called once all events are added
+func (e *events) findRoots() {
+	nested := make(map[string]bool)
+	for _, def := range e.enums {
+		for _, variant := range def.members {
+			nested[variant.Type] = true
+		}
+	}
+
+	for name, def := range e.enums {
+		if !nested[name] {
+			e.roots = append(e.roots, def)
+		}
+	}
+	slices.SortFunc(e.roots, func(a, b *typeDef) int {
+		return strings.Compare(a.name, b.name)
+	})
+}
+
+func (e *events) decode(a *ABI, keys, data []*felt.Felt) (*Event, error) {
+	if def, ok := e.bySelector[*keys[0]]; ok {
+		keyReader, dataReader := newReader(keys[1:]), newReader(data)
+		keyFields, err := a.decodeParams(def.keys, keyReader)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s keys: %w", def.name, err)
+		}
+		dataFields, err := a.decodeParams(def.data, dataReader)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s data: %w", def.name, err)
+		}
+		if !keyReader.done() || !dataReader.done() {
+			return nil, ErrTrailingData
+		}
+		return &Event{Name: def.name, Fields: append(keyFields, dataFields...)}, nil
+	}
+
+	for _, root := range e.roots {
+		keyReader, dataReader := newReader(keys), newReader(data)
+		event, err := e.decodeEnum(a, root, keyReader, dataReader)
+		if err == nil && keyReader.done() && dataReader.done() {
+			return event, nil
+		}
+	}
+	return nil, ErrUnknownEvent
+}
+
+func (e *events) decodeEnum(a *ABI, def *typeDef, keys, data *reader) (*Event, error) {
+	for i, variant := range def.members {
+		if variant.Kind == "flat" {
+			keysPos, dataPos := keys.pos, data.pos
+			if event, err := e.decodeVariant(a, variant.Type, keys, data); err == nil {
+				return event, nil
+			}
+			keys.pos, data.pos = keysPos, dataPos
+			continue
+		}
+
+		if keys.done() || !keys.felts[keys.pos].Equal(def.selectors[i]) {
+			continue
+		}
+		keys.pos++
+		return e.decodeVariant(a, variant.Type, keys, data)
+	}
+	return nil, ErrUnknownEvent
+}
+
+func (e *events) decodeVariant(a *ABI, typ string, keys, data *reader) (*Event, error) {
+	if def, ok := e.enums[typ]; ok {
+		return e.decodeEnum(a, def, keys, data)
+	}
+
+	def, ok := e.structs[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s", typ)
+	}
+
+	fields := make(Struct, 0, len(def.members))
+	for _, member := range def.members {
+		r := data
+		if member.Kind == "key" {
+			r = keys
+		}
+
+		value, err := a.decodeCairo1(member.Type, r)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", member.Name, err)
+		}
+		fields = append(fields, Field{Name: member.Name, Value: value})
+	}
+	return &Event{Name: def.name, Fields: fields}, nil
+}
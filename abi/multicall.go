@@ -0,0 +1,89 @@
+package abi
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrNotMulticall = errors.New("calldata is not a multicall")
+
+// AccountCall is one of the calls made by an account's __execute__ entry point
+type AccountCall struct {
+	To       *felt.Felt
+	Selector *felt.Felt
+	Calldata []*felt.Felt
+}
+
+// DecodeMulticall splits the calldata of an account's __execute__ entry point into the calls it makes.
+//
+// Two layouts are in use. Cairo 1 accounts serialise an array of calls, each with its own calldata:
+//
+//	[calls_len, to, selector, calldata_len, calldata..., ...]
+//
+// Cairo 0 accounts serialise an array of calls pointing into a calldata array shared by all calls:
+//
+//	[calls_len, to, selector, data_offset, data_len, ..., calldata_len, calldata...]
+func DecodeMulticall(calldata []*felt.Felt) ([]AccountCall, error) {
+	if calls, err := decodeCairo1Multicall(calldata); err == nil {
+		return calls, nil
+	}
+	return decodeCairo0Multicall(calldata)
+}
+
+func decodeCairo1Multicall(calldata []*felt.Felt) ([]AccountCall, error) {
+	r := newReader(calldata)
+	n, err := r.length()
+	if err != nil {
+		return nil, ErrNotMulticall
+	}
+
+	calls := make([]AccountCall, 0, n)
+	for i := 0; i < n; i++ {
+		to, toErr := r.next()
+		selector, selectorErr := r.next()
+		length, lengthErr := r.length()
+		if err = errors.Join(toErr, selectorErr, lengthErr); err != nil {
+			return nil, ErrNotMulticall
+		}
+
+		calls = append(calls, AccountCall{To: to, Selector: selector, Calldata: r.felts[r.pos : r.pos+length]})
+		r.pos += length
+	}
+
+	if !r.done() {
+		return nil, ErrNotMulticall
+	}
+	return calls, nil
+}
+
+func decodeCairo0Multicall(calldata []*felt.Felt) ([]AccountCall, error) {
+	const callSize = 4
+
+	r := newReader(calldata)
+	n, err := r.length()
+	if err != nil || n*callSize > len(calldata)-r.pos {
+		return nil, ErrNotMulticall
+	}
+
+	callArray := r.felts[r.pos : r.pos+n*callSize]
+	r.pos += n * callSize
+	length, err := r.length()
+	if err != nil || r.pos+length != len(calldata) {
+		return nil, ErrNotMulticall
+	}
+	data := r.felts[r.pos:]
+
+	calls := make([]AccountCall, 0, n)
+	for i := 0; i < n; i++ {
+		call := callArray[i*callSize : (i+1)*callSize]
+		offset, offsetOk := feltToInt(call[2], len(data))
+		dataLen, dataLenOk := feltToInt(call[3], len(data))
+		if !offsetOk || !dataLenOk || offset+dataLen > len(data) {
+			return nil, ErrNotMulticall
+		}
+
+		calls = append(calls, AccountCall{To: call[0], Selector: call[1], Calldata: data[offset : offset+dataLen]})
+	}
+	return calls, nil
+}
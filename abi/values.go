@@ -0,0 +1,112 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Field is a named value of a [Struct]
+type Field struct {
+	Name  string
+	Value any
+}
+
+// Struct is a decoded struct. Its fields keep the order in which they are declared, which is also the
+// order of the keys of its JSON encoding.
+type Struct []Field
+
+func (s Struct) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range s {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Get returns the value of the field with the given name
+func (s Struct) Get(name string) (any, bool) {
+	for _, field := range s {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Enum is a decoded enum variant. It is encoded in JSON as an object with the variant name as its only key.
+type Enum struct {
+	Variant string
+	Value   any
+}
+
+func (e Enum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{e.Variant: e.Value})
+}
+
+// reader consumes the felts being decoded
+type reader struct {
+	felts []*felt.Felt
+	pos   int
+}
+
+func newReader(felts []*felt.Felt) *reader {
+	return &reader{felts: felts}
+}
+
+func (r *reader) next() (*felt.Felt, error) {
+	if r.done() {
+		return nil, ErrNotEnoughData
+	}
+	f := r.felts[r.pos]
+	r.pos++
+	return f, nil
+}
+
+// length reads a length prefix
+func (r *reader) length() (int, error) {
+	f, err := r.next()
+	if err != nil {
+		return 0, err
+	}
+	return r.checkLength(f)
+}
+
+// checkLength makes sure there are at least as many felts left as the given length, so that decoding
+// malformed data does not allocate huge arrays
+func (r *reader) checkLength(length *felt.Felt) (int, error) {
+	n, ok := feltToInt(length, len(r.felts)-r.pos)
+	if !ok {
+		return 0, ErrNotEnoughData
+	}
+	return n, nil
+}
+
+func (r *reader) done() bool {
+	return r.pos == len(r.felts)
+}
+
+// feltToInt converts the felt to an int if it is not greater than limit
+func feltToInt(f *felt.Felt, limit int) (int, bool) {
+	if bits := f.Bits(); bits[1] != 0 || bits[2] != 0 || bits[3] != 0 || bits[0] > uint64(limit) {
+		return 0, false
+	}
+	return int(f.Uint64()), true
+}
@@ -1,30 +1,42 @@
 package core2p2p
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/NethermindEth/juno/adapters/core2sn"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/p2p/starknet/spec"
 )
 
-func AdaptClass(class core.Class, compiledHash *felt.Felt) *spec.Class {
+// AdaptClass sends the definition of a class in JSON, the way the feeder gateway serves it, as peers need the
+// entry points and ABI of classes, and the Sierra program of Cairo 1 classes, to verify their hashes. Cairo 1
+// classes are sent without their CASM, which peers compile.
+func AdaptClass(class core.Class, compiledHash *felt.Felt) (*spec.Class, error) {
 	if class == nil {
-		return nil
+		return nil, nil
 	}
 
+	var definition any
 	switch v := class.(type) {
 	case *core.Cairo0Class:
-		return &spec.Class{
-			CompiledHash: AdaptHash(compiledHash),
-			Definition:   []byte(v.Program),
+		var err error
+		if definition, err = core2sn.AdaptCairo0Class(v); err != nil {
+			return nil, err
 		}
 	case *core.Cairo1Class:
-		return &spec.Class{
-			CompiledHash: AdaptHash(compiledHash),
-			Definition:   v.Compiled,
-		}
+		definition = core2sn.AdaptCairo1Class(v)
 	default:
-		panic(fmt.Errorf("unsupported cairo class %T (version=%d)", v, class.Version()))
+		return nil, fmt.Errorf("unsupported cairo class %T (version=%d)", v, class.Version())
+	}
+
+	definitionJSON, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
 	}
+	return &spec.Class{
+		CompiledHash: AdaptHash(compiledHash),
+		Definition:   definitionJSON,
+	}, nil
 }
@@ -0,0 +1,77 @@
+package core2p2p
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
+)
+
+func AdaptStateDiffProof(proof *core.StateDiffProof) *spec.StateDiffProof {
+	return &spec.StateDiffProof{
+		OldContractsRoot: AdaptHash(proof.OldContractsRoot),
+		OldClassesRoot:   AdaptHash(proof.OldClassesRoot),
+		NewContractsRoot: AdaptHash(proof.NewContractsRoot),
+		NewClassesRoot:   AdaptHash(proof.NewClassesRoot),
+		Contracts:        utils.Map(proof.Contracts, adaptContractDiffProof),
+		Classes:          utils.Map(proof.Classes, adaptDeclaredClassProof),
+	}
+}
+
+func adaptContractDiffProof(proof *core.ContractDiffProof) *spec.ContractDiffProof {
+	return &spec.ContractDiffProof{
+		Address: AdaptAddress(proof.Address),
+		Old:     adaptContractLeafProof(proof.Old),
+		New:     adaptContractLeafProof(proof.New),
+		Storage: utils.Map(proof.Storage, adaptStorageDiffProof),
+	}
+}
+
+func adaptContractLeafProof(proof *core.ContractLeafProof) *spec.ContractLeafProof {
+	return &spec.ContractLeafProof{
+		ClassHash:   AdaptHash(proof.ClassHash),
+		Nonce:       AdaptFelt(proof.Nonce),
+		StorageRoot: AdaptHash(proof.StorageRoot),
+		Proof:       adaptProofNodes(proof.Proof),
+	}
+}
+
+func adaptStorageDiffProof(proof *core.StorageDiffProof) *spec.StorageDiffProof {
+	return &spec.StorageDiffProof{
+		Key:      AdaptFelt(proof.Key),
+		OldValue: AdaptFelt(proof.OldValue),
+		OldProof: adaptProofNodes(proof.OldProof),
+		NewProof: adaptProofNodes(proof.NewProof),
+	}
+}
+
+func adaptDeclaredClassProof(proof *core.DeclaredClassProof) *spec.DeclaredClassProof {
+	return &spec.DeclaredClassProof{
+		ClassHash: AdaptHash(proof.ClassHash),
+		Proof:     adaptProofNodes(proof.Proof),
+	}
+}
+
+func adaptProofNodes(nodes []trie.ProofNode) []*spec.PatriciaNode {
+	specNodes := make([]*spec.PatriciaNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Binary != nil {
+			specNodes = append(specNodes, &spec.PatriciaNode{
+				Node: &spec.PatriciaNode_Binary_{Binary: &spec.PatriciaNode_Binary{
+					Left:  AdaptFelt(node.Binary.LeftHash),
+					Right: AdaptFelt(node.Binary.RightHash),
+				}},
+			})
+			continue
+		}
+		path := node.Edge.Path.Felt()
+		specNodes = append(specNodes, &spec.PatriciaNode{
+			Node: &spec.PatriciaNode_Edge_{Edge: &spec.PatriciaNode_Edge{
+				Length: uint32(node.Edge.Path.Len()),
+				Path:   AdaptFelt(&path),
+				Value:  AdaptFelt(node.Edge.Child),
+			}},
+		})
+	}
+	return specNodes
+}
@@ -15,7 +15,7 @@ func AdaptStateDiff(addr, classHash, nonce *felt.Felt, diff map[felt.Felt]*felt.
 }
 
 func AdaptStorageDiff(diff map[felt.Felt]*felt.Felt) []*spec.ContractStoredValue {
-	result := make([]*spec.ContractStoredValue, len(diff))
+	result := make([]*spec.ContractStoredValue, 0, len(diff))
 	for key, value := range diff {
 		result = append(result, &spec.ContractStoredValue{
 			Key:   AdaptFelt(&key),
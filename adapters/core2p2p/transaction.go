@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/p2p/starknet/spec"
 )
 
@@ -30,8 +31,25 @@ func AdaptTransaction(transaction core.Transaction) *spec.Transaction {
 					Calldata:    AdaptFeltSlice(tx.ConstructorCallData),
 				},
 			}
+		case tx.Version.Is(3):
+			specTx.Txn = &spec.Transaction_DeployAccountV3_{
+				DeployAccountV3: &spec.Transaction_DeployAccountV3{
+					MaxFee:      AdaptFelt(tx.MaxFee),
+					Signature:   AdaptAccountSignature(tx.Signature()),
+					ClassHash:   AdaptHash(tx.ClassHash),
+					Nonce:       AdaptFelt(tx.Nonce),
+					AddressSalt: AdaptFelt(tx.ContractAddressSalt),
+					Calldata:    AdaptFeltSlice(tx.ConstructorCallData),
+					L1Gas:       adaptResourceLimits(tx.ResourceBounds[core.ResourceL1Gas]),
+					L2Gas:       adaptResourceLimits(tx.ResourceBounds[core.ResourceL2Gas]),
+					Tip:         AdaptFelt(new(felt.Felt).SetUint64(tx.Tip)),
+					Paymaster:   adaptPaymaster(tx.PaymasterData),
+					NonceDomain: adaptDAMode(tx.NonceDAMode),
+					FeeDomain:   adaptDAMode(tx.FeeDAMode),
+				},
+			}
 		default:
-			panic(fmt.Errorf("unsupported InvokeTransaction version %s", tx.Version))
+			panic(fmt.Errorf("unsupported DeployAccount transaction version %s", tx.Version))
 		}
 	case *core.DeclareTransaction:
 		switch {
@@ -65,6 +83,26 @@ func AdaptTransaction(transaction core.Transaction) *spec.Transaction {
 					CompiledClassHash: AdaptFelt(tx.CompiledClassHash),
 				},
 			}
+		case tx.Version.Is(3):
+			if len(tx.AccountDeploymentData) > 0 {
+				panic("account deployment data is not supported")
+			}
+			specTx.Txn = &spec.Transaction_DeclareV3_{
+				DeclareV3: &spec.Transaction_DeclareV3{
+					Sender:            AdaptAddress(tx.SenderAddress),
+					MaxFee:            AdaptFelt(tx.MaxFee),
+					Signature:         AdaptAccountSignature(tx.Signature()),
+					ClassHash:         AdaptHash(tx.ClassHash),
+					Nonce:             AdaptFelt(tx.Nonce),
+					CompiledClassHash: AdaptFelt(tx.CompiledClassHash),
+					L1Gas:             adaptResourceLimits(tx.ResourceBounds[core.ResourceL1Gas]),
+					L2Gas:             adaptResourceLimits(tx.ResourceBounds[core.ResourceL2Gas]),
+					Tip:               AdaptFelt(new(felt.Felt).SetUint64(tx.Tip)),
+					Paymaster:         adaptPaymaster(tx.PaymasterData),
+					NonceDomain:       adaptDAMode(tx.NonceDAMode),
+					FeeDomain:         adaptDAMode(tx.FeeDAMode),
+				},
+			}
 		default:
 			panic(fmt.Errorf("unsupported Declare transaction version %s", tx.Version))
 		}
@@ -89,6 +127,24 @@ func AdaptTransaction(transaction core.Transaction) *spec.Transaction {
 					Calldata:  AdaptFeltSlice(tx.CallData),
 				},
 			}
+		case tx.Version.Is(3):
+			if len(tx.AccountDeploymentData) > 0 {
+				panic("account deployment data is not supported")
+			}
+			specTx.Txn = &spec.Transaction_InvokeV3_{
+				InvokeV3: &spec.Transaction_InvokeV3{
+					Sender:      AdaptAddress(tx.SenderAddress),
+					MaxFee:      AdaptFelt(tx.MaxFee),
+					Signature:   AdaptAccountSignature(tx.Signature()),
+					Calldata:    AdaptFeltSlice(tx.CallData),
+					L1Gas:       adaptResourceLimits(tx.ResourceBounds[core.ResourceL1Gas]),
+					L2Gas:       adaptResourceLimits(tx.ResourceBounds[core.ResourceL2Gas]),
+					Tip:         AdaptFelt(new(felt.Felt).SetUint64(tx.Tip)),
+					Paymaster:   adaptPaymaster(tx.PaymasterData),
+					NonceDomain: adaptDAMode(tx.NonceDAMode),
+					FeeDomain:   adaptDAMode(tx.FeeDAMode),
+				},
+			}
 		default:
 			panic(fmt.Errorf("unsupported Invoke transaction version %s", tx.Version))
 		}
@@ -99,6 +155,32 @@ func AdaptTransaction(transaction core.Transaction) *spec.Transaction {
 	return &specTx
 }
 
+func adaptResourceLimits(bounds core.ResourceBounds) *spec.ResourceLimits {
+	return &spec.ResourceLimits{
+		MaxAmount:       AdaptFelt(new(felt.Felt).SetUint64(bounds.MaxAmount)),
+		MaxPricePerUnit: AdaptFelt(bounds.MaxPricePerUnit),
+	}
+}
+
+// adaptPaymaster only adapts empty paymaster data, as spec.Transaction has a single paymaster address instead
+func adaptPaymaster(paymasterData []*felt.Felt) *spec.Address {
+	if len(paymasterData) > 0 {
+		panic("paymaster data is not supported")
+	}
+	return nil
+}
+
+func adaptDAMode(mode core.DataAvailabilityMode) string {
+	switch mode {
+	case core.DAModeL1:
+		return "L1"
+	case core.DAModeL2:
+		return "L2"
+	default:
+		panic(fmt.Errorf("unsupported data availability mode %d", mode))
+	}
+}
+
 func adaptDeployTransaction(tx *core.DeployTransaction) *spec.Transaction_Deploy_ {
 	return &spec.Transaction_Deploy_{
 		Deploy: &spec.Transaction_Deploy{
@@ -109,8 +191,10 @@ func adaptDeployTransaction(tx *core.DeployTransaction) *spec.Transaction_Deploy
 	}
 }
 
+// adaptL1HandlerTransaction adapts L1 handler transactions of versions 0 and 1 alike, as the spec only has a
+// message for the latter and they only differ in their version
 func adaptL1HandlerTransaction(tx *core.L1HandlerTransaction) *spec.Transaction_L1Handler {
-	if !tx.Version.Is(1) {
+	if !tx.Version.Is(0) && !tx.Version.Is(1) {
 		panic(fmt.Errorf("unsupported L1Handler tx version %s", tx.Version))
 	}
 
@@ -0,0 +1,140 @@
+package core2sn
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+)
+
+// AdaptBlock adapts a core.Block to the format served by the feeder gateway. Block statuses
+// are not stored in core, so the caller provides the status to report.
+func AdaptBlock(block *core.Block, status string) (*starknet.Block, error) {
+	txns := make([]*starknet.Transaction, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		var err error
+		txns[i], err = AdaptTransaction(txn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receipts := make([]*starknet.TransactionReceipt, len(block.Receipts))
+	for i, receipt := range block.Receipts {
+		receipts[i] = AdaptTransactionReceipt(receipt, uint64(i))
+	}
+
+	return &starknet.Block{
+		Hash:             block.Hash,
+		ParentHash:       block.ParentHash,
+		Number:           block.Number,
+		StateRoot:        block.GlobalStateRoot,
+		Status:           status,
+		Transactions:     txns,
+		Timestamp:        block.Timestamp,
+		Version:          block.ProtocolVersion,
+		Receipts:         receipts,
+		SequencerAddress: block.SequencerAddress,
+		GasPriceWEI:      block.GasPrice,
+		GasPriceSTRK:     block.GasPriceSTRK,
+		L1DataGasPrice:   (*starknet.GasPrice)(block.L1DataGasPrice),
+		L1DAMode:         starknet.L1DAMode(block.L1DAMode),
+	}, nil
+}
+
+func AdaptTransactionReceipt(receipt *core.TransactionReceipt, index uint64) *starknet.TransactionReceipt {
+	if receipt == nil {
+		return nil
+	}
+
+	events := make([]*starknet.Event, len(receipt.Events))
+	for i, event := range receipt.Events {
+		events[i] = AdaptEvent(event)
+	}
+
+	l2ToL1Messages := make([]*starknet.L2ToL1Message, len(receipt.L2ToL1Message))
+	for i, msg := range receipt.L2ToL1Message {
+		l2ToL1Messages[i] = AdaptL2ToL1Message(msg)
+	}
+
+	executionStatus := starknet.Succeeded
+	if receipt.Reverted {
+		executionStatus = starknet.Reverted
+	}
+
+	return &starknet.TransactionReceipt{
+		ActualFee:          receipt.Fee,
+		Events:             events,
+		ExecutionStatus:    executionStatus,
+		ExecutionResources: AdaptExecutionResources(receipt.ExecutionResources),
+		L1ToL2Message:      AdaptL1ToL2Message(receipt.L1ToL2Message),
+		L2ToL1Message:      l2ToL1Messages,
+		TransactionHash:    receipt.TransactionHash,
+		TransactionIndex:   index,
+		RevertError:        receipt.RevertReason,
+	}
+}
+
+func AdaptEvent(event *core.Event) *starknet.Event {
+	if event == nil {
+		return nil
+	}
+
+	return &starknet.Event{
+		From: event.From,
+		Data: event.Data,
+		Keys: event.Keys,
+	}
+}
+
+func AdaptExecutionResources(resources *core.ExecutionResources) *starknet.ExecutionResources {
+	if resources == nil {
+		return nil
+	}
+
+	return &starknet.ExecutionResources{
+		Steps:                  resources.Steps,
+		BuiltinInstanceCounter: starknet.BuiltinInstanceCounter(resources.BuiltinInstanceCounter),
+		MemoryHoles:            resources.MemoryHoles,
+		TotalGasConsumed:       (*starknet.GasConsumed)(resources.TotalGasConsumed),
+	}
+}
+
+func AdaptL1ToL2Message(msg *core.L1ToL2Message) *starknet.L1ToL2Message {
+	if msg == nil {
+		return nil
+	}
+
+	return &starknet.L1ToL2Message{
+		From:     msg.From.Hex(),
+		Payload:  msg.Payload,
+		Selector: msg.Selector,
+		To:       msg.To,
+		Nonce:    msg.Nonce,
+	}
+}
+
+func AdaptL2ToL1Message(msg *core.L2ToL1Message) *starknet.L2ToL1Message {
+	if msg == nil {
+		return nil
+	}
+
+	return &starknet.L2ToL1Message{
+		From:    msg.From,
+		Payload: msg.Payload,
+		To:      msg.To.Hex(),
+	}
+}
+
+// AdaptSignature adapts the signature of a block to the format served by the feeder gateway.
+func AdaptSignature(header *core.Header, stateDiffCommitment *felt.Felt) *starknet.Signature {
+	sig := &starknet.Signature{
+		BlockNumber: header.Number,
+		Signature:   []*felt.Felt{},
+	}
+	if len(header.Signatures) > 0 {
+		sig.Signature = header.Signatures[0]
+	}
+	sig.SignatureInput.BlockHash = header.Hash
+	sig.SignatureInput.StateDiffCommitment = stateDiffCommitment
+	return sig
+}
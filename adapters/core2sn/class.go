@@ -3,11 +3,57 @@ package core2sn
 import (
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
 )
 
+func AdaptCairo1Class(class *core.Cairo1Class) *starknet.SierraDefinition {
+	constructors := utils.Map(utils.NonNilSlice(class.EntryPoints.Constructor), AdaptSierraEntryPoint)
+	external := utils.Map(utils.NonNilSlice(class.EntryPoints.External), AdaptSierraEntryPoint)
+	handlers := utils.Map(utils.NonNilSlice(class.EntryPoints.L1Handler), AdaptSierraEntryPoint)
+
+	return &starknet.SierraDefinition{
+		Abi:     class.Abi,
+		Version: class.SemanticVersion,
+		Program: class.Program,
+		EntryPoints: starknet.SierraEntryPoints{
+			Constructor: constructors,
+			External:    external,
+			L1Handler:   handlers,
+		},
+	}
+}
+
 func AdaptSierraEntryPoint(ep core.SierraEntryPoint) starknet.SierraEntryPoint {
 	return starknet.SierraEntryPoint{
 		Index:    ep.Index,
 		Selector: ep.Selector,
 	}
 }
+
+func AdaptCairo0Class(class *core.Cairo0Class) (*starknet.Cairo0Definition, error) {
+	constructors := utils.Map(utils.NonNilSlice(class.Constructors), AdaptEntryPoint)
+	external := utils.Map(utils.NonNilSlice(class.Externals), AdaptEntryPoint)
+	handlers := utils.Map(utils.NonNilSlice(class.L1Handlers), AdaptEntryPoint)
+
+	decompressedProgram, err := utils.Gzip64Decode(class.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	return &starknet.Cairo0Definition{
+		Program: decompressedProgram,
+		Abi:     class.Abi,
+		EntryPoints: starknet.EntryPoints{
+			Constructor: constructors,
+			External:    external,
+			L1Handler:   handlers,
+		},
+	}, nil
+}
+
+func AdaptEntryPoint(ep core.EntryPoint) starknet.EntryPoint {
+	return starknet.EntryPoint{
+		Selector: ep.Selector,
+		Offset:   ep.Offset,
+	}
+}
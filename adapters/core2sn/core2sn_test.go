@@ -0,0 +1,58 @@
+package core2sn_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/NethermindEth/juno/adapters/core2sn"
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := map[utils.Network][]uint64{
+		utils.Mainnet:     {0, 1, 2},
+		utils.Integration: {0, 1, 283364},
+	}
+
+	ctx := context.Background()
+	for network, numbers := range tests {
+		upstream := adaptfeeder.New(feeder.NewTestClient(t, network))
+		for _, number := range numbers {
+			t.Run(network.String()+" "+strconv.FormatUint(number, 10), func(t *testing.T) {
+				stateUpdate, block, err := upstream.StateUpdateWithBlock(ctx, number)
+				require.NoError(t, err)
+
+				snBlock, err := core2sn.AdaptBlock(block, "ACCEPTED_ON_L2")
+				require.NoError(t, err)
+				adaptedBlock, err := sn2core.AdaptBlock(snBlock, core2sn.AdaptSignature(block.Header, nil))
+				require.NoError(t, err)
+				assert.Equal(t, block, adaptedBlock)
+
+				adaptedUpdate, err := sn2core.AdaptStateUpdate(core2sn.AdaptStateUpdate(stateUpdate))
+				require.NoError(t, err)
+				assert.Equal(t, stateUpdate, adaptedUpdate)
+			})
+		}
+	}
+}
+
+func TestAdaptCairo1Class(t *testing.T) {
+	ctx := context.Background()
+	upstream := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+
+	classHash := utils.HexToFelt(t, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+	class, err := upstream.Class(ctx, classHash)
+	require.NoError(t, err)
+
+	cairo1Class := class.(*core.Cairo1Class)
+	adaptedClass, err := sn2core.AdaptCairo1Class(core2sn.AdaptCairo1Class(cairo1Class), cairo1Class.Compiled)
+	require.NoError(t, err)
+	assert.Equal(t, class, adaptedClass)
+}
@@ -0,0 +1,80 @@
+package core2sn
+
+import (
+	"sort"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+)
+
+func AdaptStateUpdate(update *core.StateUpdate) *starknet.StateUpdate {
+	diff := update.StateDiff
+	snDiff := starknet.StateDiff{
+		StorageDiffs:         make(map[string][]starknet.StorageDiff, len(diff.StorageDiffs)),
+		Nonces:               make(map[string]*felt.Felt, len(diff.Nonces)),
+		DeployedContracts:    make([]starknet.DeployedContract, 0, len(diff.DeployedContracts)),
+		OldDeclaredContracts: utils.NonNilSlice(diff.DeclaredV0Classes),
+		DeclaredClasses:      make([]starknet.DeclaredClass, 0, len(diff.DeclaredV1Classes)),
+		ReplacedClasses:      make([]starknet.DeployedContract, 0, len(diff.ReplacedClasses)),
+	}
+
+	for addr, diffs := range diff.StorageDiffs {
+		storageDiffs := make([]starknet.StorageDiff, 0, len(diffs))
+		for key, value := range diffs {
+			storageDiffs = append(storageDiffs, starknet.StorageDiff{
+				Key:   new(felt.Felt).Set(&key),
+				Value: value,
+			})
+		}
+		sort.Slice(storageDiffs, func(i, j int) bool {
+			return storageDiffs[i].Key.Cmp(storageDiffs[j].Key) < 0
+		})
+		snDiff.StorageDiffs[addr.String()] = storageDiffs
+	}
+
+	for addr, nonce := range diff.Nonces {
+		snDiff.Nonces[addr.String()] = nonce
+	}
+
+	for addr, classHash := range diff.DeployedContracts {
+		snDiff.DeployedContracts = append(snDiff.DeployedContracts, starknet.DeployedContract{
+			Address:   new(felt.Felt).Set(&addr),
+			ClassHash: classHash,
+		})
+	}
+	sortDeployedContracts(snDiff.DeployedContracts)
+
+	for classHash, compiledClassHash := range diff.DeclaredV1Classes {
+		snDiff.DeclaredClasses = append(snDiff.DeclaredClasses, starknet.DeclaredClass{
+			ClassHash:         new(felt.Felt).Set(&classHash),
+			CompiledClassHash: compiledClassHash,
+		})
+	}
+	sort.Slice(snDiff.DeclaredClasses, func(i, j int) bool {
+		return snDiff.DeclaredClasses[i].ClassHash.Cmp(snDiff.DeclaredClasses[j].ClassHash) < 0
+	})
+
+	for addr, classHash := range diff.ReplacedClasses {
+		snDiff.ReplacedClasses = append(snDiff.ReplacedClasses, starknet.DeployedContract{
+			Address:   new(felt.Felt).Set(&addr),
+			ClassHash: classHash,
+		})
+	}
+	sortDeployedContracts(snDiff.ReplacedClasses)
+
+	return &starknet.StateUpdate{
+		BlockHash: update.BlockHash,
+		NewRoot:   update.NewRoot,
+		OldRoot:   update.OldRoot,
+		StateDiff: snDiff,
+	}
+}
+
+// sortDeployedContracts sorts by address so that responses are deterministic.
+func sortDeployedContracts(contracts []starknet.DeployedContract) {
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Address.Cmp(contracts[j].Address) < 0
+	})
+}
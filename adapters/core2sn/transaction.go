@@ -0,0 +1,128 @@
+package core2sn
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+)
+
+func AdaptTransaction(transaction core.Transaction) (*starknet.Transaction, error) {
+	switch t := transaction.(type) {
+	case *core.DeclareTransaction:
+		return AdaptDeclareTransaction(t), nil
+	case *core.DeployTransaction:
+		return AdaptDeployTransaction(t), nil
+	case *core.InvokeTransaction:
+		return AdaptInvokeTransaction(t), nil
+	case *core.DeployAccountTransaction:
+		return AdaptDeployAccountTransaction(t), nil
+	case *core.L1HandlerTransaction:
+		return AdaptL1HandlerTransaction(t), nil
+	default:
+		return nil, fmt.Errorf("unknown transaction type %T", transaction)
+	}
+}
+
+func AdaptDeclareTransaction(t *core.DeclareTransaction) *starknet.Transaction {
+	tx := &starknet.Transaction{
+		Hash:              t.TransactionHash,
+		Type:              starknet.TxnDeclare,
+		SenderAddress:     t.SenderAddress,
+		MaxFee:            t.MaxFee,
+		Signature:         utils.Ptr(t.TransactionSignature),
+		Nonce:             t.Nonce,
+		Version:           t.Version.AsFelt(),
+		ClassHash:         t.ClassHash,
+		CompiledClassHash: t.CompiledClassHash,
+	}
+
+	if t.ResourceBounds != nil {
+		tx.ResourceBounds = adaptResourceBounds(t.ResourceBounds)
+		tx.Tip = new(felt.Felt).SetUint64(t.Tip)
+		tx.PaymasterData = utils.Ptr(utils.NonNilSlice(t.PaymasterData))
+		tx.AccountDeploymentData = utils.Ptr(utils.NonNilSlice(t.AccountDeploymentData))
+		tx.NonceDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.NonceDAMode))
+		tx.FeeDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.FeeDAMode))
+	}
+	return tx
+}
+
+func AdaptDeployTransaction(t *core.DeployTransaction) *starknet.Transaction {
+	return &starknet.Transaction{
+		Hash:                t.TransactionHash,
+		Type:                starknet.TxnDeploy,
+		ContractAddressSalt: t.ContractAddressSalt,
+		ContractAddress:     t.ContractAddress,
+		ClassHash:           t.ClassHash,
+		ConstructorCallData: utils.Ptr(t.ConstructorCallData),
+		Version:             t.Version.AsFelt(),
+	}
+}
+
+func AdaptInvokeTransaction(t *core.InvokeTransaction) *starknet.Transaction {
+	tx := &starknet.Transaction{
+		Hash:               t.TransactionHash,
+		Type:               starknet.TxnInvoke,
+		ContractAddress:    t.ContractAddress,
+		EntryPointSelector: t.EntryPointSelector,
+		Nonce:              t.Nonce,
+		CallData:           utils.Ptr(t.CallData),
+		Signature:          utils.Ptr(t.TransactionSignature),
+		MaxFee:             t.MaxFee,
+		Version:            t.Version.AsFelt(),
+		SenderAddress:      t.SenderAddress,
+	}
+
+	if t.ResourceBounds != nil {
+		tx.ResourceBounds = adaptResourceBounds(t.ResourceBounds)
+		tx.Tip = new(felt.Felt).SetUint64(t.Tip)
+		tx.PaymasterData = utils.Ptr(utils.NonNilSlice(t.PaymasterData))
+		tx.AccountDeploymentData = utils.Ptr(utils.NonNilSlice(t.AccountDeploymentData))
+		tx.NonceDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.NonceDAMode))
+		tx.FeeDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.FeeDAMode))
+	}
+	return tx
+}
+
+func AdaptL1HandlerTransaction(t *core.L1HandlerTransaction) *starknet.Transaction {
+	return &starknet.Transaction{
+		Hash:               t.TransactionHash,
+		Type:               starknet.TxnL1Handler,
+		ContractAddress:    t.ContractAddress,
+		EntryPointSelector: t.EntryPointSelector,
+		Nonce:              t.Nonce,
+		CallData:           utils.Ptr(t.CallData),
+		Version:            t.Version.AsFelt(),
+	}
+}
+
+func AdaptDeployAccountTransaction(t *core.DeployAccountTransaction) *starknet.Transaction {
+	tx := AdaptDeployTransaction(&t.DeployTransaction)
+	tx.Type = starknet.TxnDeployAccount
+	tx.MaxFee = t.MaxFee
+	tx.Signature = utils.Ptr(t.TransactionSignature)
+	tx.Nonce = t.Nonce
+
+	if t.ResourceBounds != nil {
+		tx.ResourceBounds = adaptResourceBounds(t.ResourceBounds)
+		tx.Tip = new(felt.Felt).SetUint64(t.Tip)
+		tx.PaymasterData = utils.Ptr(utils.NonNilSlice(t.PaymasterData))
+		tx.NonceDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.NonceDAMode))
+		tx.FeeDAMode = utils.Ptr(starknet.DataAvailabilityMode(t.FeeDAMode))
+	}
+	return tx
+}
+
+func adaptResourceBounds(rb map[core.Resource]core.ResourceBounds) *map[starknet.Resource]starknet.ResourceBounds {
+	snBounds := make(map[starknet.Resource]starknet.ResourceBounds, len(rb))
+	for resource, bounds := range rb {
+		snBounds[starknet.Resource(resource)] = starknet.ResourceBounds{
+			MaxAmount:       new(felt.Felt).SetUint64(bounds.MaxAmount),
+			MaxPricePerUnit: bounds.MaxPricePerUnit,
+		}
+	}
+	return &snBounds
+}
@@ -0,0 +1,47 @@
+package p2p2core
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// AdaptBlockID returns the number and hash of the identified block
+func AdaptBlockID(id *spec.BlockID) (uint64, *felt.Felt) {
+	return id.GetNumber(), AdaptHash(id.GetHeader())
+}
+
+func AdaptSignature(sig *spec.ConsensusSignature) []*felt.Felt {
+	return []*felt.Felt{AdaptFelt(sig.GetR()), AdaptFelt(sig.GetS())}
+}
+
+// AdaptBlockHeader adapts the headers core2p2p.AdaptHeader produces. The hash and signatures of the block are
+// sent separately, and the fields spec.BlockHeader does not carry, such as the protocol version and gas
+// prices, are not set.
+func AdaptBlockHeader(h *spec.BlockHeader) (*core.Header, *core.BlockCommitments) {
+	return &core.Header{
+		ParentHash:       AdaptHash(h.GetParentHeader()),
+		Number:           h.GetNumber(),
+		GlobalStateRoot:  AdaptHash(h.GetState().GetRoot()),
+		SequencerAddress: AdaptAddress(h.GetSequencerAddress()),
+		TransactionCount: uint64(h.GetTransactions().GetNLeaves()),
+		EventCount:       uint64(h.GetEvents().GetNLeaves()),
+		Timestamp:        uint64(h.GetTime().GetSeconds()),
+	}, &core.BlockCommitments{
+		TransactionCommitment: AdaptHash(h.GetTransactions().GetRoot()),
+		EventCommitment:       AdaptHash(h.GetEvents().GetRoot()),
+	}
+}
+
+func AdaptEvent(e *spec.Event) *core.Event {
+	if e == nil {
+		return nil
+	}
+
+	return &core.Event{
+		From: AdaptFelt(e.FromAddress),
+		Keys: utils.Map(e.Keys, AdaptFelt),
+		Data: utils.Map(e.Data, AdaptFelt),
+	}
+}
@@ -0,0 +1,73 @@
+package p2p2core
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/starknet"
+)
+
+// AdaptClass adapts the classes core2p2p.AdaptClass produces, and returns the hash they are sent with. Cairo 1
+// classes are adapted without their CASM.
+func AdaptClass(c *spec.Class) (*felt.Felt, core.Class, error) {
+	if c.GetCompiledHash() == nil {
+		return nil, nil, errors.New("class without hash")
+	}
+
+	var definition starknet.ClassDefinition
+	if err := json.Unmarshal(c.Definition, &definition); err != nil {
+		return nil, nil, err
+	}
+
+	var class core.Class
+	var err error
+	switch {
+	case definition.V1 != nil:
+		if err = requireFields(sierraFields(definition.V1)...); err != nil {
+			return nil, nil, err
+		}
+		class, err = sn2core.AdaptCairo1Class(definition.V1, nil)
+	case definition.V0 != nil:
+		if err = requireFields(cairo0Fields(definition.V0)...); err != nil {
+			return nil, nil, err
+		}
+		class, err = sn2core.AdaptCairo0Class(definition.V0)
+	default:
+		return nil, nil, errors.New("class without definition")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return AdaptHash(c.CompiledHash), class, nil
+}
+
+// sierraFields returns the fields the hash of a Cairo 1 class is computed from
+func sierraFields(definition *starknet.SierraDefinition) []*felt.Felt {
+	fields := slices.Clone(definition.Program)
+	for _, entryPoints := range [][]starknet.SierraEntryPoint{
+		definition.EntryPoints.Constructor, definition.EntryPoints.External, definition.EntryPoints.L1Handler,
+	} {
+		for _, entryPoint := range entryPoints {
+			fields = append(fields, entryPoint.Selector)
+		}
+	}
+	return fields
+}
+
+// cairo0Fields returns the entry point fields the hash of a Cairo 0 class is computed from
+func cairo0Fields(definition *starknet.Cairo0Definition) []*felt.Felt {
+	var fields []*felt.Felt
+	for _, entryPoints := range [][]starknet.EntryPoint{
+		definition.EntryPoints.Constructor, definition.EntryPoints.External, definition.EntryPoints.L1Handler,
+	} {
+		for _, entryPoint := range entryPoints {
+			fields = append(fields, entryPoint.Selector, entryPoint.Offset)
+		}
+	}
+	return fields
+}
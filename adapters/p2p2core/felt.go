@@ -3,6 +3,7 @@ package p2p2core
 import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
 )
 
 func AdaptHash(h *spec.Hash) *felt.Felt {
@@ -20,3 +21,19 @@ func AdaptAddress(h *spec.Address) *felt.Felt {
 
 	return new(felt.Felt).SetBytes(h.Elements)
 }
+
+func AdaptFelt(f *spec.Felt252) *felt.Felt {
+	if f == nil {
+		return nil
+	}
+
+	return new(felt.Felt).SetBytes(f.Elements)
+}
+
+func AdaptFeltSlice(sl []*spec.Felt252) []*felt.Felt {
+	return utils.Map(sl, AdaptFelt)
+}
+
+func AdaptAccountSignature(s *spec.AccountSignature) []*felt.Felt {
+	return AdaptFeltSlice(s.GetParts())
+}
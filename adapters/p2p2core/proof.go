@@ -0,0 +1,109 @@
+package p2p2core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+)
+
+// AdaptStateDiffProof adapts the proofs core2p2p.AdaptStateDiffProof produces. Missing fields are left nil,
+// which core.VerifyStateDiffProof rejects.
+func AdaptStateDiffProof(p *spec.StateDiffProof) (*core.StateDiffProof, error) {
+	proof := &core.StateDiffProof{
+		OldContractsRoot: AdaptHash(p.GetOldContractsRoot()),
+		OldClassesRoot:   AdaptHash(p.GetOldClassesRoot()),
+		NewContractsRoot: AdaptHash(p.GetNewContractsRoot()),
+		NewClassesRoot:   AdaptHash(p.GetNewClassesRoot()),
+	}
+	for _, contract := range p.GetContracts() {
+		contractProof, err := adaptContractDiffProof(contract)
+		if err != nil {
+			return nil, err
+		}
+		proof.Contracts = append(proof.Contracts, contractProof)
+	}
+	for _, class := range p.GetClasses() {
+		nodes, err := adaptProofNodes(class.GetProof())
+		if err != nil {
+			return nil, err
+		}
+		proof.Classes = append(proof.Classes, &core.DeclaredClassProof{
+			ClassHash: AdaptHash(class.GetClassHash()),
+			Proof:     nodes,
+		})
+	}
+	return proof, nil
+}
+
+func adaptContractDiffProof(p *spec.ContractDiffProof) (*core.ContractDiffProof, error) {
+	proof := &core.ContractDiffProof{Address: AdaptAddress(p.GetAddress())}
+	var err error
+	if p.GetOld() != nil {
+		if proof.Old, err = adaptContractLeafProof(p.Old); err != nil {
+			return nil, err
+		}
+	}
+	if p.GetNew() != nil {
+		if proof.New, err = adaptContractLeafProof(p.New); err != nil {
+			return nil, err
+		}
+	}
+	for _, storage := range p.GetStorage() {
+		storageProof := &core.StorageDiffProof{
+			Key:      AdaptFelt(storage.GetKey()),
+			OldValue: AdaptFelt(storage.GetOldValue()),
+		}
+		if storageProof.OldProof, err = adaptProofNodes(storage.GetOldProof()); err != nil {
+			return nil, err
+		}
+		if storageProof.NewProof, err = adaptProofNodes(storage.GetNewProof()); err != nil {
+			return nil, err
+		}
+		proof.Storage = append(proof.Storage, storageProof)
+	}
+	return proof, nil
+}
+
+func adaptContractLeafProof(p *spec.ContractLeafProof) (*core.ContractLeafProof, error) {
+	nodes, err := adaptProofNodes(p.GetProof())
+	if err != nil {
+		return nil, err
+	}
+	return &core.ContractLeafProof{
+		ClassHash:   AdaptHash(p.GetClassHash()),
+		Nonce:       AdaptFelt(p.GetNonce()),
+		StorageRoot: AdaptHash(p.GetStorageRoot()),
+		Proof:       nodes,
+	}, nil
+}
+
+// adaptProofNodes adapts the nodes of a proof. Nodes missing fields are adapted as nodes trie.VerifyProof
+// rejects.
+func adaptProofNodes(nodes []*spec.PatriciaNode) ([]trie.ProofNode, error) {
+	proofNodes := make([]trie.ProofNode, 0, len(nodes))
+	for _, node := range nodes {
+		var proofNode trie.ProofNode
+		switch n := node.GetNode().(type) {
+		case *spec.PatriciaNode_Binary_:
+			proofNode.Binary = &trie.Binary{
+				LeftHash:  AdaptFelt(n.Binary.GetLeft()),
+				RightHash: AdaptFelt(n.Binary.GetRight()),
+			}
+		case *spec.PatriciaNode_Edge_:
+			if n.Edge.GetLength() > math.MaxUint8 {
+				return nil, fmt.Errorf("edge path of %d bits", n.Edge.GetLength())
+			}
+			proofNode.Edge = &trie.Edge{Child: AdaptFelt(n.Edge.GetValue())}
+			if path := AdaptFelt(n.Edge.GetPath()); path != nil {
+				pathBytes := path.Bytes()
+				key := trie.NewKey(uint8(n.Edge.GetLength()), pathBytes[:])
+				proofNode.Edge.Path = &key
+			}
+		}
+		proofNodes = append(proofNodes, proofNode)
+	}
+	return proofNodes, nil
+}
@@ -0,0 +1,73 @@
+package p2p2core
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AdaptReceipt adapts the receipts core2p2p.AdaptReceipt produces. spec.Receipt does not carry the events of
+// the transaction, which are sent separately for the whole block, nor its L1 to L2 message and fee unit.
+func AdaptReceipt(r *spec.Receipt) *core.TransactionReceipt {
+	var receiptCommon *spec.Receipt_Common
+	switch receipt := r.GetReceipt().(type) {
+	case *spec.Receipt_Invoke_:
+		receiptCommon = receipt.Invoke.GetCommon()
+	case *spec.Receipt_L1Handler_:
+		receiptCommon = receipt.L1Handler.GetCommon()
+	case *spec.Receipt_Declare_:
+		receiptCommon = receipt.Declare.GetCommon()
+	case *spec.Receipt_DeprecatedDeploy:
+		receiptCommon = receipt.DeprecatedDeploy.GetCommon()
+	case *spec.Receipt_DeployAccount_:
+		receiptCommon = receipt.DeployAccount.GetCommon()
+	default:
+		return nil
+	}
+	if receiptCommon == nil {
+		return nil
+	}
+
+	return &core.TransactionReceipt{
+		TransactionHash:    AdaptHash(receiptCommon.TransactionHash),
+		Fee:                AdaptFelt(receiptCommon.ActualFee),
+		L2ToL1Message:      utils.Map(receiptCommon.MessagesSent, AdaptMessageToL1),
+		ExecutionResources: AdaptExecutionResources(receiptCommon.ExecutionResources),
+		Reverted:           receiptCommon.RevertReason != "",
+		RevertReason:       receiptCommon.RevertReason,
+	}
+}
+
+func AdaptMessageToL1(m *spec.MessageToL1) *core.L2ToL1Message {
+	if m == nil {
+		return nil
+	}
+
+	return &core.L2ToL1Message{
+		From:    AdaptFelt(m.FromAddress),
+		Payload: utils.Map(m.Payload, AdaptFelt),
+		To:      common.BytesToAddress(m.ToAddress.GetElements()),
+	}
+}
+
+func AdaptExecutionResources(er *spec.Receipt_ExecutionResources) *core.ExecutionResources {
+	if er == nil {
+		return nil
+	}
+
+	builtins := er.GetBuiltins()
+	return &core.ExecutionResources{
+		BuiltinInstanceCounter: core.BuiltinInstanceCounter{
+			Bitwise:    uint64(builtins.GetBitwise()),
+			Ecsda:      uint64(builtins.GetEcdsa()),
+			EcOp:       uint64(builtins.GetEcOp()),
+			Pedersen:   uint64(builtins.GetPedersen()),
+			RangeCheck: uint64(builtins.GetRangeCheck()),
+			Poseidon:   uint64(builtins.GetPoseidon()),
+			Keccak:     uint64(builtins.GetKeccak()),
+		},
+		Steps:       uint64(er.Steps),
+		MemoryHoles: uint64(er.MemoryHoles),
+	}
+}
@@ -0,0 +1,43 @@
+package p2p2core
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+)
+
+// AdaptStateDiff returns the state diff the contract diffs of a block describe. Contract diffs carry the class
+// hash of every contract they change, without telling deployed contracts from replaced classes, so the class
+// hashes are all returned as replaced classes. Declared classes are sent separately.
+func AdaptStateDiff(diffs []*spec.StateDiff_ContractDiff) (*core.StateDiff, error) {
+	stateDiff := &core.StateDiff{
+		StorageDiffs:    make(map[felt.Felt]map[felt.Felt]*felt.Felt),
+		Nonces:          make(map[felt.Felt]*felt.Felt),
+		ReplacedClasses: make(map[felt.Felt]*felt.Felt),
+	}
+	for _, diff := range diffs {
+		if diff.GetAddress() == nil {
+			return nil, errors.New("contract diff without address")
+		}
+		addr := *AdaptAddress(diff.Address)
+
+		if diff.ClassHash != nil {
+			stateDiff.ReplacedClasses[addr] = AdaptFelt(diff.ClassHash)
+		}
+		if diff.Nonce != nil {
+			stateDiff.Nonces[addr] = AdaptFelt(diff.Nonce)
+		}
+		for _, stored := range diff.Values {
+			if stored.GetKey() == nil || stored.GetValue() == nil {
+				return nil, errors.New("malformed stored value")
+			}
+			if stateDiff.StorageDiffs[addr] == nil {
+				stateDiff.StorageDiffs[addr] = make(map[felt.Felt]*felt.Felt)
+			}
+			stateDiff.StorageDiffs[addr][*AdaptFelt(stored.Key)] = AdaptFelt(stored.Value)
+		}
+	}
+	return stateDiff, nil
+}
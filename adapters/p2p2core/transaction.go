@@ -0,0 +1,294 @@
+package p2p2core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+)
+
+// AdaptTransaction adapts the transactions core2p2p.AdaptTransaction produces. The hashes of the transactions
+// are not set, and neither is the nonce of invoke v1 and v3 transactions, which spec.Transaction does not
+// carry. L1 handler transactions are all adapted as version 1, as spec.Transaction does not tell them from
+// version 0. Transactions missing the fields their hashes are computed from are rejected.
+func AdaptTransaction(t *spec.Transaction) (core.Transaction, error) {
+	switch tx := t.GetTxn().(type) {
+	case *spec.Transaction_DeclareV0_:
+		declare := &core.DeclareTransaction{
+			SenderAddress:        AdaptAddress(tx.DeclareV0.Sender),
+			MaxFee:               AdaptFelt(tx.DeclareV0.MaxFee),
+			TransactionSignature: AdaptAccountSignature(tx.DeclareV0.Signature),
+			ClassHash:            AdaptHash(tx.DeclareV0.ClassHash),
+			Nonce:                &felt.Zero,
+			Version:              txVersion(0),
+		}
+		return declare, requireFields(declare.SenderAddress, declare.MaxFee, declare.ClassHash)
+	case *spec.Transaction_DeclareV1_:
+		declare := &core.DeclareTransaction{
+			SenderAddress:        AdaptAddress(tx.DeclareV1.Sender),
+			MaxFee:               AdaptFelt(tx.DeclareV1.MaxFee),
+			TransactionSignature: AdaptAccountSignature(tx.DeclareV1.Signature),
+			ClassHash:            AdaptHash(tx.DeclareV1.ClassHash),
+			Nonce:                AdaptFelt(tx.DeclareV1.Nonce),
+			Version:              txVersion(1),
+		}
+		return declare, requireFields(declare.SenderAddress, declare.MaxFee, declare.ClassHash, declare.Nonce)
+	case *spec.Transaction_DeclareV2_:
+		declare := &core.DeclareTransaction{
+			SenderAddress:        AdaptAddress(tx.DeclareV2.Sender),
+			MaxFee:               AdaptFelt(tx.DeclareV2.MaxFee),
+			TransactionSignature: AdaptAccountSignature(tx.DeclareV2.Signature),
+			ClassHash:            AdaptHash(tx.DeclareV2.ClassHash),
+			Nonce:                AdaptFelt(tx.DeclareV2.Nonce),
+			CompiledClassHash:    AdaptFelt(tx.DeclareV2.CompiledClassHash),
+			Version:              txVersion(2),
+		}
+		return declare, requireFields(declare.SenderAddress, declare.MaxFee, declare.ClassHash, declare.Nonce,
+			declare.CompiledClassHash)
+	case *spec.Transaction_DeclareV3_:
+		return adaptDeclareV3Transaction(tx.DeclareV3)
+	case *spec.Transaction_Deploy_:
+		return adaptDeployTransaction(tx.Deploy)
+	case *spec.Transaction_DeployAccountV1_:
+		classHash := AdaptHash(tx.DeployAccountV1.ClassHash)
+		salt := AdaptFelt(tx.DeployAccountV1.AddressSalt)
+		callData := AdaptFeltSlice(tx.DeployAccountV1.Calldata)
+		maxFee := AdaptFelt(tx.DeployAccountV1.MaxFee)
+		nonce := AdaptFelt(tx.DeployAccountV1.Nonce)
+		if err := requireFields(classHash, salt, maxFee, nonce); err != nil {
+			return nil, err
+		}
+		return &core.DeployAccountTransaction{
+			DeployTransaction: core.DeployTransaction{
+				ContractAddressSalt: salt,
+				ContractAddress:     core.ContractAddress(&felt.Zero, classHash, salt, callData),
+				ClassHash:           classHash,
+				ConstructorCallData: callData,
+				Version:             txVersion(1),
+			},
+			MaxFee:               maxFee,
+			TransactionSignature: AdaptAccountSignature(tx.DeployAccountV1.Signature),
+			Nonce:                nonce,
+		}, nil
+	case *spec.Transaction_DeployAccountV3_:
+		return adaptDeployAccountV3Transaction(tx.DeployAccountV3)
+	case *spec.Transaction_InvokeV0_:
+		invoke := &core.InvokeTransaction{
+			MaxFee:               AdaptFelt(tx.InvokeV0.MaxFee),
+			TransactionSignature: AdaptAccountSignature(tx.InvokeV0.Signature),
+			ContractAddress:      AdaptAddress(tx.InvokeV0.Address),
+			EntryPointSelector:   AdaptFelt(tx.InvokeV0.EntryPointSelector),
+			CallData:             AdaptFeltSlice(tx.InvokeV0.Calldata),
+			Version:              txVersion(0),
+		}
+		return invoke, requireFields(invoke.MaxFee, invoke.ContractAddress, invoke.EntryPointSelector)
+	case *spec.Transaction_InvokeV1_:
+		invoke := &core.InvokeTransaction{
+			SenderAddress:        AdaptAddress(tx.InvokeV1.Sender),
+			MaxFee:               AdaptFelt(tx.InvokeV1.MaxFee),
+			TransactionSignature: AdaptAccountSignature(tx.InvokeV1.Signature),
+			CallData:             AdaptFeltSlice(tx.InvokeV1.Calldata),
+			Version:              txVersion(1),
+		}
+		return invoke, requireFields(invoke.SenderAddress, invoke.MaxFee)
+	case *spec.Transaction_InvokeV3_:
+		return adaptInvokeV3Transaction(tx.InvokeV3)
+	case *spec.Transaction_L1Handler:
+		l1Handler := &core.L1HandlerTransaction{
+			Nonce:              AdaptFelt(tx.L1Handler.Nonce),
+			ContractAddress:    AdaptAddress(tx.L1Handler.Address),
+			EntryPointSelector: AdaptFelt(tx.L1Handler.EntryPointSelector),
+			CallData:           AdaptFeltSlice(tx.L1Handler.Calldata),
+			Version:            txVersion(1),
+		}
+		return l1Handler, requireFields(l1Handler.Nonce, l1Handler.ContractAddress, l1Handler.EntryPointSelector)
+	default:
+		return nil, fmt.Errorf("unsupported transaction %T", tx)
+	}
+}
+
+func adaptDeployTransaction(tx *spec.Transaction_Deploy) (*core.DeployTransaction, error) {
+	classHash := AdaptHash(tx.ClassHash)
+	salt := AdaptFelt(tx.AddressSalt)
+	if err := requireFields(classHash, salt); err != nil {
+		return nil, err
+	}
+
+	callData := AdaptFeltSlice(tx.Calldata)
+	return &core.DeployTransaction{
+		ContractAddressSalt: salt,
+		ContractAddress:     core.ContractAddress(&felt.Zero, classHash, salt, callData),
+		ClassHash:           classHash,
+		ConstructorCallData: callData,
+		Version:             txVersion(0),
+	}, nil
+}
+
+func adaptDeclareV3Transaction(tx *spec.Transaction_DeclareV3) (*core.DeclareTransaction, error) {
+	fields, err := adaptV3Fields(tx.L1Gas, tx.L2Gas, tx.Tip, tx.Paymaster, tx.NonceDomain, tx.FeeDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	declare := &core.DeclareTransaction{
+		SenderAddress:        AdaptAddress(tx.Sender),
+		MaxFee:               AdaptFelt(tx.MaxFee),
+		TransactionSignature: AdaptAccountSignature(tx.Signature),
+		ClassHash:            AdaptHash(tx.ClassHash),
+		Nonce:                AdaptFelt(tx.Nonce),
+		CompiledClassHash:    AdaptFelt(tx.CompiledClassHash),
+		Version:              txVersion(3),
+		ResourceBounds:       fields.resourceBounds,
+		Tip:                  fields.tip,
+		NonceDAMode:          fields.nonceDAMode,
+		FeeDAMode:            fields.feeDAMode,
+	}
+	return declare, requireFields(declare.SenderAddress, declare.ClassHash, declare.Nonce, declare.CompiledClassHash)
+}
+
+func adaptDeployAccountV3Transaction(tx *spec.Transaction_DeployAccountV3) (*core.DeployAccountTransaction, error) {
+	fields, err := adaptV3Fields(tx.L1Gas, tx.L2Gas, tx.Tip, tx.Paymaster, tx.NonceDomain, tx.FeeDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	classHash := AdaptHash(tx.ClassHash)
+	salt := AdaptFelt(tx.AddressSalt)
+	nonce := AdaptFelt(tx.Nonce)
+	if err = requireFields(classHash, salt, nonce); err != nil {
+		return nil, err
+	}
+
+	callData := AdaptFeltSlice(tx.Calldata)
+	return &core.DeployAccountTransaction{
+		DeployTransaction: core.DeployTransaction{
+			ContractAddressSalt: salt,
+			ContractAddress:     core.ContractAddress(&felt.Zero, classHash, salt, callData),
+			ClassHash:           classHash,
+			ConstructorCallData: callData,
+			Version:             txVersion(3),
+		},
+		MaxFee:               AdaptFelt(tx.MaxFee),
+		TransactionSignature: AdaptAccountSignature(tx.Signature),
+		Nonce:                nonce,
+		ResourceBounds:       fields.resourceBounds,
+		Tip:                  fields.tip,
+		NonceDAMode:          fields.nonceDAMode,
+		FeeDAMode:            fields.feeDAMode,
+	}, nil
+}
+
+func adaptInvokeV3Transaction(tx *spec.Transaction_InvokeV3) (*core.InvokeTransaction, error) {
+	fields, err := adaptV3Fields(tx.L1Gas, tx.L2Gas, tx.Tip, tx.Paymaster, tx.NonceDomain, tx.FeeDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	invoke := &core.InvokeTransaction{
+		SenderAddress:        AdaptAddress(tx.Sender),
+		MaxFee:               AdaptFelt(tx.MaxFee),
+		TransactionSignature: AdaptAccountSignature(tx.Signature),
+		CallData:             AdaptFeltSlice(tx.Calldata),
+		Version:              txVersion(3),
+		ResourceBounds:       fields.resourceBounds,
+		Tip:                  fields.tip,
+		NonceDAMode:          fields.nonceDAMode,
+		FeeDAMode:            fields.feeDAMode,
+	}
+	return invoke, requireFields(invoke.SenderAddress)
+}
+
+// v3Fields are the fields the version 3 transactions share. Paymasters are not adapted, as spec.Transaction
+// has a single address where core transactions have paymaster data.
+type v3Fields struct {
+	resourceBounds map[core.Resource]core.ResourceBounds
+	tip            uint64
+	nonceDAMode    core.DataAvailabilityMode
+	feeDAMode      core.DataAvailabilityMode
+}
+
+func adaptV3Fields(l1Gas, l2Gas *spec.ResourceLimits, tip *spec.Felt252, paymaster *spec.Address,
+	nonceDomain, feeDomain string,
+) (*v3Fields, error) {
+	if paymaster != nil {
+		return nil, errors.New("paymasters are not supported")
+	}
+
+	var fields v3Fields
+	var err error
+	if fields.tip, err = adaptUint64(tip); err != nil {
+		return nil, fmt.Errorf("tip: %v", err)
+	}
+	l1Bounds, err := adaptResourceLimits(l1Gas)
+	if err != nil {
+		return nil, fmt.Errorf("l1 gas: %v", err)
+	}
+	l2Bounds, err := adaptResourceLimits(l2Gas)
+	if err != nil {
+		return nil, fmt.Errorf("l2 gas: %v", err)
+	}
+	fields.resourceBounds = map[core.Resource]core.ResourceBounds{
+		core.ResourceL1Gas: l1Bounds,
+		core.ResourceL2Gas: l2Bounds,
+	}
+	if fields.nonceDAMode, err = adaptDAMode(nonceDomain); err != nil {
+		return nil, err
+	}
+	if fields.feeDAMode, err = adaptDAMode(feeDomain); err != nil {
+		return nil, err
+	}
+	return &fields, nil
+}
+
+func adaptResourceLimits(limits *spec.ResourceLimits) (core.ResourceBounds, error) {
+	maxAmount, err := adaptUint64(limits.GetMaxAmount())
+	if err != nil {
+		return core.ResourceBounds{}, err
+	}
+	maxPricePerUnit := AdaptFelt(limits.GetMaxPricePerUnit())
+	if maxPricePerUnit == nil {
+		return core.ResourceBounds{}, errors.New("missing max price per unit")
+	}
+	return core.ResourceBounds{
+		MaxAmount:       maxAmount,
+		MaxPricePerUnit: maxPricePerUnit,
+	}, nil
+}
+
+func adaptUint64(f *spec.Felt252) (uint64, error) {
+	value := AdaptFelt(f)
+	if value == nil {
+		return 0, errors.New("missing value")
+	}
+	if value.Cmp(new(felt.Felt).SetUint64(value.Uint64())) != 0 {
+		return 0, fmt.Errorf("%s does not fit in 64 bits", value)
+	}
+	return value.Uint64(), nil
+}
+
+func adaptDAMode(domain string) (core.DataAvailabilityMode, error) {
+	switch domain {
+	case "L1":
+		return core.DAModeL1, nil
+	case "L2":
+		return core.DAModeL2, nil
+	default:
+		return 0, fmt.Errorf("unknown data availability mode %q", domain)
+	}
+}
+
+// requireFields rejects the messages missing the fields their hashes are computed from
+func requireFields(fields ...*felt.Felt) error {
+	for _, field := range fields {
+		if field == nil {
+			return errors.New("missing required fields")
+		}
+	}
+	return nil
+}
+
+func txVersion(v uint64) *core.TransactionVersion {
+	return new(core.TransactionVersion).SetUint64(v)
+}
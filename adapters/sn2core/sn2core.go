@@ -52,6 +52,8 @@ func AdaptBlock(response *starknet.Block, sig *starknet.Signature) (*core.Block,
 			EventsBloom:      core.EventsBloom(receipts),
 			GasPrice:         response.GasPriceETH(),
 			GasPriceSTRK:     response.GasPriceSTRK,
+			L1DataGasPrice:   (*core.GasPrice)(response.L1DataGasPrice),
+			L1DAMode:         core.L1DAMode(response.L1DAMode),
 			Signatures:       sigs,
 		},
 		Transactions: txns,
@@ -107,6 +109,7 @@ func AdaptExecutionResources(response *starknet.ExecutionResources) *core.Execut
 		BuiltinInstanceCounter: core.BuiltinInstanceCounter(response.BuiltinInstanceCounter),
 		MemoryHoles:            response.MemoryHoles,
 		Steps:                  response.Steps,
+		TotalGasConsumed:       (*core.GasConsumed)(response.TotalGasConsumed),
 	}
 }
 
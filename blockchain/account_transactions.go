@@ -0,0 +1,208 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// AccountTransaction is a transaction sent by, or deploying, an account along with its position in the chain
+type AccountTransaction struct {
+	BlockNumber uint64
+	Index       uint64
+	Transaction core.Transaction
+}
+
+// AccountTransactionsToken marks the position to resume listing an account's transactions from
+type AccountTransactionsToken struct {
+	blockNumber uint64
+	index       uint64
+}
+
+func (c *AccountTransactionsToken) String() string {
+	return fmt.Sprintf("%d-%d", c.blockNumber, c.index)
+}
+
+func (c *AccountTransactionsToken) FromString(str string) error {
+	_, err := fmt.Sscanf(str, "%d-%d", &c.blockNumber, &c.index)
+	return err
+}
+
+// accountAddress returns the address a transaction is indexed under: the sender for invoke and
+// declare transactions and the deployed contract for deploy and deploy account transactions.
+// L1 handler transactions are not sent by an account and are not indexed.
+func accountAddress(tx core.Transaction) *felt.Felt {
+	switch t := tx.(type) {
+	case *core.InvokeTransaction:
+		if t.SenderAddress != nil {
+			return t.SenderAddress
+		}
+		return t.ContractAddress
+	case *core.DeclareTransaction:
+		return t.SenderAddress
+	case *core.DeployAccountTransaction:
+		return t.ContractAddress
+	case *core.DeployTransaction:
+		return t.ContractAddress
+	default:
+		return nil
+	}
+}
+
+// accountNonce returns the nonce of a transaction, or nil if the transaction version does not carry one
+func accountNonce(tx core.Transaction) *felt.Felt {
+	var nonce *felt.Felt
+	switch t := tx.(type) {
+	case *core.InvokeTransaction:
+		nonce = t.Nonce
+	case *core.DeclareTransaction:
+		nonce = t.Nonce
+	case *core.DeployAccountTransaction:
+		return t.Nonce
+	default:
+		return nil
+	}
+
+	if version := tx.TxVersion(); version != nil && version.Is(0) {
+		return nil
+	}
+	return nonce
+}
+
+// StoreAccountTransaction indexes a transaction under the account that sent or deployed it.
+// The index is maintained by two buckets as follows:
+//
+// [db.AccountTransactions](Address, BlockNumber, Index) -> ()
+// [db.AccountTransactionsByNonce](Address, Nonce) -> (BlockNumber, Index)
+func StoreAccountTransaction(txn db.Transaction, number, i uint64, tx core.Transaction) error {
+	address := accountAddress(tx)
+	if address == nil {
+		return nil
+	}
+
+	addressBytes := address.Marshal()
+	bnIndexBytes := (&txAndReceiptDBKey{number, i}).MarshalBinary()
+	if err := txn.Set(db.AccountTransactions.Key(addressBytes, bnIndexBytes), []byte{}); err != nil {
+		return err
+	}
+
+	if nonce := accountNonce(tx); nonce != nil {
+		return txn.Set(db.AccountTransactionsByNonce.Key(addressBytes, nonce.Marshal()), bnIndexBytes)
+	}
+	return nil
+}
+
+func removeAccountTransaction(txn db.Transaction, number, i uint64, tx core.Transaction) error {
+	address := accountAddress(tx)
+	if address == nil {
+		return nil
+	}
+
+	addressBytes := address.Marshal()
+	bnIndexBytes := (&txAndReceiptDBKey{number, i}).MarshalBinary()
+	if err := txn.Delete(db.AccountTransactions.Key(addressBytes, bnIndexBytes)); err != nil {
+		return err
+	}
+
+	if nonce := accountNonce(tx); nonce != nil {
+		return txn.Delete(db.AccountTransactionsByNonce.Key(addressBytes, nonce.Marshal()))
+	}
+	return nil
+}
+
+// TransactionsByAddress returns up to chunkSize transactions sent by, or deploying, the given address in
+// the order they were included in the chain, starting from the position in cToken if it is not nil.
+// The returned token is nil when there are no more transactions to list.
+func (b *Blockchain) TransactionsByAddress(address *felt.Felt, cToken *AccountTransactionsToken,
+	chunkSize uint64,
+) ([]*AccountTransaction, *AccountTransactionsToken, error) {
+	b.listener.OnRead("TransactionsByAddress")
+	var (
+		txs    []*AccountTransaction
+		rToken *AccountTransactionsToken
+	)
+	return txs, rToken, b.database.View(func(txn db.Transaction) error {
+		var err error
+		txs, rToken, err = transactionsByAddress(txn, address, cToken, chunkSize)
+		return err
+	})
+}
+
+func transactionsByAddress(txn db.Transaction, address *felt.Felt, cToken *AccountTransactionsToken,
+	chunkSize uint64,
+) ([]*AccountTransaction, *AccountTransactionsToken, error) {
+	iterator, err := txn.NewIterator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefix := db.AccountTransactions.Key(address.Marshal())
+	start := prefix
+	if cToken != nil {
+		start = db.AccountTransactions.Key(address.Marshal(),
+			(&txAndReceiptDBKey{cToken.blockNumber, cToken.index}).MarshalBinary())
+	}
+
+	var (
+		txs    []*AccountTransaction
+		rToken *AccountTransactionsToken
+	)
+	for iterator.Seek(start); iterator.Valid(); iterator.Next() {
+		key := iterator.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		var bnIndex txAndReceiptDBKey
+		if err = bnIndex.UnmarshalBinary(key[len(prefix):]); err != nil {
+			return nil, nil, utils.RunAndWrapOnError(iterator.Close, err)
+		}
+
+		if uint64(len(txs)) == chunkSize {
+			rToken = &AccountTransactionsToken{blockNumber: bnIndex.Number, index: bnIndex.Index}
+			break
+		}
+
+		tx, tErr := transactionByBlockNumberAndIndex(txn, &bnIndex)
+		if tErr != nil {
+			return nil, nil, utils.RunAndWrapOnError(iterator.Close, tErr)
+		}
+		txs = append(txs, &AccountTransaction{
+			BlockNumber: bnIndex.Number,
+			Index:       bnIndex.Index,
+			Transaction: tx,
+		})
+	}
+
+	if err = iterator.Close(); err != nil {
+		return nil, nil, err
+	}
+	return txs, rToken, nil
+}
+
+// TransactionBySenderAndNonce returns the transaction sent by the given address with the given nonce
+func (b *Blockchain) TransactionBySenderAndNonce(sender, nonce *felt.Felt) (*AccountTransaction, error) {
+	b.listener.OnRead("TransactionBySenderAndNonce")
+	var accountTx *AccountTransaction
+	return accountTx, b.database.View(func(txn db.Transaction) error {
+		var bnIndex txAndReceiptDBKey
+		if err := txn.Get(db.AccountTransactionsByNonce.Key(sender.Marshal(), nonce.Marshal()), bnIndex.UnmarshalBinary); err != nil {
+			return err
+		}
+
+		tx, err := transactionByBlockNumberAndIndex(txn, &bnIndex)
+		if err != nil {
+			return err
+		}
+		accountTx = &AccountTransaction{
+			BlockNumber: bnIndex.Number,
+			Index:       bnIndex.Index,
+			Transaction: tx,
+		}
+		return nil
+	})
+}
@@ -30,6 +30,9 @@ type Reader interface {
 
 	TransactionByHash(hash *felt.Felt) (transaction core.Transaction, err error)
 	TransactionByBlockNumberAndIndex(blockNumber, index uint64) (transaction core.Transaction, err error)
+	TransactionsByAddress(address *felt.Felt, cToken *AccountTransactionsToken, chunkSize uint64) ([]*AccountTransaction,
+		*AccountTransactionsToken, error)
+	TransactionBySenderAndNonce(sender, nonce *felt.Felt) (*AccountTransaction, error)
 	Receipt(hash *felt.Felt) (receipt *core.TransactionReceipt, blockHash *felt.Felt, blockNumber uint64, err error)
 	StateUpdateByNumber(number uint64) (update *core.StateUpdate, err error)
 	StateUpdateByHash(hash *felt.Felt) (update *core.StateUpdate, err error)
@@ -38,6 +41,15 @@ type Reader interface {
 	StateAtBlockHash(blockHash *felt.Felt) (core.StateReader, StateCloser, error)
 	StateAtBlockNumber(blockNumber uint64) (core.StateReader, StateCloser, error)
 	PendingState() (core.StateReader, StateCloser, error)
+	TriesAtBlockNumber(blockNumber uint64) (core.TrieReader, StateCloser, error)
+
+	ContractStorageChanges(addr, key *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error)
+	ContractNonceChanges(addr *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error)
+	ContractClassHashChanges(addr *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error)
+	ContractStorageRange(addr, start *felt.Felt, blockNumber, limit uint64) ([]core.StorageEntry, *felt.Felt, error)
+	ContractsRange(start *felt.Felt, blockNumber, limit uint64) ([]core.ContractEntry, *felt.Felt, error)
+	ContractsByClassHash(classHash, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error)
+	ClassHashesBySelector(selector, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error)
 
 	BlockCommitmentsByNumber(blockNumber uint64) (*core.BlockCommitments, error)
 
@@ -48,7 +60,8 @@ type Reader interface {
 
 var (
 	ErrParentDoesNotMatchHead = errors.New("block's parent hash does not match head block hash")
-	supportedStarknetVersion  = semver.MustParse("0.13.0")
+	// SupportedStarknetVersion is the latest version of the Starknet protocol whose blocks can be stored
+	SupportedStarknetVersion = semver.MustParse("0.13.2")
 )
 
 func checkBlockVersion(protocolVersion string) error {
@@ -57,7 +70,7 @@ func checkBlockVersion(protocolVersion string) error {
 		return err
 	}
 
-	if blockVer.GreaterThan(supportedStarknetVersion) {
+	if blockVer.GreaterThan(SupportedStarknetVersion) {
 		return errors.New("unsupported block version")
 	}
 
@@ -73,6 +86,7 @@ type Blockchain struct {
 
 	log      utils.SimpleLogger
 	listener EventListener
+	archive  bool
 
 	cachedPending atomic.Pointer[Pending]
 }
@@ -92,6 +106,13 @@ func (b *Blockchain) WithListener(listener EventListener) *Blockchain {
 	return b
 }
 
+// WithArchive sets whether blocks are stored in archive mode, which keeps the state trie nodes they
+// overwrite so that the tries can be read as of any block stored since
+func (b *Blockchain) WithArchive(archive bool) *Blockchain {
+	b.archive = archive
+	return b
+}
+
 func (b *Blockchain) Network() utils.Network {
 	return b.network
 }
@@ -210,7 +231,7 @@ func (b *Blockchain) StateUpdateByNumber(number uint64) (*core.StateUpdate, erro
 	var update *core.StateUpdate
 	return update, b.database.View(func(txn db.Transaction) error {
 		var err error
-		update, err = stateUpdateByNumber(txn, number)
+		update, err = StateUpdateByNumber(txn, number)
 		return err
 	})
 }
@@ -339,39 +360,85 @@ func (b *Blockchain) Store(block *core.Block, blockCommitments *core.BlockCommit
 		if err := verifyBlock(txn, block); err != nil {
 			return err
 		}
-		if err := core.NewState(txn).Update(block.Number, stateUpdate, newClasses); err != nil {
+		if err := core.NewState(txn).WithArchive(b.archive).Update(block.Number, stateUpdate, newClasses); err != nil {
 			return err
 		}
-		if err := StoreBlockHeader(txn, block.Header); err != nil {
+		return b.storeBlock(txn, block, blockCommitments, stateUpdate)
+	})
+}
+
+// Finalise stores a block that was built locally on top of the head, such as one produced by
+// the sequencer. The block's state root, event count, events bloom, hash and commitments are
+// computed from its contents and the state update before it is stored.
+func (b *Blockchain) Finalise(pending *Pending) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		block, stateUpdate := pending.Block, pending.StateUpdate
+		if err := verifyBlock(txn, block); err != nil {
 			return err
 		}
 
-		for i, tx := range block.Transactions {
-			if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
-				block.Receipts[i]); err != nil {
-				return err
-			}
+		state := core.NewState(txn).WithArchive(b.archive)
+		oldRoot, err := state.Root()
+		if err != nil {
+			return err
 		}
+		stateUpdate.OldRoot = oldRoot
 
-		if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
+		if stateUpdate.NewRoot, err = state.Apply(block.Number, stateUpdate, pending.NewClasses); err != nil {
 			return err
 		}
 
-		if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
-			return err
+		block.GlobalStateRoot = stateUpdate.NewRoot
+		block.TransactionCount = uint64(len(block.Transactions))
+		block.EventCount = 0
+		for _, receipt := range block.Receipts {
+			block.EventCount += uint64(len(receipt.Events))
 		}
+		block.EventsBloom = core.EventsBloom(block.Receipts)
 
-		if err := b.storeEmptyPending(txn, block.Header); err != nil {
+		var commitments *core.BlockCommitments
+		if block.Hash, commitments, err = core.BlockHash(block, b.network, stateUpdate.StateDiff); err != nil {
 			return err
 		}
+		stateUpdate.BlockHash = block.Hash
 
-		// Head of the blockchain is maintained as follows:
-		// [db.ChainHeight]() -> (BlockNumber)
-		heightBin := core.MarshalBlockNumber(block.Number)
-		return txn.Set(db.ChainHeight.Key(), heightBin)
+		return b.storeBlock(txn, block, commitments, stateUpdate)
 	})
 }
 
+// storeBlock stores a block whose state update has already been applied and moves the head to it.
+func (b *Blockchain) storeBlock(txn db.Transaction, block *core.Block, blockCommitments *core.BlockCommitments,
+	stateUpdate *core.StateUpdate,
+) error {
+	if err := StoreBlockHeader(txn, block.Header); err != nil {
+		return err
+	}
+
+	for i, tx := range block.Transactions {
+		if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
+			block.Receipts[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
+		return err
+	}
+
+	if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
+		return err
+	}
+
+	if err := b.storeEmptyPending(txn, block.Header); err != nil {
+		return err
+	}
+
+	// Head of the blockchain is maintained as follows:
+	// [db.ChainHeight]() -> (BlockNumber)
+	heightBin := core.MarshalBlockNumber(block.Number)
+	return txn.Set(db.ChainHeight.Key(), heightBin)
+}
+
 // VerifyBlock assumes the block has already been sanity-checked.
 func (b *Blockchain) VerifyBlock(block *core.Block) error {
 	return b.database.View(func(txn db.Transaction) error {
@@ -602,7 +669,8 @@ func storeStateUpdate(txn db.Transaction, blockNumber uint64, update *core.State
 	return txn.Set(db.StateUpdatesByBlockNumber.Key(numBytes), updateBytes)
 }
 
-func stateUpdateByNumber(txn db.Transaction, blockNumber uint64) (*core.StateUpdate, error) {
+// StateUpdateByNumber retrieves the state update of a block from database by its number
+func StateUpdateByNumber(txn db.Transaction, blockNumber uint64) (*core.StateUpdate, error) {
 	numBytes := core.MarshalBlockNumber(blockNumber)
 
 	var update *core.StateUpdate
@@ -619,7 +687,7 @@ func stateUpdateByHash(txn db.Transaction, hash *felt.Felt) (*core.StateUpdate,
 	var update *core.StateUpdate
 	return update, txn.Get(db.BlockHeaderNumbersByHash.Key(hash.Marshal()), func(val []byte) error {
 		var err error
-		update, err = stateUpdateByNumber(txn, binary.BigEndian.Uint64(val))
+		update, err = StateUpdateByNumber(txn, binary.BigEndian.Uint64(val))
 		return err
 	})
 }
@@ -639,7 +707,11 @@ func (b *Blockchain) SanityCheckNewHeight(block *core.Block, stateUpdate *core.S
 		return nil, err
 	}
 
-	return core.VerifyBlockHash(block, b.network)
+	if err := core.VerifyCompiledClassHashes(stateUpdate.StateDiff.DeclaredV1Classes, newClasses); err != nil {
+		return nil, err
+	}
+
+	return core.VerifyBlockHash(block, b.network, stateUpdate.StateDiff)
 }
 
 type txAndReceiptDBKey struct {
@@ -691,7 +763,10 @@ func storeTransactionAndReceipt(txn db.Transaction, number, i uint64, t core.Tra
 	if err != nil {
 		return err
 	}
-	return txn.Set(db.ReceiptsByBlockNumberAndIndex.Key(bnIndexBytes), rBytes)
+	if err = txn.Set(db.ReceiptsByBlockNumberAndIndex.Key(bnIndexBytes), rBytes); err != nil {
+		return err
+	}
+	return StoreAccountTransaction(txn, number, i, t)
 }
 
 // transactionBlockNumberAndIndexByHash gets the block number and index for a given transaction hash
@@ -809,6 +884,167 @@ func (b *Blockchain) StateAtBlockHash(blockHash *felt.Felt) (core.StateReader, S
 	return core.NewStateSnapshot(core.NewState(txn), header.Number), txn.Discard, nil
 }
 
+// TriesAtBlockNumber returns a TrieReader that provides a stable view to the commitment tries of the state
+// at the given block number. Only the tries at the head, or at the blocks stored in archive mode, can be read.
+func (b *Blockchain) TriesAtBlockNumber(blockNumber uint64) (core.TrieReader, StateCloser, error) {
+	b.listener.OnRead("TriesAtBlockNumber")
+	txn, err := b.database.NewTransaction(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	atHead, err := isHead(txn, blockNumber)
+	if err != nil {
+		return nil, nil, utils.RunAndWrapOnError(txn.Discard, err)
+	}
+
+	state := core.NewState(txn)
+	if atHead {
+		return state.Tries(), txn.Discard, nil
+	}
+
+	tries, err := state.TriesAt(blockNumber)
+	if err != nil {
+		return nil, nil, utils.RunAndWrapOnError(txn.Discard, err)
+	}
+	return tries, txn.Discard, nil
+}
+
+// ContractStorageChanges returns up to limit changes to a storage location of the given contract between
+// the blocks from and to (inclusive), along with the block number of the next change if there are more
+func (b *Blockchain) ContractStorageChanges(addr, key *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+	b.listener.OnRead("ContractStorageChanges")
+	return b.valueChanges(func(state *core.State) ([]core.ValueChange, *uint64, error) {
+		return state.ContractStorageChanges(addr, key, from, to, limit)
+	})
+}
+
+// ContractNonceChanges returns up to limit changes to the nonce of the given contract between the blocks
+// from and to (inclusive), along with the block number of the next change if there are more
+func (b *Blockchain) ContractNonceChanges(addr *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+	b.listener.OnRead("ContractNonceChanges")
+	return b.valueChanges(func(state *core.State) ([]core.ValueChange, *uint64, error) {
+		return state.ContractNonceChanges(addr, from, to, limit)
+	})
+}
+
+// ContractClassHashChanges returns up to limit changes to the class hash of the given contract, including
+// its deployment, between the blocks from and to (inclusive), along with the block number of the next
+// change if there are more
+func (b *Blockchain) ContractClassHashChanges(addr *felt.Felt, from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+	b.listener.OnRead("ContractClassHashChanges")
+	return b.valueChanges(func(state *core.State) ([]core.ValueChange, *uint64, error) {
+		return state.ContractClassHashChanges(addr, from, to, limit)
+	})
+}
+
+func (b *Blockchain) valueChanges(changes func(state *core.State) ([]core.ValueChange, *uint64, error)) (
+	[]core.ValueChange, *uint64, error,
+) {
+	var (
+		values []core.ValueChange
+		next   *uint64
+	)
+	return values, next, b.database.View(func(txn db.Transaction) error {
+		var err error
+		values, next, err = changes(core.NewState(txn))
+		return err
+	})
+}
+
+// ContractStorageRange returns up to limit non-zero storage locations of the given contract as of the
+// given block, in ascending order starting from the location start, along with the next location if
+// there are more
+func (b *Blockchain) ContractStorageRange(addr, start *felt.Felt, blockNumber, limit uint64) (
+	[]core.StorageEntry, *felt.Felt, error,
+) {
+	b.listener.OnRead("ContractStorageRange")
+	var (
+		entries []core.StorageEntry
+		next    *felt.Felt
+	)
+	return entries, next, b.database.View(func(txn db.Transaction) error {
+		atHead, err := isHead(txn, blockNumber)
+		if err != nil {
+			return err
+		}
+
+		state := core.NewState(txn)
+		if atHead {
+			entries, next, err = state.ContractStorageRange(addr, start, limit)
+		} else {
+			entries, next, err = state.ContractStorageRangeAt(addr, start, blockNumber, limit)
+		}
+		return err
+	})
+}
+
+// ContractsRange returns up to limit contracts that were deployed as of the given block, in ascending
+// address order starting from the address start, along with the next address if there are more
+func (b *Blockchain) ContractsRange(start *felt.Felt, blockNumber, limit uint64) ([]core.ContractEntry, *felt.Felt, error) {
+	b.listener.OnRead("ContractsRange")
+	var (
+		entries []core.ContractEntry
+		next    *felt.Felt
+	)
+	return entries, next, b.database.View(func(txn db.Transaction) error {
+		atHead, err := isHead(txn, blockNumber)
+		if err != nil {
+			return err
+		}
+
+		state := core.NewState(txn)
+		if atHead {
+			entries, next, err = state.ContractsRange(start, limit)
+		} else {
+			entries, next, err = state.ContractsRangeAt(start, blockNumber, limit)
+		}
+		return err
+	})
+}
+
+// ContractsByClassHash returns up to limit addresses of the contracts currently using the given class, in
+// ascending order starting from the address start, along with the next address if there are more
+func (b *Blockchain) ContractsByClassHash(classHash, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error) {
+	b.listener.OnRead("ContractsByClassHash")
+	return b.indexedFelts(func(state *core.State) ([]*felt.Felt, *felt.Felt, error) {
+		return state.ContractsByClassHash(classHash, start, limit)
+	})
+}
+
+// ClassHashesBySelector returns up to limit hashes of the declared classes with an entry point of the given
+// selector, in ascending order starting from the class hash start, along with the next class hash if there
+// are more
+func (b *Blockchain) ClassHashesBySelector(selector, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error) {
+	b.listener.OnRead("ClassHashesBySelector")
+	return b.indexedFelts(func(state *core.State) ([]*felt.Felt, *felt.Felt, error) {
+		return state.ClassHashesBySelector(selector, start, limit)
+	})
+}
+
+func (b *Blockchain) indexedFelts(lookup func(state *core.State) ([]*felt.Felt, *felt.Felt, error)) (
+	[]*felt.Felt, *felt.Felt, error,
+) {
+	var values []*felt.Felt
+	var next *felt.Felt
+	return values, next, b.database.View(func(txn db.Transaction) error {
+		var err error
+		values, next, err = lookup(core.NewState(txn))
+		return err
+	})
+}
+
+// isHead returns whether the given block is the head of the chain. Blocks after the head are not found.
+func isHead(txn db.Transaction, blockNumber uint64) (bool, error) {
+	height, err := chainHeight(txn)
+	if err != nil {
+		return false, err
+	} else if blockNumber > height {
+		return false, db.ErrKeyNotFound
+	}
+	return blockNumber == height, nil
+}
+
 // EventFilter returns an EventFilter object that is tied to a snapshot of the blockchain
 func (b *Blockchain) EventFilter(from *felt.Felt, keys [][]felt.Felt) (*EventFilter, error) {
 	b.listener.OnRead("EventFilter")
@@ -837,7 +1073,7 @@ func (b *Blockchain) revertHead(txn db.Transaction) error {
 	}
 	numBytes := core.MarshalBlockNumber(blockNumber)
 
-	stateUpdate, err := stateUpdateByNumber(txn, blockNumber)
+	stateUpdate, err := StateUpdateByNumber(txn, blockNumber)
 	if err != nil {
 		return err
 	}
@@ -919,6 +1155,9 @@ func removeTxsAndReceipts(txn db.Transaction, blockNumber, numTxs uint64) error
 		if err = txn.Delete(db.TransactionBlockNumbersAndIndicesByHash.Key(reorgedTxn.Hash().Marshal())); err != nil {
 			return err
 		}
+		if err = removeAccountTransaction(txn, blockNumber, i, reorgedTxn); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -959,16 +1198,8 @@ func (b *Blockchain) storeEmptyPending(txn db.Transaction, latestHeader *core.He
 // StorePending stores a pending block given that it is for the next height
 func (b *Blockchain) StorePending(pending *Pending) error {
 	return b.database.Update(func(txn db.Transaction) error {
-		expectedParentHash := new(felt.Felt)
-		h, err := headsHeader(txn)
-		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		if err := checkPendingParent(txn, pending); err != nil {
 			return err
-		} else if err == nil {
-			expectedParentHash = h.Hash
-		}
-
-		if !expectedParentHash.Equal(pending.Block.ParentHash) {
-			return ErrParentDoesNotMatchHead
 		}
 
 		existingPending, err := b.pendingBlock(txn)
@@ -980,6 +1211,32 @@ func (b *Blockchain) StorePending(pending *Pending) error {
 	})
 }
 
+// ReplacePending stores the pending block even if the stored one has as many transactions, which
+// allows changes to the pending state that are not made by transactions.
+func (b *Blockchain) ReplacePending(pending *Pending) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		if err := checkPendingParent(txn, pending); err != nil {
+			return err
+		}
+		return b.storePending(txn, pending)
+	})
+}
+
+func checkPendingParent(txn db.Transaction, pending *Pending) error {
+	expectedParentHash := new(felt.Felt)
+	h, err := headsHeader(txn)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	} else if err == nil {
+		expectedParentHash = h.Hash
+	}
+
+	if !expectedParentHash.Equal(pending.Block.ParentHash) {
+		return ErrParentDoesNotMatchHead
+	}
+	return nil
+}
+
 func (b *Blockchain) storePending(txn db.Transaction, pending *Pending) error {
 	if err := storePending(txn, pending); err != nil {
 		return err
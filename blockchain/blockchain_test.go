@@ -208,6 +208,23 @@ func TestSanityCheckNewHeight(t *testing.T) {
 			_, err = chain.SanityCheckNewHeight(mainnetBlock1, stateUpdate, nil)
 			assert.EqualError(t, err, "block's GlobalStateRoot does not match state update's NewRoot")
 		})
+
+	t.Run("error when compiled class hash does not match the declared one", func(t *testing.T) {
+		mainnetBlock1, err := gw.BlockByNumber(context.Background(), 1)
+		require.NoError(t, err)
+
+		classHash := utils.HexToFelt(t, "0x1338d85d3e579f6944ba06c005238d145920afeb32f94e3a1e234d21e1e9292")
+		class, err := adaptfeeder.New(feeder.NewTestClient(t, utils.Goerli)).Class(context.Background(), classHash)
+		require.NoError(t, err)
+
+		stateUpdate := &core.StateUpdate{
+			BlockHash: mainnetBlock1.Hash,
+			NewRoot:   mainnetBlock1.GlobalStateRoot,
+			StateDiff: &core.StateDiff{DeclaredV1Classes: map[felt.Felt]*felt.Felt{*classHash: h1}},
+		}
+		_, err = chain.SanityCheckNewHeight(mainnetBlock1, stateUpdate, map[felt.Felt]core.Class{*classHash: class})
+		assert.ErrorContains(t, err, "cannot verify compiled class hash")
+	})
 }
 
 func TestStore(t *testing.T) {
@@ -357,6 +374,87 @@ func TestTransactionAndReceipt(t *testing.T) {
 	})
 }
 
+func TestAccountTransactions(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	chain := blockchain.New(testDB, utils.Goerli2, utils.NewNopZapLogger())
+
+	client := feeder.NewTestClient(t, utils.Goerli2)
+	gw := adaptfeeder.New(client)
+
+	for i := uint64(0); i < 6; i++ {
+		b, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(b, &emptyCommitments, su, nil))
+	}
+
+	// deployed in block 2 and sends the only transactions in blocks 4 and 5
+	account := utils.HexToFelt(t, "0x2ee9bf3da86f3715e8a20429feed8e37fef58004ee5cf52baf2d8fc0d94c9c8")
+
+	t.Run("all transactions", func(t *testing.T) {
+		txs, cToken, err := chain.TransactionsByAddress(account, nil, 10)
+		require.NoError(t, err)
+		require.Nil(t, cToken)
+		require.Len(t, txs, 3)
+		for i, blockNumber := range []uint64{2, 4, 5} {
+			assert.Equal(t, blockNumber, txs[i].BlockNumber)
+			assert.Equal(t, uint64(0), txs[i].Index)
+
+			expected, err := chain.TransactionByBlockNumberAndIndex(blockNumber, 0)
+			require.NoError(t, err)
+			assert.Equal(t, expected, txs[i].Transaction)
+		}
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		var (
+			blockNumbers []uint64
+			cToken       *blockchain.AccountTransactionsToken
+		)
+		for {
+			txs, nextToken, err := chain.TransactionsByAddress(account, cToken, 1)
+			require.NoError(t, err)
+			require.Len(t, txs, 1)
+			blockNumbers = append(blockNumbers, txs[0].BlockNumber)
+			if nextToken == nil {
+				break
+			}
+
+			cToken = new(blockchain.AccountTransactionsToken)
+			require.NoError(t, cToken.FromString(nextToken.String()))
+		}
+		assert.Equal(t, []uint64{2, 4, 5}, blockNumbers)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		txs, cToken, err := chain.TransactionsByAddress(new(felt.Felt).SetUint64(1), nil, 10)
+		require.NoError(t, err)
+		assert.Nil(t, cToken)
+		assert.Empty(t, txs)
+	})
+
+	t.Run("by sender and nonce", func(t *testing.T) {
+		tx, err := chain.TransactionBySenderAndNonce(account, new(felt.Felt).SetUint64(1))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), tx.BlockNumber)
+
+		_, err = chain.TransactionBySenderAndNonce(account, new(felt.Felt).SetUint64(2))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("revert removes the index", func(t *testing.T) {
+		require.NoError(t, chain.RevertHead())
+
+		txs, _, err := chain.TransactionsByAddress(account, nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, txs, 2)
+
+		_, err = chain.TransactionBySenderAndNonce(account, new(felt.Felt).SetUint64(1))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+}
+
 func TestState(t *testing.T) {
 	testDB := pebble.NewMemTest(t)
 	chain := blockchain.New(testDB, utils.Mainnet, utils.NewNopZapLogger())
@@ -397,6 +495,58 @@ func TestState(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("contracts range", func(t *testing.T) {
+		su0, err := gw.StateUpdate(context.Background(), 0)
+		require.NoError(t, err)
+		su1, err := gw.StateUpdate(context.Background(), 1)
+		require.NoError(t, err)
+
+		atGenesis, _, err := chain.ContractsRange(&felt.Zero, 0, 1000)
+		require.NoError(t, err)
+		assert.Len(t, atGenesis, len(su0.StateDiff.DeployedContracts))
+
+		atHead, _, err := chain.ContractsRange(&felt.Zero, 1, 1000)
+		require.NoError(t, err)
+		assert.Len(t, atHead, len(su0.StateDiff.DeployedContracts)+len(su1.StateDiff.DeployedContracts))
+
+		_, _, err = chain.ContractsRange(&felt.Zero, 2, 1000)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("tries", func(t *testing.T) {
+		tries, closer, err := chain.TriesAtBlockNumber(1)
+		require.NoError(t, err)
+		_, _, err = tries.GlobalRoots()
+		require.NoError(t, err)
+		require.NoError(t, closer())
+
+		_, _, err = chain.TriesAtBlockNumber(0)
+		require.ErrorIs(t, err, core.ErrStateNotArchived)
+		_, _, err = chain.TriesAtBlockNumber(2)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		archive := blockchain.New(pebble.NewMemTest(t), utils.Mainnet, utils.NewNopZapLogger()).WithArchive(true)
+		var su0 *core.StateUpdate
+		for i := uint64(0); i < 2; i++ {
+			block, err := gw.BlockByNumber(context.Background(), i)
+			require.NoError(t, err)
+			su, err := gw.StateUpdate(context.Background(), i)
+			require.NoError(t, err)
+			require.NoError(t, archive.Store(block, &emptyCommitments, su, nil))
+			if i == 0 {
+				su0 = su
+			}
+		}
+
+		tries, closer, err = archive.TriesAtBlockNumber(0)
+		require.NoError(t, err)
+		contractsRoot, classesRoot, err := tries.GlobalRoots()
+		require.NoError(t, err)
+		assert.Equal(t, su0.NewRoot, contractsRoot)
+		assert.True(t, classesRoot.IsZero())
+		require.NoError(t, closer())
+	})
+
 	t.Run("existing hash", func(t *testing.T) {
 		_, closer, err := chain.StateAtBlockHash(existingBlockHash)
 		require.NoError(t, err)
@@ -740,6 +890,25 @@ func TestPending(t *testing.T) {
 		assert.Equal(t, expectedPending, gotPending)
 	})
 
+	t.Run("replace pending block with the same transactions", func(t *testing.T) {
+		header := *b.Header
+		header.Timestamp++
+		replacement := blockchain.Pending{
+			Block:       &core.Block{Header: &header, Transactions: b.Transactions, Receipts: b.Receipts},
+			StateUpdate: su,
+		}
+
+		require.NoError(t, chain.StorePending(&replacement))
+		gotPending, pErr := chain.Pending()
+		require.NoError(t, pErr)
+		assert.Equal(t, b.Timestamp, gotPending.Block.Timestamp)
+
+		require.NoError(t, chain.ReplacePending(&replacement))
+		gotPending, pErr = chain.Pending()
+		require.NoError(t, pErr)
+		assert.Equal(t, header.Timestamp, gotPending.Block.Timestamp)
+	})
+
 	t.Run("fetch a txn from pending block", func(t *testing.T) {
 		hash := utils.HexToFelt(t, "0x2f07a65f9f7a6445b2a0b1fb90ef12f5fd3b94128d06a67712efd3b2f163533")
 		tx, tErr := chain.TransactionByHash(hash)
@@ -0,0 +1,96 @@
+package feeder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveEntry maps a feeder gateway request to its location in an archive of responses.
+// The layout is the one used by the test data: <dir>/<query argument value>.json
+func archiveEntry(urlPath string, query url.Values) (string, string, bool) {
+	const blockNumberArg = "blockNumber"
+
+	var dir, queryArg string
+	switch {
+	case strings.HasSuffix(urlPath, "get_block"):
+		dir = "block"
+		queryArg = blockNumberArg
+	case strings.HasSuffix(urlPath, "get_state_update"):
+		queryArg = blockNumberArg
+		if includeBlock, ok := query["includeBlock"]; !ok || len(includeBlock) == 0 {
+			dir = "state_update"
+		} else {
+			dir = "state_update_with_block"
+		}
+	case strings.HasSuffix(urlPath, "get_transaction"):
+		dir = "transaction"
+		queryArg = "transactionHash"
+	case strings.HasSuffix(urlPath, "get_class_by_hash"):
+		dir = "class"
+		queryArg = "classHash"
+	case strings.HasSuffix(urlPath, "get_compiled_class_by_class_hash"):
+		dir = "compiled_class"
+		queryArg = "classHash"
+	case strings.HasSuffix(urlPath, "get_public_key"):
+		return "public_key", "pk", true
+	case strings.HasSuffix(urlPath, "get_signature"):
+		dir = "signature"
+		queryArg = blockNumberArg
+	case strings.HasSuffix(urlPath, "get_block_traces"):
+		dir = "traces"
+		queryArg = "blockHash"
+	default:
+		return "", "", false
+	}
+
+	name, found := query[queryArg]
+	if !found || len(name) == 0 {
+		return "", "", false
+	}
+	return dir, name[0], true
+}
+
+// record writes the response body of the given request into the recording directory and
+// returns a reader over the same body, so that the caller can decode it as usual.
+func (c *Client) record(reqURL *url.URL, body io.ReadCloser) (io.ReadCloser, error) {
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, name, ok := archiveEntry(reqURL.Path, reqURL.Query())
+	if !ok {
+		c.log.Debugw("Not recording unknown feeder request", "req", reqURL.String())
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	dir = filepath.Join(c.recordDir, dir)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// Write to a temporary file first so that an interrupted recording never leaves a truncated response behind.
+	// Each recording gets a temporary file of its own, as concurrent requests may record the same response.
+	tmp, err := os.CreateTemp(dir, name+"*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, name+".json"))
+	}
+	if err != nil {
+		return nil, errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
@@ -31,6 +31,7 @@ type Client struct {
 	log        utils.SimpleLogger
 	userAgent  string
 	listener   EventListener
+	recordDir  string
 }
 
 func (c *Client) WithListener(l EventListener) *Client {
@@ -68,6 +69,14 @@ func (c *Client) WithUserAgent(ua string) *Client {
 	return c
 }
 
+// WithRecording makes the client write every successful response it receives into dir,
+// using the same layout as the test data. The recorded responses can be replayed with
+// starknetdata/archive.
+func (c *Client) WithRecording(dir string) *Client {
+	c.recordDir = dir
+	return c
+}
+
 func (c *Client) WithTimeout(t time.Duration) *Client {
 	c.client.Timeout = t
 	return c
@@ -120,62 +129,27 @@ func newTestServer(network utils.Network) *httptest.Server {
 		}
 
 		base := wd[:strings.LastIndex(wd, "juno")+4]
-		queryArg := ""
-		dir := ""
-		const blockNumberArg = "blockNumber"
-		switch {
-		case strings.HasSuffix(r.URL.Path, "get_block"):
-			dir = "block"
-			queryArg = blockNumberArg
-		case strings.HasSuffix(r.URL.Path, "get_state_update"):
-			queryArg = blockNumberArg
-			if includeBlock, ok := queryMap["includeBlock"]; !ok || len(includeBlock) == 0 {
-				dir = "state_update"
-			} else {
-				dir = "state_update_with_block"
-			}
-		case strings.HasSuffix(r.URL.Path, "get_transaction"):
-			dir = "transaction"
-			queryArg = "transactionHash"
-		case strings.HasSuffix(r.URL.Path, "get_class_by_hash"):
-			dir = "class"
-			queryArg = "classHash"
-		case strings.HasSuffix(r.URL.Path, "get_compiled_class_by_class_hash"):
-			dir = "compiled_class"
-			queryArg = "classHash"
-		case strings.HasSuffix(r.URL.Path, "get_public_key"):
-			dir = "public_key"
-			queryArg = "pk"
-			queryMap[queryArg] = []string{queryArg}
-		case strings.HasSuffix(r.URL.Path, "get_signature"):
-			dir = "signature"
-			queryArg = blockNumberArg
-		case strings.HasSuffix(r.URL.Path, "get_block_traces"):
-			dir = "traces"
-			queryArg = "blockHash"
-		}
-
-		fileName, found := queryMap[queryArg]
+		dir, fileName, found := archiveEntry(r.URL.Path, queryMap)
 		if !found {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
-		path := filepath.Join(base, "clients", "feeder", "testdata", network.String(), dir, fileName[0]+".json")
+		path := filepath.Join(base, "clients", "feeder", "testdata", network.String(), dir, fileName+".json")
 		read, err := os.ReadFile(path)
 		if err != nil {
-			handleNotFound(dir, queryArg, w)
+			handleNotFound(dir, w)
 			return
 		}
 		w.Write(read) //nolint:errcheck
 	}))
 }
 
-func handleNotFound(dir, queryArg string, w http.ResponseWriter) {
+func handleNotFound(dir string, w http.ResponseWriter) {
 	// If a transaction data is missing, respond with
 	// {"finality_status": "NOT_RECEIVED", "status": "NOT_RECEIVED"}
 	// instead of 404 as per real test server behaviour.
-	if dir == "transaction" && queryArg == "transactionHash" {
+	if dir == "transaction" {
 		w.Write([]byte("{\"finality_status\": \"NOT_RECEIVED\", \"status\": \"NOT_RECEIVED\"}")) //nolint:errcheck
 	} else {
 		w.WriteHeader(http.StatusBadRequest)
@@ -237,6 +211,9 @@ func (c *Client) get(ctx context.Context, queryURL string) (io.ReadCloser, error
 			if err == nil {
 				c.listener.OnResponse(req.URL.Path, res.StatusCode, time.Since(reqTimer))
 				if res.StatusCode == http.StatusOK {
+					if c.recordDir != "" {
+						return c.record(req.URL, res.Body)
+					}
 					return res.Body, nil
 				} else {
 					err = errors.New(res.Status)
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -639,3 +642,57 @@ func TestEventListener(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, isCalled)
 }
+
+func TestRecording(t *testing.T) {
+	dir := t.TempDir()
+	client := feeder.NewTestClient(t, utils.Integration).WithRecording(dir)
+
+	block, err := client.Block(context.Background(), "0")
+	require.NoError(t, err)
+	_, err = client.StateUpdateWithBlock(context.Background(), "0")
+	require.NoError(t, err)
+	classHash := utils.HexToFelt(t, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+	_, err = client.CompiledClassDefinition(context.Background(), classHash)
+	require.NoError(t, err)
+
+	for _, file := range []string{
+		filepath.Join("block", "0.json"),
+		filepath.Join("state_update_with_block", "0.json"),
+		filepath.Join("compiled_class", classHash.String()+".json"),
+	} {
+		recorded, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		expected, err := os.ReadFile(filepath.Join("testdata", utils.Integration.String(), file))
+		require.NoError(t, err)
+		assert.Equal(t, expected, recorded, file)
+	}
+
+	recordedBlock := new(starknet.Block)
+	data, err := os.ReadFile(filepath.Join(dir, "block", "0.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, recordedBlock))
+	assert.Equal(t, block, recordedBlock)
+
+	t.Run("concurrent recordings of the same response", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Block(context.Background(), "1")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		recorded, err := os.ReadFile(filepath.Join(dir, "block", "1.json"))
+		require.NoError(t, err)
+		expected, err := os.ReadFile(filepath.Join("testdata", utils.Integration.String(), "block", "1.json"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, recorded)
+
+		leftovers, err := filepath.Glob(filepath.Join(dir, "block", "*.tmp"))
+		require.NoError(t, err)
+		assert.Empty(t, leftovers)
+	})
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/backends"
+	"github.com/NethermindEth/juno/db/compress"
+	"github.com/NethermindEth/juno/migration"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	migrateStatusF = "status"
+	migrateDryRunF = "dry-run"
+
+	migrateStatusUsage = "Lists the migrations applied to the database and those pending, without applying any."
+	migrateDryRunUsage = "Reports the buckets the pending migrations touch and how many keys they hold, " +
+		"without applying any. Counting the keys of a synced database can take a while."
+)
+
+// DBCmd returns the command that manages the database at the given path.
+func DBCmd(defaultDBPath string) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manages the database of the node.",
+	}
+	dbCmd.AddCommand(migrateCmd(defaultDBPath), recompressCmd(defaultDBPath))
+	return dbCmd
+}
+
+func migrateCmd(defaultDBPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [flags]",
+		Short: "Applies the pending database migrations, which the node otherwise does when it starts.",
+		Long: "Applies the pending database migrations, which the node otherwise does when it starts. Migrations " +
+			"rewrite data in place and cannot be rolled back, so back up the database first if you may need to " +
+			"run an older version of Juno on it.",
+		Args: cobra.NoArgs,
+	}
+
+	network := utils.Mainnet
+	addDBFlags(cmd, defaultDBPath)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().Bool(migrateStatusF, false, migrateStatusUsage)
+	cmd.Flags().Bool(migrateDryRunF, false, migrateDryRunUsage)
+	cmd.MarkFlagsMutuallyExclusive(migrateStatusF, migrateDryRunF)
+
+	cmd.RunE = func(cmd *cobra.Command, _ []string) (err error) {
+		status, err := cmd.Flags().GetBool(migrateStatusF)
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool(migrateDryRunF)
+		if err != nil {
+			return err
+		}
+
+		log, err := utils.NewZapLogger(utils.INFO, true)
+		if err != nil {
+			return err
+		}
+		// opening a database creates it, which only applying migrations should do
+		access := readWrite
+		if status || dryRun {
+			access = readOnly
+		}
+		database, err := openDB(cmd, access)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := database.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("close DB: %w", closeErr)
+			}
+		}()
+
+		switch {
+		case status:
+			return printMigrationStatus(cmd.OutOrStdout(), database)
+		case dryRun:
+			estimates, dryRunErr := migration.DryRun(cmd.Context(), database)
+			if dryRunErr != nil {
+				return dryRunErr
+			}
+			return printMigrationEstimates(cmd.OutOrStdout(), estimates)
+		default:
+			return migration.MigrateIfNeeded(cmd.Context(), database, network, Version, log)
+		}
+	}
+	return cmd
+}
+
+func recompressCmd(defaultDBPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "recompress [flags]",
+		Short: "Compresses the values stored before --db-compression was configured, or with other algorithms, " +
+			"with the configured algorithms.",
+		Args: cobra.NoArgs,
+	}
+	addDBFlags(cmd, defaultDBPath)
+
+	cmd.RunE = func(cmd *cobra.Command, _ []string) (err error) {
+		log, err := utils.NewZapLogger(utils.INFO, true)
+		if err != nil {
+			return err
+		}
+		database, err := openDB(cmd, readWriteExisting)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := database.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("close DB: %w", closeErr)
+			}
+		}()
+		return migration.Recompress(cmd.Context(), database, log)
+	}
+	return cmd
+}
+
+// addDBFlags adds the flags openDB opens the database with
+func addDBFlags(cmd *cobra.Command, defaultDBPath string) {
+	cmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
+	cmd.Flags().String(dbBackendF, defaultDBBackend, dbBackendUsage+strings.Join(backends.Names(), ", ")+".")
+	cmd.Flags().String(dbCompressionF, defaultDBCompression, dbCompressionUsage)
+}
+
+// dbAccess is how a command opens the database
+type dbAccess uint8
+
+const (
+	// readWrite opens the database, creating it if there is none
+	readWrite dbAccess = iota
+	// readWriteExisting opens the database, failing if there is none
+	readWriteExisting
+	// readOnly opens the database without writing to it, failing if there is none
+	readOnly
+)
+
+// openDB opens the database the flags of cmd configure
+func openDB(cmd *cobra.Command, access dbAccess) (*compress.DB, error) {
+	dbPath, err := cmd.Flags().GetString(dbPathF)
+	if err != nil {
+		return nil, err
+	}
+	dbBackend, err := cmd.Flags().GetString(dbBackendF)
+	if err != nil {
+		return nil, err
+	}
+	dbCompression, err := cmd.Flags().GetString(dbCompressionF)
+	if err != nil {
+		return nil, err
+	}
+	algorithms, err := compress.ParseConfig(dbCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = os.Stat(dbPath); access != readWrite && errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no database at %s", dbPath)
+	}
+
+	dbLog, err := utils.NewZapLogger(utils.ERROR, true)
+	if err != nil {
+		return nil, err
+	}
+	var database db.DB
+	if access == readOnly {
+		database, err = backends.OpenReadOnly(dbPath, defaultCacheSizeMb, dbLog)
+	} else {
+		database, err = backends.Open(dbBackend, dbPath, defaultCacheSizeMb, dbLog)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open DB: %w", err)
+	}
+	compressedDB, err := compress.New(database, algorithms)
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, err)
+	}
+	return compressedDB, nil
+}
+
+func printMigrationStatus(out io.Writer, database db.DB) error {
+	statuses, err := migration.Status(database)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tSTATE\tAPPLIED BY\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedBy, appliedAt := "pending", "", ""
+		switch {
+		case status.Applied:
+			state = "applied"
+		case status.InProgress:
+			state = "in progress"
+		}
+		if status.Record != nil {
+			appliedBy, appliedAt = status.Record.JunoVersion, status.Record.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedBy, appliedAt)
+	}
+	return w.Flush()
+}
+
+func printMigrationEstimates(out io.Writer, estimates []migration.MigrationEstimate) error {
+	if len(estimates) == 0 {
+		_, err := fmt.Fprintln(out, "The database is up to date.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tBUCKETS")
+	for _, estimate := range estimates {
+		buckets := "unknown"
+		if len(estimate.Buckets) > 0 {
+			counts := make([]string, 0, len(estimate.Buckets))
+			for _, bucket := range estimate.Buckets {
+				counts = append(counts, fmt.Sprintf("%s (%d keys)", bucket, estimate.Keys[bucket]))
+			}
+			buckets = strings.Join(counts, ", ")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", estimate.Version, estimate.Name, buckets)
+	}
+	return w.Flush()
+}
@@ -8,9 +8,11 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/NethermindEth/juno/db/backends"
 	"github.com/NethermindEth/juno/node"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/mitchellh/mapstructure"
@@ -51,6 +53,11 @@ const (
 	p2pF                 = "p2p"
 	p2pAddrF             = "p2p-addr"
 	p2pBootPeersF        = "p2p-boot-peers"
+	p2pPrivateKeyF       = "p2p-private-key"
+	p2pKeyFileF          = "p2p-private-key-file"
+	p2pForwardTxsF       = "p2p-forward-transactions"
+	p2pMDNSF             = "p2p-mdns"
+	p2pAdminRPCF         = "p2p-admin-rpc"
 	metricsF             = "metrics"
 	metricsHostF         = "metrics-host"
 	metricsPortF         = "metrics-port"
@@ -61,7 +68,23 @@ const (
 	maxVMQueueF          = "max-vm-queue"
 	remoteDBF            = "remote-db"
 	rpcMaxBlockScanF     = "rpc-max-block-scan"
+	dbBackendF           = "db-backend"
 	dbCacheSizeF         = "db-cache-size"
+	dbCompressionF       = "db-compression"
+	archiveTrieF         = "archive-trie"
+	feederArchiveF       = "feeder-archive"
+	feederRecordF        = "feeder-record"
+	feederGatewayF       = "feeder-gateway"
+	feederGatewayHostF   = "feeder-gateway-host"
+	feederGatewayPortF   = "feeder-gateway-port"
+	syncReexecutionF     = "sync-reexecution"
+	seqEnableF           = "seq-enable"
+	seqBlockTimeF        = "seq-block-time"
+	seqGenesisFileF      = "seq-genesis-file"
+	seqForkDBF           = "seq-fork-db"
+	seqForkRemoteDBF     = "seq-fork-remote-db"
+	seqForkHeightF       = "seq-fork-height"
+	seqAddressF          = "seq-address"
 
 	defaultConfig              = ""
 	defaulHost                 = "localhost"
@@ -77,13 +100,33 @@ const (
 	defaultP2p                 = false
 	defaultP2pAddr             = ""
 	defaultP2pBootPeers        = ""
+	defaultP2pPrivateKey       = ""
+	defaultP2pKeyFile          = ""
+	defaultP2pForwardTxs       = false
+	defaultP2pMDNS             = false
+	defaultP2pAdminRPC         = false
 	defaultMetrics             = false
 	defaultMetricsPort         = 9090
 	defaultGRPC                = false
 	defaultGRPCPort            = 6064
 	defaultRemoteDB            = ""
 	defaultRPCMaxBlockScan     = math.MaxUint
+	defaultDBBackend           = backends.Default
 	defaultCacheSizeMb         = 8
+	defaultDBCompression       = ""
+	defaultArchiveTrie         = false
+	defaultFeederArchive       = ""
+	defaultFeederRecord        = ""
+	defaultFeederGateway       = false
+	defaultFeederGatewayPort   = 6065
+	defaultSyncReexecution     = ""
+	defaultSeqEnable           = false
+	defaultSeqBlockTime        = time.Duration(0)
+	defaultSeqGenesisFile      = ""
+	defaultSeqForkDB           = ""
+	defaultSeqForkRemoteDB     = ""
+	defaultSeqForkHeight       = uint64(0)
+	defaultSeqAddress          = ""
 
 	configFlagUsage   = "The yaml configuration file."
 	logLevelFlagUsage = "Options: debug, info, warn, error."
@@ -105,6 +148,11 @@ const (
 	p2pUsage                 = "enable p2p server"
 	p2PAddrUsage             = "specify p2p source address as multiaddr"
 	p2pBootPeersUsage        = "specify list of p2p boot peers splitted by a comma"
+	p2pPrivateKeyUsage       = "Hex-encoded private key of the p2p host. Generated and stored in the key file on the first start by default."
+	p2pKeyFileUsage          = "File the private key of the p2p host is kept in, readable only by its owner. Defaults to p2p.key in the database directory."
+	p2pForwardTxsUsage       = "Forwards the transactions gossiped by peers to the gateway, or to the sequencer in sequencer mode."
+	p2pMDNSUsage             = "Discovers and connects to the nodes of the same network on the local network with mDNS."
+	p2pAdminRPCUsage         = "Serves juno_addPeer and juno_removePeer, which connect and disconnect peers. Only enable it when the RPC endpoints are not public."
 	metricsUsage             = "Enables the prometheus metrics endpoint on the default port."
 	metricsHostUsage         = "The interface on which the prometheus endpoint will listen for requests."
 	metricsPortUsage         = "The port on which the prometheus endpoint will listen for requests."
@@ -115,7 +163,30 @@ const (
 	maxVMQueueUsage          = "Maximum number for requests to queue after reaching max-vms before starting to reject incoming requets"
 	remoteDBUsage            = "gRPC URL of a remote Juno node"
 	rpcMaxBlockScanUsage     = "Maximum number of blocks scanned in single starknet_getEvents call"
+	dbBackendUsage           = "Storage engine of the database, which must be the one it was created with. Options: "
 	dbCacheSizeUsage         = "Determines the amount of memory (in megabytes) allocated for caching data in the database."
+	archiveTrieUsage         = "Keeps the state trie nodes overwritten by every block, so that state proofs can be computed for past blocks."
+	feederArchiveUsage       = "Directory of recorded feeder gateway responses to sync from instead of the feeder gateway."
+	feederRecordUsage        = "Directory in which every response received from the feeder gateway is recorded."
+	feederGatewayUsage       = "Enables the feeder gateway compatible HTTP server, serving data from the local database, on the default port."
+	feederGatewayHostUsage   = "The interface on which the feeder gateway compatible HTTP server will listen for requests."
+	feederGatewayPortUsage   = "The port on which the feeder gateway compatible HTTP server will listen for requests."
+	syncReexecutionUsage     = "Re-executes every synced block and compares the fees, revert statuses, events and state diff " +
+		"to the synced ones. Options: warn (log mismatches), halt (stop syncing on a mismatch). Disabled by default."
+	dbCompressionUsage = "Comma separated buckets and the algorithm their values are compressed with, such as " +
+		"Class=zstd,ReceiptsByBlockNumberAndIndex=snappy. Options: Class, TransactionsByBlockNumberAndIndex, " +
+		"ReceiptsByBlockNumberAndIndex, StateUpdatesByBlockNumber; zstd, snappy, none. " +
+		"Values stored before are compressed by juno db recompress."
+	seqEnableUsage = "Runs a local devnet: transactions sent to the RPC server are executed and built into blocks instead " +
+		"of syncing with the network."
+	seqBlockTimeUsage   = "How often the sequencer builds a block out of pending transactions (only on demand with juno_createBlock by default)"
+	seqGenesisFileUsage = "JSON file with the classes and predeployed contracts of the sequencer's genesis block."
+	seqForkDBUsage      = "Location of a synced database the sequencer forks from. The database is only read: " +
+		"the blocks built by the sequencer are stored in the database at --db-path."
+	seqForkRemoteDBUsage = "gRPC URL of a remote Juno node the sequencer forks from, instead of a local database."
+	seqForkHeightUsage   = "Block of the forked chain the sequencer builds on top of (the head of the forked chain by default)."
+	seqAddressUsage      = "Address the sequencer collects the fees of the blocks it builds at " +
+		"(0x6a756e6f2d73657175656e636572, the felt of \"juno-sequencer\", by default)."
 )
 
 var Version string
@@ -192,8 +263,16 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 
 		// TextUnmarshallerHookFunc allows us to unmarshal values that satisfy the
 		// encoding.TextUnmarshaller interface (see the LogLevel type for an example).
-		return v.Unmarshal(config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
-			mapstructure.TextUnmarshallerHookFunc(), mapstructure.StringToTimeDurationHookFunc())))
+		if err := v.Unmarshal(config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+			mapstructure.TextUnmarshallerHookFunc(), mapstructure.StringToTimeDurationHookFunc()))); err != nil {
+			return err
+		}
+
+		// The sequencer forks from the head of the forked chain unless a height, which may be 0, is given
+		if !v.IsSet(seqForkHeightF) {
+			config.SeqForkHeight = nil
+		}
+		return nil
 	}
 
 	var defaultDBPath string
@@ -230,6 +309,11 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().Bool(p2pF, defaultP2p, p2pUsage)
 	junoCmd.Flags().String(p2pAddrF, defaultP2pAddr, p2PAddrUsage)
 	junoCmd.Flags().String(p2pBootPeersF, defaultP2pBootPeers, p2pBootPeersUsage)
+	junoCmd.Flags().String(p2pPrivateKeyF, defaultP2pPrivateKey, p2pPrivateKeyUsage)
+	junoCmd.Flags().String(p2pKeyFileF, defaultP2pKeyFile, p2pKeyFileUsage)
+	junoCmd.Flags().Bool(p2pForwardTxsF, defaultP2pForwardTxs, p2pForwardTxsUsage)
+	junoCmd.Flags().Bool(p2pMDNSF, defaultP2pMDNS, p2pMDNSUsage)
+	junoCmd.Flags().Bool(p2pAdminRPCF, defaultP2pAdminRPC, p2pAdminRPCUsage)
 	junoCmd.Flags().Bool(metricsF, defaultMetrics, metricsUsage)
 	junoCmd.Flags().String(metricsHostF, defaulHost, metricsHostUsage)
 	junoCmd.Flags().Uint16(metricsPortF, defaultMetricsPort, metricsPortUsage)
@@ -240,7 +324,25 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().Uint(maxVMQueueF, 2*uint(defaultMaxVMs), maxVMQueueUsage)
 	junoCmd.Flags().String(remoteDBF, defaultRemoteDB, remoteDBUsage)
 	junoCmd.Flags().Uint(rpcMaxBlockScanF, defaultRPCMaxBlockScan, rpcMaxBlockScanUsage)
+	junoCmd.Flags().String(dbBackendF, defaultDBBackend, dbBackendUsage+strings.Join(backends.Names(), ", ")+".")
 	junoCmd.Flags().Uint(dbCacheSizeF, defaultCacheSizeMb, dbCacheSizeUsage)
+	junoCmd.Flags().String(dbCompressionF, defaultDBCompression, dbCompressionUsage)
+	junoCmd.Flags().Bool(archiveTrieF, defaultArchiveTrie, archiveTrieUsage)
+	junoCmd.Flags().String(feederArchiveF, defaultFeederArchive, feederArchiveUsage)
+	junoCmd.Flags().String(feederRecordF, defaultFeederRecord, feederRecordUsage)
+	junoCmd.Flags().Bool(feederGatewayF, defaultFeederGateway, feederGatewayUsage)
+	junoCmd.Flags().String(feederGatewayHostF, defaulHost, feederGatewayHostUsage)
+	junoCmd.Flags().Uint16(feederGatewayPortF, defaultFeederGatewayPort, feederGatewayPortUsage)
+	junoCmd.Flags().String(syncReexecutionF, defaultSyncReexecution, syncReexecutionUsage)
+	junoCmd.Flags().Bool(seqEnableF, defaultSeqEnable, seqEnableUsage)
+	junoCmd.Flags().Duration(seqBlockTimeF, defaultSeqBlockTime, seqBlockTimeUsage)
+	junoCmd.Flags().String(seqGenesisFileF, defaultSeqGenesisFile, seqGenesisFileUsage)
+	junoCmd.Flags().String(seqForkDBF, defaultSeqForkDB, seqForkDBUsage)
+	junoCmd.Flags().String(seqForkRemoteDBF, defaultSeqForkRemoteDB, seqForkRemoteDBUsage)
+	junoCmd.Flags().Uint64(seqForkHeightF, defaultSeqForkHeight, seqForkHeightUsage)
+	junoCmd.Flags().String(seqAddressF, defaultSeqAddress, seqAddressUsage)
+
+	junoCmd.AddCommand(DBCmd(defaultDBPath))
 
 	return junoCmd
 }
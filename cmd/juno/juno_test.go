@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"bytes"
 	"context"
 	"math"
 	"os"
@@ -45,7 +46,9 @@ func TestConfigPrecedence(t *testing.T) {
 	defaultPendingPollInterval := time.Duration(0)
 	defaultMaxVMs := uint(3 * runtime.GOMAXPROCS(0))
 	defaultRPCMaxBlockScan := uint(math.MaxUint)
+	defaultDBBackend := "pebble"
 	defaultMaxCacheSize := uint(8)
+	defaultFeederGatewayPort := uint16(6065)
 
 	tests := map[string]struct {
 		cfgFile         bool
@@ -81,6 +84,9 @@ func TestConfigPrecedence(t *testing.T) {
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"config file path is empty string": {
@@ -110,6 +116,9 @@ func TestConfigPrecedence(t *testing.T) {
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"config file doesn't exist": {
@@ -144,6 +153,9 @@ func TestConfigPrecedence(t *testing.T) {
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"config file with all settings but without any other flags": {
@@ -180,6 +192,9 @@ pprof: true
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"config file with some settings but without any other flags": {
@@ -213,6 +228,9 @@ http-port: 4576
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"all flags without config file": {
@@ -221,29 +239,32 @@ http-port: 4576
 				"--db-path", "/home/.juno", "--network", "goerli", "--pprof", "--db-cache-size", "8",
 			},
 			expectedConfig: &node.Config{
-				LogLevel:        utils.DEBUG,
-				HTTP:            defaultHTTP,
-				HTTPHost:        "0.0.0.0",
-				HTTPPort:        4576,
-				Websocket:       defaultWS,
-				WebsocketHost:   defaultHost,
-				WebsocketPort:   defaultWSPort,
-				GRPC:            defaultGRPC,
-				GRPCHost:        defaultHost,
-				GRPCPort:        defaultGRPCPort,
-				Metrics:         defaultMetrics,
-				MetricsHost:     defaultHost,
-				MetricsPort:     defaultMetricsPort,
-				DatabasePath:    "/home/.juno",
-				Network:         utils.Goerli,
-				Pprof:           true,
-				PprofHost:       defaultHost,
-				PprofPort:       defaultPprofPort,
-				Colour:          defaultColour,
-				MaxVMs:          defaultMaxVMs,
-				MaxVMQueue:      2 * defaultMaxVMs,
-				RPCMaxBlockScan: defaultRPCMaxBlockScan,
-				DBCacheSize:     defaultMaxCacheSize,
+				LogLevel:          utils.DEBUG,
+				HTTP:              defaultHTTP,
+				HTTPHost:          "0.0.0.0",
+				HTTPPort:          4576,
+				Websocket:         defaultWS,
+				WebsocketHost:     defaultHost,
+				WebsocketPort:     defaultWSPort,
+				GRPC:              defaultGRPC,
+				GRPCHost:          defaultHost,
+				GRPCPort:          defaultGRPCPort,
+				Metrics:           defaultMetrics,
+				MetricsHost:       defaultHost,
+				MetricsPort:       defaultMetricsPort,
+				DatabasePath:      "/home/.juno",
+				Network:           utils.Goerli,
+				Pprof:             true,
+				PprofHost:         defaultHost,
+				PprofPort:         defaultPprofPort,
+				Colour:            defaultColour,
+				MaxVMs:            defaultMaxVMs,
+				MaxVMQueue:        2 * defaultMaxVMs,
+				RPCMaxBlockScan:   defaultRPCMaxBlockScan,
+				DBCacheSize:       defaultMaxCacheSize,
+				DBBackend:         defaultDBBackend,
+				FeederGatewayHost: defaultHost,
+				FeederGatewayPort: defaultFeederGatewayPort,
 			},
 		},
 		"some flags without config file": {
@@ -276,6 +297,9 @@ http-port: 4576
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"all setting set in both config file and flags": {
@@ -332,6 +356,9 @@ db-cache-size: 8
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         9,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"some setting set in both config file and flags": {
@@ -367,6 +394,9 @@ network: goerli
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 		"some setting set in default, config file and flags": {
@@ -398,6 +428,9 @@ network: goerli
 				MaxVMQueue:          2 * defaultMaxVMs,
 				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
 				DBCacheSize:         defaultMaxCacheSize,
+				DBBackend:           defaultDBBackend,
+				FeederGatewayHost:   defaultHost,
+				FeederGatewayPort:   defaultFeederGatewayPort,
 			},
 		},
 	}
@@ -425,6 +458,106 @@ network: goerli
 	}
 }
 
+func TestSeqForkHeight(t *testing.T) {
+	tests := map[string]struct {
+		args   []string
+		height *uint64
+	}{
+		"unset":       {args: []string{}},
+		"genesis":     {args: []string{"--seq-fork-height", "0"}, height: utils.Ptr(uint64(0))},
+		"later block": {args: []string{"--seq-fork-height", "7"}, height: utils.Ptr(uint64(7))},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := new(node.Config)
+			cmd := juno.NewCmd(config, func(*cobra.Command, []string) error { return nil })
+			cmd.SetArgs(tc.args)
+			require.NoError(t, cmd.ExecuteContext(context.Background()))
+			assert.Equal(t, tc.height, config.SeqForkHeight)
+		})
+	}
+
+	t.Run("config file", func(t *testing.T) {
+		config := new(node.Config)
+		cmd := juno.NewCmd(config, func(*cobra.Command, []string) error { return nil })
+		cmd.SetArgs([]string{"--config", tempCfgFile(t, "seq-fork-height: 0\n")})
+		require.NoError(t, cmd.ExecuteContext(context.Background()))
+		assert.Equal(t, utils.Ptr(uint64(0)), config.SeqForkHeight)
+	})
+}
+
+func TestDBMigrate(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "juno")
+	execute := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		cmd := juno.NewCmd(new(node.Config), func(*cobra.Command, []string) error { return nil })
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"db", "migrate", "--db-path", dbPath}, args...))
+		err := cmd.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	_, err := execute(t, "--status")
+	require.ErrorContains(t, err, "no database")
+
+	_, err = execute(t)
+	require.NoError(t, err)
+
+	out, err := execute(t, "--status")
+	require.NoError(t, err)
+	assert.Contains(t, out, "migration0000")
+	assert.Contains(t, out, "applied")
+	assert.NotContains(t, out, "pending")
+
+	out, err = execute(t, "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "up to date")
+
+	_, err = execute(t, "--status", "--dry-run")
+	require.Error(t, err)
+
+	t.Run("status and dry run do not write to the database", func(t *testing.T) {
+		files := func() map[string]time.Time {
+			entries, err := os.ReadDir(dbPath)
+			require.NoError(t, err)
+			modTimes := make(map[string]time.Time, len(entries))
+			for _, entry := range entries {
+				// even read-only, pebble locks the database
+				if entry.Name() == "LOCK" {
+					continue
+				}
+				info, err := entry.Info()
+				require.NoError(t, err)
+				modTimes[entry.Name()] = info.ModTime()
+			}
+			return modTimes
+		}
+
+		before := files()
+		_, err := execute(t, "--status")
+		require.NoError(t, err)
+		_, err = execute(t, "--dry-run")
+		require.NoError(t, err)
+		assert.Equal(t, before, files())
+	})
+}
+
+func TestDBRecompress(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "juno")
+	execute := func(t *testing.T, args ...string) error {
+		t.Helper()
+		cmd := juno.NewCmd(new(node.Config), func(*cobra.Command, []string) error { return nil })
+		cmd.SetArgs(append([]string{"db", args[0], "--db-path", dbPath}, args[1:]...))
+		return cmd.ExecuteContext(context.Background())
+	}
+
+	require.ErrorContains(t, execute(t, "recompress", "--db-compression", "Class=zstd"), "no database")
+	require.NoError(t, execute(t, "migrate"))
+	require.NoError(t, execute(t, "recompress", "--db-compression", "Class=zstd,StateUpdatesByBlockNumber=snappy"))
+	require.Error(t, execute(t, "recompress", "--db-compression", "StateTrie=zstd"))
+}
+
 func tempCfgFile(t *testing.T, cfg string) string {
 	t.Helper()
 
@@ -39,8 +39,25 @@ type Header struct {
 	Signatures [][]*felt.Felt
 	// Amount of STRK charged per Gas spent
 	GasPriceSTRK *felt.Felt
+	// Price of the L1 data gas spent publishing state diffs in blobs
+	L1DataGasPrice *GasPrice
+	// How the state diff of this block is published on L1
+	L1DAMode L1DAMode
 }
 
+type GasPrice struct {
+	PriceInWei *felt.Felt
+	PriceInFri *felt.Felt
+}
+
+// L1DAMode is how the state diff of a block is published on L1
+type L1DAMode uint8
+
+const (
+	Calldata L1DAMode = iota
+	Blob
+)
+
 type Block struct {
 	*Header
 	Transactions []Transaction
@@ -102,11 +119,14 @@ func NetworkBlockHashMetaInfo(network utils.Network) *blockHashMetaInfo {
 type BlockCommitments struct {
 	TransactionCommitment *felt.Felt
 	EventCommitment       *felt.Felt
+	// The following are only committed to by blocks following Starknet 0.13.2
+	ReceiptCommitment   *felt.Felt
+	StateDiffCommitment *felt.Felt
 }
 
 // VerifyBlockHash verifies the block hash. Due to bugs in Starknet alpha, not all blocks have
-// verifiable hashes.
-func VerifyBlockHash(b *Block, network utils.Network) (*BlockCommitments, error) {
+// verifiable hashes. The state diff of the block is only committed to by blocks following Starknet 0.13.2.
+func VerifyBlockHash(b *Block, network utils.Network, stateDiff *StateDiff) (*BlockCommitments, error) {
 	if len(b.Transactions) != len(b.Receipts) {
 		return nil, fmt.Errorf("len of transactions: %v do not match len of receipts: %v",
 			len(b.Transactions), len(b.Receipts))
@@ -132,7 +152,7 @@ func VerifyBlockHash(b *Block, network utils.Network) (*BlockCommitments, error)
 			overrideSeq = fallbackSeq
 		}
 
-		hash, commitments, err := blockHash(b, network, overrideSeq)
+		hash, commitments, err := blockHash(b, network, stateDiff, overrideSeq)
 		if err != nil {
 			return nil, err
 		}
@@ -150,14 +170,68 @@ func VerifyBlockHash(b *Block, network utils.Network) (*BlockCommitments, error)
 	return nil, errors.New("can not verify hash in block header")
 }
 
+// VerifyHeaderHash verifies the hash of a header against the commitments of its block, for headers received
+// without the transactions and events of their blocks. Only the hashes of blocks preceding Starknet 0.13.2
+// can be verified this way, as the hashes of later blocks commit to the protocol version, gas prices,
+// receipts and state diff of the block.
+func VerifyHeaderHash(h *Header, commitments *BlockCommitments, network utils.Network) error {
+	if h.Hash == nil || h.ParentHash == nil || h.GlobalStateRoot == nil || commitments == nil ||
+		commitments.TransactionCommitment == nil {
+		return errors.New("incomplete block header")
+	}
+
+	metaInfo := NetworkBlockHashMetaInfo(network)
+	if h.Number < metaInfo.First07Block {
+		if pre07HeaderHash(h, commitments, network.ChainID()).Equal(h.Hash) {
+			return nil
+		}
+	} else {
+		if commitments.EventCommitment == nil {
+			return errors.New("incomplete block header")
+		}
+		for _, fallbackSeq := range []*felt.Felt{&felt.Zero, metaInfo.FallBackSequencerAddress} {
+			seqAddr := h.SequencerAddress
+			if seqAddr == nil {
+				seqAddr = fallbackSeq
+			}
+			if post07HeaderHash(h, commitments, seqAddr).Equal(h.Hash) {
+				return nil
+			}
+		}
+	}
+
+	if unverifiableRange := metaInfo.UnverifiableRange; unverifiableRange != nil &&
+		h.Number >= unverifiableRange[0] && h.Number <= unverifiableRange[1] {
+		return nil
+	}
+	return errors.New("can not verify hash in block header")
+}
+
+// BlockHash computes the hash and commitments of a block built locally, using the
+// hashing scheme the network uses at the block's height.
+func BlockHash(b *Block, network utils.Network, stateDiff *StateDiff) (*felt.Felt, *BlockCommitments, error) {
+	return blockHash(b, network, stateDiff, nil)
+}
+
 // blockHash computes the block hash, with option to override sequence address
-func blockHash(b *Block, network utils.Network, overrideSeqAddr *felt.Felt) (*felt.Felt, *BlockCommitments, error) {
+func blockHash(b *Block, network utils.Network, stateDiff *StateDiff, overrideSeqAddr *felt.Felt) (*felt.Felt,
+	*BlockCommitments, error,
+) {
 	metaInfo := NetworkBlockHashMetaInfo(network)
 
 	if b.Number < metaInfo.First07Block {
 		return pre07Hash(b, network.ChainID())
 	}
-	return post07Hash(b, overrideSeqAddr)
+
+	blockVersion, err := ParseBlockVersion(b.ProtocolVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	// blockVersion < 0.13.2
+	if blockVersion.LessThan(v0_13_2) {
+		return post07Hash(b, overrideSeqAddr)
+	}
+	return post0132Hash(b, stateDiff, overrideSeqAddr)
 }
 
 // pre07Hash computes the block hash for blocks generated before Cairo 0.7.0
@@ -167,20 +241,26 @@ func pre07Hash(b *Block, chain *felt.Felt) (*felt.Felt, *BlockCommitments, error
 		return nil, nil, err
 	}
 
+	commitments := &BlockCommitments{TransactionCommitment: txCommitment}
+	return pre07HeaderHash(b.Header, commitments, chain), commitments, nil
+}
+
+// pre07HeaderHash hashes a header generated before Cairo 0.7.0 with the commitments of its block
+func pre07HeaderHash(h *Header, commitments *BlockCommitments, chain *felt.Felt) *felt.Felt {
 	return crypto.PedersenArray(
-		new(felt.Felt).SetUint64(b.Number), // block number
-		b.GlobalStateRoot,                  // global state root
+		new(felt.Felt).SetUint64(h.Number), // block number
+		h.GlobalStateRoot,                  // global state root
 		&felt.Zero,                         // reserved: sequencer address
 		&felt.Zero,                         // reserved: block timestamp
-		new(felt.Felt).SetUint64(b.TransactionCount), // number of transactions
-		txCommitment, // transaction commitment
-		&felt.Zero,   // reserved: number of events
-		&felt.Zero,   // reserved: event commitment
-		&felt.Zero,   // reserved: protocol version
-		&felt.Zero,   // reserved: extra data
-		chain,        // extra data: chain id
-		b.ParentHash, // parent hash
-	), &BlockCommitments{TransactionCommitment: txCommitment}, nil
+		new(felt.Felt).SetUint64(h.TransactionCount), // number of transactions
+		commitments.TransactionCommitment,            // transaction commitment
+		&felt.Zero,                                   // reserved: number of events
+		&felt.Zero,                                   // reserved: event commitment
+		&felt.Zero,                                   // reserved: protocol version
+		&felt.Zero,                                   // reserved: extra data
+		chain,                                        // extra data: chain id
+		h.ParentHash,                                 // parent hash
+	)
 }
 
 // post07Hash computes the block hash for blocks generated after Cairo 0.7.0
@@ -209,6 +289,12 @@ func post07Hash(b *Block, overrideSeqAddr *felt.Felt) (*felt.Felt, *BlockCommitm
 		return nil, nil, eErr
 	}
 
+	commitments := &BlockCommitments{TransactionCommitment: txCommitment, EventCommitment: eCommitment}
+	return post07HeaderHash(b.Header, commitments, seqAddr), commitments, nil
+}
+
+// post07HeaderHash hashes a header generated after Cairo 0.7.0 with the commitments of its block
+func post07HeaderHash(h *Header, commitments *BlockCommitments, seqAddr *felt.Felt) *felt.Felt {
 	// Unlike the pre07Hash computation, we exclude the chain
 	// id and replace the zero felt with the actual values for:
 	// - sequencer address
@@ -216,18 +302,111 @@ func post07Hash(b *Block, overrideSeqAddr *felt.Felt) (*felt.Felt, *BlockCommitm
 	// - number of events
 	// - event commitment
 	return crypto.PedersenArray(
-		new(felt.Felt).SetUint64(b.Number),           // block number
-		b.GlobalStateRoot,                            // global state root
+		new(felt.Felt).SetUint64(h.Number),           // block number
+		h.GlobalStateRoot,                            // global state root
 		seqAddr,                                      // sequencer address
-		new(felt.Felt).SetUint64(b.Timestamp),        // block timestamp
-		new(felt.Felt).SetUint64(b.TransactionCount), // number of transactions
-		txCommitment,                                 // transaction commitment
-		new(felt.Felt).SetUint64(b.EventCount),       // number of events
-		eCommitment,                                  // event commitment
+		new(felt.Felt).SetUint64(h.Timestamp),        // block timestamp
+		new(felt.Felt).SetUint64(h.TransactionCount), // number of transactions
+		commitments.TransactionCommitment,            // transaction commitment
+		new(felt.Felt).SetUint64(h.EventCount),       // number of events
+		commitments.EventCommitment,                  // event commitment
 		&felt.Zero,                                   // reserved: protocol version
 		&felt.Zero,                                   // reserved: extra data
-		b.ParentHash,                                 // parent block hash
-	), &BlockCommitments{TransactionCommitment: txCommitment, EventCommitment: eCommitment}, nil
+		h.ParentHash,                                 // parent block hash
+	)
+}
+
+// Post0132Commitments computes the transaction, event, receipt and state diff commitments of a block
+// following Starknet 0.13.2
+func Post0132Commitments(b *Block, stateDiff *StateDiff) (*BlockCommitments, error) {
+	if stateDiff == nil {
+		return nil, errors.New("state diff is required to compute the commitments of the block")
+	}
+
+	wg := conc.NewWaitGroup()
+	var txCommitment, eCommitment, rCommitment, sdCommitment *felt.Felt
+	var tErr, eErr, rErr error
+
+	wg.Go(func() {
+		txCommitment, tErr = transactionCommitmentPoseidon(b.Transactions)
+	})
+	wg.Go(func() {
+		eCommitment, eErr = eventCommitmentPoseidon(b.Receipts)
+	})
+	wg.Go(func() {
+		rCommitment, rErr = receiptCommitment(b.Receipts)
+	})
+	wg.Go(func() {
+		sdCommitment = stateDiff.Hash()
+	})
+	wg.Wait()
+
+	if err := errors.Join(tErr, eErr, rErr); err != nil {
+		return nil, err
+	}
+	return &BlockCommitments{
+		TransactionCommitment: txCommitment,
+		EventCommitment:       eCommitment,
+		ReceiptCommitment:     rCommitment,
+		StateDiffCommitment:   sdCommitment,
+	}, nil
+}
+
+// post0132Hash computes the block hash for blocks generated after Starknet 0.13.2
+func post0132Hash(b *Block, stateDiff *StateDiff, overrideSeqAddr *felt.Felt) (*felt.Felt, *BlockCommitments, error) {
+	seqAddr := b.SequencerAddress
+	if overrideSeqAddr != nil {
+		seqAddr = overrideSeqAddr
+	}
+
+	commitments, err := Post0132Commitments(b, stateDiff)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l1DataGasPrice := b.L1DataGasPrice
+	if b.GasPrice == nil || b.GasPriceSTRK == nil || l1DataGasPrice == nil || l1DataGasPrice.PriceInWei == nil ||
+		l1DataGasPrice.PriceInFri == nil {
+		return nil, nil, errors.New("gas prices are required to compute the hash of the block")
+	}
+
+	return crypto.PoseidonArray(
+		new(felt.Felt).SetBytes([]byte("STARKNET_BLOCK_HASH0")),
+		new(felt.Felt).SetUint64(b.Number),    // block number
+		b.GlobalStateRoot,                     // global state root
+		seqAddr,                               // sequencer address
+		new(felt.Felt).SetUint64(b.Timestamp), // block timestamp
+		concatCounts(b.TransactionCount, b.EventCount, stateDiff.Length(), b.L1DAMode),
+		commitments.StateDiffCommitment,                    // state diff commitment
+		commitments.TransactionCommitment,                  // transaction commitment
+		commitments.EventCommitment,                        // event commitment
+		commitments.ReceiptCommitment,                      // receipt commitment
+		b.GasPrice,                                         // L1 gas price in wei
+		b.GasPriceSTRK,                                     // L1 gas price in fri
+		l1DataGasPrice.PriceInWei,                          // L1 data gas price in wei
+		l1DataGasPrice.PriceInFri,                          // L1 data gas price in fri
+		new(felt.Felt).SetBytes([]byte(b.ProtocolVersion)), // protocol version
+		&felt.Zero,   // reserved: extra data
+		b.ParentHash, // parent block hash
+	), commitments, nil
+}
+
+// concatCounts packs the transaction, event and state diff counts of a block with its data availability
+// mode into a single felt: three 64-bit big endian counts followed by a byte whose most significant bit
+// is set for blobs, padded with zeros to 64 bits.
+func concatCounts(txCount, eventCount, stateDiffLength uint64, l1DAMode L1DAMode) *felt.Felt {
+	var l1DAByte byte
+	if l1DAMode == Blob {
+		l1DAByte = 0b1000_0000
+	}
+
+	const countsSize = 32
+	counts := make([]byte, 0, countsSize)
+	counts = binary.BigEndian.AppendUint64(counts, txCount)
+	counts = binary.BigEndian.AppendUint64(counts, eventCount)
+	counts = binary.BigEndian.AppendUint64(counts, stateDiffLength)
+	counts = append(counts, l1DAByte, 0, 0, 0, 0, 0, 0, 0)
+	return new(felt.Felt).SetBytes(counts)
 }
 
 func MarshalBlockNumber(blockNumber uint64) []byte {
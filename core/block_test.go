@@ -175,7 +175,7 @@ func TestBlockHash(t *testing.T) {
 			block, err := gw.BlockByNumber(context.Background(), tc.number)
 			require.NoError(t, err)
 
-			commitments, err := core.VerifyBlockHash(block, tc.chain)
+			commitments, err := core.VerifyBlockHash(block, tc.chain, nil)
 			assert.NoError(t, err)
 			assert.NotNil(t, commitments)
 		})
@@ -193,7 +193,7 @@ func TestBlockHash(t *testing.T) {
 		mainnetBlock1.Hash = h1
 
 		expectedErr := "can not verify hash in block header"
-		commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet)
+		commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet, nil)
 		assert.EqualError(t, err, expectedErr)
 		assert.Nil(t, commitments)
 	})
@@ -204,7 +204,7 @@ func TestBlockHash(t *testing.T) {
 		block119802, err := goerliGW.BlockByNumber(context.Background(), 119802)
 		require.NoError(t, err)
 
-		commitments, err := core.VerifyBlockHash(block119802, utils.Goerli)
+		commitments, err := core.VerifyBlockHash(block119802, utils.Goerli, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, commitments)
 	})
@@ -218,7 +218,7 @@ func TestBlockHash(t *testing.T) {
 		expectedErr := fmt.Sprintf("len of transactions: %v do not match len of receipts: %v",
 			len(mainnetBlock1.Transactions), len(mainnetBlock1.Receipts))
 
-		commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet)
+		commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet, nil)
 		assert.EqualError(t, err, expectedErr)
 		assert.Nil(t, commitments)
 	})
@@ -233,8 +233,134 @@ func TestBlockHash(t *testing.T) {
 				"transaction hash (%v) at index: %v does not match receipt's hash (%v)",
 				mainnetBlock1.Transactions[1].Hash().String(), 1,
 				mainnetBlock1.Receipts[1].TransactionHash)
-			commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet)
+			commitments, err := core.VerifyBlockHash(mainnetBlock1, utils.Mainnet, nil)
 			assert.EqualError(t, err, expectedErr)
 			assert.Nil(t, commitments)
 		})
 }
+
+func TestPost0132BlockHash(t *testing.T) {
+	client := feeder.NewTestClient(t, utils.Integration)
+	gw := adaptfeeder.New(client)
+
+	stateUpdate, block, err := gw.StateUpdateWithBlock(context.Background(), 283364)
+	require.NoError(t, err)
+	require.NotEmpty(t, block.Receipts)
+
+	// The block predates Starknet 0.13.2, so its header is filled in as if it followed it
+	block.ProtocolVersion = "0.13.2"
+	block.GasPriceSTRK = new(felt.Felt).SetUint64(13)
+	block.L1DataGasPrice = &core.GasPrice{
+		PriceInWei: new(felt.Felt).SetUint64(7),
+		PriceInFri: new(felt.Felt).SetUint64(11),
+	}
+	block.L1DAMode = core.Blob
+	block.Receipts[0].ExecutionResources.TotalGasConsumed = &core.GasConsumed{L1Gas: 3, L1DataGas: 5}
+
+	hash, commitments, err := core.BlockHash(block, utils.Integration, stateUpdate.StateDiff)
+	require.NoError(t, err)
+	assert.Equal(t, stateUpdate.StateDiff.Hash(), commitments.StateDiffCommitment)
+	assert.NotNil(t, commitments.ReceiptCommitment)
+
+	block.Hash = hash
+	verifiedCommitments, err := core.VerifyBlockHash(block, utils.Integration, stateUpdate.StateDiff)
+	require.NoError(t, err)
+	assert.Equal(t, commitments, verifiedCommitments)
+
+	t.Run("state diff is required", func(t *testing.T) {
+		_, err := core.VerifyBlockHash(block, utils.Integration, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("gas prices are required", func(t *testing.T) {
+		price := block.L1DataGasPrice
+		block.L1DataGasPrice = nil
+		defer func() { block.L1DataGasPrice = price }()
+
+		_, err := core.VerifyBlockHash(block, utils.Integration, stateUpdate.StateDiff)
+		require.Error(t, err)
+	})
+
+	for name, tamper := range map[string]func(*core.Block, *core.StateDiff) func(){
+		"data availability mode": func(b *core.Block, _ *core.StateDiff) func() {
+			b.L1DAMode = core.Calldata
+			return func() { b.L1DAMode = core.Blob }
+		},
+		"l1 data gas price": func(b *core.Block, _ *core.StateDiff) func() {
+			price := b.L1DataGasPrice.PriceInFri
+			b.L1DataGasPrice.PriceInFri = new(felt.Felt).SetUint64(12)
+			return func() { b.L1DataGasPrice.PriceInFri = price }
+		},
+		"gas consumed": func(b *core.Block, _ *core.StateDiff) func() {
+			b.Receipts[0].ExecutionResources.TotalGasConsumed.L1DataGas++
+			return func() { b.Receipts[0].ExecutionResources.TotalGasConsumed.L1DataGas-- }
+		},
+		"state diff": func(_ *core.Block, d *core.StateDiff) func() {
+			addr := new(felt.Felt).SetUint64(1)
+			d.Nonces[*addr] = new(felt.Felt).SetUint64(1)
+			return func() { delete(d.Nonces, *addr) }
+		},
+	} {
+		t.Run(name+" is committed to", func(t *testing.T) {
+			restore := tamper(block, stateUpdate.StateDiff)
+			defer restore()
+
+			_, err := core.VerifyBlockHash(block, utils.Integration, stateUpdate.StateDiff)
+			assert.EqualError(t, err, "can not verify hash in block header")
+		})
+	}
+}
+
+func TestVerifyHeaderHashAndCommitments(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+
+	// pre 0.7.0, post 0.7.0 without and with sequencer address
+	for _, number := range []uint64{2, 833, 16789} {
+		block, err := gw.BlockByNumber(context.Background(), number)
+		require.NoError(t, err)
+		commitments, err := core.VerifyBlockHash(block, utils.Mainnet, nil)
+		require.NoError(t, err)
+
+		var events []*core.Event
+		for _, receipt := range block.Receipts {
+			events = append(events, receipt.Events...)
+		}
+
+		t.Run(fmt.Sprintf("block %d", number), func(t *testing.T) {
+			assert.NoError(t, core.VerifyHeaderHash(block.Header, commitments, utils.Mainnet))
+			assert.NoError(t, core.VerifyTransactionCommitment(block.Transactions, commitments.TransactionCommitment))
+			if commitments.EventCommitment != nil {
+				assert.NoError(t, core.VerifyEventCommitment(events, commitments.EventCommitment))
+			}
+
+			header := *block.Header
+			header.GlobalStateRoot = new(felt.Felt).SetUint64(1)
+			assert.EqualError(t, core.VerifyHeaderHash(&header, commitments, utils.Mainnet),
+				"can not verify hash in block header")
+
+			header = *block.Header
+			header.ParentHash = nil
+			assert.EqualError(t, core.VerifyHeaderHash(&header, commitments, utils.Mainnet), "incomplete block header")
+
+			reordered := append([]core.Transaction{}, block.Transactions...)
+			reordered[0], reordered[1] = reordered[1], reordered[0]
+			assert.ErrorContains(t, core.VerifyTransactionCommitment(reordered, commitments.TransactionCommitment),
+				"do not match transaction commitment")
+		})
+	}
+
+	t.Run("tampered event", func(t *testing.T) {
+		block, err := gw.BlockByNumber(context.Background(), 16789)
+		require.NoError(t, err)
+		commitments, err := core.VerifyBlockHash(block, utils.Mainnet, nil)
+		require.NoError(t, err)
+
+		var events []*core.Event
+		for _, receipt := range block.Receipts {
+			events = append(events, receipt.Events...)
+		}
+		require.NotEmpty(t, events)
+		events[0] = &core.Event{From: events[0].From, Keys: events[0].Keys}
+		assert.ErrorContains(t, core.VerifyEventCommitment(events, commitments.EventCommitment), "do not match event commitment")
+	})
+}
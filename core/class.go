@@ -2,10 +2,12 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
 )
 
 var (
@@ -84,6 +86,80 @@ func (c *Cairo1Class) Hash() (*felt.Felt, error) {
 	), nil
 }
 
+// CompiledClassHash computes the hash of the class's CASM, which the declare transaction commits to
+func (c *Cairo1Class) CompiledClassHash() (*felt.Felt, error) {
+	var compiled starknet.CompiledClass
+	if err := json.Unmarshal(c.Compiled, &compiled); err != nil {
+		return nil, fmt.Errorf("unmarshal compiled class: %v", err)
+	}
+
+	bytecodeHash := crypto.PoseidonArray(compiled.Bytecode...)
+	if compiled.BytecodeSegmentLengths != nil {
+		var err error
+		if bytecodeHash, err = segmentedBytecodeHash(compiled.Bytecode, compiled.BytecodeSegmentLengths); err != nil {
+			return nil, err
+		}
+	}
+
+	return crypto.PoseidonArray(
+		new(felt.Felt).SetBytes([]byte("COMPILED_CLASS_V1")),
+		crypto.PoseidonArray(flattenCompiledEntryPoints(compiled.EntryPoints.External)...),
+		crypto.PoseidonArray(flattenCompiledEntryPoints(compiled.EntryPoints.L1Handler)...),
+		crypto.PoseidonArray(flattenCompiledEntryPoints(compiled.EntryPoints.Constructor)...),
+		bytecodeHash,
+	), nil
+}
+
+func flattenCompiledEntryPoints(entryPoints []starknet.CompiledEntryPoint) []*felt.Felt {
+	result := make([]*felt.Felt, len(entryPoints)*3)
+	for i, entryPoint := range entryPoints {
+		builtins := make([]*felt.Felt, len(entryPoint.Builtins))
+		for j, builtin := range entryPoint.Builtins {
+			builtins[j] = new(felt.Felt).SetBytes([]byte(builtin))
+		}
+
+		result[3*i] = entryPoint.Selector
+		result[3*i+1] = entryPoint.Offset
+		result[3*i+2] = crypto.PoseidonArray(builtins...)
+	}
+	return result
+}
+
+// segmentedBytecodeHash hashes the bytecode as a tree of segments. A leaf segment is hashed as its
+// bytecode, while a node is hashed as the lengths and hashes of its segments, plus one.
+func segmentedBytecodeHash(bytecode []*felt.Felt, segmentLengths *starknet.SegmentLengths) (*felt.Felt, error) {
+	var offset uint64
+	var hashSegment func(segment *starknet.SegmentLengths) (uint64, *felt.Felt, error)
+	hashSegment = func(segment *starknet.SegmentLengths) (uint64, *felt.Felt, error) {
+		if segment.Children == nil {
+			if offset+segment.Length > uint64(len(bytecode)) {
+				return 0, nil, errors.New("bytecode segment lengths exceed the bytecode length")
+			}
+			hash := crypto.PoseidonArray(bytecode[offset : offset+segment.Length]...)
+			offset += segment.Length
+			return segment.Length, hash, nil
+		}
+
+		var (
+			length uint64
+			digest crypto.PoseidonDigest
+		)
+		for i := range segment.Children {
+			childLength, childHash, err := hashSegment(&segment.Children[i])
+			if err != nil {
+				return 0, nil, err
+			}
+			digest.Update(new(felt.Felt).SetUint64(childLength), childHash)
+			length += childLength
+		}
+		hash := digest.Finish()
+		return length, hash.Add(hash, new(felt.Felt).SetUint64(1)), nil
+	}
+
+	_, hash, err := hashSegment(segmentLengths)
+	return hash, err
+}
+
 func flattenSierraEntryPoints(entryPoints []SierraEntryPoint) []*felt.Felt {
 	result := make([]*felt.Felt, len(entryPoints)*2)
 	for i, entryPoint := range entryPoints {
@@ -114,3 +190,31 @@ func VerifyClassHashes(classes map[felt.Felt]Class) error {
 
 	return nil
 }
+
+// VerifyCompiledClassHashes checks that the CASM of each Cairo 1 class hashes to the compiled class hash
+// declared for it in the state diff
+func VerifyCompiledClassHashes(declaredV1Classes map[felt.Felt]*felt.Felt, classes map[felt.Felt]Class) error {
+	for hash, class := range classes {
+		cairo1Class, ok := class.(*Cairo1Class)
+		if !ok {
+			continue
+		}
+
+		declaredHash, ok := declaredV1Classes[hash]
+		if !ok {
+			continue
+		}
+
+		compiledHash, err := cairo1Class.CompiledClassHash()
+		if err != nil {
+			return fmt.Errorf("cannot verify compiled class hash of %v: %v", hash.String(), err)
+		}
+
+		if !compiledHash.Equal(declaredHash) {
+			return fmt.Errorf("cannot verify compiled class hash of %v: calculated hash %v, declared hash %v",
+				hash.String(), compiledHash.String(), declaredHash.String())
+		}
+	}
+
+	return nil
+}
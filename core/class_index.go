@@ -0,0 +1,98 @@
+package core
+
+import (
+	"bytes"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+)
+
+// The reverse class indexes are maintained by two buckets as follows:
+//
+// [db.ContractAddressesByClassHash](ClassHash, ContractAddress) -> ()
+// [db.ClassHashesBySelector](Selector, ClassHash) -> ()
+
+// IndexContractClass records that the contract at addr uses the class with the given hash
+func IndexContractClass(txn db.Transaction, classHash, addr *felt.Felt) error {
+	return txn.Set(db.ContractAddressesByClassHash.Key(classHash.Marshal(), addr.Marshal()), []byte{})
+}
+
+func unindexContractClass(txn db.Transaction, classHash, addr *felt.Felt) error {
+	return txn.Delete(db.ContractAddressesByClassHash.Key(classHash.Marshal(), addr.Marshal()))
+}
+
+// IndexClassSelectors records the entry point selectors defined by the class with the given hash
+func IndexClassSelectors(txn db.Transaction, classHash *felt.Felt, class Class) error {
+	for _, selector := range classSelectors(class) {
+		if err := txn.Set(db.ClassHashesBySelector.Key(selector.Marshal(), classHash.Marshal()), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unindexClassSelectors(txn db.Transaction, classHash *felt.Felt, class Class) error {
+	for _, selector := range classSelectors(class) {
+		if err := txn.Delete(db.ClassHashesBySelector.Key(selector.Marshal(), classHash.Marshal())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classSelectors returns the selectors of the external, L1 handler and constructor entry points of a class
+func classSelectors(class Class) []*felt.Felt {
+	var selectors []*felt.Felt
+	switch c := class.(type) {
+	case *Cairo0Class:
+		for _, entryPoints := range [][]EntryPoint{c.Externals, c.L1Handlers, c.Constructors} {
+			for _, entryPoint := range entryPoints {
+				selectors = append(selectors, entryPoint.Selector)
+			}
+		}
+	case *Cairo1Class:
+		for _, entryPoints := range [][]SierraEntryPoint{c.EntryPoints.External, c.EntryPoints.L1Handler, c.EntryPoints.Constructor} {
+			for _, entryPoint := range entryPoints {
+				selectors = append(selectors, entryPoint.Selector)
+			}
+		}
+	}
+	return selectors
+}
+
+// ContractsByClassHash returns up to limit addresses of the contracts currently using the class with the
+// given hash, in ascending order starting from the address start. If there are more, the next address is
+// returned.
+func (s *State) ContractsByClassHash(classHash, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error) {
+	return indexedFelts(s.txn, db.ContractAddressesByClassHash.Key(classHash.Marshal()), start, limit)
+}
+
+// ClassHashesBySelector returns up to limit hashes of the declared classes that define an entry point with
+// the given selector, in ascending order starting from the class hash start. If there are more, the next
+// class hash is returned.
+func (s *State) ClassHashesBySelector(selector, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error) {
+	return indexedFelts(s.txn, db.ClassHashesBySelector.Key(selector.Marshal()), start, limit)
+}
+
+// indexedFelts returns up to limit felts that follow the given prefix in index keys, starting from start
+func indexedFelts(txn db.Transaction, prefix []byte, start *felt.Felt, limit uint64) ([]*felt.Felt, *felt.Felt, error) {
+	it, err := txn.NewIterator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var values []*felt.Felt
+	for it.Seek(append(bytes.Clone(prefix), start.Marshal()...)); it.Valid(); it.Next() {
+		key := it.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		value := new(felt.Felt).SetBytes(key[len(prefix):])
+		if uint64(len(values)) == limit {
+			return values, value, it.Close()
+		}
+		values = append(values, value)
+	}
+	return values, nil, it.Close()
+}
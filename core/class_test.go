@@ -9,6 +9,7 @@ import (
 
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/encoder"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
@@ -200,3 +201,87 @@ func TestVerifyClassHash(t *testing.T) {
 		assert.NoError(t, core.VerifyClassHashes(classMap))
 	})
 }
+
+func TestCompiledClassHash(t *testing.T) {
+	felts := func(values ...uint64) []*felt.Felt {
+		result := make([]*felt.Felt, 0, len(values))
+		for _, v := range values {
+			result = append(result, new(felt.Felt).SetUint64(v))
+		}
+		return result
+	}
+	poseidon := func(values ...uint64) *felt.Felt {
+		return crypto.PoseidonArray(felts(values...)...)
+	}
+	// the hash of the entry points in the CASM below
+	entryPointsHash := crypto.PoseidonArray(
+		new(felt.Felt).SetUint64(0xabc), new(felt.Felt).SetUint64(3),
+		crypto.PoseidonArray(new(felt.Felt).SetBytes([]byte("range_check")), new(felt.Felt).SetBytes([]byte("poseidon"))),
+	)
+	classHash := func(bytecodeHash *felt.Felt) *felt.Felt {
+		return crypto.PoseidonArray(
+			new(felt.Felt).SetBytes([]byte("COMPILED_CLASS_V1")),
+			entryPointsHash,
+			crypto.PoseidonArray(),
+			crypto.PoseidonArray(),
+			bytecodeHash,
+		)
+	}
+	compiled := func(segmentLengths string) json.RawMessage {
+		return json.RawMessage(`{
+			"prime": "0x800000000000011000000000000000000000000000000000000000000000001",
+			"compiler_version": "2.6.0",
+			"bytecode": ["0x1", "0x2", "0x3", "0x4", "0x5"],
+			"hints": [],` + segmentLengths + `
+			"entry_points_by_type": {
+				"EXTERNAL": [{"selector": "0xabc", "offset": 3, "builtins": ["range_check", "poseidon"]}],
+				"L1_HANDLER": [],
+				"CONSTRUCTOR": []
+			}
+		}`)
+	}
+
+	t.Run("declared class", func(t *testing.T) {
+		gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+		classHash := utils.HexToFelt(t, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+		class, err := gw.Class(context.Background(), classHash)
+		require.NoError(t, err)
+
+		hash, err := class.(*core.Cairo1Class).CompiledClassHash()
+		require.NoError(t, err)
+		assert.Equal(t, utils.HexToFelt(t, "0x20a6110c6e226e18145a0474173b18280a842b994192b542ccb62be544d944"), hash)
+	})
+
+	t.Run("bytecode without segments", func(t *testing.T) {
+		hash, err := (&core.Cairo1Class{Compiled: compiled("")}).CompiledClassHash()
+		require.NoError(t, err)
+		assert.Equal(t, classHash(poseidon(1, 2, 3, 4, 5)), hash)
+	})
+
+	t.Run("segmented bytecode", func(t *testing.T) {
+		hash, err := (&core.Cairo1Class{Compiled: compiled(`"bytecode_segment_lengths": [2, [1, 2]],`)}).CompiledClassHash()
+		require.NoError(t, err)
+
+		one := new(felt.Felt).SetUint64(1)
+		innerHash := crypto.PoseidonArray(one, poseidon(3), new(felt.Felt).SetUint64(2), poseidon(4, 5))
+		innerHash.Add(innerHash, one)
+		bytecodeHash := crypto.PoseidonArray(new(felt.Felt).SetUint64(2), poseidon(1, 2), new(felt.Felt).SetUint64(3), innerHash)
+		bytecodeHash.Add(bytecodeHash, one)
+		assert.Equal(t, classHash(bytecodeHash), hash)
+
+		_, err = (&core.Cairo1Class{Compiled: compiled(`"bytecode_segment_lengths": [2, [1, 3]],`)}).CompiledClassHash()
+		require.Error(t, err)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		class := &core.Cairo1Class{Compiled: compiled("")}
+		classes := map[felt.Felt]core.Class{*new(felt.Felt).SetUint64(1): class}
+
+		require.NoError(t, core.VerifyCompiledClassHashes(map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(1): classHash(poseidon(1, 2, 3, 4, 5)),
+		}, classes))
+		require.ErrorContains(t, core.VerifyCompiledClassHashes(map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(1): new(felt.Felt).SetUint64(2),
+		}, classes), "cannot verify compiled class hash of 0x1")
+	})
+}
@@ -95,6 +95,9 @@ type ContractUpdater struct {
 	Address *felt.Felt
 	// txn to access the database
 	txn db.Transaction
+	// archive is the number of the block updating the contract, if the storage trie nodes it overwrites
+	// are archived
+	archive *uint64
 }
 
 // Purge eliminates the contract instance, deleting all associated data from storage
@@ -144,9 +147,21 @@ func ContractRoot(addr *felt.Felt, txn db.Transaction) (*felt.Felt, error) {
 
 type OnValueChanged = func(location, oldValue *felt.Felt) error
 
+// WithArchive makes the updater archive the storage trie nodes that the block with the given number
+// overwrites
+func (c *ContractUpdater) WithArchive(blockNumber uint64) *ContractUpdater {
+	c.archive = &blockNumber
+	return c
+}
+
 // UpdateStorage applies a change-set to the contract storage.
 func (c *ContractUpdater) UpdateStorage(diff map[felt.Felt]*felt.Felt, cb OnValueChanged) error {
-	cStorage, err := storage(c.Address, c.txn)
+	trieTxn := storageTxn(c.Address, c.txn)
+	if c.archive != nil {
+		trieTxn.WithArchive(*c.archive)
+	}
+
+	cStorage, err := trie.NewTriePedersen(trieTxn, contractStorageTrieHeight)
 	if err != nil {
 		return err
 	}
@@ -206,7 +221,9 @@ func (c *ContractUpdater) Replace(classHash *felt.Felt) error {
 // storage returns the [core.Trie] that represents the
 // storage of the contract.
 func storage(addr *felt.Felt, txn db.Transaction) (*trie.Trie, error) {
-	addrBytes := addr.Marshal()
-	trieTxn := trie.NewTransactionStorage(txn, db.ContractStorage.Key(addrBytes))
-	return trie.NewTriePedersen(trieTxn, contractStorageTrieHeight)
+	return trie.NewTriePedersen(storageTxn(addr, txn), contractStorageTrieHeight)
+}
+
+func storageTxn(addr *felt.Felt, txn db.Transaction) *trie.TransactionStorage {
+	return trie.NewTransactionStorage(txn, db.ContractStorage.Key(addr.Marshal()))
 }
@@ -63,6 +63,72 @@ func (h *history) valueAt(key []byte, height uint64) ([]byte, error) {
 	return nil, utils.RunAndWrapOnError(it.Close, ErrCheckHeadState)
 }
 
+// ValueChange records that a value changed from OldValue to NewValue in the block BlockNumber
+type ValueChange struct {
+	BlockNumber uint64
+	OldValue    *felt.Felt
+	NewValue    *felt.Felt
+}
+
+// changes returns up to limit changes logged for key in the blocks between from and to (inclusive), in
+// the order of the log keys. If more changes remain in the range, the block number of the next one is
+// returned. The new value of a change is the old value logged by the following change, or the value
+// returned by headValue if there is none.
+func (h *history) changes(key []byte, from, to, limit uint64,
+	headValue func() (*felt.Felt, error),
+) ([]ValueChange, *uint64, error) {
+	it, err := h.txn.NewIterator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		changes []ValueChange
+		next    *uint64
+		// the last change seen, whose new value is logged by the change after it
+		last *ValueChange
+	)
+	for it.Seek(logDBKey(key, from)); it.Valid(); it.Next() {
+		seekedKey := it.Key()
+		if len(seekedKey) != len(key)+8 || !bytes.HasPrefix(seekedKey, key) {
+			break
+		}
+
+		val, itErr := it.Value()
+		if itErr != nil {
+			return nil, nil, utils.RunAndWrapOnError(it.Close, itErr)
+		}
+		oldValue := new(felt.Felt).SetBytes(val)
+
+		if last != nil {
+			last.NewValue = oldValue
+			changes = append(changes, *last)
+			last = nil
+		}
+
+		seekedHeight := binary.BigEndian.Uint64(seekedKey[len(key):])
+		if seekedHeight > to {
+			break
+		} else if uint64(len(changes)) == limit {
+			next = &seekedHeight
+			break
+		}
+		last = &ValueChange{BlockNumber: seekedHeight, OldValue: oldValue}
+	}
+
+	if err = it.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	if last != nil {
+		if last.NewValue, err = headValue(); err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, *last)
+	}
+	return changes, next, nil
+}
+
 func storageLogKey(contractAddress, storageLocation *felt.Felt) []byte {
 	return db.ContractStorageHistory.Key(contractAddress.Marshal(), storageLocation.Marshal())
 }
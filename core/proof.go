@@ -0,0 +1,153 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+)
+
+// CommitmentProof proves that a leaf is included in the transaction or event commitment of a block
+type CommitmentProof struct {
+	// Index of the leaf in the commitment trie. Events are indexed by their position in the block.
+	Index uint64
+	Leaf  *felt.Felt
+	Nodes []trie.ProofNode
+}
+
+// TransactionProof rebuilds the transaction commitment trie of the block and proves the inclusion of the
+// transaction at the given index
+func TransactionProof(b *Block, index uint64) (*CommitmentProof, error) {
+	if index >= uint64(len(b.Transactions)) {
+		return nil, fmt.Errorf("transaction index %d out of range", index)
+	}
+
+	blockVersion, err := ParseBlockVersion(b.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([]*felt.Felt, 0, len(b.Transactions))
+	for _, transaction := range b.Transactions {
+		if blockVersion.LessThan(v0_13_2) {
+			leaves = append(leaves, transactionLeaf(transaction, blockVersion))
+		} else {
+			leaves = append(leaves, transactionLeafPoseidon(transaction))
+		}
+	}
+	return commitmentProof(leaves, index, blockVersion)
+}
+
+// EventProof rebuilds the event commitment trie of the block and proves the inclusion of the event at
+// the given index in the events emitted by the transaction at the given index
+func EventProof(b *Block, txIndex, eventIndex uint64) (*CommitmentProof, error) {
+	if txIndex >= uint64(len(b.Receipts)) {
+		return nil, fmt.Errorf("transaction index %d out of range", txIndex)
+	}
+	if eventIndex >= uint64(len(b.Receipts[txIndex].Events)) {
+		return nil, fmt.Errorf("event index %d out of range", eventIndex)
+	}
+
+	blockVersion, err := ParseBlockVersion(b.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaves []*felt.Felt
+	var index uint64
+	for i, receipt := range b.Receipts {
+		if uint64(i) == txIndex {
+			index = uint64(len(leaves)) + eventIndex
+		}
+		for _, event := range receipt.Events {
+			if blockVersion.LessThan(v0_13_2) {
+				leaves = append(leaves, eventLeaf(event))
+			} else {
+				leaves = append(leaves, eventLeafPoseidon(event, receipt.TransactionHash))
+			}
+		}
+	}
+	return commitmentProof(leaves, index, blockVersion)
+}
+
+func commitmentProof(leaves []*felt.Felt, index uint64, blockVersion *semver.Version) (*CommitmentProof, error) {
+	runOnTempTrie := trie.RunOnTempTrie
+	if !blockVersion.LessThan(v0_13_2) {
+		runOnTempTrie = trie.RunOnTempTriePoseidon
+	}
+
+	proof := &CommitmentProof{Index: index, Leaf: leaves[index]}
+	return proof, runOnTempTrie(commitmentTrieHeight, func(tempTrie *trie.Trie) error {
+		for i, leaf := range leaves {
+			if _, err := tempTrie.Put(new(felt.Felt).SetUint64(uint64(i)), leaf); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		proof.Nodes, err = tempTrie.Prove(new(felt.Felt).SetUint64(index))
+		return err
+	})
+}
+
+var errInvalidProof = errors.New("invalid commitment proof")
+
+// VerifyTransactionProof checks that the proof shows the transaction to be included in the transaction
+// commitment of a block of the given protocol version
+func VerifyTransactionProof(commitments *BlockCommitments, protocolVersion string, transaction Transaction,
+	proof *CommitmentProof,
+) error {
+	if commitments == nil || commitments.TransactionCommitment == nil {
+		return errors.New("block does not commit to transactions")
+	}
+
+	blockVersion, err := ParseBlockVersion(protocolVersion)
+	if err != nil {
+		return err
+	}
+
+	leaf := transactionLeafPoseidon(transaction)
+	if blockVersion.LessThan(v0_13_2) {
+		leaf = transactionLeaf(transaction, blockVersion)
+	}
+	return verifyCommitmentProof(commitments.TransactionCommitment, leaf, proof, blockVersion)
+}
+
+// VerifyEventProof checks that the proof shows the event emitted by the transaction with the given hash to
+// be included in the event commitment of a block of the given protocol version
+func VerifyEventProof(commitments *BlockCommitments, protocolVersion string, event *Event, transactionHash *felt.Felt,
+	proof *CommitmentProof,
+) error {
+	if commitments == nil || commitments.EventCommitment == nil {
+		return errors.New("block does not commit to events")
+	}
+
+	blockVersion, err := ParseBlockVersion(protocolVersion)
+	if err != nil {
+		return err
+	}
+
+	leaf := eventLeafPoseidon(event, transactionHash)
+	if blockVersion.LessThan(v0_13_2) {
+		leaf = eventLeaf(event)
+	}
+	return verifyCommitmentProof(commitments.EventCommitment, leaf, proof, blockVersion)
+}
+
+func verifyCommitmentProof(root, leaf *felt.Felt, proof *CommitmentProof, blockVersion *semver.Version) error {
+	if proof == nil || (proof.Leaf != nil && !proof.Leaf.Equal(leaf)) {
+		return errInvalidProof
+	}
+
+	hash := crypto.Pedersen
+	if !blockVersion.LessThan(v0_13_2) {
+		hash = crypto.Poseidon
+	}
+	if !trie.VerifyProof(root, new(felt.Felt).SetUint64(proof.Index), leaf, commitmentTrieHeight, proof.Nodes, hash) {
+		return errInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitmentProofs(t *testing.T) {
+	tests := map[string]struct {
+		network  utils.Network
+		number   uint64
+		post0132 bool
+	}{
+		"pre 0.11.1":  {network: utils.Mainnet, number: 16789},
+		"post 0.11.1": {network: utils.Goerli2, number: 110238},
+		"post 0.13.2": {network: utils.Mainnet, number: 16789, post0132: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gw := adaptfeeder.New(feeder.NewTestClient(t, test.network))
+			block, err := gw.BlockByNumber(context.Background(), test.number)
+			require.NoError(t, err)
+
+			var stateDiff *core.StateDiff
+			if test.post0132 {
+				// The block predates Starknet 0.13.2, so its header is filled in as if it followed it
+				stateDiff = core.EmptyStateDiff()
+				block.ProtocolVersion = "0.13.2"
+				block.GasPriceSTRK = new(felt.Felt).SetUint64(1)
+				block.L1DataGasPrice = &core.GasPrice{PriceInWei: new(felt.Felt).SetUint64(2), PriceInFri: new(felt.Felt).SetUint64(3)}
+				block.Hash, _, err = core.BlockHash(block, test.network, stateDiff)
+				require.NoError(t, err)
+			}
+			commitments, err := core.VerifyBlockHash(block, test.network, stateDiff)
+			require.NoError(t, err)
+
+			t.Run("transactions", func(t *testing.T) {
+				require.NotEmpty(t, block.Transactions)
+				for _, index := range []uint64{0, uint64(len(block.Transactions) - 1)} {
+					proof, err := core.TransactionProof(block, index)
+					require.NoError(t, err)
+					assert.Equal(t, index, proof.Index)
+					require.NoError(t, core.VerifyTransactionProof(commitments, block.ProtocolVersion, block.Transactions[index], proof))
+
+					if len(block.Transactions) > 1 {
+						other := block.Transactions[(index+1)%uint64(len(block.Transactions))]
+						assert.Error(t, core.VerifyTransactionProof(commitments, block.ProtocolVersion, other, proof))
+					}
+					proof.Index++
+					assert.Error(t, core.VerifyTransactionProof(commitments, block.ProtocolVersion, block.Transactions[index], proof))
+				}
+
+				_, err := core.TransactionProof(block, uint64(len(block.Transactions)))
+				assert.Error(t, err)
+			})
+
+			t.Run("events", func(t *testing.T) {
+				var txIndex, eventIndex, blockEventIndex uint64
+				for i, receipt := range block.Receipts {
+					if len(receipt.Events) > 1 {
+						txIndex, eventIndex = uint64(i), uint64(len(receipt.Events)-1)
+						blockEventIndex += eventIndex
+						break
+					}
+					blockEventIndex += uint64(len(receipt.Events))
+				}
+				receipt := block.Receipts[txIndex]
+				require.NotEmpty(t, receipt.Events)
+
+				proof, err := core.EventProof(block, txIndex, eventIndex)
+				require.NoError(t, err)
+				assert.Equal(t, blockEventIndex, proof.Index)
+				event := receipt.Events[eventIndex]
+				require.NoError(t, core.VerifyEventProof(commitments, block.ProtocolVersion, event, receipt.TransactionHash, proof))
+
+				tampered := *event
+				tampered.Data = append([]*felt.Felt{new(felt.Felt).SetUint64(1)}, event.Data...)
+				assert.Error(t, core.VerifyEventProof(commitments, block.ProtocolVersion, &tampered, receipt.TransactionHash, proof))
+				assert.Error(t, core.VerifyEventProof(&core.BlockCommitments{}, block.ProtocolVersion, event, receipt.TransactionHash, proof))
+
+				_, err = core.EventProof(block, txIndex, uint64(len(receipt.Events)))
+				assert.Error(t, err)
+			})
+		})
+	}
+}
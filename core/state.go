@@ -45,6 +45,8 @@ type StateReader interface {
 type State struct {
 	*history
 	txn db.Transaction
+	// archive makes updates keep the trie nodes they overwrite, see [State.TriesAt]
+	archive bool
 }
 
 func NewState(txn db.Transaction) *State {
@@ -54,6 +56,13 @@ func NewState(txn db.Transaction) *State {
 	}
 }
 
+// WithArchive sets whether updates to the state archive the trie nodes they overwrite. Once a block was
+// stored in archive mode, all the blocks after it must be too.
+func (s *State) WithArchive(archive bool) *State {
+	s.archive = archive
+	return s
+}
+
 // putNewContract creates a contract storage instance in the state and stores the relation between contract address and class hash to be
 // queried later with [GetContractClass].
 func (s *State) putNewContract(stateTrie *trie.Trie, addr, classHash *felt.Felt, blockNumber uint64) error {
@@ -62,6 +71,10 @@ func (s *State) putNewContract(stateTrie *trie.Trie, addr, classHash *felt.Felt,
 		return err
 	}
 
+	if err = IndexContractClass(s.txn, classHash, addr); err != nil {
+		return err
+	}
+
 	numBytes := MarshalBlockNumber(blockNumber)
 	if err = s.txn.Set(db.ContractDeploymentHeight.Key(addr.Marshal()), numBytes); err != nil {
 		return err
@@ -115,25 +128,43 @@ func (s *State) Root() (*felt.Felt, error) {
 		return nil, err
 	}
 
+	return stateCommitment(storageRoot, classesRoot), nil
+}
+
+func stateCommitment(storageRoot, classesRoot *felt.Felt) *felt.Felt {
 	if classesRoot.IsZero() {
-		return storageRoot, nil
+		return storageRoot
 	}
-
-	return crypto.PoseidonArray(stateVersion, storageRoot, classesRoot), nil
+	return crypto.PoseidonArray(stateVersion, storageRoot, classesRoot)
 }
 
 // storage returns a [core.Trie] that represents the Starknet global state in the given Txn context.
 func (s *State) storage() (*trie.Trie, func() error, error) {
-	return s.globalTrie(db.StateTrie, trie.NewTriePedersen)
+	return s.globalTrie(db.StateTrie, trie.NewTriePedersen, nil)
 }
 
 func (s *State) classesTrie() (*trie.Trie, func() error, error) {
-	return s.globalTrie(db.ClassesTrie, trie.NewTriePoseidon)
+	return s.globalTrie(db.ClassesTrie, trie.NewTriePoseidon, nil)
+}
+
+// blockStorage is like storage, but in archive mode the trie keeps the nodes that the block with the
+// given number overwrites
+func (s *State) blockStorage(blockNumber uint64) (*trie.Trie, func() error, error) {
+	return s.globalTrie(db.StateTrie, trie.NewTriePedersen, &blockNumber)
 }
 
-func (s *State) globalTrie(bucket db.Bucket, newTrie trie.NewTrieFunc) (*trie.Trie, func() error, error) {
+// blockClassesTrie is like classesTrie, but in archive mode the trie keeps the nodes that the block with
+// the given number overwrites
+func (s *State) blockClassesTrie(blockNumber uint64) (*trie.Trie, func() error, error) {
+	return s.globalTrie(db.ClassesTrie, trie.NewTriePoseidon, &blockNumber)
+}
+
+func (s *State) globalTrie(bucket db.Bucket, newTrie trie.NewTrieFunc, blockNumber *uint64) (*trie.Trie, func() error, error) {
 	dbPrefix := bucket.Key()
 	tTxn := trie.NewTransactionStorage(s.txn, dbPrefix)
+	if s.archive && blockNumber != nil {
+		tTxn.WithArchive(*blockNumber)
+	}
 
 	// fetch root key
 	rootKeyDBKey := dbPrefix
@@ -197,43 +228,71 @@ func (s *State) verifyStateUpdateRoot(root *felt.Felt) error {
 // old or new root does not match the state's old or new roots,
 // [ErrMismatchedRoot] is returned.
 func (s *State) Update(blockNumber uint64, update *StateUpdate, declaredClasses map[felt.Felt]Class) error {
-	err := s.verifyStateUpdateRoot(update.OldRoot)
+	newRoot, err := s.Apply(blockNumber, update, declaredClasses)
 	if err != nil {
 		return err
 	}
 
+	if !update.NewRoot.Equal(newRoot) {
+		return fmt.Errorf("state's current root: %s does not match the expected root: %s", newRoot, update.NewRoot)
+	}
+	return nil
+}
+
+// Apply applies a StateUpdate whose new root is not known yet, such as one produced by
+// executing transactions locally, and returns the resulting state root. Update's old root
+// must match the state's current root.
+func (s *State) Apply(blockNumber uint64, update *StateUpdate, declaredClasses map[felt.Felt]Class) (*felt.Felt, error) {
+	err := s.verifyStateUpdateRoot(update.OldRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.archive && blockNumber > 0 {
+		if _, _, err = globalRootsAt(s.txn, blockNumber-1); err == nil {
+			return nil, fmt.Errorf("block %d was stored in archive mode, so block %d must be too", blockNumber-1, blockNumber)
+		} else if !errors.Is(err, ErrStateNotArchived) {
+			return nil, err
+		}
+	}
+
 	// register declared classes mentioned in stateDiff.deployedContracts and stateDiff.declaredClasses
 	for cHash, class := range declaredClasses {
 		if err = s.putClass(&cHash, class, blockNumber); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if err = s.updateDeclaredClassesTrie(update.StateDiff.DeclaredV1Classes, declaredClasses); err != nil {
-		return err
+	if err = s.updateDeclaredClassesTrie(blockNumber, update.StateDiff.DeclaredV1Classes, declaredClasses); err != nil {
+		return nil, err
 	}
 
-	stateTrie, storageCloser, err := s.storage()
+	stateTrie, storageCloser, err := s.blockStorage(blockNumber)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// register deployed contracts
 	for addr, classHash := range update.StateDiff.DeployedContracts {
 		if err = s.putNewContract(stateTrie, &addr, classHash, blockNumber); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if err = s.updateContracts(stateTrie, blockNumber, update.StateDiff, true); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err = storageCloser(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.verifyStateUpdateRoot(update.NewRoot)
+	if s.archive {
+		if err = s.archiveGlobalRoots(blockNumber); err != nil {
+			return nil, err
+		}
+	}
+	return s.Root()
 }
 
 var (
@@ -293,6 +352,13 @@ func (s *State) replaceContract(stateTrie *trie.Trie, addr, classHash *felt.Felt
 		return nil, err
 	}
 
+	if err = unindexContractClass(s.txn, oldClassHash, addr); err != nil {
+		return nil, err
+	}
+	if err = IndexContractClass(s.txn, classHash, addr); err != nil {
+		return nil, err
+	}
+
 	if err = s.updateContractCommitment(stateTrie, contract); err != nil {
 		return nil, err
 	}
@@ -321,7 +387,10 @@ func (s *State) putClass(classHash *felt.Felt, class Class, declaredAt uint64) e
 			return encErr
 		}
 
-		return s.txn.Set(classKey, classEncoded)
+		if err = s.txn.Set(classKey, classEncoded); err != nil {
+			return err
+		}
+		return IndexClassSelectors(s.txn, classHash, class)
 	}
 	return err
 }
@@ -350,6 +419,10 @@ func (s *State) updateStorageBuffered(contractAddr *felt.Felt, updateDiff map[fe
 	if err != nil {
 		return nil, err
 	}
+	// changes are only logged when applying a block, which is when the trie nodes are archived as well
+	if s.archive && logChanges {
+		bufferedContract.WithArchive(blockNumber)
+	}
 
 	onValueChanged := func(location, oldValue *felt.Felt) error {
 		if logChanges {
@@ -486,8 +559,10 @@ func calculateContractCommitment(storageRoot, classHash, nonce *felt.Felt) *felt
 	return crypto.Pedersen(crypto.Pedersen(crypto.Pedersen(classHash, storageRoot), nonce), &felt.Zero)
 }
 
-func (s *State) updateDeclaredClassesTrie(declaredClasses map[felt.Felt]*felt.Felt, classDefinitions map[felt.Felt]Class) error {
-	classesTrie, classesCloser, err := s.classesTrie()
+func (s *State) updateDeclaredClassesTrie(blockNumber uint64, declaredClasses map[felt.Felt]*felt.Felt,
+	classDefinitions map[felt.Felt]Class,
+) error {
+	classesTrie, classesCloser, err := s.blockClassesTrie(blockNumber)
 	if err != nil {
 		return err
 	}
@@ -509,11 +584,8 @@ func (s *State) updateDeclaredClassesTrie(declaredClasses map[felt.Felt]*felt.Fe
 
 // ContractIsAlreadyDeployedAt returns if contract at given addr was deployed at blockNumber
 func (s *State) ContractIsAlreadyDeployedAt(addr *felt.Felt, blockNumber uint64) (bool, error) {
-	var deployedAt uint64
-	if err := s.txn.Get(db.ContractDeploymentHeight.Key(addr.Marshal()), func(bytes []byte) error {
-		deployedAt = binary.BigEndian.Uint64(bytes)
-		return nil
-	}); err != nil {
+	deployedAt, err := s.contractDeploymentHeight(addr)
+	if err != nil {
 		if errors.Is(err, db.ErrKeyNotFound) {
 			return false, nil
 		}
@@ -522,6 +594,77 @@ func (s *State) ContractIsAlreadyDeployedAt(addr *felt.Felt, blockNumber uint64)
 	return deployedAt <= blockNumber, nil
 }
 
+func (s *State) contractDeploymentHeight(addr *felt.Felt) (uint64, error) {
+	var deployedAt uint64
+	return deployedAt, s.txn.Get(db.ContractDeploymentHeight.Key(addr.Marshal()), func(bytes []byte) error {
+		deployedAt = binary.BigEndian.Uint64(bytes)
+		return nil
+	})
+}
+
+// ContractStorageChanges returns up to limit changes to a storage location of the given contract in the
+// blocks between from and to (inclusive), along with the block number of the next change if there are more.
+func (s *State) ContractStorageChanges(addr, key *felt.Felt, from, to, limit uint64) ([]ValueChange, *uint64, error) {
+	if _, err := s.contractDeploymentHeight(addr); err != nil {
+		return nil, nil, err
+	}
+
+	return s.changes(storageLogKey(addr, key), from, to, limit, func() (*felt.Felt, error) {
+		return s.ContractStorage(addr, key)
+	})
+}
+
+// ContractNonceChanges returns up to limit changes to the nonce of the given contract in the blocks
+// between from and to (inclusive), along with the block number of the next change if there are more.
+func (s *State) ContractNonceChanges(addr *felt.Felt, from, to, limit uint64) ([]ValueChange, *uint64, error) {
+	if _, err := s.contractDeploymentHeight(addr); err != nil {
+		return nil, nil, err
+	}
+
+	return s.changes(nonceLogKey(addr), from, to, limit, func() (*felt.Felt, error) {
+		return s.ContractNonce(addr)
+	})
+}
+
+// ContractClassHashChanges returns up to limit changes to the class hash of the given contract in the
+// blocks between from and to (inclusive), along with the block number of the next change if there are more.
+// The deployment of the contract is reported as a change from the zero class hash, so a contract upgraded in
+// the block it was deployed in has two changes in that block. Pages continuing from the block number returned
+// start again at its first change.
+func (s *State) ContractClassHashChanges(addr *felt.Felt, from, to, limit uint64) ([]ValueChange, *uint64, error) {
+	deployedAt, err := s.contractDeploymentHeight(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headClassHash := func() (*felt.Felt, error) {
+		return s.ContractClassHash(addr)
+	}
+	changes, next, err := s.changes(classHashLogKey(addr), from, to, limit, headClassHash)
+	if err != nil || deployedAt < from || deployedAt > to || limit == 0 {
+		return changes, next, err
+	}
+
+	deployedClassHash, err := s.ContractClassHashAt(addr, deployedAt)
+	if errors.Is(err, ErrCheckHeadState) {
+		deployedClassHash, err = headClassHash()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes = append([]ValueChange{{
+		BlockNumber: deployedAt,
+		OldValue:    &felt.Zero,
+		NewValue:    deployedClassHash,
+	}}, changes...)
+	if uint64(len(changes)) > limit {
+		next = &changes[limit].BlockNumber
+		changes = changes[:limit]
+	}
+	return changes, next, nil
+}
+
 func (s *State) Revert(blockNumber uint64, update *StateUpdate) error {
 	err := s.verifyStateUpdateRoot(update.NewRoot)
 	if err != nil {
@@ -585,6 +728,9 @@ func (s *State) Revert(blockNumber uint64, update *StateUpdate) error {
 		}
 	}
 
+	if err = s.deleteArchive(blockNumber); err != nil {
+		return fmt.Errorf("delete archive: %v", err)
+	}
 	return s.verifyStateUpdateRoot(update.OldRoot)
 }
 
@@ -611,6 +757,9 @@ func (s *State) removeDeclaredClasses(blockNumber uint64, v0Classes []*felt.Felt
 		if err = s.txn.Delete(db.Class.Key(cHash.Marshal())); err != nil {
 			return fmt.Errorf("delete class: %v", err)
 		}
+		if err = unindexClassSelectors(s.txn, cHash, declaredClass.Class); err != nil {
+			return fmt.Errorf("unindex class selectors: %v", err)
+		}
 
 		// cairo1 class, update the class commitment trie as well
 		if declaredClass.Class.Version() == 1 {
@@ -637,6 +786,14 @@ func (s *State) purgeContract(addr *felt.Felt) error {
 		return err
 	}
 
+	classHash, err := ContractClassHash(addr, s.txn)
+	if err != nil {
+		return err
+	}
+	if err = unindexContractClass(s.txn, classHash, addr); err != nil {
+		return err
+	}
+
 	if _, err = state.Put(contract.Address, &felt.Zero); err != nil {
 		return err
 	}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+)
+
+// ErrStateNotArchived is returned when the tries of a state are read as of a block that was not stored in
+// archive mode
+var ErrStateNotArchived = errors.New("state is not archived")
+
+// TrieReader reads the commitment tries of a state
+type TrieReader interface {
+	// GlobalRoots returns the roots of the contracts and classes tries, which make up the state commitment
+	GlobalRoots() (contractsRoot, classesRoot *felt.Felt, err error)
+	ContractStorageRoot(addr *felt.Felt) (*felt.Felt, error)
+
+	// ContractProof proves the commitment of the contract at the given address against the contracts root
+	ContractProof(addr *felt.Felt) ([]trie.ProofNode, error)
+	// ContractStorageProof proves the value of a storage location of the given contract against its
+	// storage root
+	ContractStorageProof(addr, key *felt.Felt) ([]trie.ProofNode, error)
+	// ClassProof proves the leaf of the class with the given hash against the classes root
+	ClassProof(classHash *felt.Felt) ([]trie.ProofNode, error)
+}
+
+// Tries returns the commitment tries of the state
+func (s *State) Tries() TrieReader {
+	return &tries{txn: s.txn}
+}
+
+// TriesAt returns the commitment tries of the state as of the given block. Trie nodes are overwritten in
+// place, so only the tries of the blocks stored in archive mode can be read.
+func (s *State) TriesAt(blockNumber uint64) (TrieReader, error) {
+	contractsRoot, classesRoot, err := globalRootsAt(s.txn, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archivedTries{
+		tries:         &tries{txn: s.txn, asOf: &blockNumber},
+		contractsRoot: contractsRoot,
+		classesRoot:   classesRoot,
+	}, nil
+}
+
+// archiveGlobalRoots stores the roots of the global tries after the block with the given number, which
+// also marks the block as archived
+func (s *State) archiveGlobalRoots(blockNumber uint64) error {
+	contractsRoot, classesRoot, err := s.Tries().GlobalRoots()
+	if err != nil {
+		return err
+	}
+	return s.txn.Set(db.GlobalTrieRoots.Key(MarshalBlockNumber(blockNumber)), append(contractsRoot.Marshal(), classesRoot.Marshal()...))
+}
+
+// deleteArchive drops the trie nodes and roots archived by the block with the given number
+func (s *State) deleteArchive(blockNumber uint64) error {
+	if err := trie.DeleteArchive(s.txn, blockNumber); err != nil {
+		return err
+	}
+	return s.txn.Delete(db.GlobalTrieRoots.Key(MarshalBlockNumber(blockNumber)))
+}
+
+func globalRootsAt(txn db.Transaction, blockNumber uint64) (*felt.Felt, *felt.Felt, error) {
+	var contractsRoot, classesRoot *felt.Felt
+	err := txn.Get(db.GlobalTrieRoots.Key(MarshalBlockNumber(blockNumber)), func(val []byte) error {
+		if len(val) != 2*felt.Bytes {
+			return fmt.Errorf("invalid global trie roots of length %d", len(val))
+		}
+		contractsRoot = new(felt.Felt).SetBytes(val[:felt.Bytes])
+		classesRoot = new(felt.Felt).SetBytes(val[felt.Bytes:])
+		return nil
+	})
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil, ErrStateNotArchived
+	}
+	return contractsRoot, classesRoot, err
+}
+
+// tries reads the commitment tries at the head, or as of a block if asOf is set
+type tries struct {
+	txn  db.Transaction
+	asOf *uint64
+}
+
+func (t *tries) open(prefix []byte, newTrie trie.NewTrieFunc, height uint8) (*trie.Trie, error) {
+	tTxn := trie.NewTransactionStorage(t.txn, prefix)
+	if t.asOf != nil {
+		tTxn.WithHistoricalReads(*t.asOf)
+	}
+	return newTrie(tTxn, height)
+}
+
+func (t *tries) contractsTrie() (*trie.Trie, error) {
+	return t.open(db.StateTrie.Key(), trie.NewTriePedersen, globalTrieHeight)
+}
+
+func (t *tries) classesTrie() (*trie.Trie, error) {
+	return t.open(db.ClassesTrie.Key(), trie.NewTriePoseidon, globalTrieHeight)
+}
+
+func (t *tries) storageTrie(addr *felt.Felt) (*trie.Trie, error) {
+	return t.open(db.ContractStorage.Key(addr.Marshal()), trie.NewTriePedersen, contractStorageTrieHeight)
+}
+
+func (t *tries) GlobalRoots() (*felt.Felt, *felt.Felt, error) {
+	contractsTrie, err := t.contractsTrie()
+	if err != nil {
+		return nil, nil, err
+	}
+	contractsRoot, err := contractsTrie.Root()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	classesTrie, err := t.classesTrie()
+	if err != nil {
+		return nil, nil, err
+	}
+	classesRoot, err := classesTrie.Root()
+	if err != nil {
+		return nil, nil, err
+	}
+	return contractsRoot, classesRoot, nil
+}
+
+func (t *tries) ContractStorageRoot(addr *felt.Felt) (*felt.Felt, error) {
+	storageTrie, err := t.storageTrie(addr)
+	if err != nil {
+		return nil, err
+	}
+	return storageTrie.Root()
+}
+
+func (t *tries) ContractProof(addr *felt.Felt) ([]trie.ProofNode, error) {
+	contractsTrie, err := t.contractsTrie()
+	if err != nil {
+		return nil, err
+	}
+	return contractsTrie.Prove(addr)
+}
+
+func (t *tries) ContractStorageProof(addr, key *felt.Felt) ([]trie.ProofNode, error) {
+	storageTrie, err := t.storageTrie(addr)
+	if err != nil {
+		return nil, err
+	}
+	return storageTrie.Prove(key)
+}
+
+func (t *tries) ClassProof(classHash *felt.Felt) ([]trie.ProofNode, error) {
+	classesTrie, err := t.classesTrie()
+	if err != nil {
+		return nil, err
+	}
+	return classesTrie.Prove(classHash)
+}
+
+// archivedTries reads the commitment tries as of an archived block, whose global roots are stored
+type archivedTries struct {
+	*tries
+	contractsRoot *felt.Felt
+	classesRoot   *felt.Felt
+}
+
+func (t *archivedTries) GlobalRoots() (*felt.Felt, *felt.Felt, error) {
+	return t.contractsRoot, t.classesRoot, nil
+}
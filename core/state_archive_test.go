@@ -0,0 +1,127 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivedTries(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	state := core.NewState(txn).WithArchive(true)
+
+	type snapshot struct {
+		contractsRoot  *felt.Felt
+		classesRoot    *felt.Felt
+		storageRoots   map[felt.Felt]*felt.Felt
+		contractProofs map[felt.Felt][]trie.ProofNode
+	}
+
+	var (
+		updates   []*core.StateUpdate
+		snapshots []snapshot
+	)
+	// the contracts whose storage changes in any of the blocks
+	addresses := make(map[felt.Felt]struct{})
+	for number := uint64(0); number < 3; number++ {
+		su, err := gw.StateUpdate(context.Background(), number)
+		require.NoError(t, err)
+		require.NoError(t, state.Update(number, su, nil))
+		updates = append(updates, su)
+		for addr := range su.StateDiff.StorageDiffs {
+			addresses[addr] = struct{}{}
+		}
+	}
+
+	for number := range updates {
+		// replay the blocks to take snapshots of the tries at the head
+		replayTxn, err := pebble.NewMemTest(t).NewTransaction(true)
+		require.NoError(t, err)
+		replay := core.NewState(replayTxn)
+		for i := 0; i <= number; i++ {
+			require.NoError(t, replay.Update(uint64(i), updates[i], nil))
+		}
+
+		tries := replay.Tries()
+		snap := snapshot{
+			storageRoots:   make(map[felt.Felt]*felt.Felt),
+			contractProofs: make(map[felt.Felt][]trie.ProofNode),
+		}
+		snap.contractsRoot, snap.classesRoot, err = tries.GlobalRoots()
+		require.NoError(t, err)
+		for addr := range addresses {
+			snap.storageRoots[addr], err = tries.ContractStorageRoot(&addr)
+			require.NoError(t, err)
+			snap.contractProofs[addr], err = tries.ContractProof(&addr)
+			require.NoError(t, err)
+		}
+		snapshots = append(snapshots, snap)
+		require.NoError(t, replayTxn.Discard())
+	}
+
+	assertArchived := func(t *testing.T, number uint64) {
+		tries, err := state.TriesAt(number)
+		require.NoError(t, err)
+
+		snap := snapshots[number]
+		contractsRoot, classesRoot, err := tries.GlobalRoots()
+		require.NoError(t, err)
+		assert.Equal(t, snap.contractsRoot, contractsRoot)
+		assert.Equal(t, snap.classesRoot, classesRoot)
+
+		for addr := range addresses {
+			storageRoot, err := tries.ContractStorageRoot(&addr)
+			require.NoError(t, err)
+			assert.Equal(t, snap.storageRoots[addr], storageRoot)
+
+			proof, err := tries.ContractProof(&addr)
+			require.NoError(t, err)
+			assert.Equal(t, snap.contractProofs[addr], proof)
+		}
+
+		for addr, diff := range updates[number].StateDiff.StorageDiffs {
+			for key, value := range diff {
+				proof, err := tries.ContractStorageProof(&addr, &key)
+				require.NoError(t, err)
+				assert.True(t, trie.VerifyProof(snap.storageRoots[addr], &key, value, 251, proof, crypto.Pedersen))
+			}
+		}
+	}
+
+	for number := range updates {
+		assertArchived(t, uint64(number))
+	}
+
+	t.Run("updates must stay archived", func(t *testing.T) {
+		su, err := gw.StateUpdate(context.Background(), 21656)
+		require.NoError(t, err)
+		su.OldRoot = updates[2].NewRoot
+		assert.ErrorContains(t, core.NewState(txn).Update(3, su, nil), "archive mode")
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		require.NoError(t, state.Revert(2, updates[2]))
+
+		_, err := state.TriesAt(2)
+		require.ErrorIs(t, err, core.ErrStateNotArchived)
+		assertArchived(t, 0)
+		assertArchived(t, 1)
+	})
+}
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+)
+
+// StateDiffProof proves the entries of the state diff of a block against the state commitments before and
+// after it, so that the state diff can be verified without executing the block
+type StateDiffProof struct {
+	OldContractsRoot *felt.Felt
+	OldClassesRoot   *felt.Felt
+	NewContractsRoot *felt.Felt
+	NewClassesRoot   *felt.Felt
+
+	Contracts []*ContractDiffProof
+	Classes   []*DeclaredClassProof
+}
+
+// ContractDiffProof proves the leaf of a contract before and after a block, and the values of the storage
+// locations the block updated
+type ContractDiffProof struct {
+	Address *felt.Felt
+	Old     *ContractLeafProof
+	New     *ContractLeafProof
+	Storage []*StorageDiffProof
+}
+
+// ContractLeafProof proves the commitment of a contract against a contracts root. A nil class hash means that
+// the contract is not deployed, in which case its absence is proven.
+type ContractLeafProof struct {
+	ClassHash   *felt.Felt
+	Nonce       *felt.Felt
+	StorageRoot *felt.Felt
+	Proof       []trie.ProofNode
+}
+
+// StorageDiffProof proves the value of a storage location before and after a block against the storage roots
+// of the contract
+type StorageDiffProof struct {
+	Key      *felt.Felt
+	OldValue *felt.Felt
+	OldProof []trie.ProofNode
+	NewProof []trie.ProofNode
+}
+
+// DeclaredClassProof proves the leaf of a declared class against the classes root after a block
+type DeclaredClassProof struct {
+	ClassHash *felt.Felt
+	Proof     []trie.ProofNode
+}
+
+// NewStateDiffProof proves the state diff of a block with the states and commitment tries before and after it.
+// The old state and tries are nil for the first block.
+func NewStateDiffProof(stateDiff *StateDiff, oldState StateReader, oldTries TrieReader, newState StateReader,
+	newTries TrieReader,
+) (*StateDiffProof, error) {
+	proof := &StateDiffProof{
+		OldContractsRoot: &felt.Zero,
+		OldClassesRoot:   &felt.Zero,
+	}
+
+	var err error
+	if oldTries != nil {
+		if proof.OldContractsRoot, proof.OldClassesRoot, err = oldTries.GlobalRoots(); err != nil {
+			return nil, err
+		}
+	}
+	if proof.NewContractsRoot, proof.NewClassesRoot, err = newTries.GlobalRoots(); err != nil {
+		return nil, err
+	}
+
+	for _, addr := range stateDiffContracts(stateDiff) {
+		contractProof := &ContractDiffProof{Address: addr}
+		if oldTries == nil {
+			contractProof.Old = &ContractLeafProof{}
+		} else if contractProof.Old, err = proveContractLeaf(addr, oldState, oldTries); err != nil {
+			return nil, err
+		}
+		if contractProof.New, err = proveContractLeaf(addr, newState, newTries); err != nil {
+			return nil, err
+		}
+
+		for key := range stateDiff.StorageDiffs[*addr] {
+			storageProof := &StorageDiffProof{
+				Key:      new(felt.Felt).Set(&key),
+				OldValue: &felt.Zero,
+			}
+			if oldTries != nil {
+				if storageProof.OldValue, err = contractStorage(oldState, addr, &key); err != nil {
+					return nil, err
+				}
+				if storageProof.OldProof, err = oldTries.ContractStorageProof(addr, &key); err != nil {
+					return nil, err
+				}
+			}
+			if storageProof.NewProof, err = newTries.ContractStorageProof(addr, &key); err != nil {
+				return nil, err
+			}
+			contractProof.Storage = append(contractProof.Storage, storageProof)
+		}
+		proof.Contracts = append(proof.Contracts, contractProof)
+	}
+
+	for classHash := range stateDiff.DeclaredV1Classes {
+		classProof := &DeclaredClassProof{ClassHash: new(felt.Felt).Set(&classHash)}
+		if classProof.Proof, err = newTries.ClassProof(&classHash); err != nil {
+			return nil, err
+		}
+		proof.Classes = append(proof.Classes, classProof)
+	}
+	return proof, nil
+}
+
+// stateDiffContracts returns the addresses of the contracts a state diff changes
+func stateDiffContracts(stateDiff *StateDiff) []*felt.Felt {
+	seen := make(map[felt.Felt]struct{})
+	var addresses []*felt.Felt
+	add := func(addr felt.Felt) {
+		if _, found := seen[addr]; !found {
+			seen[addr] = struct{}{}
+			addresses = append(addresses, &addr)
+		}
+	}
+
+	for addr := range stateDiff.DeployedContracts {
+		add(addr)
+	}
+	for addr := range stateDiff.ReplacedClasses {
+		add(addr)
+	}
+	for addr := range stateDiff.Nonces {
+		add(addr)
+	}
+	for addr := range stateDiff.StorageDiffs {
+		add(addr)
+	}
+	return addresses
+}
+
+func proveContractLeaf(addr *felt.Felt, state StateReader, tries TrieReader) (*ContractLeafProof, error) {
+	leaf := new(ContractLeafProof)
+	var err error
+	if leaf.Proof, err = tries.ContractProof(addr); err != nil {
+		return nil, err
+	}
+
+	leaf.ClassHash, err = state.ContractClassHash(addr)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return &ContractLeafProof{Proof: leaf.Proof}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if leaf.Nonce, err = state.ContractNonce(addr); err != nil {
+		return nil, err
+	}
+	if leaf.StorageRoot, err = tries.ContractStorageRoot(addr); err != nil {
+		return nil, err
+	}
+	return leaf, nil
+}
+
+func contractStorage(state StateReader, addr, key *felt.Felt) (*felt.Felt, error) {
+	value, err := state.ContractStorage(addr, key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return &felt.Zero, nil
+	}
+	return value, err
+}
+
+// VerifyStateDiffProof checks that the proof proves the state diff of a block against the state commitments
+// before and after it
+func VerifyStateDiffProof(proof *StateDiffProof, stateDiff *StateDiff, oldRoot, newRoot *felt.Felt) error {
+	if proof.OldContractsRoot == nil || proof.OldClassesRoot == nil || proof.NewContractsRoot == nil ||
+		proof.NewClassesRoot == nil {
+		return errors.New("missing global roots")
+	}
+	if !stateCommitment(proof.OldContractsRoot, proof.OldClassesRoot).Equal(oldRoot) {
+		return errors.New("global roots do not match the old state root")
+	}
+	if !stateCommitment(proof.NewContractsRoot, proof.NewClassesRoot).Equal(newRoot) {
+		return errors.New("global roots do not match the new state root")
+	}
+
+	contractProofs := make(map[felt.Felt]*ContractDiffProof, len(proof.Contracts))
+	for _, contractProof := range proof.Contracts {
+		if contractProof == nil || contractProof.Address == nil {
+			return errors.New("malformed contract proof")
+		}
+		contractProofs[*contractProof.Address] = contractProof
+	}
+
+	for _, addr := range stateDiffContracts(stateDiff) {
+		contractProof, found := contractProofs[*addr]
+		if !found {
+			return fmt.Errorf("missing proof of contract %s", addr)
+		}
+		if err := verifyContractDiffProof(contractProof, stateDiff, proof.OldContractsRoot,
+			proof.NewContractsRoot); err != nil {
+			return fmt.Errorf("contract %s: %v", addr, err)
+		}
+	}
+
+	classProofs := make(map[felt.Felt][]trie.ProofNode, len(proof.Classes))
+	for _, classProof := range proof.Classes {
+		if classProof == nil || classProof.ClassHash == nil {
+			return errors.New("malformed class proof")
+		}
+		classProofs[*classProof.ClassHash] = classProof.Proof
+	}
+	for classHash, compiledClassHash := range stateDiff.DeclaredV1Classes {
+		classProof, found := classProofs[classHash]
+		if !found {
+			return fmt.Errorf("missing proof of class %s", classHash.String())
+		}
+		leaf := crypto.Poseidon(leafVersion, compiledClassHash)
+		if !trie.VerifyProof(proof.NewClassesRoot, &classHash, leaf, globalTrieHeight, classProof, crypto.Poseidon) {
+			return fmt.Errorf("invalid proof of class %s", classHash.String())
+		}
+	}
+	return nil
+}
+
+func verifyContractDiffProof(proof *ContractDiffProof, stateDiff *StateDiff, oldContractsRoot,
+	newContractsRoot *felt.Felt,
+) error {
+	addr := proof.Address
+	if proof.Old == nil || proof.New == nil {
+		return errors.New("missing leaf proof")
+	}
+	if err := verifyContractLeafProof(proof.Old, addr, oldContractsRoot); err != nil {
+		return fmt.Errorf("old leaf: %v", err)
+	}
+	if err := verifyContractLeafProof(proof.New, addr, newContractsRoot); err != nil {
+		return fmt.Errorf("new leaf: %v", err)
+	}
+
+	classHash := stateDiff.DeployedContracts[*addr]
+	if replacedClassHash, found := stateDiff.ReplacedClasses[*addr]; found {
+		classHash = replacedClassHash
+	}
+	if proof.New.ClassHash == nil || (classHash != nil && !classHash.Equal(proof.New.ClassHash)) {
+		return errors.New("class hash does not match the state diff")
+	}
+	if nonce, found := stateDiff.Nonces[*addr]; found && !nonce.Equal(proof.New.Nonce) {
+		return errors.New("nonce does not match the state diff")
+	}
+
+	storageProofs := make(map[felt.Felt]*StorageDiffProof, len(proof.Storage))
+	for _, storageProof := range proof.Storage {
+		if storageProof == nil || storageProof.Key == nil || storageProof.OldValue == nil {
+			return errors.New("malformed storage proof")
+		}
+		storageProofs[*storageProof.Key] = storageProof
+	}
+
+	oldStorageRoot := proof.Old.StorageRoot
+	if oldStorageRoot == nil {
+		oldStorageRoot = &felt.Zero
+	}
+	for key, value := range stateDiff.StorageDiffs[*addr] {
+		storageProof, found := storageProofs[key]
+		if !found {
+			return fmt.Errorf("missing proof of storage location %s", key.String())
+		}
+		if !trie.VerifyProof(oldStorageRoot, &key, storageProof.OldValue, contractStorageTrieHeight,
+			storageProof.OldProof, crypto.Pedersen) {
+			return fmt.Errorf("invalid proof of the old value of storage location %s", key.String())
+		}
+		if !trie.VerifyProof(proof.New.StorageRoot, &key, value, contractStorageTrieHeight,
+			storageProof.NewProof, crypto.Pedersen) {
+			return fmt.Errorf("invalid proof of the new value of storage location %s", key.String())
+		}
+	}
+	return nil
+}
+
+func verifyContractLeafProof(leaf *ContractLeafProof, addr, contractsRoot *felt.Felt) error {
+	value := &felt.Zero
+	if leaf.ClassHash != nil {
+		if leaf.Nonce == nil || leaf.StorageRoot == nil {
+			return errors.New("missing nonce or storage root")
+		}
+		value = calculateContractCommitment(leaf.StorageRoot, leaf.ClassHash, leaf.Nonce)
+	}
+	if !trie.VerifyProof(contractsRoot, addr, value, globalTrieHeight, leaf.Proof, crypto.Pedersen) {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
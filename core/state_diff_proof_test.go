@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/encoder"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiffProof(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	state := core.NewState(txn).WithArchive(true)
+
+	var updates []*core.StateUpdate
+	for number := uint64(0); number < 3; number++ {
+		su, err := gw.StateUpdate(context.Background(), number)
+		require.NoError(t, err)
+		require.NoError(t, state.Update(number, su, nil))
+		updates = append(updates, su)
+	}
+
+	prove := func(t *testing.T, number uint64) *core.StateDiffProof {
+		newTries, err := state.TriesAt(number)
+		require.NoError(t, err)
+		newState := core.NewStateSnapshot(state, number)
+
+		var (
+			oldState core.StateReader
+			oldTries core.TrieReader
+		)
+		if number > 0 {
+			oldTries, err = state.TriesAt(number - 1)
+			require.NoError(t, err)
+			oldState = core.NewStateSnapshot(state, number-1)
+		}
+
+		proof, err := core.NewStateDiffProof(updates[number].StateDiff, oldState, oldTries, newState, newTries)
+		require.NoError(t, err)
+
+		// proofs are sent to peers encoded
+		proofBytes, err := encoder.Marshal(proof)
+		require.NoError(t, err)
+		var decoded core.StateDiffProof
+		require.NoError(t, encoder.Unmarshal(proofBytes, &decoded))
+		return &decoded
+	}
+
+	for number, su := range updates {
+		proof := prove(t, uint64(number))
+		assert.NoError(t, core.VerifyStateDiffProof(proof, su.StateDiff, su.OldRoot, su.NewRoot), "block %d", number)
+	}
+
+	su := updates[1]
+	proof := prove(t, 1)
+
+	t.Run("wrong roots", func(t *testing.T) {
+		assert.ErrorContains(t, core.VerifyStateDiffProof(proof, su.StateDiff, su.NewRoot, su.NewRoot), "old state root")
+		assert.ErrorContains(t, core.VerifyStateDiffProof(proof, su.StateDiff, su.OldRoot, su.OldRoot), "new state root")
+	})
+
+	t.Run("tampered storage value", func(t *testing.T) {
+		tampered := copyStateDiff(su.StateDiff)
+		for _, diff := range tampered.StorageDiffs {
+			for key, value := range diff {
+				diff[key] = new(felt.Felt).Add(value, new(felt.Felt).SetUint64(1))
+				break
+			}
+			break
+		}
+		assert.ErrorContains(t, core.VerifyStateDiffProof(proof, tampered, su.OldRoot, su.NewRoot), "invalid proof")
+	})
+
+	t.Run("tampered class hash", func(t *testing.T) {
+		tampered := copyStateDiff(su.StateDiff)
+		for addr := range tampered.DeployedContracts {
+			tampered.DeployedContracts[addr] = new(felt.Felt).SetUint64(1)
+			break
+		}
+		assert.ErrorContains(t, core.VerifyStateDiffProof(proof, tampered, su.OldRoot, su.NewRoot), "class hash")
+	})
+
+	t.Run("unproven contract", func(t *testing.T) {
+		tampered := copyStateDiff(su.StateDiff)
+		tampered.Nonces[*new(felt.Felt).SetUint64(1)] = new(felt.Felt).SetUint64(1)
+		assert.ErrorContains(t, core.VerifyStateDiffProof(proof, tampered, su.OldRoot, su.NewRoot), "missing proof")
+	})
+
+	t.Run("tampered proof", func(t *testing.T) {
+		tampered := prove(t, 1)
+		for _, contract := range tampered.Contracts {
+			if len(contract.Storage) > 0 {
+				contract.Storage[0].OldValue = new(felt.Felt).SetUint64(1)
+				break
+			}
+		}
+		assert.ErrorContains(t, core.VerifyStateDiffProof(tampered, su.StateDiff, su.OldRoot, su.NewRoot), "old value")
+	})
+}
+
+func copyStateDiff(diff *core.StateDiff) *core.StateDiff {
+	cp := &core.StateDiff{
+		StorageDiffs:      make(map[felt.Felt]map[felt.Felt]*felt.Felt, len(diff.StorageDiffs)),
+		Nonces:            make(map[felt.Felt]*felt.Felt, len(diff.Nonces)),
+		DeployedContracts: make(map[felt.Felt]*felt.Felt, len(diff.DeployedContracts)),
+		DeclaredV0Classes: diff.DeclaredV0Classes,
+		DeclaredV1Classes: diff.DeclaredV1Classes,
+		ReplacedClasses:   diff.ReplacedClasses,
+	}
+	for addr, storage := range diff.StorageDiffs {
+		cp.StorageDiffs[addr] = make(map[felt.Felt]*felt.Felt, len(storage))
+		for key, value := range storage {
+			cp.StorageDiffs[addr][key] = value
+		}
+	}
+	for addr, nonce := range diff.Nonces {
+		cp.Nonces[addr] = nonce
+	}
+	for addr, classHash := range diff.DeployedContracts {
+		cp.DeployedContracts[addr] = classHash
+	}
+	return cp
+}
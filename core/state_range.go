@@ -0,0 +1,210 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// StorageEntry is a storage location of a contract and the value it holds
+type StorageEntry struct {
+	Key   *felt.Felt
+	Value *felt.Felt
+}
+
+// ContractEntry is a deployed contract along with its class hash and nonce
+type ContractEntry struct {
+	Address   *felt.Felt
+	ClassHash *felt.Felt
+	Nonce     *felt.Felt
+}
+
+// ContractStorageRange returns up to limit non-zero storage locations of the given contract, in
+// ascending order starting from the location start. If there are more, the next location is returned.
+func (s *State) ContractStorageRange(addr, start *felt.Felt, limit uint64) ([]StorageEntry, *felt.Felt, error) {
+	if _, err := s.contractDeploymentHeight(addr); err != nil {
+		return nil, nil, err
+	}
+
+	it, err := s.contractStorageIterator(addr, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []StorageEntry
+	for ; it.Valid(); it.Next() {
+		if uint64(len(entries)) == limit {
+			return entries, it.Key(), nil
+		}
+		entries = append(entries, StorageEntry{Key: it.Key(), Value: it.Value()})
+	}
+	return entries, nil, it.Err()
+}
+
+// ContractStorageRangeAt returns up to limit non-zero storage locations of the given contract as of the
+// given block, in ascending order starting from the location start. If there are more, the next
+// location is returned.
+//
+// The storage trie only holds the latest values, so the locations that changed after the block are
+// found in the storage history, which is ordered by location as well, and merged with the trie's.
+func (s *State) ContractStorageRangeAt(addr, start *felt.Felt, blockNumber, limit uint64) ([]StorageEntry, *felt.Felt, error) {
+	if deployed, err := s.ContractIsAlreadyDeployedAt(addr, blockNumber); err != nil {
+		return nil, nil, err
+	} else if !deployed {
+		return nil, nil, db.ErrKeyNotFound
+	}
+
+	trieIt, err := s.contractStorageIterator(addr, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logIt, err := s.txn.NewIterator()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logPrefix := db.ContractStorageHistory.Key(addr.Marshal())
+	logIt.Seek(db.ContractStorageHistory.Key(addr.Marshal(), start.Marshal()))
+
+	var (
+		entries []StorageEntry
+		next    *felt.Felt
+	)
+	for {
+		var logLocation *felt.Felt
+		if logIt.Valid() && bytes.HasPrefix(logIt.Key(), logPrefix) {
+			logLocation = new(felt.Felt).SetBytes(logIt.Key()[len(logPrefix) : len(logPrefix)+felt.Bytes])
+		}
+
+		location, inTrie := logLocation, false
+		if trieIt.Valid() && (location == nil || trieIt.Key().Cmp(location) <= 0) {
+			location, inTrie = trieIt.Key(), true
+		}
+		if location == nil {
+			break
+		} else if uint64(len(entries)) == limit {
+			next = location
+			break
+		}
+
+		value, valueErr := s.ContractStorageAt(addr, location, blockNumber)
+		if errors.Is(valueErr, ErrCheckHeadState) {
+			value, valueErr = &felt.Zero, nil
+			if inTrie {
+				value = trieIt.Value()
+			}
+		}
+		if valueErr != nil {
+			return nil, nil, utils.RunAndWrapOnError(logIt.Close, valueErr)
+		}
+
+		if !value.IsZero() {
+			entries = append(entries, StorageEntry{Key: location, Value: value})
+		}
+
+		if inTrie {
+			trieIt.Next()
+		}
+		if logLocation != nil && logLocation.Equal(location) {
+			locationPrefix := storageLogKey(addr, location)
+			for logIt.Next() {
+				if !bytes.HasPrefix(logIt.Key(), locationPrefix) {
+					break
+				}
+			}
+		}
+	}
+
+	if err = trieIt.Err(); err != nil {
+		return nil, nil, utils.RunAndWrapOnError(logIt.Close, err)
+	}
+	return entries, next, logIt.Close()
+}
+
+func (s *State) contractStorageIterator(addr, start *felt.Felt) (*trie.Iterator, error) {
+	contractStorage, err := storage(addr, s.txn)
+	if err != nil {
+		return nil, err
+	}
+
+	it := contractStorage.NewIterator()
+	it.Seek(start)
+	return it, it.Err()
+}
+
+// ContractsRange returns up to limit deployed contracts in ascending address order starting from the
+// address start. If there are more, the next address is returned.
+func (s *State) ContractsRange(start *felt.Felt, limit uint64) ([]ContractEntry, *felt.Felt, error) {
+	return s.contractsRange(start, limit, func(addr *felt.Felt) (*ContractEntry, error) {
+		classHash, err := s.ContractClassHash(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce, err := s.ContractNonce(addr)
+		if err != nil {
+			return nil, err
+		}
+		return &ContractEntry{Address: addr, ClassHash: classHash, Nonce: nonce}, nil
+	})
+}
+
+// ContractsRangeAt returns up to limit contracts that were deployed as of the given block, in ascending
+// address order starting from the address start. If there are more, the next address is returned.
+func (s *State) ContractsRangeAt(start *felt.Felt, blockNumber, limit uint64) ([]ContractEntry, *felt.Felt, error) {
+	snapshot := NewStateSnapshot(s, blockNumber)
+	return s.contractsRange(start, limit, func(addr *felt.Felt) (*ContractEntry, error) {
+		classHash, err := snapshot.ContractClassHash(addr)
+		if err != nil {
+			if errors.Is(err, db.ErrKeyNotFound) {
+				// deployed after the block
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		nonce, err := snapshot.ContractNonce(addr)
+		if err != nil {
+			return nil, err
+		}
+		return &ContractEntry{Address: addr, ClassHash: classHash, Nonce: nonce}, nil
+	})
+}
+
+// contractsRange walks the global state trie, using entry to build the entry of each contract. A nil entry
+// skips the contract.
+func (s *State) contractsRange(start *felt.Felt, limit uint64,
+	entry func(addr *felt.Felt) (*ContractEntry, error),
+) ([]ContractEntry, *felt.Felt, error) {
+	stateTrie, closer, err := s.storage()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []ContractEntry
+	it := stateTrie.NewIterator()
+	for it.Seek(start); it.Valid(); it.Next() {
+		addr := it.Key()
+		contract, entryErr := entry(addr)
+		if entryErr != nil {
+			return nil, nil, utils.RunAndWrapOnError(closer, entryErr)
+		} else if contract == nil {
+			continue
+		}
+
+		if uint64(len(entries)) == limit {
+			return entries, addr, closer()
+		}
+		entries = append(entries, *contract)
+	}
+
+	if err = it.Err(); err != nil {
+		return nil, nil, utils.RunAndWrapOnError(closer, err)
+	}
+	return entries, nil, closer()
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/NethermindEth/juno/clients/feeder"
@@ -210,6 +211,35 @@ func TestContractClassHash(t *testing.T) {
 
 		assert.Equal(t, utils.HexToFelt(t, "0x1337"), gotClassHash)
 	})
+
+	t.Run("class hash changes", func(t *testing.T) {
+		addr := new(felt.Felt).Set(&su1FirstDeployedAddress)
+		deployed := su1.StateDiff.DeployedContracts[su1FirstDeployedAddress]
+
+		changes, next, err := state.ContractClassHashChanges(addr, 0, 2, 10)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []core.ValueChange{
+			{BlockNumber: 1, OldValue: &felt.Zero, NewValue: deployed},
+			{BlockNumber: 2, OldValue: deployed, NewValue: utils.HexToFelt(t, "0x1337")},
+		}, changes)
+
+		changes, next, err = state.ContractClassHashChanges(addr, 0, 2, 1)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, uint64(2), *next)
+		assert.Len(t, changes, 1)
+
+		changes, next, err = state.ContractClassHashChanges(addr, *next, 2, 1)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []core.ValueChange{
+			{BlockNumber: 2, OldValue: deployed, NewValue: utils.HexToFelt(t, "0x1337")},
+		}, changes)
+
+		_, _, err = state.ContractClassHashChanges(utils.HexToFelt(t, "0xDEADBEEF"), 0, 2, 10)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
 }
 
 func TestNonce(t *testing.T) {
@@ -308,6 +338,39 @@ func TestStateHistory(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, oldValue, utils.HexToFelt(t, "0x22b"))
 	})
+
+	t.Run("should list the changes to a location", func(t *testing.T) {
+		first := core.ValueChange{BlockNumber: 0, OldValue: &felt.Zero, NewValue: utils.HexToFelt(t, "0x22b")}
+		second := core.ValueChange{BlockNumber: 1, OldValue: utils.HexToFelt(t, "0x22b"), NewValue: utils.HexToFelt(t, "0x44")}
+
+		changes, next, err := state.ContractStorageChanges(contractAddr, changedLoc, 0, 1, 10)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []core.ValueChange{first, second}, changes)
+
+		changes, next, err = state.ContractStorageChanges(contractAddr, changedLoc, 0, 0, 10)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []core.ValueChange{first}, changes)
+
+		changes, next, err = state.ContractStorageChanges(contractAddr, changedLoc, 0, 1, 1)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, uint64(1), *next)
+		assert.Equal(t, []core.ValueChange{first}, changes)
+
+		changes, next, err = state.ContractStorageChanges(contractAddr, changedLoc, *next, 1, 1)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []core.ValueChange{second}, changes)
+
+		changes, _, err = state.ContractStorageChanges(contractAddr, utils.HexToFelt(t, "0xDEADBEEF"), 0, 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		_, _, err = state.ContractNonceChanges(utils.HexToFelt(t, "0xDEADBEEF"), 0, 1, 10)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
 }
 
 func TestContractIsDeployedAt(t *testing.T) {
@@ -689,3 +752,230 @@ func TestRevertDeclaredClasses(t *testing.T) {
 	_, err = state.Class(sierraHash)
 	require.ErrorIs(t, err, db.ErrKeyNotFound)
 }
+
+func TestStateRange(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	client := feeder.NewTestClient(t, utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	state := core.NewState(txn)
+	su0, err := gw.StateUpdate(context.Background(), 0)
+	require.NoError(t, err)
+	require.NoError(t, state.Update(0, su0, nil))
+
+	contractAddr := utils.HexToFelt(t, "0x20cfa74ee3564b4cd5435cdace0f9c4d43b939620e4a0bb5076105df0a626c6")
+	storageAt0 := make([]core.StorageEntry, 0, len(su0.StateDiff.StorageDiffs[*contractAddr]))
+	for key, value := range su0.StateDiff.StorageDiffs[*contractAddr] {
+		if !value.IsZero() {
+			storageAt0 = append(storageAt0, core.StorageEntry{Key: new(felt.Felt).Set(&key), Value: value})
+		}
+	}
+	sort.Slice(storageAt0, func(i, j int) bool {
+		return storageAt0[i].Key.Cmp(storageAt0[j].Key) < 0
+	})
+	require.Greater(t, len(storageAt0), 2)
+
+	// change the first location, clear the second and add a new one
+	newAddr := utils.HexToFelt(t, "0xDEADBEEF")
+	su1 := &core.StateUpdate{
+		OldRoot: su0.NewRoot,
+		StateDiff: &core.StateDiff{
+			StorageDiffs: map[felt.Felt]map[felt.Felt]*felt.Felt{
+				*contractAddr: {
+					*storageAt0[0].Key: utils.HexToFelt(t, "0x44"),
+					*storageAt0[1].Key: &felt.Zero,
+					felt.Zero:          utils.HexToFelt(t, "0x55"),
+				},
+			},
+			DeployedContracts: map[felt.Felt]*felt.Felt{
+				*newAddr: utils.HexToFelt(t, "0x1337"),
+			},
+			Nonces: map[felt.Felt]*felt.Felt{
+				*contractAddr: utils.HexToFelt(t, "0x1"),
+			},
+		},
+	}
+	_, err = state.Apply(1, su1, nil)
+	require.NoError(t, err)
+
+	collect := func(t *testing.T, limit uint64, get func(start *felt.Felt, limit uint64) ([]core.StorageEntry, *felt.Felt, error),
+	) []core.StorageEntry {
+		var all []core.StorageEntry
+		start := &felt.Zero
+		for {
+			entries, next, err := get(start, limit)
+			require.NoError(t, err)
+			all = append(all, entries...)
+			if next == nil {
+				return all
+			}
+			require.Len(t, entries, int(limit))
+			start = next
+		}
+	}
+
+	t.Run("storage at head", func(t *testing.T) {
+		expected := append([]core.StorageEntry{
+			{Key: &felt.Zero, Value: utils.HexToFelt(t, "0x55")},
+			{Key: storageAt0[0].Key, Value: utils.HexToFelt(t, "0x44")},
+		}, storageAt0[2:]...)
+
+		for _, limit := range []uint64{1, 2, 100} {
+			assert.Equal(t, expected, collect(t, limit, func(start *felt.Felt, limit uint64) ([]core.StorageEntry, *felt.Felt, error) {
+				return state.ContractStorageRange(contractAddr, start, limit)
+			}))
+		}
+	})
+
+	t.Run("storage at an older block", func(t *testing.T) {
+		for _, limit := range []uint64{1, 2, 100} {
+			assert.Equal(t, storageAt0, collect(t, limit, func(start *felt.Felt, limit uint64) ([]core.StorageEntry, *felt.Felt, error) {
+				return state.ContractStorageRangeAt(contractAddr, start, 0, limit)
+			}))
+		}
+	})
+
+	t.Run("storage of a contract that is not deployed", func(t *testing.T) {
+		_, _, err := state.ContractStorageRange(utils.HexToFelt(t, "0x1234"), &felt.Zero, 10)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+		_, _, err = state.ContractStorageRangeAt(newAddr, &felt.Zero, 0, 10)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("contracts", func(t *testing.T) {
+		findContract := func(entries []core.ContractEntry, addr *felt.Felt) *core.ContractEntry {
+			for i := range entries {
+				if entries[i].Address.Equal(addr) {
+					return &entries[i]
+				}
+			}
+			return nil
+		}
+
+		head, next, err := state.ContractsRange(&felt.Zero, 100)
+		require.NoError(t, err)
+		require.Nil(t, next)
+		require.Len(t, head, len(su0.StateDiff.DeployedContracts)+1)
+		assert.Equal(t, &core.ContractEntry{
+			Address:   newAddr,
+			ClassHash: utils.HexToFelt(t, "0x1337"),
+			Nonce:     &felt.Zero,
+		}, findContract(head, newAddr))
+		assert.Equal(t, utils.HexToFelt(t, "0x1"), findContract(head, contractAddr).Nonce)
+
+		atGenesis, next, err := state.ContractsRangeAt(&felt.Zero, 0, 100)
+		require.NoError(t, err)
+		require.Nil(t, next)
+		require.Len(t, atGenesis, len(su0.StateDiff.DeployedContracts))
+		assert.Nil(t, findContract(atGenesis, newAddr))
+		assert.Equal(t, &felt.Zero, findContract(atGenesis, contractAddr).Nonce)
+
+		firstPage, next, err := state.ContractsRangeAt(&felt.Zero, 0, 1)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, atGenesis[:1], firstPage)
+		assert.Equal(t, atGenesis[1].Address, next)
+	})
+}
+
+func TestClassIndex(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+	state := core.NewState(txn)
+
+	transfer, constructor := utils.HexToFelt(t, "0x5"), utils.HexToFelt(t, "0x6")
+	cairo0Hash, cairo1Hash := utils.HexToFelt(t, "0xC0"), utils.HexToFelt(t, "0xC1")
+	cairo1Class := &core.Cairo1Class{}
+	cairo1Class.EntryPoints.External = []core.SierraEntryPoint{{Selector: transfer}}
+	cairo1Class.EntryPoints.Constructor = []core.SierraEntryPoint{{Selector: constructor}}
+	classes := map[felt.Felt]core.Class{
+		*cairo0Hash: &core.Cairo0Class{Externals: []core.EntryPoint{{Selector: transfer, Offset: &felt.Zero}}},
+		*cairo1Hash: cairo1Class,
+	}
+	for _, class := range classes {
+		if err = encoder.RegisterType(reflect.TypeOf(class)); err != nil {
+			require.Contains(t, err.Error(), "already exists in TagSet")
+		}
+	}
+
+	addr1, addr2 := utils.HexToFelt(t, "0xA1"), utils.HexToFelt(t, "0xA2")
+	su0 := &core.StateUpdate{
+		OldRoot: &felt.Zero,
+		StateDiff: &core.StateDiff{
+			DeclaredV0Classes: []*felt.Felt{cairo0Hash},
+			DeclaredV1Classes: map[felt.Felt]*felt.Felt{*cairo1Hash: cairo1Hash},
+			DeployedContracts: map[felt.Felt]*felt.Felt{*addr1: cairo0Hash, *addr2: cairo0Hash},
+		},
+	}
+	su0.NewRoot, err = state.Apply(0, su0, classes)
+	require.NoError(t, err)
+
+	contracts := func(classHash *felt.Felt) []*felt.Felt {
+		addresses, next, err := state.ContractsByClassHash(classHash, &felt.Zero, 10)
+		require.NoError(t, err)
+		require.Nil(t, next)
+		return addresses
+	}
+
+	t.Run("deployed contracts", func(t *testing.T) {
+		assert.Equal(t, []*felt.Felt{addr1, addr2}, contracts(cairo0Hash))
+		assert.Empty(t, contracts(cairo1Hash))
+
+		addresses, next, err := state.ContractsByClassHash(cairo0Hash, &felt.Zero, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []*felt.Felt{addr1}, addresses)
+		assert.Equal(t, addr2, next)
+	})
+
+	t.Run("declared classes", func(t *testing.T) {
+		classHashes, next, err := state.ClassHashesBySelector(transfer, &felt.Zero, 10)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []*felt.Felt{cairo0Hash, cairo1Hash}, classHashes)
+
+		classHashes, _, err = state.ClassHashesBySelector(constructor, &felt.Zero, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []*felt.Felt{cairo1Hash}, classHashes)
+
+		classHashes, next, err = state.ClassHashesBySelector(transfer, cairo1Hash, 10)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []*felt.Felt{cairo1Hash}, classHashes)
+	})
+
+	su1 := &core.StateUpdate{
+		OldRoot: su0.NewRoot,
+		StateDiff: &core.StateDiff{
+			ReplacedClasses: map[felt.Felt]*felt.Felt{*addr1: cairo1Hash},
+		},
+	}
+	su1.NewRoot, err = state.Apply(1, su1, nil)
+	require.NoError(t, err)
+
+	t.Run("replaced class", func(t *testing.T) {
+		assert.Equal(t, []*felt.Felt{addr2}, contracts(cairo0Hash))
+		assert.Equal(t, []*felt.Felt{addr1}, contracts(cairo1Hash))
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		require.NoError(t, state.Revert(1, su1))
+		assert.Equal(t, []*felt.Felt{addr1, addr2}, contracts(cairo0Hash))
+		assert.Empty(t, contracts(cairo1Hash))
+
+		require.NoError(t, state.Revert(0, su0))
+		assert.Empty(t, contracts(cairo0Hash))
+		classHashes, _, err := state.ClassHashesBySelector(transfer, &felt.Zero, 10)
+		require.NoError(t, err)
+		assert.Empty(t, classHashes)
+	})
+}
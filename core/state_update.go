@@ -140,6 +140,77 @@ func (d *StateDiff) Commitment() *felt.Felt {
 	return commitmentDigest.Finish()
 }
 
+// Length is the number of entries in the state diff: storage updates, nonce updates, deployed contracts,
+// declared classes and replaced classes.
+func (d *StateDiff) Length() uint64 {
+	length := len(d.Nonces) + len(d.DeployedContracts) + len(d.DeclaredV0Classes) + len(d.DeclaredV1Classes) +
+		len(d.ReplacedClasses)
+	for _, diffs := range d.StorageDiffs {
+		length += len(diffs)
+	}
+	return uint64(length)
+}
+
+// Hash computes the state diff commitment included in the hash of blocks following Starknet 0.13.2.
+// Unlike [StateDiff.Commitment], it hashes a single flat list:
+//
+//	["STARKNET_STATE_DIFF0", number_of_updated_contracts, address_1, class_hash_1, ...,
+//		number_of_declared_classes, class_hash_1, compiled_class_hash_1, ...,
+//		number_of_old_declared_classes, class_hash_1, ..., 1, 0,
+//		number_of_updated_contracts, contract_address_1, number_of_updates_in_contract, key_1, value_1, ...,
+//		number_of_updated_nonces, address_1, nonce_1, ...]
+//
+// where the updated contracts are the deployed contracts and the contracts with a replaced class, and
+// 1, 0 are the number of data availability modes and the L1 mode.
+func (d *StateDiff) Hash() *felt.Felt {
+	var digest crypto.PoseidonDigest
+	digest.Update(new(felt.Felt).SetBytes([]byte("STARKNET_STATE_DIFF0")))
+
+	// The sequencer guarantees that a contract is not both deployed and replaced in the same state diff
+	updatedContracts := make(map[felt.Felt]*felt.Felt, len(d.DeployedContracts)+len(d.ReplacedClasses))
+	for addr, classHash := range d.DeployedContracts {
+		updatedContracts[addr] = classHash
+	}
+	for addr, classHash := range d.ReplacedClasses {
+		updatedContracts[addr] = classHash
+	}
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(updatedContracts))))
+	for _, addr := range sortedFeltKeys(updatedContracts) {
+		digest.Update(&addr, updatedContracts[addr])
+	}
+
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(d.DeclaredV1Classes))))
+	for _, classHash := range sortedFeltKeys(d.DeclaredV1Classes) {
+		digest.Update(&classHash, d.DeclaredV1Classes[classHash])
+	}
+
+	declaredV0Classes := make([]*felt.Felt, len(d.DeclaredV0Classes))
+	copy(declaredV0Classes, d.DeclaredV0Classes)
+	sort.Slice(declaredV0Classes, func(i, j int) bool {
+		return declaredV0Classes[i].Cmp(declaredV0Classes[j]) == -1
+	})
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(declaredV0Classes))))
+	digest.Update(declaredV0Classes...)
+
+	digest.Update(new(felt.Felt).SetUint64(1), &felt.Zero)
+
+	storageDiffAddrs := sortedFeltKeys(d.StorageDiffs)
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(storageDiffAddrs))))
+	for _, addr := range storageDiffAddrs {
+		diffKeys := sortedFeltKeys(d.StorageDiffs[addr])
+		digest.Update(&addr, new(felt.Felt).SetUint64(uint64(len(diffKeys))))
+		for _, key := range diffKeys {
+			digest.Update(&key, d.StorageDiffs[addr][key])
+		}
+	}
+
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(d.Nonces))))
+	for _, addr := range sortedFeltKeys(d.Nonces) {
+		digest.Update(&addr, d.Nonces[addr])
+	}
+	return digest.Finish()
+}
+
 func sortedFeltKeys[V any](m map[felt.Felt]V) []felt.Felt {
 	keys := make([]felt.Felt, 0, len(m))
 	for addr := range m {
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/stretchr/testify/assert"
@@ -42,3 +45,48 @@ func TestStateDiffCommitment(t *testing.T) {
 		assert.Equal(t, utils.HexToFelt(t, test.expected), commitment)
 	}
 }
+
+func TestStateDiffHash(t *testing.T) {
+	prefix := new(felt.Felt).SetBytes([]byte("STARKNET_STATE_DIFF0"))
+	felts := func(values ...uint64) []*felt.Felt {
+		f := make([]*felt.Felt, 0, len(values))
+		for _, v := range values {
+			f = append(f, new(felt.Felt).SetUint64(v))
+		}
+		return f
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		stateDiff := core.EmptyStateDiff()
+		assert.Equal(t, uint64(0), stateDiff.Length())
+		assert.Equal(t, crypto.PoseidonArray(append([]*felt.Felt{prefix}, felts(0, 0, 0, 1, 0, 0, 0)...)...), stateDiff.Hash())
+	})
+
+	t.Run("entries are sorted", func(t *testing.T) {
+		f := func(v uint64) *felt.Felt { return new(felt.Felt).SetUint64(v) }
+		stateDiff := &core.StateDiff{
+			StorageDiffs: map[felt.Felt]map[felt.Felt]*felt.Felt{
+				*f(9): {*f(2): f(20), *f(1): f(10)},
+				*f(8): {*f(3): f(30)},
+			},
+			Nonces:            map[felt.Felt]*felt.Felt{*f(9): f(1), *f(7): f(2)},
+			DeployedContracts: map[felt.Felt]*felt.Felt{*f(6): f(60)},
+			DeclaredV0Classes: []*felt.Felt{f(41), f(40)},
+			DeclaredV1Classes: map[felt.Felt]*felt.Felt{*f(51): f(510), *f(50): f(500)},
+			ReplacedClasses:   map[felt.Felt]*felt.Felt{*f(5): f(61)},
+		}
+		assert.Equal(t, uint64(11), stateDiff.Length())
+
+		expected := append([]*felt.Felt{prefix}, felts(
+			2, 5, 61, 6, 60, // updated contracts
+			2, 50, 500, 51, 510, // declared classes
+			2, 40, 41, // deprecated declared classes
+			1, 0, // data availability modes
+			2, 8, 1, 3, 30, 9, 2, 1, 10, 2, 20, // storage diffs
+			2, 7, 2, 9, 1, // nonces
+		)...)
+		assert.Equal(t, crypto.PoseidonArray(expected...), stateDiff.Hash())
+		// the declared classes are not sorted in place
+		assert.Equal(t, []*felt.Felt{f(41), f(40)}, stateDiff.DeclaredV0Classes)
+	})
+}
@@ -99,6 +99,13 @@ type ExecutionResources struct {
 	BuiltinInstanceCounter BuiltinInstanceCounter
 	MemoryHoles            uint64
 	Steps                  uint64
+	// TotalGasConsumed is only reported since Starknet 0.13.2
+	TotalGasConsumed *GasConsumed
+}
+
+type GasConsumed struct {
+	L1Gas     uint64
+	L1DataGas uint64
 }
 
 type BuiltinInstanceCounter struct {
@@ -611,11 +618,17 @@ func VerifyTransactions(txs []Transaction, n utils.Network, protocolVersion stri
 
 const commitmentTrieHeight = 64
 
+var (
+	// transactions commit to their signatures since v0_11_1
+	v0_11_1 = semver.MustParse("0.11.1")
+	// blocks are hashed with Poseidon and commit to receipts and state diffs since v0_13_2
+	v0_13_2 = semver.MustParse("0.13.2")
+)
+
 // transactionCommitment is the root of a height 64 binary Merkle Patricia tree of the
 // transaction hashes and signatures in a block.
 func transactionCommitment(transactions []Transaction, protocolVersion string) (*felt.Felt, error) {
 	var commitment *felt.Felt
-	v0_11_1 := semver.MustParse("0.11.1")
 	return commitment, trie.RunOnTempTrie(commitmentTrieHeight, func(trie *trie.Trie) error {
 		blockVersion, err := ParseBlockVersion(protocolVersion)
 		if err != nil {
@@ -623,17 +636,7 @@ func transactionCommitment(transactions []Transaction, protocolVersion string) (
 		}
 
 		for i, transaction := range transactions {
-			signatureHash := crypto.PedersenArray()
-
-			// blockVersion >= 0.11.1
-			if blockVersion.Compare(v0_11_1) != -1 {
-				signatureHash = crypto.PedersenArray(transaction.Signature()...)
-			} else if _, ok := transaction.(*InvokeTransaction); ok {
-				signatureHash = crypto.PedersenArray(transaction.Signature()...)
-			}
-
-			if _, err = trie.Put(new(felt.Felt).SetUint64(uint64(i)),
-				crypto.Pedersen(transaction.Hash(), signatureHash)); err != nil {
+			if _, err = trie.Put(new(felt.Felt).SetUint64(uint64(i)), transactionLeaf(transaction, blockVersion)); err != nil {
 				return err
 			}
 		}
@@ -646,6 +649,50 @@ func transactionCommitment(transactions []Transaction, protocolVersion string) (
 	})
 }
 
+// VerifyTransactionCommitment checks the transactions of a block preceding Starknet 0.13.2 against its
+// transaction commitment, for transactions received without the protocol version of their block. Such
+// blocks only differ in whether the signatures of transactions other than invokes are committed to, so the
+// transactions are checked against both.
+func VerifyTransactionCommitment(transactions []Transaction, commitment *felt.Felt) error {
+	for _, protocolVersion := range []string{v0_11_1.String(), ""} {
+		computed, err := transactionCommitment(transactions, protocolVersion)
+		if err != nil {
+			return err
+		}
+		if computed.Equal(commitment) {
+			return nil
+		}
+	}
+	return fmt.Errorf("transactions do not match transaction commitment %s", commitment)
+}
+
+// VerifyEventCommitment checks the events of a block preceding Starknet 0.13.2, in the order they were
+// emitted, against its event commitment
+func VerifyEventCommitment(events []*Event, commitment *felt.Felt) error {
+	computed, err := eventCommitment([]*TransactionReceipt{{Events: events}})
+	if err != nil {
+		return err
+	}
+	if !computed.Equal(commitment) {
+		return fmt.Errorf("events do not match event commitment %s", commitment)
+	}
+	return nil
+}
+
+// transactionLeaf is the hash of the transaction hash and signature. Only the signatures of invoke
+// transactions are committed to before Starknet 0.11.1.
+func transactionLeaf(transaction Transaction, blockVersion *semver.Version) *felt.Felt {
+	signatureHash := crypto.PedersenArray()
+
+	// blockVersion >= 0.11.1
+	if blockVersion.Compare(v0_11_1) != -1 {
+		signatureHash = crypto.PedersenArray(transaction.Signature()...)
+	} else if _, ok := transaction.(*InvokeTransaction); ok {
+		signatureHash = crypto.PedersenArray(transaction.Signature()...)
+	}
+	return crypto.Pedersen(transaction.Hash(), signatureHash)
+}
+
 // ParseBlockVersion computes the block version, defaulting to "0.0.0" for empty strings
 func ParseBlockVersion(protocolVersion string) (*semver.Version, error) {
 	if protocolVersion == "" {
@@ -688,11 +735,7 @@ func eventCommitment(receipts []*TransactionReceipt) (*felt.Felt, error) {
 
 					for _, receipt := range receiptsSliced {
 						for _, event := range receipt.Events {
-							eventHash := crypto.PedersenArray(
-								event.From,
-								crypto.PedersenArray(event.Keys...),
-								crypto.PedersenArray(event.Data...),
-							)
+							eventHash := eventLeaf(event)
 
 							eventTrieKey := new(felt.Felt).SetUint64(curEventIdx)
 							trieMutex.Lock()
@@ -721,6 +764,128 @@ func eventCommitment(receipts []*TransactionReceipt) (*felt.Felt, error) {
 	})
 }
 
+// poseidonCommitment is the root of a height 64 binary Merkle Patricia tree, hashed with Poseidon, of the
+// given leaves keyed by their index. Commitments of blocks following Starknet 0.13.2 are computed this way.
+func poseidonCommitment(leaves []*felt.Felt) (*felt.Felt, error) {
+	var commitment *felt.Felt
+	return commitment, trie.RunOnTempTriePoseidon(commitmentTrieHeight, func(trie *trie.Trie) error {
+		for i, leaf := range leaves {
+			if _, err := trie.Put(new(felt.Felt).SetUint64(uint64(i)), leaf); err != nil {
+				return err
+			}
+		}
+		root, err := trie.Root()
+		if err != nil {
+			return err
+		}
+		commitment = root
+		return nil
+	})
+}
+
+// transactionCommitmentPoseidon computes the transaction commitment of blocks following Starknet 0.13.2
+func transactionCommitmentPoseidon(transactions []Transaction) (*felt.Felt, error) {
+	leaves := make([]*felt.Felt, 0, len(transactions))
+	for _, transaction := range transactions {
+		leaves = append(leaves, transactionLeafPoseidon(transaction))
+	}
+	return poseidonCommitment(leaves)
+}
+
+// transactionLeafPoseidon is the hash of the transaction hash and signature. Unsigned transactions are
+// hashed with a zero signature.
+func transactionLeafPoseidon(transaction Transaction) *felt.Felt {
+	var digest crypto.PoseidonDigest
+	digest.Update(transaction.Hash())
+	if signature := transaction.Signature(); len(signature) > 0 {
+		digest.Update(signature...)
+	} else {
+		digest.Update(&felt.Zero)
+	}
+	return digest.Finish()
+}
+
+// eventCommitmentPoseidon computes the event commitment of blocks following Starknet 0.13.2
+func eventCommitmentPoseidon(receipts []*TransactionReceipt) (*felt.Felt, error) {
+	var leaves []*felt.Felt
+	for _, receipt := range receipts {
+		for _, event := range receipt.Events {
+			leaves = append(leaves, eventLeafPoseidon(event, receipt.TransactionHash))
+		}
+	}
+	return poseidonCommitment(leaves)
+}
+
+// eventLeafPoseidon is the hash of the emitting contract, the hash of the emitting transaction, and the
+// keys and data of the event
+func eventLeafPoseidon(event *Event, transactionHash *felt.Felt) *felt.Felt {
+	var digest crypto.PoseidonDigest
+	digest.Update(event.From, transactionHash, new(felt.Felt).SetUint64(uint64(len(event.Keys))))
+	digest.Update(event.Keys...)
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(event.Data))))
+	digest.Update(event.Data...)
+	return digest.Finish()
+}
+
+// receiptCommitment computes the commitment to the receipts of blocks following Starknet 0.13.2
+func receiptCommitment(receipts []*TransactionReceipt) (*felt.Felt, error) {
+	leaves := make([]*felt.Felt, 0, len(receipts))
+	for _, receipt := range receipts {
+		leaf, err := receipt.hash()
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return poseidonCommitment(leaves)
+}
+
+// hash is the leaf of the receipt in the receipt commitment
+func (r *TransactionReceipt) hash() (*felt.Felt, error) {
+	revertReasonHash := &felt.Zero
+	if r.Reverted {
+		var err error
+		if revertReasonHash, err = crypto.StarknetKeccak([]byte(r.RevertReason)); err != nil {
+			return nil, err
+		}
+	}
+
+	// receipts stored before Starknet 0.13.2 do not report the gas consumed
+	var gasConsumed GasConsumed
+	if r.ExecutionResources != nil && r.ExecutionResources.TotalGasConsumed != nil {
+		gasConsumed = *r.ExecutionResources.TotalGasConsumed
+	}
+
+	return crypto.PoseidonArray(
+		r.TransactionHash,
+		r.Fee,
+		messagesSentHash(r.L2ToL1Message),
+		revertReasonHash,
+		&felt.Zero, // L2 gas consumed
+		new(felt.Felt).SetUint64(gasConsumed.L1Gas),
+		new(felt.Felt).SetUint64(gasConsumed.L1DataGas),
+	), nil
+}
+
+func messagesSentHash(messages []*L2ToL1Message) *felt.Felt {
+	var digest crypto.PoseidonDigest
+	digest.Update(new(felt.Felt).SetUint64(uint64(len(messages))))
+	for _, msg := range messages {
+		digest.Update(msg.From, new(felt.Felt).SetBytes(msg.To.Bytes()), new(felt.Felt).SetUint64(uint64(len(msg.Payload))))
+		digest.Update(msg.Payload...)
+	}
+	return digest.Finish()
+}
+
+// eventLeaf is the hash of the emitting contract, and the keys and data of the event
+func eventLeaf(event *Event) *felt.Felt {
+	return crypto.PedersenArray(
+		event.From,
+		crypto.PedersenArray(event.Keys...),
+		crypto.PedersenArray(event.Data...),
+	)
+}
+
 func EventsBloom(receipts []*TransactionReceipt) *bloom.BloomFilter {
 	filter := bloom.New(eventsBloomLength, eventsBloomHashFuncs)
 
@@ -0,0 +1,142 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// The archive keeps the versions of the nodes and root keys of a trie that blocks overwrote, keyed by the
+// trie prefix, the node key and the number of the overwriting block. An empty version means that the node
+// did not exist before the block. The version a node had at the end of a block is the one kept by the
+// first block after it to overwrite the node, or the current node if no such block exists.
+//
+// Versions are indexed by block number as well, so that the ones kept by a reverted block can be dropped.
+
+var errHistoricalWrite = errors.New("historical trie storage is read-only")
+
+// WithArchive makes the storage keep the version of every node it overwrites as the version that node had
+// before the block with the given number
+func (t *TransactionStorage) WithArchive(blockNumber uint64) *TransactionStorage {
+	t.archive = &blockNumber
+	return t
+}
+
+// WithHistoricalReads makes the storage a read-only view of the trie as of the end of the block with the
+// given number. The trie must have been written in archive mode by every block since.
+func (t *TransactionStorage) WithHistoricalReads(blockNumber uint64) *TransactionStorage {
+	t.asOf = &blockNumber
+	return t
+}
+
+// historyKey returns the key the versions of the node with the given key, or of the root key if it is nil,
+// are kept under
+func (t *TransactionStorage) historyKey(key *Key) []byte {
+	if key == nil {
+		return db.TrieRootKeyHistory.Key(t.prefix)
+	}
+
+	var keyBytes bytes.Buffer
+	if _, err := key.WriteTo(&keyBytes); err != nil {
+		// writing to a bytes.Buffer never fails
+		panic(err)
+	}
+	return db.TrieNodeHistory.Key(t.prefix, keyBytes.Bytes())
+}
+
+func versionKey(historyKey []byte, blockNumber uint64) []byte {
+	return binary.BigEndian.AppendUint64(historyKey, blockNumber)
+}
+
+// archiveVersion keeps the value under dbKey as the version of the node with the given key that the block
+// being written overwrites, unless the block already overwrote the node
+func (t *TransactionStorage) archiveVersion(key *Key, dbKey []byte) error {
+	if t.asOf != nil {
+		return errHistoricalWrite
+	} else if t.archive == nil {
+		return nil
+	}
+
+	historyKey := t.historyKey(key)
+	versionDBKey := versionKey(bytes.Clone(historyKey), *t.archive)
+	err := t.txn.Get(versionDBKey, func([]byte) error { return nil })
+	if err == nil || !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
+	version := []byte{}
+	if err = t.txn.Get(dbKey, func(val []byte) error {
+		version = bytes.Clone(val)
+		return nil
+	}); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
+	if err = t.txn.Set(versionDBKey, version); err != nil {
+		return err
+	}
+	return t.txn.Set(db.TrieHistoryByBlockNumber.Key(binary.BigEndian.AppendUint64(nil, *t.archive), historyKey), []byte{})
+}
+
+// get calls cb with the value under dbKey, or with the version it had as of the block the storage is read
+// at if it is a historical view
+func (t *TransactionStorage) get(key *Key, dbKey []byte, cb func([]byte) error) error {
+	if t.asOf == nil {
+		return t.txn.Get(dbKey, cb)
+	}
+
+	it, err := t.txn.NewIterator()
+	if err != nil {
+		return err
+	}
+
+	// the versions of a node are the only keys prefixed by its history key
+	historyKey := t.historyKey(key)
+	if it.Seek(versionKey(bytes.Clone(historyKey), *t.asOf+1)) && bytes.HasPrefix(it.Key(), historyKey) {
+		version, itErr := it.Value()
+		if err = utils.RunAndWrapOnError(it.Close, itErr); err != nil {
+			return err
+		}
+
+		if len(version) == 0 {
+			return db.ErrKeyNotFound
+		}
+		return cb(version)
+	}
+
+	if err = it.Close(); err != nil {
+		return err
+	}
+	return t.txn.Get(dbKey, cb)
+}
+
+// DeleteArchive drops the versions of trie nodes kept by the block with the given number, which is being
+// reverted
+func DeleteArchive(txn db.Transaction, blockNumber uint64) error {
+	it, err := txn.NewIterator()
+	if err != nil {
+		return err
+	}
+
+	prefix := db.TrieHistoryByBlockNumber.Key(binary.BigEndian.AppendUint64(nil, blockNumber))
+	var keys [][]byte
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		keys = append(keys, bytes.Clone(it.Key()))
+	}
+	if err = it.Close(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err = txn.Delete(versionKey(bytes.Clone(key[len(prefix):]), blockNumber)); err != nil {
+			return err
+		}
+		if err = txn.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
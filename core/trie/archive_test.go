@@ -0,0 +1,124 @@
+package trie_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	txn, err := pebble.NewMemTest(t).NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, txn.Discard()) })
+
+	prefix := []byte{1, 2, 3}
+	const height = 251
+	// blocks map keys to values, where a zero value deletes the key
+	blocks := []map[uint64]uint64{
+		{1: 10, 2: 20, 5: 50},
+		{2: 21, 7: 70},
+		{1: 0, 5: 51, 100: 1000},
+		{2: 0, 5: 0, 7: 0, 100: 0},
+	}
+
+	apply := func(t *testing.T, tTxn *trie.TransactionStorage, block map[uint64]uint64) *felt.Felt {
+		tempTrie, err := trie.NewTriePedersen(tTxn, height)
+		require.NoError(t, err)
+		for key, value := range block {
+			_, err = tempTrie.Put(new(felt.Felt).SetUint64(key), new(felt.Felt).SetUint64(value))
+			require.NoError(t, err)
+		}
+		root, err := tempTrie.Root()
+		require.NoError(t, err)
+		return root
+	}
+
+	var (
+		roots  []*felt.Felt
+		states []map[uint64]uint64
+	)
+	state := make(map[uint64]uint64)
+	for number, block := range blocks {
+		roots = append(roots, apply(t, trie.NewTransactionStorage(txn, prefix).WithArchive(uint64(number)), block))
+
+		next := make(map[uint64]uint64)
+		for key, value := range state {
+			next[key] = value
+		}
+		for key, value := range block {
+			next[key] = value
+		}
+		states, state = append(states, next), next
+	}
+	assert.True(t, roots[len(roots)-1].IsZero())
+
+	assertHistory := func(t *testing.T, number uint64, root *felt.Felt, state map[uint64]uint64) {
+		tTxn := trie.NewTransactionStorage(txn, prefix).WithHistoricalReads(number)
+		historicalTrie, err := trie.NewTriePedersen(tTxn, height)
+		require.NoError(t, err)
+
+		historicalRoot, err := historicalTrie.Root()
+		require.NoError(t, err)
+		assert.Equal(t, root, historicalRoot)
+
+		for key := range states[len(states)-1] {
+			keyFelt := new(felt.Felt).SetUint64(key)
+			value, err := historicalTrie.Get(keyFelt)
+			require.NoError(t, err)
+			assert.Equal(t, new(felt.Felt).SetUint64(state[key]), value, key)
+
+			proof, err := historicalTrie.Prove(keyFelt)
+			require.NoError(t, err)
+			assert.True(t, trie.VerifyProof(root, keyFelt, value, height, proof, crypto.Pedersen), key)
+		}
+
+		_, err = historicalTrie.Put(new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(3))
+		assert.Error(t, err)
+	}
+
+	for number := range blocks {
+		t.Run(fmt.Sprintf("block %d", number), func(t *testing.T) {
+			assertHistory(t, uint64(number), roots[number], states[number])
+		})
+	}
+
+	t.Run("revert and replace the last block", func(t *testing.T) {
+		last := uint64(len(blocks) - 1)
+		reverse := make(map[uint64]uint64)
+		for key := range blocks[last] {
+			reverse[key] = states[last-1][key]
+		}
+		assert.Equal(t, roots[last-1], apply(t, trie.NewTransactionStorage(txn, prefix), reverse))
+		require.NoError(t, trie.DeleteArchive(txn, last))
+
+		it, err := txn.NewIterator()
+		require.NoError(t, err)
+		indexPrefix := db.TrieHistoryByBlockNumber.Key(binary.BigEndian.AppendUint64(nil, last))
+		assert.False(t, it.Seek(indexPrefix) && bytes.HasPrefix(it.Key(), indexPrefix))
+		require.NoError(t, it.Close())
+
+		replacement := map[uint64]uint64{7: 71, 1: 11}
+		root := apply(t, trie.NewTransactionStorage(txn, prefix).WithArchive(last), replacement)
+		state := make(map[uint64]uint64)
+		for key, value := range states[last-1] {
+			state[key] = value
+		}
+		for key, value := range replacement {
+			state[key] = value
+		}
+
+		for number := uint64(0); number < last; number++ {
+			assertHistory(t, number, roots[number], states[number])
+		}
+		assertHistory(t, last, root, state)
+	})
+}
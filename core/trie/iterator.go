@@ -0,0 +1,110 @@
+package trie
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Iterator walks the leaves of a [Trie] in ascending key order. The trie must not be modified while
+// it is being iterated.
+type Iterator struct {
+	trie *Trie
+	// keys of the nodes that are yet to be visited, the next one is at the end
+	stack []Key
+	// leaves with keys below seekKey are skipped
+	seekKey Key
+
+	key   felt.Felt
+	value felt.Felt
+	valid bool
+	err   error
+}
+
+// NewIterator returns an iterator over the leaves of the trie. Use [Iterator.Seek] to position it.
+func (t *Trie) NewIterator() *Iterator {
+	return &Iterator{trie: t}
+}
+
+// Seek positions the iterator at the leaf with the given key or, if there is none, the leaf with the
+// next key in ascending order. It returns whether the iterator is valid after the call.
+func (it *Iterator) Seek(key *felt.Felt) bool {
+	it.stack = it.stack[:0]
+	if it.trie.rootKey != nil {
+		it.stack = append(it.stack, *it.trie.rootKey)
+	}
+	it.seekKey = it.trie.feltToKey(key)
+	it.err = nil
+	return it.advance()
+}
+
+// Next moves the iterator to the next leaf. It returns whether the iterator is valid after the call.
+func (it *Iterator) Next() bool {
+	if !it.valid {
+		return false
+	}
+	return it.advance()
+}
+
+// Valid returns true if the iterator is positioned at a leaf.
+func (it *Iterator) Valid() bool {
+	return it.valid
+}
+
+// Key returns the key of the current leaf.
+func (it *Iterator) Key() *felt.Felt {
+	key := it.key
+	return &key
+}
+
+// Value returns the value of the current leaf.
+func (it *Iterator) Value() *felt.Felt {
+	value := it.value
+	return &value
+}
+
+// Err returns the error that invalidated the iterator, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// advance pops nodes off the stack until it reaches a leaf, pushing the children of the internal
+// nodes whose subtrees may hold leaves at or after the seek key.
+func (it *Iterator) advance() bool {
+	it.valid = false
+	for len(it.stack) > 0 {
+		nodeKey := it.stack[len(it.stack)-1]
+		it.stack = it.stack[:len(it.stack)-1]
+		if !it.mayContainSeekKey(&nodeKey) {
+			continue
+		}
+
+		node, err := it.trie.storage.Get(&nodeKey)
+		if err != nil {
+			it.err = err
+			it.stack = it.stack[:0]
+			return false
+		}
+
+		if nodeKey.Len() == it.trie.height {
+			it.key = nodeKey.Felt()
+			it.value = *node.Value
+			it.valid = true
+			nodePool.Put(node)
+			return true
+		}
+
+		// the left child comes first in key order, so it goes on top
+		it.stack = append(it.stack, *node.Right, *node.Left)
+		nodePool.Put(node)
+	}
+	return false
+}
+
+// mayContainSeekKey returns whether the subtree under the node with the given key holds any leaves
+// with keys at or after the seek key.
+func (it *Iterator) mayContainSeekKey(nodeKey *Key) bool {
+	seekPrefix := it.seekKey
+	seekPrefix.DeleteLSB(seekPrefix.Len() - nodeKey.Len())
+
+	nodeFelt, seekFelt := nodeKey.Felt(), seekPrefix.Felt()
+	return nodeFelt.Cmp(&seekFelt) >= 0
+}
@@ -0,0 +1,74 @@
+package trie_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	t.Run("empty trie", func(t *testing.T) {
+		require.NoError(t, trie.RunOnTempTrie(251, func(tempTrie *trie.Trie) error {
+			it := tempTrie.NewIterator()
+			assert.False(t, it.Seek(&felt.Zero))
+			assert.False(t, it.Valid())
+			assert.False(t, it.Next())
+			return it.Err()
+		}))
+	})
+
+	t.Run("leaves in key order", func(t *testing.T) {
+		require.NoError(t, trie.RunOnTempTrie(251, func(tempTrie *trie.Trie) error {
+			keys := make([]uint64, 0, 100)
+			for len(keys) < cap(keys) {
+				key := rand.Uint64()
+				if !slices.Contains(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+
+			for _, key := range keys {
+				_, err := tempTrie.Put(new(felt.Felt).SetUint64(key), new(felt.Felt).SetUint64(key+1))
+				require.NoError(t, err)
+			}
+			// zero values are not stored in the trie
+			_, err := tempTrie.Put(new(felt.Felt).SetUint64(keys[0]), &felt.Zero)
+			require.NoError(t, err)
+			require.NoError(t, tempTrie.Commit())
+
+			keys = keys[1:]
+			slices.Sort(keys)
+
+			collect := func(it *trie.Iterator, from *felt.Felt) []uint64 {
+				var got []uint64
+				for it.Seek(from); it.Valid(); it.Next() {
+					assert.Equal(t, it.Key().Uint64()+1, it.Value().Uint64())
+					got = append(got, it.Key().Uint64())
+				}
+				require.NoError(t, it.Err())
+				return got
+			}
+
+			it := tempTrie.NewIterator()
+			assert.Equal(t, keys, collect(it, &felt.Zero))
+
+			t.Run("seek to an existing key", func(t *testing.T) {
+				assert.Equal(t, keys[40:], collect(it, new(felt.Felt).SetUint64(keys[40])))
+			})
+
+			t.Run("seek between keys", func(t *testing.T) {
+				assert.Equal(t, keys[41:], collect(it, new(felt.Felt).SetUint64(keys[40]+1)))
+			})
+
+			t.Run("seek past the last key", func(t *testing.T) {
+				assert.False(t, it.Seek(new(felt.Felt).SetUint64(keys[len(keys)-1]+1)))
+			})
+			return nil
+		}))
+	})
+}
@@ -3,6 +3,7 @@ package trie
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -45,9 +46,23 @@ func (k *Key) WriteTo(buf *bytes.Buffer) (int64, error) {
 	return int64(1 + n), err
 }
 
+func (k *Key) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := k.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (k *Key) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty key")
+	}
 	k.len = data[0]
 	k.bitset = [32]byte{}
+	if uint(len(data)) < k.EncodedLen() {
+		return fmt.Errorf("key of length %d needs %d bytes, got %d", k.len, k.EncodedLen(), len(data))
+	}
 	copy(k.inUseBytes(), data[1:1+k.bytesNeeded()])
 	return nil
 }
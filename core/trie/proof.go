@@ -0,0 +1,152 @@
+package trie
+
+import (
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ProofNode is a node on the path from the root of a [Trie] to a key. Exactly one of Binary and Edge is set.
+type ProofNode struct {
+	Binary *Binary
+	Edge   *Edge
+}
+
+// Binary is a node with two children, given by their hashes
+type Binary struct {
+	LeftHash  *felt.Felt
+	RightHash *felt.Felt
+}
+
+// Edge is a path of one or more bits leading to a child, given by its hash
+type Edge struct {
+	Child *felt.Felt
+	Path  *Key
+}
+
+// Hash calculates the hash of a [ProofNode] with the hash function of the [Trie] it is part of
+func (pn *ProofNode) Hash(hash func(*felt.Felt, *felt.Felt) *felt.Felt) *felt.Felt {
+	if pn.Binary != nil {
+		return hash(pn.Binary.LeftHash, pn.Binary.RightHash)
+	}
+	node := Node{Value: pn.Edge.Child}
+	return node.Hash(pn.Edge.Path, hash)
+}
+
+// wellFormed returns whether exactly one of Binary and Edge is set, with all of its fields, which proofs
+// received from others may not be
+func (pn *ProofNode) wellFormed() bool {
+	if pn.Binary != nil {
+		return pn.Edge == nil && pn.Binary.LeftHash != nil && pn.Binary.RightHash != nil
+	}
+	return pn.Edge != nil && pn.Edge.Child != nil && pn.Edge.Path != nil
+}
+
+// Prove returns the nodes on the path from the root of the [Trie] to the given key, which
+// prove either the value of the key or, if the path diverges from the key, its absence.
+func (t *Trie) Prove(key *felt.Felt) ([]ProofNode, error) {
+	if _, err := t.Root(); err != nil {
+		return nil, err
+	}
+
+	nodeKey := t.feltToKey(key)
+	nodes, err := t.nodesFromRoot(&nodeKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, n := range nodes {
+			nodePool.Put(n.node)
+		}
+	}()
+
+	var proof []ProofNode
+	var parentKey *Key
+	for _, n := range nodes {
+		nodePath := path(n.key, parentKey)
+		if nodePath.Len() > 0 {
+			child := *n.node.Value
+			proof = append(proof, ProofNode{Edge: &Edge{Child: &child, Path: &nodePath}})
+		}
+		if n.key.Len() == t.height || !isSubset(&nodeKey, n.key) {
+			break
+		}
+
+		leftHash, err := t.childHash(n.node.Left, n.key)
+		if err != nil {
+			return nil, err
+		}
+		rightHash, err := t.childHash(n.node.Right, n.key)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, ProofNode{Binary: &Binary{LeftHash: leftHash, RightHash: rightHash}})
+		parentKey = n.key
+	}
+	return proof, nil
+}
+
+func (t *Trie) childHash(childKey, parentKey *Key) (*felt.Felt, error) {
+	child, err := t.storage.Get(childKey)
+	if err != nil {
+		return nil, err
+	}
+	defer nodePool.Put(child)
+
+	childPath := path(childKey, parentKey)
+	return child.Hash(&childPath, t.hash), nil
+}
+
+// VerifyProof checks that the proof shows the key to hold the value in a trie of the given height,
+// root and hash function. A zero value is proven by a path that diverges from the key.
+func VerifyProof(root, key, value *felt.Felt, height uint8, proof []ProofNode,
+	hash func(*felt.Felt, *felt.Felt) *felt.Felt,
+) bool {
+	if key.BigInt(new(big.Int)).BitLen() > int(height) {
+		return false
+	}
+	keyBytes := key.Bytes()
+	nodeKey := NewKey(height, keyBytes[:])
+
+	expected := root
+	// depth is the number of bits of the key, starting from the most significant one, walked so far
+	var depth uint8
+	for i := range proof {
+		node := &proof[i]
+		if !node.wellFormed() || !node.Hash(hash).Equal(expected) {
+			return false
+		}
+
+		if node.Binary != nil {
+			if depth >= height {
+				return false
+			}
+			if nodeKey.Test(height - depth - 1) {
+				expected = node.Binary.RightHash
+			} else {
+				expected = node.Binary.LeftHash
+			}
+			depth++
+			continue
+		}
+
+		edgePath := node.Edge.Path
+		if edgePath.Len() == 0 || edgePath.Len() > height-depth {
+			return false
+		}
+		for bit := uint8(0); bit < edgePath.Len(); bit++ {
+			if edgePath.Test(edgePath.Len()-bit-1) != nodeKey.Test(height-depth-bit-1) {
+				// the path diverges from the key, so the key is not in the trie
+				return value.IsZero() && i == len(proof)-1
+			}
+		}
+		expected = node.Edge.Child
+		depth += edgePath.Len()
+	}
+
+	if len(proof) == 0 {
+		// only the empty trie has no nodes
+		return root.IsZero() && value.IsZero()
+	}
+	return depth == height && expected.Equal(value)
+}
@@ -0,0 +1,91 @@
+package trie_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProve(t *testing.T) {
+	tests := map[string]struct {
+		run    func(uint8, func(*trie.Trie) error) error
+		hash   func(*felt.Felt, *felt.Felt) *felt.Felt
+		height uint8
+	}{
+		"pedersen": {run: trie.RunOnTempTrie, hash: crypto.Pedersen, height: 251},
+		"poseidon": {run: trie.RunOnTempTriePoseidon, hash: crypto.Poseidon, height: 64},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, test.run(test.height, func(tempTrie *trie.Trie) error {
+				t.Run("empty trie", func(t *testing.T) {
+					key := new(felt.Felt).SetUint64(1)
+					proof, err := tempTrie.Prove(key)
+					require.NoError(t, err)
+					assert.Empty(t, proof)
+					assert.True(t, trie.VerifyProof(&felt.Zero, key, &felt.Zero, test.height, proof, test.hash))
+				})
+
+				values := make(map[uint64]*felt.Felt)
+				for _, key := range []uint64{0, 1, 2, 5, 6, 7, 100, 1 << 40} {
+					values[key] = new(felt.Felt).SetUint64(key + 1000)
+					_, err := tempTrie.Put(new(felt.Felt).SetUint64(key), values[key])
+					require.NoError(t, err)
+				}
+				root, err := tempTrie.Root()
+				require.NoError(t, err)
+
+				for key, value := range values {
+					keyFelt := new(felt.Felt).SetUint64(key)
+					proof, err := tempTrie.Prove(keyFelt)
+					require.NoError(t, err)
+					assert.True(t, trie.VerifyProof(root, keyFelt, value, test.height, proof, test.hash), key)
+
+					wrongValue := new(felt.Felt).SetUint64(key + 1)
+					assert.False(t, trie.VerifyProof(root, keyFelt, wrongValue, test.height, proof, test.hash))
+					assert.False(t, trie.VerifyProof(root, keyFelt, &felt.Zero, test.height, proof, test.hash))
+					wrongKey := new(felt.Felt).SetUint64(key ^ 1)
+					assert.False(t, trie.VerifyProof(root, wrongKey, value, test.height, proof, test.hash))
+					assert.False(t, trie.VerifyProof(root, keyFelt, value, test.height, proof[:len(proof)-1], test.hash))
+				}
+
+				for _, key := range []uint64{3, 8, 99, 1<<40 + 1} {
+					keyFelt := new(felt.Felt).SetUint64(key)
+					proof, err := tempTrie.Prove(keyFelt)
+					require.NoError(t, err)
+					assert.True(t, trie.VerifyProof(root, keyFelt, &felt.Zero, test.height, proof, test.hash), key)
+					assert.False(t, trie.VerifyProof(root, keyFelt, new(felt.Felt).SetUint64(1), test.height, proof, test.hash))
+				}
+
+				t.Run("tampered proof", func(t *testing.T) {
+					key := new(felt.Felt).SetUint64(5)
+					proof, err := tempTrie.Prove(key)
+					require.NoError(t, err)
+					// the keys share their most significant bits, so the root is an edge
+					require.NotNil(t, proof[0].Edge)
+					require.NotNil(t, proof[1].Binary)
+
+					proof[1].Binary.LeftHash = new(felt.Felt).SetUint64(42)
+					assert.False(t, trie.VerifyProof(root, key, values[5], test.height, proof, test.hash))
+				})
+
+				t.Run("malformed proof", func(t *testing.T) {
+					key := new(felt.Felt).SetUint64(5)
+					proof, err := tempTrie.Prove(key)
+					require.NoError(t, err)
+
+					proof[0].Edge.Path = nil
+					assert.False(t, trie.VerifyProof(root, key, values[5], test.height, proof, test.hash))
+					proof[0].Edge = nil
+					assert.False(t, trie.VerifyProof(root, key, values[5], test.height, proof, test.hash))
+				})
+				return nil
+			}))
+		})
+	}
+}
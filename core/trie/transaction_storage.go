@@ -31,6 +31,11 @@ func getBuffer() *bytes.Buffer {
 type TransactionStorage struct {
 	txn    db.Transaction
 	prefix []byte
+
+	// archive is the number of the block writing to the storage, if the nodes it overwrites are archived
+	archive *uint64
+	// asOf is the number of the block as of which the storage is read, if it is a historical view
+	asOf *uint64
 }
 
 func NewTransactionStorage(txn db.Transaction, prefix []byte) *TransactionStorage {
@@ -66,6 +71,9 @@ func (t *TransactionStorage) Put(key *Key, value *Node) error {
 	}
 
 	encodedBytes := buffer.Bytes()
+	if err = t.archiveVersion(key, encodedBytes[:keyLen]); err != nil {
+		return err
+	}
 	return t.txn.Set(encodedBytes[:keyLen], encodedBytes[keyLen:])
 }
 
@@ -78,7 +86,7 @@ func (t *TransactionStorage) Get(key *Key) (*Node, error) {
 	}
 
 	var node *Node
-	if err = t.txn.Get(buffer.Bytes(), func(val []byte) error {
+	if err = t.get(key, buffer.Bytes(), func(val []byte) error {
 		node = nodePool.Get().(*Node)
 		return node.UnmarshalBinary(val)
 	}); err != nil {
@@ -94,12 +102,15 @@ func (t *TransactionStorage) Delete(key *Key) error {
 	if err != nil {
 		return err
 	}
+	if err = t.archiveVersion(key, buffer.Bytes()); err != nil {
+		return err
+	}
 	return t.txn.Delete(buffer.Bytes())
 }
 
 func (t *TransactionStorage) RootKey() (*Key, error) {
 	var rootKey *Key
-	if err := t.txn.Get(t.prefix, func(val []byte) error {
+	if err := t.get(nil, t.prefix, func(val []byte) error {
 		rootKey = new(Key)
 		return rootKey.UnmarshalBinary(val)
 	}); err != nil {
@@ -115,17 +126,28 @@ func (t *TransactionStorage) PutRootKey(newRootKey *Key) error {
 	if err != nil {
 		return err
 	}
+	if err = t.archiveVersion(nil, t.prefix); err != nil {
+		return err
+	}
 	return t.txn.Set(t.prefix, buffer.Bytes())
 }
 
 func (t *TransactionStorage) DeleteRootKey() error {
+	if err := t.archiveVersion(nil, t.prefix); err != nil {
+		return err
+	}
 	return t.txn.Delete(t.prefix)
 }
 
 func (t *TransactionStorage) SyncedStorage() *TransactionStorage {
+	if t.asOf != nil {
+		// a historical view is never written to, and synced transactions cannot iterate over the history
+		return t
+	}
 	return &TransactionStorage{
-		txn:    db.NewSyncTransaction(t.txn),
-		prefix: t.prefix,
+		txn:     db.NewSyncTransaction(t.txn),
+		prefix:  t.prefix,
+		archive: t.archive,
 	}
 }
 
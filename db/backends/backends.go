@@ -0,0 +1,151 @@
+package backends
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/bolt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/utils"
+)
+
+const (
+	// Default is the storage engine databases are opened with unless configured otherwise
+	Default = "pebble"
+
+	// markerFile is the file in the database directory that names the storage engine the database was created with
+	markerFile = "BACKEND"
+)
+
+// Backend opens the database of a storage engine at the given path, creating it if it does not exist.
+// cacheSizeMB is how much memory the engine may use to cache data, which engines without a cache of their own
+// ignore.
+type Backend func(path string, cacheSizeMB uint, log utils.Logger) (db.DB, error)
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]Backend{
+		"pebble": func(path string, cacheSizeMB uint, log utils.Logger) (db.DB, error) {
+			return pebble.New(path, cacheSizeMB, log)
+		},
+		"bolt": func(path string, _ uint, _ utils.Logger) (db.DB, error) {
+			return bolt.New(path)
+		},
+	}
+
+	// readOnlyBackends open existing databases of the storage engines of the same name without writing to them
+	readOnlyBackends = map[string]Backend{
+		"pebble": func(path string, cacheSizeMB uint, log utils.Logger) (db.DB, error) {
+			return pebble.NewReadOnly(path, cacheSizeMB, log)
+		},
+		"bolt": func(path string, _ uint, _ utils.Logger) (db.DB, error) {
+			return bolt.NewReadOnly(path)
+		},
+	}
+)
+
+// Register makes a storage engine available under the given name, such as one being benchmarked out of tree.
+// It panics if the name is taken.
+func Register(name string, backend Backend) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("db backend %q registered twice", name))
+	}
+	backends[name] = backend
+}
+
+// Names returns the names of the registered storage engines, in alphabetical order
+func Names() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the database at the given path with the storage engine registered under the given name, or the Default
+// one if the name is empty. It fails if the database was created with another storage engine.
+func Open(backend, path string, cacheSizeMB uint, log utils.Logger) (db.DB, error) {
+	if backend == "" {
+		backend = Default
+	}
+
+	backendsLock.RLock()
+	open, ok := backends[backend]
+	backendsLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown db backend %q, options: %v", backend, Names())
+	}
+
+	created, marked, err := createdWith(path)
+	if err != nil {
+		return nil, err
+	}
+	if created != "" && created != backend {
+		return nil, fmt.Errorf("database at %s was created with the %q db backend, not %q", path, created, backend)
+	}
+
+	database, err := open(path, cacheSizeMB, log)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		if err = os.WriteFile(filepath.Join(path, markerFile), []byte(backend), 0o600); err != nil { //nolint:gomnd
+			return nil, utils.RunAndWrapOnError(database.Close, fmt.Errorf("write db backend marker: %w", err))
+		}
+	}
+	return database, nil
+}
+
+// OpenReadOnly opens the existing database at the given path with the storage engine it was created with, without
+// writing to it, so that it can be shared with the process that owns it. It fails if there is no database at the path
+// or its storage engine cannot open databases read-only.
+func OpenReadOnly(path string, cacheSizeMB uint, log utils.Logger) (db.DB, error) {
+	created, _, err := createdWith(path)
+	if err != nil {
+		return nil, err
+	}
+	if created == "" {
+		return nil, fmt.Errorf("no database at %s", path)
+	}
+
+	open, ok := readOnlyBackends[created]
+	if !ok {
+		return nil, fmt.Errorf("database at %s was created with the %q db backend, which cannot open it read-only", path,
+			created)
+	}
+	return open(path, cacheSizeMB, log)
+}
+
+// createdWith returns the storage engine the database at the given path was created with, which is empty if there is
+// no database yet, and whether the storage engine is recorded. Databases created before storage engines were recorded
+// were created with pebble.
+func createdWith(path string) (string, bool, error) {
+	marker, err := os.ReadFile(filepath.Join(path, markerFile))
+	if err == nil {
+		return string(bytes.TrimSpace(marker)), true, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", false, fmt.Errorf("read db backend marker: %w", err)
+	}
+
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) || len(entries) == 0 {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return "pebble", false, nil
+}
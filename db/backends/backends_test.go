@@ -0,0 +1,99 @@
+package backends_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/backends"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	log := utils.NewNopZapLogger()
+
+	t.Run("databases are opened with the backend they were created with", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "juno")
+		database, err := backends.Open("bolt", path, 8, log)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+
+		_, err = backends.Open("pebble", path, 8, log)
+		assert.ErrorContains(t, err, `created with the "bolt" db backend`)
+		_, err = backends.Open("", path, 8, log)
+		assert.Error(t, err)
+
+		database, err = backends.Open("bolt", path, 8, log)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+	})
+
+	t.Run("databases without a marker were created with pebble", func(t *testing.T) {
+		path := t.TempDir()
+		database, err := pebble.New(path, 8, log)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+
+		_, err = backends.Open("bolt", path, 8, log)
+		assert.ErrorContains(t, err, `created with the "pebble" db backend`)
+
+		database, err = backends.Open("", path, 8, log)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+		marker, err := os.ReadFile(filepath.Join(path, "BACKEND"))
+		require.NoError(t, err)
+		assert.Equal(t, "pebble", string(marker))
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		_, err := backends.Open("lmdb", t.TempDir(), 8, log)
+		assert.ErrorContains(t, err, "unknown db backend")
+	})
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	log := utils.NewNopZapLogger()
+	key, value := []byte("key"), []byte("value")
+
+	for _, backend := range []string{"bolt", "pebble"} {
+		t.Run(backend, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "juno")
+			database, err := backends.Open(backend, path, 8, log)
+			require.NoError(t, err)
+			require.NoError(t, database.Update(func(txn db.Transaction) error {
+				return txn.Set(key, value)
+			}))
+			require.NoError(t, database.Close())
+
+			database, err = backends.OpenReadOnly(path, 8, log)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, database.Close())
+			})
+
+			require.NoError(t, database.View(func(txn db.Transaction) error {
+				return txn.Get(key, func(got []byte) error {
+					assert.Equal(t, value, got)
+					return nil
+				})
+			}))
+			assert.Error(t, database.Update(func(txn db.Transaction) error {
+				return txn.Set(key, []byte("other"))
+			}))
+		})
+	}
+
+	t.Run("no database", func(t *testing.T) {
+		path := t.TempDir()
+		_, err := backends.OpenReadOnly(path, 8, log)
+		assert.ErrorContains(t, err, "no database")
+
+		entries, err := os.ReadDir(path)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
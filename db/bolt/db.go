@@ -0,0 +1,115 @@
+package bolt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/db"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// fileName is the file the database is stored in, in the directory at the database path
+	fileName = "juno.bolt"
+
+	// initialMmapSize is how much of the database file is memory mapped when it is opened. Growing the mapping
+	// makes the commit of a write transaction wait for all read transactions to finish, which is avoided until
+	// the database reaches that size.
+	initialMmapSize = 1 << 30
+
+	// openTimeout is how long New waits for another process holding the database file to release it
+	openTimeout = time.Second
+)
+
+var _ db.DB = (*DB)(nil)
+
+// DB stores each db.Bucket in a bbolt bucket of its own, named after it. Keys are stored whole, including the
+// prefix of their bucket, so that iterators can walk the buckets in the order of their prefixes and yield the keys
+// in the same order as a database without buckets would.
+type DB struct {
+	bolt     *bbolt.DB
+	listener db.EventListener
+}
+
+// New opens the database in the directory at the given path, creating both if they do not exist
+func New(path string) (db.DB, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil { //nolint:gomnd
+		return nil, err
+	}
+
+	bDB, err := bbolt.Open(filepath.Join(path, fileName), 0o600, &bbolt.Options{ //nolint:gomnd
+		Timeout:         openTimeout,
+		InitialMmapSize: initialMmapSize,
+		FreelistType:    bbolt.FreelistMapType,
+		NoFreelistSync:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", filepath.Join(path, fileName), err)
+	}
+	return &DB{bolt: bDB, listener: &db.SelectiveListener{}}, nil
+}
+
+// NewReadOnly opens the existing database in the directory at the given path, which fails to write. Other
+// processes may open the database read-only at the same time.
+func NewReadOnly(path string) (db.DB, error) {
+	bDB, err := bbolt.Open(filepath.Join(path, fileName), 0o600, &bbolt.Options{ //nolint:gomnd
+		Timeout:  openTimeout,
+		ReadOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", filepath.Join(path, fileName), err)
+	}
+	return &DB{bolt: bDB, listener: &db.SelectiveListener{}}, nil
+}
+
+// NewTest opens a new database in a temporary directory, which is removed with the database when the test ends
+func NewTest(t *testing.T) db.DB {
+	testDB, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("create bolt db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := testDB.Close(); err != nil {
+			t.Errorf("close bolt db: %v", err)
+		}
+	})
+	return testDB
+}
+
+// WithListener registers an EventListener
+func (d *DB) WithListener(listener db.EventListener) db.DB {
+	d.listener = listener
+	return d
+}
+
+// NewTransaction : see db.DB.NewTransaction
+func (d *DB) NewTransaction(update bool) (db.Transaction, error) {
+	tx, err := d.bolt.Begin(update)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: tx, listener: d.listener}, nil
+}
+
+// Close : see io.Closer.Close
+func (d *DB) Close() error {
+	return d.bolt.Close()
+}
+
+// View : see db.DB.View
+func (d *DB) View(fn func(txn db.Transaction) error) error {
+	return db.View(d, fn)
+}
+
+// Update : see db.DB.Update
+func (d *DB) Update(fn func(txn db.Transaction) error) error {
+	return db.Update(d, fn)
+}
+
+// Impl : see db.DB.Impl
+func (d *DB) Impl() any {
+	return d.bolt
+}
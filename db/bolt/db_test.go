@@ -0,0 +1,49 @@
+package bolt_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/bolt"
+	"github.com/NethermindEth/juno/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestConformance(t *testing.T) {
+	dbtest.Run(t, bolt.NewTest)
+}
+
+func TestReopen(t *testing.T) {
+	path := t.TempDir()
+	testDB, err := bolt.New(path)
+	require.NoError(t, err)
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		if err := txn.Set(db.ChainHeight.Key(), []byte{1}); err != nil {
+			return err
+		}
+		return txn.Set(db.Class.Key([]byte("hash")), []byte{2})
+	}))
+	require.NoError(t, testDB.Close())
+
+	testDB, err = bolt.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, testDB.Close())
+	})
+	require.NoError(t, testDB.View(func(txn db.Transaction) error {
+		return txn.Get(db.ChainHeight.Key(), func(val []byte) error {
+			assert.Equal(t, []byte{1}, val)
+			return nil
+		})
+	}))
+
+	// keys are stored whole in the buckets named after their prefix
+	require.NoError(t, testDB.Impl().(*bbolt.DB).View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(db.Class.String()))
+		require.NotNil(t, bucket)
+		assert.Equal(t, []byte{2}, bucket.Get(db.Class.Key([]byte("hash"))))
+		return nil
+	}))
+}
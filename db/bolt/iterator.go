@@ -0,0 +1,125 @@
+package bolt
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/NethermindEth/juno/db"
+	"go.etcd.io/bbolt"
+)
+
+var _ db.Iterator = (*iterator)(nil)
+
+// iterator walks the buckets in the order of their prefixes, with a cursor over the bucket it is in
+type iterator struct {
+	txn        *Transaction
+	cursor     *bbolt.Cursor
+	prefix     int
+	key        []byte
+	value      []byte
+	positioned bool
+}
+
+// Valid : see db.Transaction.Iterator.Valid
+func (i *iterator) Valid() bool {
+	return i.key != nil
+}
+
+// Key : see db.Transaction.Iterator.Key
+func (i *iterator) Key() []byte {
+	return bytes.Clone(i.key)
+}
+
+// Value : see db.Transaction.Iterator.Value
+func (i *iterator) Value() ([]byte, error) {
+	if i.key == nil {
+		return nil, nil
+	}
+	return bytes.Clone(i.value), nil
+}
+
+// Next : see db.Transaction.Iterator.Next
+func (i *iterator) Next() bool {
+	i.txn.lock.Lock()
+	defer i.txn.lock.Unlock()
+	if i.txn.tx == nil {
+		return i.set(nil, nil)
+	}
+
+	if !i.positioned {
+		i.positioned = true
+		return i.first(0)
+	}
+	if i.key == nil {
+		return false
+	}
+
+	var k, v []byte
+	if i.txn.tx.Writable() {
+		// changes to the bucket invalidate its cursors, so the cursor is moved back to the current key first,
+		// which lands on the following one if the current key was deleted
+		k, v = i.cursor.Seek(i.key)
+		if k != nil && bytes.Equal(k, i.key) {
+			k, v = i.cursor.Next()
+		}
+	} else {
+		k, v = i.cursor.Next()
+	}
+
+	if k == nil {
+		return i.first(i.prefix + 1)
+	}
+	return i.set(k, v)
+}
+
+// Seek : see db.Transaction.Iterator.Seek
+func (i *iterator) Seek(key []byte) bool {
+	i.txn.lock.Lock()
+	defer i.txn.lock.Unlock()
+	i.positioned = true
+	if i.txn.tx == nil {
+		return i.set(nil, nil)
+	}
+	if len(key) == 0 {
+		return i.first(0)
+	}
+
+	if bucket := i.txn.tx.Bucket(bucketNames[key[0]]); bucket != nil {
+		i.cursor, i.prefix = bucket.Cursor(), int(key[0])
+		if k, v := i.cursor.Seek(key); k != nil {
+			return i.set(k, v)
+		}
+	}
+	return i.first(int(key[0]) + 1)
+}
+
+// first moves the iterator to the first key of the first bucket from the given prefix on that holds any
+func (i *iterator) first(prefix int) bool {
+	for ; prefix <= math.MaxUint8; prefix++ {
+		bucket := i.txn.tx.Bucket(bucketNames[prefix])
+		if bucket == nil {
+			continue
+		}
+
+		i.cursor, i.prefix = bucket.Cursor(), prefix
+		if k, v := i.cursor.First(); k != nil {
+			return i.set(k, v)
+		}
+	}
+	return i.set(nil, nil)
+}
+
+func (i *iterator) set(key, value []byte) bool {
+	// the current key is copied to move the cursor back to it, the slices of bbolt do not outlive changes to buckets
+	i.key, i.value = bytes.Clone(key), value
+	if key == nil {
+		i.cursor = nil
+	}
+	return i.key != nil
+}
+
+// Close : see db.Transaction.Iterator.Close
+func (i *iterator) Close() error {
+	i.cursor, i.key, i.value = nil, nil, nil
+	return nil
+}
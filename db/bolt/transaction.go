@@ -0,0 +1,169 @@
+package bolt
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+	"go.etcd.io/bbolt"
+)
+
+var ErrDiscardedTransaction = errors.New("discarded txn")
+
+var _ db.Transaction = (*Transaction)(nil)
+
+// bucketNames are the names of the bbolt buckets the keys with each prefix are stored in
+var bucketNames = func() [math.MaxUint8 + 1][]byte {
+	var names [math.MaxUint8 + 1][]byte
+	for prefix := range names {
+		names[prefix] = []byte(db.Bucket(prefix).String())
+	}
+	return names
+}()
+
+// Transaction serialises the use of its bbolt transaction, which is not safe for concurrent use
+type Transaction struct {
+	lock     sync.Mutex
+	tx       *bbolt.Tx
+	listener db.EventListener
+}
+
+// Discard : see db.Transaction.Discard
+func (t *Transaction) Discard() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.discard()
+}
+
+func (t *Transaction) discard() error {
+	if t.tx == nil {
+		return nil
+	}
+	err := t.tx.Rollback()
+	t.tx = nil
+	return err
+}
+
+// Commit : see db.Transaction.Commit
+func (t *Transaction) Commit() error {
+	start := time.Now()
+	defer func() { t.listener.OnCommit(time.Since(start)) }()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.tx == nil || !t.tx.Writable() {
+		return utils.RunAndWrapOnError(t.discard, ErrDiscardedTransaction)
+	}
+	err := t.tx.Commit()
+	t.tx = nil
+	return err
+}
+
+// Set : see db.Transaction.Set
+func (t *Transaction) Set(key, val []byte) error {
+	start := time.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return errors.New("empty key")
+	}
+
+	defer func() { t.listener.OnIO(true, time.Since(start)) }()
+	bucket, err := t.tx.CreateBucketIfNotExists(bucketNames[key[0]])
+	if err != nil {
+		return err
+	}
+	// bbolt requires keys and values to stay unchanged until the transaction ends, which callers do not ensure
+	return bucket.Put(bytes.Clone(key), bytes.Clone(val))
+}
+
+// Delete : see db.Transaction.Delete
+func (t *Transaction) Delete(key []byte) error {
+	start := time.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return nil
+	}
+
+	defer func() { t.listener.OnIO(true, time.Since(start)) }()
+	bucket := t.tx.Bucket(bucketNames[key[0]])
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Delete(key)
+}
+
+func (t *Transaction) checkWritable() error {
+	if t.tx == nil {
+		return ErrDiscardedTransaction
+	}
+	if !t.tx.Writable() {
+		return errors.New("read only transaction")
+	}
+	return nil
+}
+
+// Get : see db.Transaction.Get
+func (t *Transaction) Get(key []byte, cb func([]byte) error) error {
+	start := time.Now()
+	val, err := t.get(key)
+	t.listener.OnIO(false, time.Since(start))
+	if err != nil {
+		return err
+	}
+	// cb may use the transaction, and the slices of bbolt stay valid until it ends
+	return cb(val)
+}
+
+func (t *Transaction) get(key []byte) ([]byte, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.tx == nil {
+		return nil, ErrDiscardedTransaction
+	}
+	if len(key) == 0 {
+		return nil, db.ErrKeyNotFound
+	}
+	bucket := t.tx.Bucket(bucketNames[key[0]])
+	if bucket == nil {
+		return nil, db.ErrKeyNotFound
+	}
+
+	// bbolt's Get does not tell missing keys from empty values apart
+	k, val := bucket.Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, db.ErrKeyNotFound
+	}
+	if val == nil {
+		val = []byte{}
+	}
+	return val, nil
+}
+
+// Impl : see db.Transaction.Impl
+func (t *Transaction) Impl() any {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.tx
+}
+
+// NewIterator : see db.Transaction.NewIterator
+func (t *Transaction) NewIterator() (db.Iterator, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.tx == nil {
+		return nil, ErrDiscardedTransaction
+	}
+	return &iterator{txn: t}, nil
+}
@@ -1,6 +1,9 @@
 package db
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+)
 
 type Bucket byte
 
@@ -27,13 +30,79 @@ const (
 	ContractClassHashHistory
 	ContractDeploymentHeight
 	L1Height
-	SchemaVersion
+	SchemaVersion // the schema version, followed by the migrations applied to the database
 	Pending
 	BlockCommitments
 	Temporary // used temporarily for migrations
 	SchemaIntermediateState
+	AccountTransactions          // maps account addresses, block numbers and indices to nothing
+	AccountTransactionsByNonce   // maps sender addresses and nonces to block number and index
+	ContractAddressesByClassHash // maps class hashes and the addresses of the contracts using them to nothing
+	ClassHashesBySelector        // maps entry point selectors and the hashes of the classes defining them to nothing
+	TrieNodeHistory              // maps trie prefixes, node keys and block numbers to the nodes the blocks overwrote
+	TrieRootKeyHistory           // maps trie prefixes and block numbers to the root keys the blocks overwrote
+	TrieHistoryByBlockNumber     // maps block numbers and the trie history keys they wrote to nothing
+	GlobalTrieRoots              // maps block numbers to the roots of the contracts and classes tries
+	P2PIdentity                  // stored the private key of the p2p host, which is now moved to a key file
+	Peers                        // maps peer IDs to their addresses, protocols and when they were last seen
+	Overlay                      // marks a database holding the changes of an overlay on top of a base database
 )
 
+var bucketNames = [...]string{
+	"StateTrie",
+	"Unused",
+	"ContractClassHash",
+	"ContractStorage",
+	"Class",
+	"ContractNonce",
+	"ChainHeight",
+	"BlockHeaderNumbersByHash",
+	"BlockHeadersByNumber",
+	"TransactionBlockNumbersAndIndicesByHash",
+	"TransactionsByBlockNumberAndIndex",
+	"ReceiptsByBlockNumberAndIndex",
+	"StateUpdatesByBlockNumber",
+	"ClassesTrie",
+	"ContractStorageHistory",
+	"ContractNonceHistory",
+	"ContractClassHashHistory",
+	"ContractDeploymentHeight",
+	"L1Height",
+	"SchemaVersion",
+	"Pending",
+	"BlockCommitments",
+	"Temporary",
+	"SchemaIntermediateState",
+	"AccountTransactions",
+	"AccountTransactionsByNonce",
+	"ContractAddressesByClassHash",
+	"ClassHashesBySelector",
+	"TrieNodeHistory",
+	"TrieRootKeyHistory",
+	"TrieHistoryByBlockNumber",
+	"GlobalTrieRoots",
+	"P2PIdentity",
+	"Peers",
+	"Overlay",
+}
+
+// Buckets returns all the buckets, in the order of their prefixes.
+func Buckets() []Bucket {
+	buckets := make([]Bucket, len(bucketNames))
+	for i := range buckets {
+		buckets[i] = Bucket(i)
+	}
+	return buckets
+}
+
+// String returns the name of the bucket.
+func (b Bucket) String() string {
+	if int(b) < len(bucketNames) {
+		return bucketNames[b]
+	}
+	return fmt.Sprintf("Bucket(%d)", byte(b))
+}
+
 // Key flattens a prefix and series of byte arrays into a single []byte.
 func (b Bucket) Key(key ...[]byte) []byte {
 	return append([]byte{byte(b)}, bytes.Join(key, []byte{})...)
@@ -28,3 +28,11 @@ func TestKey(t *testing.T) {
 		}
 	})
 }
+
+func TestBucketNames(t *testing.T) {
+	buckets := db.Buckets()
+	assert.Equal(t, db.Overlay, buckets[len(buckets)-1])
+	assert.Equal(t, "StateTrie", db.StateTrie.String())
+	assert.Equal(t, "Overlay", db.Overlay.String())
+	assert.Equal(t, "Bucket(255)", db.Bucket(255).String())
+}
@@ -0,0 +1,97 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algorithm is how the values of a bucket are compressed
+type Algorithm byte
+
+const (
+	None Algorithm = iota
+	Snappy
+	Zstd
+)
+
+var algorithmNames = [...]string{
+	None:   "none",
+	Snappy: "snappy",
+	Zstd:   "zstd",
+}
+
+func (a Algorithm) String() string {
+	if int(a) < len(algorithmNames) {
+		return algorithmNames[a]
+	}
+	return fmt.Sprintf("Algorithm(%d)", a)
+}
+
+// UnmarshalText parses the name of an algorithm
+func (a *Algorithm) UnmarshalText(text []byte) error {
+	for algorithm, name := range algorithmNames {
+		if strings.EqualFold(string(text), name) {
+			*a = Algorithm(algorithm)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown compression algorithm %q, options: %s", text, strings.Join(algorithmNames[:], ", "))
+}
+
+// Buckets are the buckets whose values can be compressed. Their values are CBOR encoded, and an encoded value never
+// starts with the CBOR break code, which marks compressed values.
+var Buckets = []db.Bucket{
+	db.Class,
+	db.TransactionsByBlockNumberAndIndex,
+	db.ReceiptsByBlockNumberAndIndex,
+	db.StateUpdatesByBlockNumber,
+}
+
+// marker starts every compressed value, followed by the Algorithm it was compressed with
+const marker = 0xff
+
+var (
+	// the zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// compress returns the value to store for val, which is val itself if compressing does not make it smaller
+func compress(algorithm Algorithm, val []byte) []byte {
+	header := []byte{marker, byte(algorithm)}
+	var compressed []byte
+	switch algorithm {
+	case Snappy:
+		compressed = append(header, s2.EncodeSnappy(nil, val)...)
+	case Zstd:
+		compressed = zstdEncoder.EncodeAll(val, header)
+	default:
+		return val
+	}
+
+	if len(compressed) >= len(val) {
+		return val
+	}
+	return compressed
+}
+
+// decompress returns the value stored as val, which is val itself unless it was compressed
+func decompress(val []byte) ([]byte, error) {
+	if len(val) < 2 || val[0] != marker {
+		return val, nil
+	}
+
+	switch algorithm := Algorithm(val[1]); algorithm {
+	case Snappy:
+		return s2.Decode(nil, val[2:])
+	case Zstd:
+		return zstdDecoder.DecodeAll(val[2:], nil)
+	default:
+		return nil, errors.New("value compressed with unknown algorithm " + algorithm.String())
+	}
+}
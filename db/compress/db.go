@@ -0,0 +1,106 @@
+package compress
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/juno/db"
+)
+
+var _ db.DB = (*DB)(nil)
+
+// DB compresses the values written to the buckets it is configured to compress, and decompresses the values read
+// from Buckets whatever they were compressed with, so that the configuration can change without rewriting the
+// database. Values stored uncompressed, such as those written before compression was configured, are read as is.
+type DB struct {
+	inner      db.DB
+	algorithms map[db.Bucket]Algorithm
+	listener   EventListener
+}
+
+// New wraps a database, compressing the values of each bucket with the given algorithm
+func New(inner db.DB, algorithms map[db.Bucket]Algorithm) (*DB, error) {
+	for bucket := range algorithms {
+		if !slices.Contains(Buckets, bucket) {
+			return nil, fmt.Errorf("values of %s cannot be compressed, options: %v", bucket, Buckets)
+		}
+	}
+	return &DB{inner: inner, algorithms: algorithms, listener: &SelectiveListener{}}, nil
+}
+
+// ParseConfig parses a comma separated list of buckets and the algorithm to compress their values with,
+// such as "Class=zstd,ReceiptsByBlockNumberAndIndex=snappy"
+func ParseConfig(config string) (map[db.Bucket]Algorithm, error) {
+	algorithms := make(map[db.Bucket]Algorithm)
+	if config == "" {
+		return algorithms, nil
+	}
+
+	for _, entry := range strings.Split(config, ",") {
+		name, algorithmName, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid compression config %q, expected bucket=algorithm", entry)
+		}
+
+		i := slices.IndexFunc(Buckets, func(bucket db.Bucket) bool {
+			return strings.EqualFold(bucket.String(), name)
+		})
+		if i == -1 {
+			return nil, fmt.Errorf("values of %q cannot be compressed, options: %v", name, Buckets)
+		}
+
+		var algorithm Algorithm
+		if err := algorithm.UnmarshalText([]byte(algorithmName)); err != nil {
+			return nil, err
+		}
+		algorithms[Buckets[i]] = algorithm
+	}
+	return algorithms, nil
+}
+
+// WithCompressionListener registers an EventListener
+func (d *DB) WithCompressionListener(listener EventListener) *DB {
+	d.listener = listener
+	return d
+}
+
+// WithListener registers a db.EventListener on the wrapped database
+func (d *DB) WithListener(listener db.EventListener) db.DB {
+	d.inner.WithListener(listener)
+	return d
+}
+
+// NewTransaction : see db.DB.NewTransaction
+func (d *DB) NewTransaction(update bool) (db.Transaction, error) {
+	txn, err := d.inner.NewTransaction(update)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{inner: txn, db: d}, nil
+}
+
+// View : see db.DB.View
+func (d *DB) View(fn func(txn db.Transaction) error) error {
+	return db.View(d, fn)
+}
+
+// Update : see db.DB.Update
+func (d *DB) Update(fn func(txn db.Transaction) error) error {
+	return db.Update(d, fn)
+}
+
+// Close closes the wrapped database
+func (d *DB) Close() error {
+	return d.inner.Close()
+}
+
+// Impl : see db.DB.Impl
+func (d *DB) Impl() any {
+	return d.inner.Impl()
+}
+
+// Algorithm returns the algorithm the values of the bucket are compressed with when they are written
+func (d *DB) Algorithm(bucket db.Bucket) Algorithm {
+	return d.algorithms[bucket]
+}
@@ -0,0 +1,129 @@
+package compress_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/compress"
+	"github.com/NethermindEth/juno/db/dbtest"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) db.DB {
+		testDB, err := compress.New(pebble.NewMemTest(t), map[db.Bucket]compress.Algorithm{db.Class: compress.Zstd})
+		require.NoError(t, err)
+		return testDB
+	})
+}
+
+func get(t *testing.T, database db.DB, key []byte) []byte {
+	t.Helper()
+	var val []byte
+	require.NoError(t, database.View(func(txn db.Transaction) error {
+		return txn.Get(key, func(b []byte) error {
+			val = bytes.Clone(b)
+			return nil
+		})
+	}))
+	return val
+}
+
+func TestCompression(t *testing.T) {
+	// classes are large and repetitive, which compresses well
+	class := bytes.Repeat([]byte("sierra_program"), 1000)
+	classKey := db.Class.Key([]byte("hash"))
+	heightKey := db.ChainHeight.Key()
+
+	for _, algorithm := range []compress.Algorithm{compress.Snappy, compress.Zstd} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			inner := pebble.NewMemTest(t)
+			var size, storedSize int
+			testDB, err := compress.New(inner, map[db.Bucket]compress.Algorithm{db.Class: algorithm})
+			require.NoError(t, err)
+			testDB.WithCompressionListener(&compress.SelectiveListener{
+				OnCompressCb: func(bucket db.Bucket, a compress.Algorithm, s, stored int) {
+					assert.Equal(t, db.Class, bucket)
+					assert.Equal(t, algorithm, a)
+					size, storedSize = s, stored
+				},
+			})
+
+			require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+				require.NoError(t, txn.Set(heightKey, []byte{1}))
+				return txn.Set(classKey, class)
+			}))
+			assert.Equal(t, len(class), size)
+			assert.Less(t, storedSize, len(class)/10)
+
+			assert.Equal(t, class, get(t, testDB, classKey))
+			assert.Len(t, get(t, inner, classKey), storedSize)
+			assert.Equal(t, []byte{1}, get(t, inner, heightKey), "buckets without compression are stored as is")
+
+			require.NoError(t, testDB.View(func(txn db.Transaction) error {
+				it, err := txn.NewIterator()
+				require.NoError(t, err)
+				require.True(t, it.Seek(classKey))
+				val, err := it.Value()
+				require.NoError(t, err)
+				assert.Equal(t, class, val)
+				return it.Close()
+			}))
+
+			// values are read whatever they were compressed with
+			withoutCompression, err := compress.New(inner, nil)
+			require.NoError(t, err)
+			assert.Equal(t, class, get(t, withoutCompression, classKey))
+		})
+	}
+
+	t.Run("values that do not compress are stored as is", func(t *testing.T) {
+		inner := pebble.NewMemTest(t)
+		testDB, err := compress.New(inner, map[db.Bucket]compress.Algorithm{db.Class: compress.Zstd})
+		require.NoError(t, err)
+
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set(classKey, []byte{0xa1, 0x01})
+		}))
+		assert.Equal(t, []byte{0xa1, 0x01}, get(t, inner, classKey))
+		assert.Equal(t, []byte{0xa1, 0x01}, get(t, testDB, classKey))
+	})
+
+	t.Run("values stored before compression was configured", func(t *testing.T) {
+		inner := pebble.NewMemTest(t)
+		require.NoError(t, inner.Update(func(txn db.Transaction) error {
+			return txn.Set(classKey, class)
+		}))
+
+		testDB, err := compress.New(inner, map[db.Bucket]compress.Algorithm{db.Class: compress.Snappy})
+		require.NoError(t, err)
+		assert.Equal(t, class, get(t, testDB, classKey))
+	})
+
+	t.Run("only buckets of CBOR values can be compressed", func(t *testing.T) {
+		_, err := compress.New(pebble.NewMemTest(t), map[db.Bucket]compress.Algorithm{db.StateTrie: compress.Zstd})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	algorithms, err := compress.ParseConfig("Class=zstd, receiptsByBlockNumberAndIndex=Snappy,StateUpdatesByBlockNumber=none")
+	require.NoError(t, err)
+	assert.Equal(t, map[db.Bucket]compress.Algorithm{
+		db.Class:                         compress.Zstd,
+		db.ReceiptsByBlockNumberAndIndex: compress.Snappy,
+		db.StateUpdatesByBlockNumber:     compress.None,
+	}, algorithms)
+
+	algorithms, err = compress.ParseConfig("")
+	require.NoError(t, err)
+	assert.Empty(t, algorithms)
+
+	for _, config := range []string{"Class", "Class=lz4", "StateTrie=zstd", "Class=zstd,"} {
+		_, err = compress.ParseConfig(config)
+		assert.Error(t, err, config)
+	}
+}
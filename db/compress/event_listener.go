@@ -0,0 +1,18 @@
+package compress
+
+import "github.com/NethermindEth/juno/db"
+
+type EventListener interface {
+	// OnCompress is called when a value of a bucket is written, with its size and the size it was stored with
+	OnCompress(bucket db.Bucket, algorithm Algorithm, size, storedSize int)
+}
+
+type SelectiveListener struct {
+	OnCompressCb func(bucket db.Bucket, algorithm Algorithm, size, storedSize int)
+}
+
+func (l *SelectiveListener) OnCompress(bucket db.Bucket, algorithm Algorithm, size, storedSize int) {
+	if l.OnCompressCb != nil {
+		l.OnCompressCb(bucket, algorithm, size, storedSize)
+	}
+}
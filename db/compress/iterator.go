@@ -0,0 +1,44 @@
+package compress
+
+import "github.com/NethermindEth/juno/db"
+
+var _ db.Iterator = (*iterator)(nil)
+
+// iterator decompresses the values of the iterator it wraps
+type iterator struct {
+	inner db.Iterator
+}
+
+// Valid : see db.Transaction.Iterator.Valid
+func (i *iterator) Valid() bool {
+	return i.inner.Valid()
+}
+
+// Key : see db.Transaction.Iterator.Key
+func (i *iterator) Key() []byte {
+	return i.inner.Key()
+}
+
+// Value : see db.Transaction.Iterator.Value
+func (i *iterator) Value() ([]byte, error) {
+	val, err := i.inner.Value()
+	if err != nil || !compressible(i.inner.Key()) {
+		return val, err
+	}
+	return decompress(val)
+}
+
+// Next : see db.Transaction.Iterator.Next
+func (i *iterator) Next() bool {
+	return i.inner.Next()
+}
+
+// Seek : see db.Transaction.Iterator.Seek
+func (i *iterator) Seek(key []byte) bool {
+	return i.inner.Seek(key)
+}
+
+// Close : see db.Transaction.Iterator.Close
+func (i *iterator) Close() error {
+	return i.inner.Close()
+}
@@ -0,0 +1,79 @@
+package compress
+
+import (
+	"slices"
+
+	"github.com/NethermindEth/juno/db"
+)
+
+var _ db.Transaction = (*transaction)(nil)
+
+type transaction struct {
+	inner db.Transaction
+	db    *DB
+}
+
+// Discard : see db.Transaction.Discard
+func (t *transaction) Discard() error {
+	return t.inner.Discard()
+}
+
+// Commit : see db.Transaction.Commit
+func (t *transaction) Commit() error {
+	return t.inner.Commit()
+}
+
+// Set : see db.Transaction.Set
+func (t *transaction) Set(key, val []byte) error {
+	if len(key) == 0 {
+		return t.inner.Set(key, val)
+	}
+
+	bucket := db.Bucket(key[0])
+	algorithm, ok := t.db.algorithms[bucket]
+	if !ok {
+		return t.inner.Set(key, val)
+	}
+
+	stored := compress(algorithm, val)
+	t.db.listener.OnCompress(bucket, algorithm, len(val), len(stored))
+	return t.inner.Set(key, stored)
+}
+
+// Delete : see db.Transaction.Delete
+func (t *transaction) Delete(key []byte) error {
+	return t.inner.Delete(key)
+}
+
+// Get : see db.Transaction.Get
+func (t *transaction) Get(key []byte, cb func([]byte) error) error {
+	if !compressible(key) {
+		return t.inner.Get(key, cb)
+	}
+	return t.inner.Get(key, func(val []byte) error {
+		decompressed, err := decompress(val)
+		if err != nil {
+			return err
+		}
+		return cb(decompressed)
+	})
+}
+
+// Impl : see db.Transaction.Impl
+func (t *transaction) Impl() any {
+	return t.inner.Impl()
+}
+
+// NewIterator : see db.Transaction.NewIterator
+func (t *transaction) NewIterator() (db.Iterator, error) {
+	it, err := t.inner.NewIterator()
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{inner: it}, nil
+}
+
+// compressible tells whether the value of key is in one of the Buckets, and may be compressed
+func compressible(key []byte) bool {
+	return len(key) > 0 && slices.Contains(Buckets, db.Bucket(key[0]))
+}
@@ -0,0 +1,436 @@
+// Package dbtest holds the conformance tests of db.DB implementations, so that storage engines can be swapped
+// without the rest of the node noticing.
+package dbtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewDB opens an empty database, which is closed when the test ends
+type NewDB func(t *testing.T) db.DB
+
+// Run runs the conformance tests against the databases newDB opens, one per test
+func Run(t *testing.T, newDB NewDB) {
+	t.Run("transactions", func(t *testing.T) { testTransactions(t, newDB) })
+	t.Run("view and update", func(t *testing.T) { testViewUpdate(t, newDB) })
+	t.Run("iterators", func(t *testing.T) { testIterators(t, newDB) })
+	t.Run("seek", func(t *testing.T) { testSeek(t, newDB) })
+	t.Run("concurrent writers", func(t *testing.T) { testConcurrentWriters(t, newDB) })
+	t.Run("concurrent readers and writer", func(t *testing.T) { testConcurrentReadersAndWriter(t, newDB) })
+}
+
+func noop([]byte) error { return nil }
+
+func get(t *testing.T, txn db.Transaction, key []byte) []byte {
+	t.Helper()
+	var val []byte
+	require.NoError(t, txn.Get(key, func(b []byte) error {
+		val = bytes.Clone(b)
+		return nil
+	}))
+	return val
+}
+
+func testTransactions(t *testing.T, newDB NewDB) {
+	t.Run("committed values are visible to new transactions", func(t *testing.T) {
+		testDB := newDB(t)
+
+		txn, err := testDB.NewTransaction(true)
+		require.NoError(t, err)
+		require.NoError(t, txn.Set([]byte("key"), []byte("value")))
+		assert.Equal(t, []byte("value"), get(t, txn, []byte("key")))
+		require.NoError(t, txn.Commit())
+
+		readTxn, err := testDB.NewTransaction(false)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value"), get(t, readTxn, []byte("key")))
+		require.NoError(t, readTxn.Discard())
+	})
+
+	t.Run("discarded values are not", func(t *testing.T) {
+		testDB := newDB(t)
+
+		txn, err := testDB.NewTransaction(true)
+		require.NoError(t, err)
+		require.NoError(t, txn.Set([]byte("key"), []byte("value")))
+		require.NoError(t, txn.Discard())
+		assert.Error(t, txn.Commit(), "discarded transactions cannot commit")
+
+		readTxn, err := testDB.NewTransaction(false)
+		require.NoError(t, err)
+		assert.ErrorIs(t, readTxn.Get([]byte("key"), noop), db.ErrKeyNotFound)
+		require.NoError(t, readTxn.Discard())
+	})
+
+	t.Run("transactions do not see values committed after they were created", func(t *testing.T) {
+		testDB := newDB(t)
+
+		readTxn, err := testDB.NewTransaction(false)
+		require.NoError(t, err)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set([]byte("key"), []byte("value"))
+		}))
+		assert.ErrorIs(t, readTxn.Get([]byte("key"), noop), db.ErrKeyNotFound)
+		require.NoError(t, readTxn.Discard())
+	})
+
+	t.Run("deleted keys are not found", func(t *testing.T) {
+		testDB := newDB(t)
+
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set([]byte("key"), []byte("value"))
+		}))
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			require.NoError(t, txn.Delete([]byte("key")))
+			require.NoError(t, txn.Delete([]byte("missing")))
+			assert.ErrorIs(t, txn.Get([]byte("key"), noop), db.ErrKeyNotFound)
+			return nil
+		}))
+		assert.ErrorIs(t, testDB.View(func(txn db.Transaction) error {
+			return txn.Get([]byte("key"), noop)
+		}), db.ErrKeyNotFound)
+	})
+
+	t.Run("read only transactions cannot write", func(t *testing.T) {
+		testDB := newDB(t)
+
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			assert.Error(t, txn.Set([]byte("key"), []byte("value")))
+			assert.Error(t, txn.Delete([]byte("key")))
+			return nil
+		}))
+	})
+
+	t.Run("values do not change with the slices they were set from", func(t *testing.T) {
+		testDB := newDB(t)
+
+		key, val := []byte("key"), []byte("value")
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			require.NoError(t, txn.Set(key, val))
+			key[0], val[0] = 'x', 'x'
+			return nil
+		}))
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			assert.Equal(t, []byte("value"), get(t, txn, []byte("key")))
+			return nil
+		}))
+	})
+
+	t.Run("panicking updates are discarded", func(t *testing.T) {
+		testDB := newDB(t)
+
+		var panickingTxn db.Transaction
+		require.Panics(t, func() {
+			_ = testDB.Update(func(txn db.Transaction) error {
+				panickingTxn = txn
+				require.NoError(t, txn.Set([]byte{0}, []byte{0}))
+				panic("update")
+			})
+		})
+		assert.Error(t, panickingTxn.Get([]byte{0}, noop))
+		assert.ErrorIs(t, testDB.View(func(txn db.Transaction) error {
+			return txn.Get([]byte{0}, noop)
+		}), db.ErrKeyNotFound)
+	})
+}
+
+func testViewUpdate(t *testing.T, newDB NewDB) {
+	t.Run("failed updates are not committed", func(t *testing.T) {
+		testDB := newDB(t)
+
+		updateErr := errors.New("update")
+		require.ErrorIs(t, testDB.Update(func(txn db.Transaction) error {
+			require.NoError(t, txn.Set([]byte("key"), []byte("value")))
+			return updateErr
+		}), updateErr)
+		assert.ErrorIs(t, testDB.View(func(txn db.Transaction) error {
+			return txn.Get([]byte("key"), noop)
+		}), db.ErrKeyNotFound)
+	})
+
+	t.Run("empty and nil values", func(t *testing.T) {
+		testDB := newDB(t)
+
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			require.NoError(t, txn.Set([]byte("empty"), []byte{}))
+			return txn.Set([]byte("nil"), nil)
+		}))
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			assert.Empty(t, get(t, txn, []byte("empty")))
+			assert.Empty(t, get(t, txn, []byte("nil")))
+			return nil
+		}))
+	})
+
+	t.Run("empty keys are rejected", func(t *testing.T) {
+		testDB := newDB(t)
+
+		assert.Error(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set([]byte{}, []byte("value"))
+		}))
+		assert.Error(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set(nil, []byte("value"))
+		}))
+	})
+}
+
+// keys spans several prefixes, including keys that are only a prefix, in lexicographical order
+var keys = [][]byte{
+	{0}, {0, 1}, {0, 1, 2}, {0, 2}, {1}, {3, 0}, {3, 0, 0}, {200}, {255, 255},
+}
+
+func setKeys(t *testing.T, txn db.Transaction) {
+	t.Helper()
+	// in reverse, so that the order of insertion does not give the expected order away
+	for i := len(keys) - 1; i >= 0; i-- {
+		require.NoError(t, txn.Set(keys[i], append([]byte{byte(i)}, keys[i]...)))
+	}
+}
+
+func iterate(t *testing.T, it db.Iterator) [][]byte {
+	t.Helper()
+	var iterated [][]byte
+	for ; it.Valid(); it.Next() {
+		val, err := it.Value()
+		require.NoError(t, err)
+		require.Equal(t, it.Key(), val[1:], "value of %v", it.Key())
+		iterated = append(iterated, it.Key())
+	}
+	return iterated
+}
+
+func testIterators(t *testing.T, newDB NewDB) {
+	t.Run("iterators yield keys in lexicographical order", func(t *testing.T) {
+		testDB := newDB(t)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			setKeys(t, txn)
+			return nil
+		}))
+
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			assert.False(t, it.Valid(), "new iterators are not positioned")
+			require.True(t, it.Next())
+			assert.Equal(t, keys, iterate(t, it))
+			assert.False(t, it.Next(), "iterators stay invalid once exhausted")
+			return it.Close()
+		}))
+	})
+
+	t.Run("iterators of update transactions see their writes", func(t *testing.T) {
+		testDB := newDB(t)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			setKeys(t, txn)
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			require.True(t, it.Next())
+			assert.Equal(t, keys, iterate(t, it))
+			return it.Close()
+		}))
+	})
+
+	t.Run("deleting the current key does not skip the following ones", func(t *testing.T) {
+		testDB := newDB(t)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			setKeys(t, txn)
+			return nil
+		}))
+
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+
+			var deleted [][]byte
+			for it.Next(); it.Valid(); it.Next() {
+				key := it.Key()
+				deleted = append(deleted, bytes.Clone(key))
+				require.NoError(t, txn.Delete(key))
+				// callers may reuse the keys they are given
+				key[0] = 100
+			}
+			assert.Equal(t, keys, deleted)
+			return it.Close()
+		}))
+
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			assert.False(t, it.Next())
+			return it.Close()
+		}))
+	})
+
+	t.Run("values set while iterating", func(t *testing.T) {
+		testDB := newDB(t)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			setKeys(t, txn)
+			return nil
+		}))
+
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			var iterated [][]byte
+			for it.Next(); it.Valid(); it.Next() {
+				iterated = append(iterated, it.Key())
+				val, err := it.Value()
+				require.NoError(t, err)
+				require.NoError(t, txn.Set(it.Key(), append(val, 0)))
+			}
+			assert.Equal(t, keys, iterated)
+			return it.Close()
+		}))
+	})
+}
+
+func testSeek(t *testing.T, newDB NewDB) {
+	testDB := newDB(t)
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		setKeys(t, txn)
+		return nil
+	}))
+
+	tests := map[string]struct {
+		seek     []byte
+		expected [][]byte
+	}{
+		"existing key":              {seek: []byte{0, 1}, expected: keys[1:]},
+		"key between keys":          {seek: []byte{0, 1, 3}, expected: keys[3:]},
+		"prefix without keys":       {seek: []byte{2}, expected: keys[5:]},
+		"key after a prefix's keys": {seek: []byte{3, 1}, expected: keys[7:]},
+		"empty key":                 {seek: []byte{}, expected: keys},
+		"key after all keys":        {seek: []byte{255, 255, 0}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, testDB.View(func(txn db.Transaction) error {
+				it, err := txn.NewIterator()
+				require.NoError(t, err)
+				assert.Equal(t, test.expected != nil, it.Seek(test.seek))
+				assert.Equal(t, test.expected, iterate(t, it))
+				if test.expected == nil {
+					assert.Nil(t, it.Key())
+				}
+				return it.Close()
+			}))
+		})
+	}
+
+	t.Run("prefix search", func(t *testing.T) {
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+
+			prefix := []byte{0, 1}
+			var found [][]byte
+			for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+				found = append(found, it.Key())
+			}
+			assert.Equal(t, keys[1:3], found)
+			return it.Close()
+		}))
+	})
+}
+
+func testConcurrentWriters(t *testing.T, newDB NewDB) {
+	testDB := newDB(t)
+	key := []byte{0}
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		return txn.Set(key, make([]byte, 8))
+	}))
+
+	const writers, updates = 10, 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				assert.NoError(t, testDB.Update(func(txn db.Transaction) error {
+					var counter uint64
+					if err := txn.Get(key, func(b []byte) error {
+						counter = binary.BigEndian.Uint64(b)
+						return nil
+					}); err != nil {
+						return err
+					}
+					return txn.Set(key, binary.BigEndian.AppendUint64(nil, counter+1))
+				}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, testDB.View(func(txn db.Transaction) error {
+		assert.Equal(t, uint64(writers*updates), binary.BigEndian.Uint64(get(t, txn, key)))
+		return nil
+	}))
+}
+
+func testConcurrentReadersAndWriter(t *testing.T, newDB NewDB) {
+	testDB := newDB(t)
+	// the writer keeps both keys equal, which readers must always see
+	keyA, keyB := []byte{1, 'a'}, []byte{2, 'b'}
+	write := func(counter uint64) error {
+		return testDB.Update(func(txn db.Transaction) error {
+			val := binary.BigEndian.AppendUint64(nil, counter)
+			if err := txn.Set(keyA, val); err != nil {
+				return err
+			}
+			return txn.Set(keyB, val)
+		})
+	}
+	require.NoError(t, write(0))
+
+	const readers, writes = 4, 100
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				assert.NoError(t, testDB.View(func(txn db.Transaction) error {
+					var a []byte
+					if err := txn.Get(keyA, func(b []byte) error {
+						a = bytes.Clone(b)
+						return nil
+					}); err != nil {
+						return err
+					}
+					it, err := txn.NewIterator()
+					if err != nil {
+						return err
+					}
+					it.Seek(keyB)
+					b, err := it.Value()
+					if err != nil {
+						return errors.Join(err, it.Close())
+					}
+					assert.Equal(t, a, b)
+					return it.Close()
+				}))
+			}
+		}()
+	}
+
+	for counter := uint64(1); counter <= writes; counter++ {
+		require.NoError(t, write(counter))
+	}
+	close(done)
+	wg.Wait()
+}
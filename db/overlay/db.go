@@ -0,0 +1,128 @@
+package overlay
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+var _ db.DB = (*DB)(nil)
+
+// ErrNotOverlay is returned when a database holding data of its own is used as an overlay
+var ErrNotOverlay = errors.New("database is not an overlay")
+
+// Values in the overlay are prefixed with a marker so that keys deleted from the base can be told
+// apart from keys that were never written to the overlay.
+const (
+	deleted byte = iota
+	present
+)
+
+// DB layers a writable database on top of a read-only base database, such as a synced node's database
+// or a remote Juno node. Reads see the base with the overlay's changes applied, and all writes,
+// including deletions of base keys, go to the overlay. The base is never modified.
+type DB struct {
+	base    db.DB
+	overlay db.DB
+}
+
+func New(base, overlay db.DB) *DB {
+	return &DB{
+		base:    base,
+		overlay: overlay,
+	}
+}
+
+// Open layers overlay on top of base like New, after checking that overlay is empty or was opened as an overlay
+// before, and marks it as an overlay. Values in an overlay carry a marker byte, so marked databases must only be
+// read through a DB.
+func Open(base, overlay db.DB) (*DB, error) {
+	err := overlay.Update(func(txn db.Transaction) error {
+		marked, err := isOverlay(txn)
+		if err != nil || marked {
+			return err
+		}
+
+		it, err := txn.NewIterator()
+		if err != nil {
+			return err
+		}
+		empty := !it.Seek([]byte{})
+		if err = it.Close(); err != nil {
+			return err
+		}
+		if !empty {
+			return ErrNotOverlay
+		}
+		return txn.Set(db.Overlay.Key(), []byte{present})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(base, overlay), nil
+}
+
+// IsOverlay returns whether the database was opened as an overlay, in which case it must not be read on its own
+func IsOverlay(database db.DB) (bool, error) {
+	var marked bool
+	return marked, database.View(func(txn db.Transaction) error {
+		var err error
+		marked, err = isOverlay(txn)
+		return err
+	})
+}
+
+func isOverlay(txn db.Transaction) (bool, error) {
+	err := txn.Get(db.Overlay.Key(), func([]byte) error {
+		return nil
+	})
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NewTransaction : see db.DB.NewTransaction
+func (d *DB) NewTransaction(update bool) (db.Transaction, error) {
+	baseTxn, err := d.base.NewTransaction(false)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayTxn, err := d.overlay.NewTransaction(update)
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(baseTxn.Discard, err)
+	}
+
+	return &transaction{
+		base:    baseTxn,
+		overlay: overlayTxn,
+	}, nil
+}
+
+// View : see db.DB.View
+func (d *DB) View(fn func(txn db.Transaction) error) error {
+	return db.View(d, fn)
+}
+
+// Update : see db.DB.Update
+func (d *DB) Update(fn func(txn db.Transaction) error) error {
+	return db.Update(d, fn)
+}
+
+// WithListener registers an EventListener on the overlay database
+func (d *DB) WithListener(listener db.EventListener) db.DB {
+	d.overlay.WithListener(listener)
+	return d
+}
+
+// Close closes both the base and the overlay databases
+func (d *DB) Close() error {
+	return utils.RunAndWrapOnError(d.base.Close, d.overlay.Close())
+}
+
+// Impl : see db.DB.Impl
+func (d *DB) Impl() any {
+	return d.overlay.Impl()
+}
@@ -0,0 +1,162 @@
+package overlay_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/overlay"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, txn db.Transaction, key string) (string, error) {
+	t.Helper()
+
+	var value string
+	err := txn.Get([]byte(key), func(val []byte) error {
+		value = string(val)
+		return nil
+	})
+	return value, err
+}
+
+func TestOverlay(t *testing.T) {
+	base := pebble.NewMemTest(t)
+	require.NoError(t, base.Update(func(txn db.Transaction) error {
+		for _, key := range []string{"a", "c", "e", "g"} {
+			if err := txn.Set([]byte(key), []byte("base-"+key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	overlayDB := pebble.NewMemTest(t)
+	testDB := overlay.New(base, overlayDB)
+
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		require.NoError(t, txn.Set([]byte("b"), []byte("overlay-b")))
+		require.NoError(t, txn.Set([]byte("c"), []byte("overlay-c")))
+		require.NoError(t, txn.Delete([]byte("e")))
+		require.NoError(t, txn.Set([]byte("h"), []byte{}))
+		return nil
+	}))
+
+	t.Run("reads see overlay changes on top of the base", func(t *testing.T) {
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			for key, expected := range map[string]string{
+				"a": "base-a",
+				"b": "overlay-b",
+				"c": "overlay-c",
+				"g": "base-g",
+				"h": "",
+			} {
+				value, err := get(t, txn, key)
+				require.NoError(t, err)
+				assert.Equal(t, expected, value, key)
+			}
+
+			for _, key := range []string{"e", "f"} {
+				_, err := get(t, txn, key)
+				require.ErrorIs(t, err, db.ErrKeyNotFound, key)
+			}
+			return nil
+		}))
+	})
+
+	t.Run("base is not modified", func(t *testing.T) {
+		require.NoError(t, base.View(func(txn db.Transaction) error {
+			value, err := get(t, txn, "c")
+			require.NoError(t, err)
+			assert.Equal(t, "base-c", value)
+			value, err = get(t, txn, "e")
+			require.NoError(t, err)
+			assert.Equal(t, "base-e", value)
+			_, err = get(t, txn, "b")
+			require.ErrorIs(t, err, db.ErrKeyNotFound)
+			return nil
+		}))
+	})
+
+	t.Run("iterator merges base and overlay in key order", func(t *testing.T) {
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			iter, err := txn.NewIterator()
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, iter.Close())
+			}()
+
+			var pairs [][2]string
+			for iter.Next() {
+				value, err := iter.Value()
+				require.NoError(t, err)
+				pairs = append(pairs, [2]string{string(iter.Key()), string(value)})
+			}
+			assert.Equal(t, [][2]string{
+				{"a", "base-a"},
+				{"b", "overlay-b"},
+				{"c", "overlay-c"},
+				{"g", "base-g"},
+				{"h", ""},
+			}, pairs)
+
+			require.True(t, iter.Seek([]byte("d")))
+			assert.Equal(t, []byte("g"), iter.Key())
+			require.True(t, iter.Next())
+			assert.Equal(t, []byte("h"), iter.Key())
+			assert.False(t, iter.Next())
+			assert.False(t, iter.Seek([]byte("i")))
+			return nil
+		}))
+	})
+
+	t.Run("discarded changes are not visible", func(t *testing.T) {
+		txn, err := testDB.NewTransaction(true)
+		require.NoError(t, err)
+		require.NoError(t, txn.Set([]byte("z"), []byte("z")))
+		require.NoError(t, txn.Discard())
+
+		require.NoError(t, testDB.View(func(txn db.Transaction) error {
+			_, err := get(t, txn, "z")
+			require.ErrorIs(t, err, db.ErrKeyNotFound)
+			return nil
+		}))
+	})
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("empty database is marked as an overlay", func(t *testing.T) {
+		overlayDB := pebble.NewMemTest(t)
+		marked, err := overlay.IsOverlay(overlayDB)
+		require.NoError(t, err)
+		assert.False(t, marked)
+
+		testDB, err := overlay.Open(pebble.NewMemTest(t), overlayDB)
+		require.NoError(t, err)
+		require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+			return txn.Set([]byte("a"), []byte("overlay-a"))
+		}))
+
+		marked, err = overlay.IsOverlay(overlayDB)
+		require.NoError(t, err)
+		assert.True(t, marked)
+
+		_, err = overlay.Open(pebble.NewMemTest(t), overlayDB)
+		require.NoError(t, err)
+	})
+
+	t.Run("database with data of its own is refused", func(t *testing.T) {
+		overlayDB := pebble.NewMemTest(t)
+		require.NoError(t, overlayDB.Update(func(txn db.Transaction) error {
+			return txn.Set([]byte("a"), []byte("a"))
+		}))
+
+		_, err := overlay.Open(pebble.NewMemTest(t), overlayDB)
+		require.ErrorIs(t, err, overlay.ErrNotOverlay)
+
+		marked, err := overlay.IsOverlay(overlayDB)
+		require.NoError(t, err)
+		assert.False(t, marked)
+	})
+}
@@ -0,0 +1,112 @@
+package overlay
+
+import (
+	"bytes"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+var _ db.Iterator = (*iterator)(nil)
+
+// iterator merges the key/value pairs of the base and the overlay in key order. When both contain a key
+// the overlay's value is used, and keys deleted in the overlay are skipped.
+type iterator struct {
+	base         db.Iterator
+	overlay      db.Iterator
+	baseValid    bool
+	overlayValid bool
+	positioned   bool
+}
+
+// Valid : see db.Transaction.Iterator.Valid
+func (i *iterator) Valid() bool {
+	return i.baseValid || i.overlayValid
+}
+
+// Key : see db.Transaction.Iterator.Key
+func (i *iterator) Key() []byte {
+	if !i.Valid() {
+		return nil
+	}
+	if i.compare() < 0 {
+		return i.base.Key()
+	}
+	return i.overlay.Key()
+}
+
+// Value : see db.Transaction.Iterator.Value
+func (i *iterator) Value() ([]byte, error) {
+	if !i.Valid() {
+		return nil, nil
+	}
+	if i.compare() < 0 {
+		return i.base.Value()
+	}
+
+	val, err := i.overlay.Value()
+	if err != nil || len(val) == 0 {
+		return nil, err
+	}
+	return val[1:], nil
+}
+
+// Next : see db.Transaction.Iterator.Next
+func (i *iterator) Next() bool {
+	if !i.positioned {
+		i.positioned = true
+		i.baseValid = i.base.Next()
+		i.overlayValid = i.overlay.Next()
+	} else if i.Valid() {
+		i.advance()
+	}
+	return i.skipDeleted()
+}
+
+// Seek : see db.Transaction.Iterator.Seek
+func (i *iterator) Seek(key []byte) bool {
+	i.positioned = true
+	i.baseValid = i.base.Seek(key)
+	i.overlayValid = i.overlay.Seek(key)
+	return i.skipDeleted()
+}
+
+// Close : see db.Transaction.Iterator.Close
+func (i *iterator) Close() error {
+	return utils.RunAndWrapOnError(i.base.Close, i.overlay.Close())
+}
+
+// compare returns a negative number if the current key comes from the base, a positive number if it
+// comes from the overlay and zero if both are positioned at the same key.
+func (i *iterator) compare() int {
+	switch {
+	case !i.overlayValid:
+		return -1
+	case !i.baseValid:
+		return 1
+	default:
+		return bytes.Compare(i.base.Key(), i.overlay.Key())
+	}
+}
+
+// advance moves past the current key in whichever iterators are positioned at it.
+func (i *iterator) advance() {
+	cmp := i.compare()
+	if cmp <= 0 {
+		i.baseValid = i.base.Next()
+	}
+	if cmp >= 0 {
+		i.overlayValid = i.overlay.Next()
+	}
+}
+
+func (i *iterator) skipDeleted() bool {
+	for i.Valid() && i.compare() >= 0 {
+		val, err := i.overlay.Value()
+		if err != nil || len(val) == 0 || val[0] != deleted {
+			break
+		}
+		i.advance()
+	}
+	return i.Valid()
+}
@@ -0,0 +1,74 @@
+package overlay
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+var _ db.Transaction = (*transaction)(nil)
+
+type transaction struct {
+	base    db.Transaction
+	overlay db.Transaction
+}
+
+// Discard : see db.Transaction.Discard
+func (t *transaction) Discard() error {
+	return utils.RunAndWrapOnError(t.base.Discard, t.overlay.Discard())
+}
+
+// Commit : see db.Transaction.Commit
+func (t *transaction) Commit() error {
+	return utils.RunAndWrapOnError(t.base.Discard, t.overlay.Commit())
+}
+
+// Set : see db.Transaction.Set
+func (t *transaction) Set(key, val []byte) error {
+	return t.overlay.Set(key, append([]byte{present}, val...))
+}
+
+// Delete : see db.Transaction.Delete
+func (t *transaction) Delete(key []byte) error {
+	return t.overlay.Set(key, []byte{deleted})
+}
+
+// Get : see db.Transaction.Get
+func (t *transaction) Get(key []byte, cb func([]byte) error) error {
+	var inOverlay bool
+	err := t.overlay.Get(key, func(val []byte) error {
+		inOverlay = true
+		if len(val) == 0 || val[0] == deleted {
+			return db.ErrKeyNotFound
+		}
+		return cb(val[1:])
+	})
+	if inOverlay || !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	return t.base.Get(key, cb)
+}
+
+// Impl : see db.Transaction.Impl
+func (t *transaction) Impl() any {
+	return t.overlay.Impl()
+}
+
+// NewIterator : see db.Transaction.NewIterator
+func (t *transaction) NewIterator() (db.Iterator, error) {
+	baseIter, err := t.base.NewIterator()
+	if err != nil {
+		return nil, err
+	}
+
+	overlayIter, err := t.overlay.NewIterator()
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(baseIter.Close, err)
+	}
+
+	return &iterator{
+		base:    baseIter,
+		overlay: overlayIter,
+	}, nil
+}
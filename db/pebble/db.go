@@ -40,6 +40,19 @@ func New(path string, cache uint, logger pebble.Logger) (db.DB, error) {
 	return pDB, nil
 }
 
+// NewReadOnly opens the existing database at the given path, which fails to write
+func NewReadOnly(path string, cache uint, logger pebble.Logger) (db.DB, error) {
+	if cache < minCache {
+		cache = minCache
+	}
+	return newPebble(path, &pebble.Options{
+		Logger:           logger,
+		Cache:            pebble.NewCache(int64(cache * megabyte)),
+		ReadOnly:         true,
+		ErrorIfNotExists: true,
+	})
+}
+
 // NewMem opens a new in-memory database
 func NewMem() (db.DB, error) {
 	return newPebble("", &pebble.Options{
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/dbtest"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -417,3 +418,7 @@ func TestPanic(t *testing.T) {
 		}))
 	})
 }
+
+func TestConformance(t *testing.T) {
+	dbtest.Run(t, pebble.NewMemTest)
+}
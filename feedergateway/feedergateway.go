@@ -0,0 +1,392 @@
+package feedergateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/NethermindEth/juno/adapters/core2sn"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+)
+
+const (
+	latestID  = "latest"
+	pendingID = "pending"
+
+	statusPending      = "PENDING"
+	statusAcceptedOnL2 = "ACCEPTED_ON_L2"
+	statusAcceptedOnL1 = "ACCEPTED_ON_L1"
+	statusReverted     = "REVERTED"
+	statusNotReceived  = "NOT_RECEIVED"
+)
+
+// Error codes returned by the feeder gateway.
+const (
+	codeBlockNotFound    = "StarknetErrorCode.BLOCK_NOT_FOUND"
+	codeUndeclaredClass  = "StarknetErrorCode.UNDECLARED_CLASS"
+	codeMalformedRequest = "StarkErrorCode.MALFORMED_REQUEST"
+	codeInvalidEndpoint  = "StarkErrorCode.INVALID_ENDPOINT"
+	codeInternalError    = "StarkErrorCode.INTERNAL_ERROR"
+)
+
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	status int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errNotFound(code string, err error) *Error {
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return &Error{Code: code, Message: err.Error(), status: http.StatusBadRequest}
+	}
+	return errInternal(err)
+}
+
+func errInternal(err error) *Error {
+	return &Error{Code: codeInternalError, Message: err.Error(), status: http.StatusInternalServerError}
+}
+
+func errMalformed(format string, args ...any) *Error {
+	return &Error{Code: codeMalformedRequest, Message: fmt.Sprintf(format, args...), status: http.StatusBadRequest}
+}
+
+// Handler serves the read-only feeder gateway endpoints from the local database, so that
+// tooling built against the feeder gateway, including other nodes, can use Juno in its place.
+type Handler struct {
+	bcReader blockchain.Reader
+	log      utils.SimpleLogger
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+func New(bcReader blockchain.Reader, log utils.SimpleLogger) *Handler {
+	return &Handler{
+		bcReader: bcReader,
+		log:      log,
+	}
+}
+
+// ServeHTTP dispatches on the last element of the request path so that the endpoints can be
+// served both at the root and under the usual /feeder_gateway/ prefix.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var response any
+	var err *Error
+	switch endpoint := path.Base(r.URL.Path); endpoint {
+	case "get_block":
+		response, err = h.Block(query)
+	case "get_state_update":
+		response, err = h.StateUpdate(query)
+	case "get_signature":
+		response, err = h.Signature(query)
+	case "get_transaction":
+		response, err = h.Transaction(query)
+	case "get_class_by_hash":
+		response, err = h.ClassByHash(query)
+	case "get_compiled_class_by_class_hash":
+		response, err = h.CompiledClassByClassHash(query)
+	default:
+		err = &Error{Code: codeInvalidEndpoint, Message: "unknown endpoint " + endpoint, status: http.StatusNotFound}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(err.status)
+		response = err
+	}
+	// Class programs contain hints such as "n > 0", keep them as they were received.
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if encErr := enc.Encode(response); encErr != nil {
+		h.log.Warnw("Failed to write feeder gateway response", "path", r.URL.Path, "err", encErr)
+	}
+}
+
+// Block serves get_block.
+func (h *Handler) Block(query url.Values) (*starknet.Block, *Error) {
+	block, status, err := h.blockByID(query)
+	if err != nil {
+		return nil, err
+	}
+
+	response, adaptErr := core2sn.AdaptBlock(block, status)
+	if adaptErr != nil {
+		return nil, errInternal(adaptErr)
+	}
+	return response, nil
+}
+
+// StateUpdate serves get_state_update, including the block when includeBlock is set.
+func (h *Handler) StateUpdate(query url.Values) (any, *Error) {
+	block, status, err := h.blockByID(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var update *core.StateUpdate
+	var updateErr error
+	if status == statusPending {
+		var pending blockchain.Pending
+		pending, updateErr = h.bcReader.Pending()
+		update = pending.StateUpdate
+	} else {
+		update, updateErr = h.bcReader.StateUpdateByNumber(block.Number)
+	}
+	if updateErr != nil {
+		return nil, errNotFound(codeBlockNotFound, updateErr)
+	}
+
+	stateUpdate := core2sn.AdaptStateUpdate(update)
+	if includeBlock := query.Get("includeBlock"); includeBlock == "" || includeBlock == "false" {
+		return stateUpdate, nil
+	}
+
+	response, adaptErr := core2sn.AdaptBlock(block, status)
+	if adaptErr != nil {
+		return nil, errInternal(adaptErr)
+	}
+	return &starknet.StateUpdateWithBlock{
+		Block:       response,
+		StateUpdate: stateUpdate,
+	}, nil
+}
+
+// Signature serves get_signature.
+func (h *Handler) Signature(query url.Values) (*starknet.Signature, *Error) {
+	block, status, err := h.blockByID(query)
+	if err != nil {
+		return nil, err
+	}
+	if status == statusPending {
+		return nil, errMalformed("pending block is not signed")
+	}
+
+	update, updateErr := h.bcReader.StateUpdateByNumber(block.Number)
+	if updateErr != nil {
+		return nil, errNotFound(codeBlockNotFound, updateErr)
+	}
+	return core2sn.AdaptSignature(block.Header, update.StateDiff.Commitment()), nil
+}
+
+// Transaction serves get_transaction. As the feeder gateway does, unknown transactions are
+// reported with a NOT_RECEIVED status rather than an error.
+func (h *Handler) Transaction(query url.Values) (*starknet.TransactionStatus, *Error) {
+	hash, err := feltArg(query, "transactionHash")
+	if err != nil {
+		return nil, err
+	}
+
+	notReceived := &starknet.TransactionStatus{
+		Status:         statusNotReceived,
+		FinalityStatus: starknet.NotReceived,
+	}
+
+	receipt, blockHash, blockNumber, receiptErr := h.bcReader.Receipt(hash)
+	if receiptErr != nil {
+		if errors.Is(receiptErr, db.ErrKeyNotFound) {
+			return notReceived, nil
+		}
+		return nil, errInternal(receiptErr)
+	}
+
+	var block *core.Block
+	var blockErr error
+	if blockHash == nil {
+		var pending blockchain.Pending
+		pending, blockErr = h.bcReader.Pending()
+		block = pending.Block
+	} else {
+		block, blockErr = h.bcReader.BlockByNumber(blockNumber)
+	}
+	if blockErr != nil {
+		return nil, errInternal(blockErr)
+	}
+
+	for i, txn := range block.Transactions {
+		if !hash.Equal(txn.Hash()) {
+			continue
+		}
+
+		tx, adaptErr := core2sn.AdaptTransaction(txn)
+		if adaptErr != nil {
+			return nil, errInternal(adaptErr)
+		}
+
+		status := &starknet.TransactionStatus{
+			Status:           statusAcceptedOnL2,
+			FinalityStatus:   starknet.AcceptedOnL2,
+			ExecutionStatus:  starknet.Succeeded,
+			BlockHash:        blockHash,
+			BlockNumber:      blockNumber,
+			TransactionIndex: uint64(i),
+			Transaction:      tx,
+			RevertError:      receipt.RevertReason,
+		}
+		if blockHash != nil && h.isL1Verified(blockNumber) {
+			status.Status = statusAcceptedOnL1
+			status.FinalityStatus = starknet.AcceptedOnL1
+		}
+		if receipt.Reverted {
+			status.Status = statusReverted
+			status.ExecutionStatus = starknet.Reverted
+		}
+		return status, nil
+	}
+	return notReceived, nil
+}
+
+// ClassByHash serves get_class_by_hash.
+func (h *Handler) ClassByHash(query url.Values) (any, *Error) {
+	class, err := h.class(query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := class.(type) {
+	case *core.Cairo0Class:
+		definition, adaptErr := core2sn.AdaptCairo0Class(c)
+		if adaptErr != nil {
+			return nil, errInternal(adaptErr)
+		}
+		return definition, nil
+	case *core.Cairo1Class:
+		return core2sn.AdaptCairo1Class(c), nil
+	default:
+		return nil, errInternal(fmt.Errorf("unknown class type %T", class))
+	}
+}
+
+// CompiledClassByClassHash serves get_compiled_class_by_class_hash.
+func (h *Handler) CompiledClassByClassHash(query url.Values) (json.RawMessage, *Error) {
+	class, err := h.class(query)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := class.(*core.Cairo1Class)
+	if !ok {
+		return nil, &Error{Code: codeUndeclaredClass, Message: "class is not a Sierra class", status: http.StatusBadRequest}
+	}
+	return c.Compiled, nil
+}
+
+func (h *Handler) class(query url.Values) (core.Class, *Error) {
+	classHash, err := feltArg(query, "classHash")
+	if err != nil {
+		return nil, err
+	}
+
+	var state core.StateReader
+	var closer blockchain.StateCloser
+	var stateErr error
+	switch blockID := query.Get("blockNumber"); {
+	case query.Has("blockHash"):
+		blockHash, hashErr := feltArg(query, "blockHash")
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		state, closer, stateErr = h.bcReader.StateAtBlockHash(blockHash)
+	case blockID == pendingID:
+		state, closer, stateErr = h.bcReader.PendingState()
+	case blockID == "" || blockID == latestID:
+		state, closer, stateErr = h.bcReader.HeadState()
+	default:
+		number, parseErr := strconv.ParseUint(blockID, 10, 64)
+		if parseErr != nil {
+			return nil, errMalformed("invalid block number %q", blockID)
+		}
+		state, closer, stateErr = h.bcReader.StateAtBlockNumber(number)
+	}
+	if stateErr != nil {
+		return nil, errNotFound(codeBlockNotFound, stateErr)
+	}
+	defer func() {
+		if closeErr := closer(); closeErr != nil {
+			h.log.Warnw("Failed to close state", "err", closeErr)
+		}
+	}()
+
+	declared, classErr := state.Class(classHash)
+	if classErr != nil {
+		return nil, errNotFound(codeUndeclaredClass, classErr)
+	}
+	return declared.Class, nil
+}
+
+// blockByID resolves the blockHash or blockNumber arguments, defaulting to the latest block,
+// and returns the block together with the status to report for it.
+func (h *Handler) blockByID(query url.Values) (*core.Block, string, *Error) {
+	var block *core.Block
+	var err error
+	switch blockID := query.Get("blockNumber"); {
+	case query.Has("blockHash"):
+		hash, hashErr := feltArg(query, "blockHash")
+		if hashErr != nil {
+			return nil, "", hashErr
+		}
+		block, err = h.bcReader.BlockByHash(hash)
+	case blockID == pendingID:
+		var pending blockchain.Pending
+		if pending, err = h.bcReader.Pending(); err != nil {
+			return nil, "", errNotFound(codeBlockNotFound, err)
+		}
+		return pending.Block, statusPending, nil
+	case blockID == "" || blockID == latestID:
+		block, err = h.bcReader.Head()
+	default:
+		number, parseErr := strconv.ParseUint(blockID, 10, 64)
+		if parseErr != nil {
+			return nil, "", errMalformed("invalid block number %q", blockID)
+		}
+		block, err = h.bcReader.BlockByNumber(number)
+	}
+	if err != nil {
+		return nil, "", errNotFound(codeBlockNotFound, err)
+	}
+
+	if h.isL1Verified(block.Number) {
+		return block, statusAcceptedOnL1, nil
+	}
+	return block, statusAcceptedOnL2, nil
+}
+
+func (h *Handler) isL1Verified(n uint64) bool {
+	l1Head, err := h.bcReader.L1Head()
+	if err != nil {
+		return false
+	}
+	return l1Head.BlockNumber >= n
+}
+
+func feltArg(query url.Values, name string) (*felt.Felt, *Error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, errMalformed("missing %s", name)
+	}
+
+	f, err := new(felt.Felt).SetString(value)
+	if err != nil {
+		return nil, errMalformed("invalid %s %q: %v", name, value, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,190 @@
+package feedergateway_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/feedergateway"
+	"github.com/NethermindEth/juno/starknet"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup stores the first mainnet blocks, declaring newClasses in the genesis block, and serves them.
+func setup(t *testing.T, newClasses map[felt.Felt]core.Class) (*blockchain.Blockchain, *adaptfeeder.Feeder, *feeder.Client) {
+	t.Helper()
+
+	network := utils.Mainnet
+	chain := blockchain.New(pebble.NewMemTest(t), network, utils.NewNopZapLogger())
+	upstream := adaptfeeder.New(feeder.NewTestClient(t, network))
+	ctx := context.Background()
+
+	for i := uint64(0); i < 3; i++ {
+		stateUpdate, block, err := upstream.StateUpdateWithBlock(ctx, i)
+		require.NoError(t, err)
+		if i > 0 {
+			require.NoError(t, chain.Store(block, &core.BlockCommitments{}, stateUpdate, nil))
+			continue
+		}
+
+		for classHash := range newClasses {
+			stateUpdate.StateDiff.DeclaredV0Classes = append(stateUpdate.StateDiff.DeclaredV0Classes, classHash.Clone())
+		}
+		require.NoError(t, chain.Store(block, &core.BlockCommitments{}, stateUpdate, newClasses))
+	}
+
+	srv := httptest.NewServer(feedergateway.New(chain, utils.NewNopZapLogger()))
+	t.Cleanup(srv.Close)
+
+	client := feeder.NewClient(srv.URL + "/feeder_gateway/").WithBackoff(feeder.NopBackoff).WithMaxRetries(0)
+	return chain, upstream, client
+}
+
+func TestBlockAndStateUpdate(t *testing.T) {
+	chain, upstream, client := setup(t, nil)
+	served := adaptfeeder.New(client)
+	ctx := context.Background()
+
+	for i := uint64(0); i < 3; i++ {
+		expectedUpdate, expectedBlock, err := upstream.StateUpdateWithBlock(ctx, i)
+		require.NoError(t, err)
+
+		block, err := served.BlockByNumber(ctx, i)
+		require.NoError(t, err)
+		assert.Equal(t, expectedBlock, block)
+
+		update, err := served.StateUpdate(ctx, i)
+		require.NoError(t, err)
+		assert.Equal(t, expectedUpdate, update)
+
+		update, block, err = served.StateUpdateWithBlock(ctx, i)
+		require.NoError(t, err)
+		assert.Equal(t, expectedUpdate, update)
+		assert.Equal(t, expectedBlock, block)
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		head, err := chain.Head()
+		require.NoError(t, err)
+
+		block, err := served.BlockLatest(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, head, block)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		block, err := client.Block(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "ACCEPTED_ON_L2", block.Status)
+
+		require.NoError(t, chain.SetL1Head(&core.L1Head{BlockNumber: 1}))
+		block, err = client.Block(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "ACCEPTED_ON_L1", block.Status)
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		_, err := served.BlockByNumber(ctx, 42)
+		require.ErrorContains(t, err, "400")
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	chain, _, client := setup(t, nil)
+	ctx := context.Background()
+
+	block, err := chain.BlockByNumber(2)
+	require.NoError(t, err)
+
+	for i, txn := range block.Transactions {
+		status, err := client.Transaction(ctx, txn.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, starknet.AcceptedOnL2, status.FinalityStatus)
+		assert.Equal(t, starknet.Succeeded, status.ExecutionStatus)
+		assert.Equal(t, block.Hash, status.BlockHash)
+		assert.Equal(t, uint64(2), status.BlockNumber)
+		assert.Equal(t, uint64(i), status.TransactionIndex)
+
+		served, err := adaptfeeder.New(client).Transaction(ctx, txn.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, txn, served)
+	}
+
+	t.Run("not received", func(t *testing.T) {
+		status, err := client.Transaction(ctx, utils.HexToFelt(t, "0xdead"))
+		require.NoError(t, err)
+		assert.Equal(t, starknet.NotReceived, status.FinalityStatus)
+	})
+}
+
+func TestClass(t *testing.T) {
+	ctx := context.Background()
+	classHash := utils.HexToFelt(t, "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8")
+	class, err := adaptfeeder.New(feeder.NewTestClient(t, utils.Goerli)).Class(ctx, classHash)
+	require.NoError(t, err)
+
+	_, _, client := setup(t, map[felt.Felt]core.Class{*classHash: class})
+	served := adaptfeeder.New(client)
+
+	t.Run("cairo 0 class", func(t *testing.T) {
+		servedClass, err := served.Class(ctx, classHash)
+		require.NoError(t, err)
+
+		// The JSON of the ABI and the program is compacted when served, so compare them separately.
+		expected, actual := *class.(*core.Cairo0Class), *servedClass.(*core.Cairo0Class)
+		assert.Equal(t, compact(t, expected.Abi), compact(t, actual.Abi))
+		assert.Equal(t, compactProgram(t, expected.Program), compactProgram(t, actual.Program))
+		expected.Abi, actual.Abi = nil, nil
+		expected.Program, actual.Program = "", ""
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("cairo 0 class has no compiled class", func(t *testing.T) {
+		_, err := client.CompiledClassDefinition(ctx, classHash)
+		require.ErrorContains(t, err, "400")
+	})
+
+	t.Run("undeclared class", func(t *testing.T) {
+		_, err := client.ClassDefinition(ctx, utils.HexToFelt(t, "0xdead"))
+		require.ErrorContains(t, err, "400")
+	})
+}
+
+func compact(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var compacted bytes.Buffer
+	require.NoError(t, json.Compact(&compacted, data))
+	return compacted.Bytes()
+}
+
+func compactProgram(t *testing.T, program string) []byte {
+	t.Helper()
+
+	decompressed, err := utils.Gzip64Decode(program)
+	require.NoError(t, err)
+	return compact(t, decompressed)
+}
+
+func TestUnknownEndpoint(t *testing.T) {
+	handler := feedergateway.New(nil, utils.NewNopZapLogger())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feeder_gateway/get_something", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/feeder_gateway/get_block", http.NoBody))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -3,6 +3,7 @@ package migration
 import (
 	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/utils"
@@ -17,6 +18,9 @@ type (
 
 type BucketMigrator struct {
 	target db.Bucket
+	name   string
+	// buckets written to besides target
+	destinations []db.Bucket
 
 	// number of entries to update before returning migration.ErrCallWithNewTransaction
 	batchSize uint
@@ -31,6 +35,7 @@ type BucketMigrator struct {
 func NewBucketMigrator(target db.Bucket, do BucketMigratorDoFunc) *BucketMigrator {
 	return &BucketMigrator{
 		target:    target,
+		name:      funcName(do),
 		startFrom: target.Key(),
 		batchSize: 1_000_000,
 
@@ -41,7 +46,7 @@ func NewBucketMigrator(target db.Bucket, do BucketMigratorDoFunc) *BucketMigrato
 }
 
 func NewBucketMover(source, destination db.Bucket) *BucketMigrator {
-	return NewBucketMigrator(source, func(txn db.Transaction, key, value []byte, n utils.Network) error {
+	m := NewBucketMigrator(source, func(txn db.Transaction, key, value []byte, n utils.Network) error {
 		err := txn.Delete(key)
 		if err != nil {
 			return err
@@ -49,7 +54,9 @@ func NewBucketMover(source, destination db.Bucket) *BucketMigrator {
 
 		key[0] = byte(destination)
 		return txn.Set(key, value)
-	})
+	}).WithDestinations(destination)
+	m.name = fmt.Sprintf("move%sTo%s", source, destination)
+	return m
 }
 
 func (m *BucketMigrator) WithBatchSize(batchSize uint) *BucketMigrator {
@@ -67,6 +74,21 @@ func (m *BucketMigrator) WithKeyFilter(keyFilter BucketMigratorKeyFilter) *Bucke
 	return m
 }
 
+// WithDestinations sets the buckets the migration writes to besides the one it iterates over, which dry runs count
+// the keys of.
+func (m *BucketMigrator) WithDestinations(destinations ...db.Bucket) *BucketMigrator {
+	m.destinations = destinations
+	return m
+}
+
+func (m *BucketMigrator) Name() string {
+	return m.name
+}
+
+func (m *BucketMigrator) Buckets() []db.Bucket {
+	return append([]db.Bucket{m.target}, m.destinations...)
+}
+
 func (m *BucketMigrator) Before(_ []byte) error {
 	m.before()
 	return nil
@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/core/trie"
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/compress"
 	"github.com/NethermindEth/juno/encoder"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/bits-and-blooms/bitset"
@@ -24,6 +27,16 @@ import (
 type schemaMetadata struct {
 	Version           uint64
 	IntermediateState []byte
+	History           []MigrationRecord
+}
+
+// MigrationRecord tells which version of Juno applied a migration to a database, and when.
+// Migrations applied before the history was kept have no record.
+type MigrationRecord struct {
+	// Version is the schema version the migration upgraded the database to
+	Version     uint64
+	JunoVersion string
+	AppliedAt   time.Time
 }
 
 type Migration interface {
@@ -46,30 +59,48 @@ func (f MigrationFunc) Before(_ []byte) error { return nil }
 // After making breaking changes to the DB layout, add new migrations to this list.
 var defaultMigrations = []Migration{
 	MigrationFunc(migration0000),
-	MigrationFunc(relocateContractStorageRootKeys),
-	MigrationFunc(recalculateBloomFilters),
-	new(changeTrieNodeEncoding),
-	MigrationFunc(calculateBlockCommitments),
+	touches(MigrationFunc(relocateContractStorageRootKeys), db.Unused, db.ContractStorage),
+	touches(MigrationFunc(recalculateBloomFilters), db.BlockHeadersByNumber),
+	touches(new(changeTrieNodeEncoding), db.ClassesTrie, db.StateTrie, db.ContractStorage),
+	touches(MigrationFunc(calculateBlockCommitments), db.BlockHeadersByNumber, db.BlockCommitments),
 	NewBucketMigrator(db.ClassesTrie, migrateTrieRootKeysFromBitsetToTrieKeys).WithKeyFilter(rootKeysFilter(db.ClassesTrie)),
 	NewBucketMigrator(db.StateTrie, migrateTrieRootKeysFromBitsetToTrieKeys).WithKeyFilter(rootKeysFilter(db.StateTrie)),
 	NewBucketMigrator(db.ContractStorage, migrateTrieRootKeysFromBitsetToTrieKeys).WithKeyFilter(rootKeysFilter(db.ContractStorage)),
-	NewBucketMigrator(db.ClassesTrie, migrateTrieNodesFromBitsetToTrieKey(db.ClassesTrie)).WithKeyFilter(nodesFilter(db.ClassesTrie)),
+	NewBucketMigrator(db.ClassesTrie, migrateTrieNodesFromBitsetToTrieKey(db.ClassesTrie)).
+		WithKeyFilter(nodesFilter(db.ClassesTrie)).WithDestinations(db.Temporary),
 	NewBucketMover(db.Temporary, db.ClassesTrie),
-	NewBucketMigrator(db.StateTrie, migrateTrieNodesFromBitsetToTrieKey(db.StateTrie)).WithKeyFilter(nodesFilter(db.StateTrie)),
+	NewBucketMigrator(db.StateTrie, migrateTrieNodesFromBitsetToTrieKey(db.StateTrie)).
+		WithKeyFilter(nodesFilter(db.StateTrie)).WithDestinations(db.Temporary),
 	NewBucketMover(db.Temporary, db.StateTrie),
 	NewBucketMigrator(db.ContractStorage, migrateTrieNodesFromBitsetToTrieKey(db.ContractStorage)).
-		WithKeyFilter(nodesFilter(db.ContractStorage)),
+		WithKeyFilter(nodesFilter(db.ContractStorage)).WithDestinations(db.Temporary),
 	NewBucketMover(db.Temporary, db.ContractStorage),
 	NewBucketMigrator(db.StateUpdatesByBlockNumber, changeStateDiffStruct).WithBatchSize(10_000), //nolint:gomnd
+	NewBucketMigrator(db.TransactionsByBlockNumberAndIndex, indexAccountTransactions).
+		WithDestinations(db.AccountTransactions, db.AccountTransactionsByNonce).WithBatchSize(100_000), //nolint:gomnd
+	NewBucketMigrator(db.ContractClassHash, indexContractClasses).
+		WithDestinations(db.ContractAddressesByClassHash).WithBatchSize(100_000), //nolint:gomnd
+	NewBucketMigrator(db.Class, indexClassSelectors).
+		WithDestinations(db.ClassHashesBySelector).WithBatchSize(1_000), //nolint:gomnd
+	NewBucketMigrator(db.BlockHeadersByNumber, calculatePost0132Commitments).
+		WithDestinations(db.BlockCommitments).WithBatchSize(1_000), //nolint:gomnd
 }
 
-var ErrCallWithNewTransaction = errors.New("call with new transaction")
+var (
+	ErrCallWithNewTransaction = errors.New("call with new transaction")
+	ErrSchemaTooNew           = errors.New("database schema is newer than this version of Juno supports")
+)
 
-func MigrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network, log utils.SimpleLogger) error {
-	return migrateIfNeeded(ctx, targetDB, network, log, defaultMigrations)
+// MigrateIfNeeded applies the migrations the database is missing and records which junoVersion applied them.
+func MigrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network, junoVersion string,
+	log utils.SimpleLogger,
+) error {
+	return migrateIfNeeded(ctx, targetDB, network, junoVersion, log, defaultMigrations)
 }
 
-func migrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network, log utils.SimpleLogger, migrations []Migration) error {
+func migrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network, junoVersion string,
+	log utils.SimpleLogger, migrations []Migration,
+) error {
 	/*
 		Schema metadata of the targetDB determines which set of migrations need to be applied to the database.
 		After a migration is successfully executed, which may update the database, the schema version is incremented
@@ -90,6 +121,9 @@ func migrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network,
 	if err != nil {
 		return err
 	}
+	if err = checkSchemaVersion(metadata, migrations); err != nil {
+		return err
+	}
 
 	for i := metadata.Version; i < uint64(len(migrations)); i++ {
 		if err = ctx.Err(); err != nil {
@@ -108,6 +142,11 @@ func migrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network,
 				case err == nil || errors.Is(err, ctx.Err()):
 					if metadata.IntermediateState == nil {
 						metadata.Version++
+						metadata.History = append(metadata.History, MigrationRecord{
+							Version:     metadata.Version,
+							JunoVersion: junoVersion,
+							AppliedAt:   time.Now().UTC(),
+						})
 					}
 					return updateSchemaMetadata(txn, metadata)
 				case errors.Is(err, ErrCallWithNewTransaction):
@@ -127,6 +166,24 @@ func migrateIfNeeded(ctx context.Context, targetDB db.DB, network utils.Network,
 	return nil
 }
 
+// CheckSchemaVersion refuses databases migrated by a newer version of Juno, which must not be read before
+// MigrateIfNeeded is called.
+func CheckSchemaVersion(targetDB db.DB) error {
+	metadata, err := SchemaMetadata(targetDB)
+	if err != nil {
+		return err
+	}
+	return checkSchemaVersion(metadata, defaultMigrations)
+}
+
+// checkSchemaVersion refuses databases migrated by a newer version of Juno, whose layout is unknown to this one
+func checkSchemaVersion(metadata schemaMetadata, migrations []Migration) error {
+	if metadata.Version > uint64(len(migrations)) {
+		return fmt.Errorf("%w: schema version %d, latest known %d", ErrSchemaTooNew, metadata.Version, len(migrations))
+	}
+	return nil
+}
+
 // SchemaMetadata retrieves metadata about a database schema from the given database.
 func SchemaMetadata(targetDB db.DB) (schemaMetadata, error) {
 	metadata := schemaMetadata{}
@@ -134,8 +191,12 @@ func SchemaMetadata(targetDB db.DB) (schemaMetadata, error) {
 	if err != nil {
 		return metadata, err
 	}
+	// the history follows the version, so that versions of Juno that predate it still read the version
 	if err := txn.Get(db.SchemaVersion.Key(), func(b []byte) error {
 		metadata.Version = binary.BigEndian.Uint64(b)
+		if history := b[8:]; len(history) > 0 { //nolint:gomnd
+			return cbor.Unmarshal(history, &metadata.History)
+		}
 		return nil
 	}); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
 		return metadata, utils.RunAndWrapOnError(txn.Discard, err)
@@ -153,17 +214,21 @@ func SchemaMetadata(targetDB db.DB) (schemaMetadata, error) {
 // updateSchemaMetadata updates the schema in given database.
 func updateSchemaMetadata(txn db.Transaction, schema schemaMetadata) error {
 	var (
-		version [8]byte
 		state   []byte
+		history []byte
 		err     error
 	)
-	binary.BigEndian.PutUint64(version[:], schema.Version)
 	state, err = cbor.Marshal(schema.IntermediateState)
 	if err != nil {
 		return err
 	}
+	history, err = cbor.Marshal(schema.History)
+	if err != nil {
+		return err
+	}
 
-	if err := txn.Set(db.SchemaVersion.Key(), version[:]); err != nil {
+	version := binary.BigEndian.AppendUint64(nil, schema.Version)
+	if err := txn.Set(db.SchemaVersion.Key(), append(version, history...)); err != nil {
 		return err
 	}
 	return txn.Set(db.SchemaIntermediateState.Key(), state)
@@ -433,14 +498,21 @@ func calculateBlockCommitments(txn db.Transaction, network utils.Network) error
 	for blockNumber := 0; ; blockNumber++ {
 		txnLock.RLock()
 		block, err := blockchain.BlockByNumber(txn, uint64(blockNumber))
+		if err != nil {
+			txnLock.RUnlock()
+			if errors.Is(err, db.ErrKeyNotFound) {
+				break
+			}
+			return err
+		}
+		stateUpdate, err := blockchain.StateUpdateByNumber(txn, uint64(blockNumber))
 		txnLock.RUnlock()
-
-		if errors.Is(err, db.ErrKeyNotFound) {
-			break
+		if err != nil {
+			return err
 		}
 
 		workerPool.Go(func() error {
-			commitments, err := core.VerifyBlockHash(block, network)
+			commitments, err := core.VerifyBlockHash(block, network, stateUpdate.StateDiff)
 			if err != nil {
 				return err
 			}
@@ -630,3 +702,114 @@ func changeStateDiffStruct(txn db.Transaction, key, value []byte, _ utils.Networ
 
 	return nil
 }
+
+// indexAccountTransactions back-fills the account transaction index for transactions stored before it existed
+func indexAccountTransactions(txn db.Transaction, key, value []byte, _ utils.Network) error {
+	const bnIndexSize = 16
+	if len(key) != 1+bnIndexSize {
+		return fmt.Errorf("unexpected transaction key length: %d", len(key))
+	}
+
+	var tx core.Transaction
+	if err := encoder.Unmarshal(value, &tx); err != nil {
+		return fmt.Errorf("unmarshal: %v", err)
+	}
+
+	blockNumber := binary.BigEndian.Uint64(key[1:9])
+	index := binary.BigEndian.Uint64(key[9:])
+	return blockchain.StoreAccountTransaction(txn, blockNumber, index, tx)
+}
+
+// indexContractClasses back-fills the index of contracts by class hash for contracts deployed before it existed
+func indexContractClasses(txn db.Transaction, key, value []byte, _ utils.Network) error {
+	addr := new(felt.Felt).SetBytes(key[1:])
+	classHash := new(felt.Felt).SetBytes(value)
+	return core.IndexContractClass(txn, classHash, addr)
+}
+
+// indexClassSelectors back-fills the index of classes by entry point selector for classes declared before it existed
+func indexClassSelectors(txn db.Transaction, key, value []byte, _ utils.Network) error {
+	var declaredClass core.DeclaredClass
+	if err := encoder.Unmarshal(value, &declaredClass); err != nil {
+		return fmt.Errorf("unmarshal: %v", err)
+	}
+
+	classHash := new(felt.Felt).SetBytes(key[1:])
+	return core.IndexClassSelectors(txn, classHash, declaredClass.Class)
+}
+
+// calculatePost0132Commitments computes the receipt and state diff commitments, along with the Poseidon based
+// transaction and event commitments, of blocks following Starknet 0.13.2 stored before they were introduced
+func calculatePost0132Commitments(txn db.Transaction, _, value []byte, _ utils.Network) error {
+	var header core.Header
+	if err := encoder.Unmarshal(value, &header); err != nil {
+		return fmt.Errorf("unmarshal: %v", err)
+	}
+
+	blockVersion, err := core.ParseBlockVersion(header.ProtocolVersion)
+	if err != nil {
+		return err
+	}
+	// blockVersion < 0.13.2
+	if blockVersion.LessThan(semver.MustParse("0.13.2")) {
+		return nil
+	}
+
+	block, err := blockchain.BlockByNumber(txn, header.Number)
+	if err != nil {
+		return err
+	}
+	stateUpdate, err := blockchain.StateUpdateByNumber(txn, header.Number)
+	if err != nil {
+		return err
+	}
+
+	commitments, err := core.Post0132Commitments(block, stateUpdate.StateDiff)
+	if err != nil {
+		return err
+	}
+	return blockchain.StoreBlockCommitments(txn, header.Number, commitments)
+}
+
+// recompressValues rewrites the values of a bucket, which compresses them with the algorithm the database is
+// configured to compress the bucket with, whatever they were stored with before.
+func recompressValues(bucket db.Bucket) *BucketMigrator {
+	return NewBucketMigrator(bucket, func(txn db.Transaction, key, value []byte, _ utils.Network) error {
+		return txn.Set(key, value)
+	}).WithBatchSize(10_000) //nolint:gomnd
+}
+
+// Recompress rewrites the values of the buckets targetDB compresses, so that the values stored before its
+// compression was configured, or with other algorithms, are compressed with the configured ones. Unlike migrations,
+// it is not recorded in the schema and is applied whenever the compression configuration changes.
+func Recompress(ctx context.Context, targetDB *compress.DB, log utils.SimpleLogger) error {
+	for _, bucket := range compress.Buckets {
+		algorithm := targetDB.Algorithm(bucket)
+		if algorithm == compress.None {
+			continue
+		}
+
+		log.Infow("Recompressing values", "bucket", bucket, "algorithm", algorithm)
+		m := recompressValues(bucket)
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			callWithNewTransaction := false
+			if err := targetDB.Update(func(txn db.Transaction) error {
+				_, err := m.Migrate(ctx, txn, utils.Mainnet)
+				if errors.Is(err, ErrCallWithNewTransaction) {
+					callWithNewTransaction = true
+					return nil
+				}
+				return err
+			}); err != nil {
+				return err
+			} else if !callWithNewTransaction {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/core/trie"
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/compress"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/encoder"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
@@ -354,7 +355,7 @@ func TestMigrateIfNeededInternal(t *testing.T) {
 				},
 			},
 		}
-		require.ErrorContains(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, utils.NewNopZapLogger(), migrations), "bar")
+		require.ErrorContains(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger(), migrations), "bar")
 	})
 
 	t.Run("call with new tx", func(t *testing.T) {
@@ -374,7 +375,7 @@ func TestMigrateIfNeededInternal(t *testing.T) {
 				},
 			},
 		}
-		require.NoError(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, utils.NewNopZapLogger(), migrations))
+		require.NoError(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger(), migrations))
 	})
 
 	t.Run("error during migration", func(t *testing.T) {
@@ -389,10 +390,45 @@ func TestMigrateIfNeededInternal(t *testing.T) {
 				},
 			},
 		}
-		require.ErrorContains(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, utils.NewNopZapLogger(), migrations), "foo")
+		require.ErrorContains(t, migrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger(), migrations), "foo")
 	})
 }
 
+func TestDryRun(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		for i := byte(0); i < 3; i++ {
+			if err := txn.Set(db.Temporary.Key([]byte{i}), []byte{i}); err != nil {
+				return err
+			}
+		}
+		return txn.Set(db.StateTrie.Key(), []byte{1})
+	}))
+
+	noop := func(db.Transaction, []byte, []byte, utils.Network) error { return nil }
+	migrations := []Migration{
+		touches(MigrationFunc(migration0000), db.Class),
+		NewBucketMover(db.Temporary, db.StateTrie),
+		NewBucketMigrator(db.StateTrie, noop),
+	}
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		return updateSchemaMetadata(txn, schemaMetadata{Version: 1, IntermediateState: []byte{1}})
+	}))
+
+	estimates, err := dryRun(context.Background(), testDB, migrations)
+	require.NoError(t, err)
+	require.Len(t, estimates, 2)
+
+	assert.Equal(t, "moveTemporaryToStateTrie", estimates[0].Name)
+	assert.True(t, estimates[0].InProgress)
+	assert.Equal(t, []db.Bucket{db.Temporary, db.StateTrie}, estimates[0].Buckets)
+	assert.Equal(t, map[db.Bucket]uint64{db.Temporary: 3, db.StateTrie: 1}, estimates[0].Keys)
+
+	assert.Equal(t, "TestDryRun", estimates[1].Name)
+	assert.False(t, estimates[1].InProgress)
+	assert.Equal(t, map[db.Bucket]uint64{db.StateTrie: 1}, estimates[1].Keys)
+}
+
 func TestChangeStateDiffStructEmptyDB(t *testing.T) {
 	testdb := pebble.NewMemTest(t)
 	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
@@ -568,3 +604,207 @@ func TestChangeStateDiffStruct(t *testing.T) {
 		return nil
 	}))
 }
+
+func TestIndexAccountTransactions(t *testing.T) {
+	testdb := pebble.NewMemTest(t)
+	chain := blockchain.New(testdb, utils.Mainnet, utils.NewNopZapLogger())
+	client := feeder.NewTestClient(t, utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	for i := uint64(0); i < 3; i++ {
+		b, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(b, &core.BlockCommitments{}, su, nil))
+	}
+
+	indexEntries := func() map[string][]byte {
+		entries := make(map[string][]byte)
+		require.NoError(t, testdb.View(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			for it.Seek(db.AccountTransactions.Key()); it.Valid(); it.Next() {
+				key := it.Key()
+				if key[0] != byte(db.AccountTransactions) && key[0] != byte(db.AccountTransactionsByNonce) {
+					break
+				}
+				value, err := it.Value()
+				require.NoError(t, err)
+				entries[string(key)] = value
+			}
+			return it.Close()
+		}))
+		return entries
+	}
+
+	expected := indexEntries()
+	require.NotEmpty(t, expected)
+
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		for key := range expected {
+			require.NoError(t, txn.Delete([]byte(key)))
+		}
+		return nil
+	}))
+	require.Empty(t, indexEntries())
+
+	migrator := NewBucketMigrator(db.TransactionsByBlockNumberAndIndex, indexAccountTransactions)
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		_, err := migrator.Migrate(context.Background(), txn, utils.Mainnet)
+		return err
+	}))
+	assert.Equal(t, expected, indexEntries())
+}
+
+func TestIndexContractClassesAndSelectors(t *testing.T) {
+	testdb := pebble.NewMemTest(t)
+	chain := blockchain.New(testdb, utils.Mainnet, utils.NewNopZapLogger())
+	client := feeder.NewTestClient(t, utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	for i := uint64(0); i < 3; i++ {
+		b, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(b, &core.BlockCommitments{}, su, nil))
+	}
+
+	// classes stored before the selector index existed
+	classHash := utils.HexToFelt(t, "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8")
+	class, err := gw.Class(context.Background(), classHash)
+	require.NoError(t, err)
+	encodedClass, err := encoder.Marshal(core.DeclaredClass{At: 0, Class: class})
+	require.NoError(t, err)
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		return txn.Set(db.Class.Key(classHash.Marshal()), encodedClass)
+	}))
+
+	contractEntries := func() map[string]struct{} {
+		entries := make(map[string]struct{})
+		require.NoError(t, testdb.View(func(txn db.Transaction) error {
+			it, err := txn.NewIterator()
+			require.NoError(t, err)
+			prefix := db.ContractAddressesByClassHash.Key()
+			for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+				entries[string(it.Key())] = struct{}{}
+			}
+			return it.Close()
+		}))
+		return entries
+	}
+
+	expected := contractEntries()
+	require.NotEmpty(t, expected)
+
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		for key := range expected {
+			require.NoError(t, txn.Delete([]byte(key)))
+		}
+		return nil
+	}))
+	require.Empty(t, contractEntries())
+
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		for _, migrator := range []*BucketMigrator{
+			NewBucketMigrator(db.ContractClassHash, indexContractClasses),
+			NewBucketMigrator(db.Class, indexClassSelectors),
+		} {
+			if _, err := migrator.Migrate(context.Background(), txn, utils.Mainnet); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	assert.Equal(t, expected, contractEntries())
+
+	cairo0Class, ok := class.(*core.Cairo0Class)
+	require.True(t, ok)
+	require.NotEmpty(t, cairo0Class.Externals)
+	require.NoError(t, testdb.View(func(txn db.Transaction) error {
+		state := core.NewState(txn)
+		for _, entryPoint := range cairo0Class.Externals {
+			classHashes, _, err := state.ClassHashesBySelector(entryPoint.Selector, &felt.Zero, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []*felt.Felt{classHash}, classHashes)
+		}
+		return nil
+	}))
+}
+
+func TestCalculatePost0132Commitments(t *testing.T) {
+	testdb := pebble.NewMemTest(t)
+	chain := blockchain.New(testdb, utils.Mainnet, utils.NewNopZapLogger())
+	client := feeder.NewTestClient(t, utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	var post0132Block *core.Block
+	var post0132StateUpdate *core.StateUpdate
+	for i := uint64(0); i < 3; i++ {
+		b, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		if i == 2 {
+			b.ProtocolVersion = "0.13.2"
+			post0132Block, post0132StateUpdate = b, su
+		}
+		require.NoError(t, chain.Store(b, &core.BlockCommitments{}, su, nil))
+	}
+
+	migrator := NewBucketMigrator(db.BlockHeadersByNumber, calculatePost0132Commitments)
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		_, err := migrator.Migrate(context.Background(), txn, utils.Mainnet)
+		return err
+	}))
+
+	for i := uint64(0); i < 2; i++ {
+		commitments, err := chain.BlockCommitmentsByNumber(i)
+		require.NoError(t, err)
+		assert.Equal(t, &core.BlockCommitments{}, commitments)
+	}
+
+	expected, err := core.Post0132Commitments(post0132Block, post0132StateUpdate.StateDiff)
+	require.NoError(t, err)
+	commitments, err := chain.BlockCommitmentsByNumber(2)
+	require.NoError(t, err)
+	assert.Equal(t, expected, commitments)
+	assert.NotNil(t, commitments.StateDiffCommitment)
+}
+
+func TestRecompressValues(t *testing.T) {
+	inner := pebble.NewMemTest(t)
+	class := bytes.Repeat([]byte("sierra_program"), 1000)
+	receipt := bytes.Repeat([]byte("receipt"), 1000)
+	classKey := db.Class.Key([]byte("hash"))
+	receiptKey := db.ReceiptsByBlockNumberAndIndex.Key([]byte{0})
+	require.NoError(t, inner.Update(func(txn db.Transaction) error {
+		require.NoError(t, txn.Set(classKey, class))
+		return txn.Set(receiptKey, receipt)
+	}))
+
+	storedLen := func(key []byte) int {
+		var n int
+		require.NoError(t, inner.View(func(txn db.Transaction) error {
+			return txn.Get(key, func(val []byte) error {
+				n = len(val)
+				return nil
+			})
+		}))
+		return n
+	}
+
+	testDB, err := compress.New(inner, map[db.Bucket]compress.Algorithm{db.Class: compress.Zstd})
+	require.NoError(t, err)
+	require.NoError(t, Recompress(context.Background(), testDB, utils.NewNopZapLogger()))
+	assert.Less(t, storedLen(classKey), len(class))
+	assert.Equal(t, len(receipt), storedLen(receiptKey), "buckets without compression are not rewritten")
+
+	require.NoError(t, testDB.View(func(txn db.Transaction) error {
+		return txn.Get(classKey, func(val []byte) error {
+			assert.Equal(t, class, val)
+			return nil
+		})
+	}))
+}
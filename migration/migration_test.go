@@ -2,11 +2,14 @@ package migration_test
 
 import (
 	"context"
+	"encoding/binary"
 	"testing"
 
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/migration"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,7 +19,7 @@ func TestMigrateIfNeeded(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 	t.Run("Migration should not happen on cancelled ctx", func(t *testing.T) {
-		require.ErrorIs(t, migration.MigrateIfNeeded(ctx, testDB, utils.Mainnet, utils.NewNopZapLogger()), ctx.Err())
+		require.ErrorIs(t, migration.MigrateIfNeeded(ctx, testDB, utils.Mainnet, "", utils.NewNopZapLogger()), ctx.Err())
 	})
 
 	meta, err := migration.SchemaMetadata(testDB)
@@ -25,7 +28,7 @@ func TestMigrateIfNeeded(t *testing.T) {
 	require.Nil(t, meta.IntermediateState)
 
 	t.Run("Migration should happen on empty DB", func(t *testing.T) {
-		require.NoError(t, migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, utils.NewNopZapLogger()))
+		require.NoError(t, migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger()))
 	})
 
 	meta, err = migration.SchemaMetadata(testDB)
@@ -34,9 +37,66 @@ func TestMigrateIfNeeded(t *testing.T) {
 	require.Nil(t, meta.IntermediateState)
 
 	t.Run("subsequent calls to MigrateIfNeeded should not change the DB version", func(t *testing.T) {
-		require.NoError(t, migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, utils.NewNopZapLogger()))
+		require.NoError(t, migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger()))
 		postVersion, postErr := migration.SchemaMetadata(testDB)
 		require.NoError(t, postErr)
 		require.Equal(t, meta, postVersion)
 	})
 }
+
+func TestMigrationStatus(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+
+	statuses, err := migration.Status(testDB)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for i, status := range statuses {
+		assert.Equal(t, uint64(i+1), status.Version)
+		assert.NotEmpty(t, status.Name)
+		assert.False(t, status.Applied)
+		assert.Nil(t, status.Record)
+	}
+	assert.Equal(t, "migration0000", statuses[0].Name)
+
+	estimates, err := migration.DryRun(context.Background(), testDB)
+	require.NoError(t, err)
+	require.Len(t, estimates, len(statuses))
+	for _, estimate := range estimates {
+		require.Len(t, estimate.Keys, len(estimate.Buckets), estimate.Name)
+	}
+
+	// a dry run does not change the database
+	meta, err := migration.SchemaMetadata(testDB)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), meta.Version)
+
+	require.NoError(t, migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, "v0.11.0", utils.NewNopZapLogger()))
+	statuses, err = migration.Status(testDB)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		require.NotNil(t, status.Record)
+		assert.Equal(t, status.Version, status.Record.Version)
+		assert.Equal(t, "v0.11.0", status.Record.JunoVersion)
+		assert.False(t, status.Record.AppliedAt.IsZero())
+	}
+
+	estimates, err = migration.DryRun(context.Background(), testDB)
+	require.NoError(t, err)
+	assert.Empty(t, estimates)
+}
+
+func TestMigrateIfNeededRefusesNewerSchema(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	var version [8]byte
+	binary.BigEndian.PutUint64(version[:], 1_000_000)
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		return txn.Set(db.SchemaVersion.Key(), version[:])
+	}))
+
+	err := migration.MigrateIfNeeded(context.Background(), testDB, utils.Mainnet, "", utils.NewNopZapLogger())
+	require.ErrorIs(t, err, migration.ErrSchemaTooNew)
+	_, err = migration.Status(testDB)
+	require.ErrorIs(t, err, migration.ErrSchemaTooNew)
+	require.ErrorIs(t, migration.CheckSchemaVersion(testDB), migration.ErrSchemaTooNew)
+}
@@ -0,0 +1,188 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// Describer is implemented by migrations that can tell what they change, for the status of migrations and dry runs.
+type Describer interface {
+	Name() string
+	// Buckets returns the buckets the migration iterates over or writes to
+	Buckets() []db.Bucket
+}
+
+var (
+	_ Describer = MigrationFunc(nil)
+	_ Describer = (*BucketMigrator)(nil)
+	_ Describer = touching{}
+)
+
+// Name returns the name of f.
+func (f MigrationFunc) Name() string { return funcName(f) }
+
+// Buckets returns nil, the buckets f touches are unknown.
+func (f MigrationFunc) Buckets() []db.Bucket { return nil }
+
+// touching adds the buckets a migration touches to its description
+type touching struct {
+	Migration
+	buckets []db.Bucket
+}
+
+func touches(m Migration, buckets ...db.Bucket) touching {
+	return touching{Migration: m, buckets: buckets}
+}
+
+func (t touching) Name() string { return migrationName(t.Migration) }
+
+func (t touching) Buckets() []db.Bucket {
+	var buckets []db.Bucket
+	if d, ok := t.Migration.(Describer); ok {
+		buckets = d.Buckets()
+	}
+	return append(buckets, t.buckets...)
+}
+
+func migrationName(m Migration) string {
+	if d, ok := m.(Describer); ok {
+		return d.Name()
+	}
+	return reflect.Indirect(reflect.ValueOf(m)).Type().Name()
+}
+
+func migrationBuckets(m Migration) []db.Bucket {
+	if d, ok := m.(Describer); ok {
+		return d.Buckets()
+	}
+	return nil
+}
+
+// funcName returns the name of a function of this package, or of the function a closure was returned by
+func funcName(f any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	_, name, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// MigrationStatus is the state of a migration in a database.
+type MigrationStatus struct {
+	// Version is the schema version of the database once the migration is applied
+	Version    uint64
+	Name       string
+	Buckets    []db.Bucket
+	Applied    bool
+	InProgress bool
+	// Record is nil for pending migrations, and for those applied before the history was kept
+	Record *MigrationRecord
+}
+
+// Status lists the migrations applied to the database and those pending.
+func Status(targetDB db.DB) ([]MigrationStatus, error) {
+	return status(targetDB, defaultMigrations)
+}
+
+func status(targetDB db.DB, migrations []Migration) ([]MigrationStatus, error) {
+	metadata, err := SchemaMetadata(targetDB)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSchemaVersion(metadata, migrations); err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint64]MigrationRecord, len(metadata.History))
+	for _, record := range metadata.History {
+		records[record.Version] = record
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for i, m := range migrations {
+		s := MigrationStatus{
+			Version:    uint64(i) + 1,
+			Name:       migrationName(m),
+			Buckets:    migrationBuckets(m),
+			Applied:    uint64(i) < metadata.Version,
+			InProgress: uint64(i) == metadata.Version && metadata.IntermediateState != nil,
+		}
+		if record, ok := records[s.Version]; ok && s.Applied {
+			s.Record = &record
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// MigrationEstimate is what a pending migration would touch if it was applied.
+type MigrationEstimate struct {
+	MigrationStatus
+	// Keys is the number of keys in each of the buckets the migration touches. They are counted before any
+	// migration is applied, so they are estimates for the migrations following one that changes the same buckets.
+	Keys map[db.Bucket]uint64
+}
+
+// DryRun reports the buckets the pending migrations touch and how many keys they hold, without changing the
+// database.
+func DryRun(ctx context.Context, targetDB db.DB) ([]MigrationEstimate, error) {
+	return dryRun(ctx, targetDB, defaultMigrations)
+}
+
+func dryRun(ctx context.Context, targetDB db.DB, migrations []Migration) ([]MigrationEstimate, error) {
+	statuses, err := status(targetDB, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := make(map[db.Bucket]uint64)
+	var estimates []MigrationEstimate
+	err = targetDB.View(func(txn db.Transaction) error {
+		for _, s := range statuses {
+			if s.Applied {
+				continue
+			}
+
+			estimate := MigrationEstimate{MigrationStatus: s, Keys: make(map[db.Bucket]uint64, len(s.Buckets))}
+			for _, bucket := range s.Buckets {
+				keys, ok := counted[bucket]
+				if !ok {
+					if keys, err = countKeys(ctx, txn, bucket); err != nil {
+						return err
+					}
+					counted[bucket] = keys
+				}
+				estimate.Keys[bucket] = keys
+			}
+			estimates = append(estimates, estimate)
+		}
+		return nil
+	})
+	return estimates, err
+}
+
+func countKeys(ctx context.Context, txn db.Transaction, bucket db.Bucket) (uint64, error) {
+	it, err := txn.NewIterator()
+	if err != nil {
+		return 0, err
+	}
+
+	const ctxCheckInterval = 10_000
+	var keys uint64
+	prefix := bucket.Key()
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		keys++
+		if keys%ctxCheckInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				return 0, utils.RunAndWrapOnError(it.Close, err)
+			}
+		}
+	}
+	return keys, it.Close()
+}
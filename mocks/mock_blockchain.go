@@ -115,6 +115,118 @@ func (mr *MockReaderMockRecorder) BlockHeaderByNumber(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHeaderByNumber", reflect.TypeOf((*MockReader)(nil).BlockHeaderByNumber), arg0)
 }
 
+// ClassHashesBySelector mocks base method.
+func (m *MockReader) ClassHashesBySelector(arg0, arg1 *felt.Felt, arg2 uint64) ([]*felt.Felt, *felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClassHashesBySelector", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(*felt.Felt)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClassHashesBySelector indicates an expected call of ClassHashesBySelector.
+func (mr *MockReaderMockRecorder) ClassHashesBySelector(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassHashesBySelector", reflect.TypeOf((*MockReader)(nil).ClassHashesBySelector), arg0, arg1, arg2)
+}
+
+// ContractClassHashChanges mocks base method.
+func (m *MockReader) ContractClassHashChanges(arg0 *felt.Felt, arg1, arg2, arg3 uint64) ([]core.ValueChange, *uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractClassHashChanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]core.ValueChange)
+	ret1, _ := ret[1].(*uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractClassHashChanges indicates an expected call of ContractClassHashChanges.
+func (mr *MockReaderMockRecorder) ContractClassHashChanges(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractClassHashChanges", reflect.TypeOf((*MockReader)(nil).ContractClassHashChanges), arg0, arg1, arg2, arg3)
+}
+
+// ContractNonceChanges mocks base method.
+func (m *MockReader) ContractNonceChanges(arg0 *felt.Felt, arg1, arg2, arg3 uint64) ([]core.ValueChange, *uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractNonceChanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]core.ValueChange)
+	ret1, _ := ret[1].(*uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractNonceChanges indicates an expected call of ContractNonceChanges.
+func (mr *MockReaderMockRecorder) ContractNonceChanges(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractNonceChanges", reflect.TypeOf((*MockReader)(nil).ContractNonceChanges), arg0, arg1, arg2, arg3)
+}
+
+// ContractStorageChanges mocks base method.
+func (m *MockReader) ContractStorageChanges(arg0, arg1 *felt.Felt, arg2, arg3, arg4 uint64) ([]core.ValueChange, *uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractStorageChanges", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]core.ValueChange)
+	ret1, _ := ret[1].(*uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractStorageChanges indicates an expected call of ContractStorageChanges.
+func (mr *MockReaderMockRecorder) ContractStorageChanges(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractStorageChanges", reflect.TypeOf((*MockReader)(nil).ContractStorageChanges), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ContractStorageRange mocks base method.
+func (m *MockReader) ContractStorageRange(arg0, arg1 *felt.Felt, arg2, arg3 uint64) ([]core.StorageEntry, *felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractStorageRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]core.StorageEntry)
+	ret1, _ := ret[1].(*felt.Felt)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractStorageRange indicates an expected call of ContractStorageRange.
+func (mr *MockReaderMockRecorder) ContractStorageRange(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractStorageRange", reflect.TypeOf((*MockReader)(nil).ContractStorageRange), arg0, arg1, arg2, arg3)
+}
+
+// ContractsByClassHash mocks base method.
+func (m *MockReader) ContractsByClassHash(arg0, arg1 *felt.Felt, arg2 uint64) ([]*felt.Felt, *felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractsByClassHash", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(*felt.Felt)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractsByClassHash indicates an expected call of ContractsByClassHash.
+func (mr *MockReaderMockRecorder) ContractsByClassHash(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractsByClassHash", reflect.TypeOf((*MockReader)(nil).ContractsByClassHash), arg0, arg1, arg2)
+}
+
+// ContractsRange mocks base method.
+func (m *MockReader) ContractsRange(arg0 *felt.Felt, arg1, arg2 uint64) ([]core.ContractEntry, *felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractsRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]core.ContractEntry)
+	ret1, _ := ret[1].(*felt.Felt)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContractsRange indicates an expected call of ContractsRange.
+func (mr *MockReaderMockRecorder) ContractsRange(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractsRange", reflect.TypeOf((*MockReader)(nil).ContractsRange), arg0, arg1, arg2)
+}
+
 // EventFilter mocks base method.
 func (m *MockReader) EventFilter(arg0 *felt.Felt, arg1 [][]felt.Felt) (*blockchain.EventFilter, error) {
 	m.ctrl.T.Helper()
@@ -345,3 +457,50 @@ func (mr *MockReaderMockRecorder) TransactionByHash(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionByHash", reflect.TypeOf((*MockReader)(nil).TransactionByHash), arg0)
 }
+
+// TransactionBySenderAndNonce mocks base method.
+func (m *MockReader) TransactionBySenderAndNonce(arg0, arg1 *felt.Felt) (*blockchain.AccountTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionBySenderAndNonce", arg0, arg1)
+	ret0, _ := ret[0].(*blockchain.AccountTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionBySenderAndNonce indicates an expected call of TransactionBySenderAndNonce.
+func (mr *MockReaderMockRecorder) TransactionBySenderAndNonce(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionBySenderAndNonce", reflect.TypeOf((*MockReader)(nil).TransactionBySenderAndNonce), arg0, arg1)
+}
+
+// TransactionsByAddress mocks base method.
+func (m *MockReader) TransactionsByAddress(arg0 *felt.Felt, arg1 *blockchain.AccountTransactionsToken, arg2 uint64) ([]*blockchain.AccountTransaction, *blockchain.AccountTransactionsToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionsByAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*blockchain.AccountTransaction)
+	ret1, _ := ret[1].(*blockchain.AccountTransactionsToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TransactionsByAddress indicates an expected call of TransactionsByAddress.
+func (mr *MockReaderMockRecorder) TransactionsByAddress(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionsByAddress", reflect.TypeOf((*MockReader)(nil).TransactionsByAddress), arg0, arg1, arg2)
+}
+
+// TriesAtBlockNumber mocks base method.
+func (m *MockReader) TriesAtBlockNumber(arg0 uint64) (core.TrieReader, func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriesAtBlockNumber", arg0)
+	ret0, _ := ret[0].(core.TrieReader)
+	ret1, _ := ret[1].(func() error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TriesAtBlockNumber indicates an expected call of TriesAtBlockNumber.
+func (mr *MockReaderMockRecorder) TriesAtBlockNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriesAtBlockNumber", reflect.TypeOf((*MockReader)(nil).TriesAtBlockNumber), arg0)
+}
@@ -9,9 +9,12 @@ import (
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/compress"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/l1"
+	"github.com/NethermindEth/juno/p2p/starknet"
 	"github.com/NethermindEth/juno/sync"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -77,6 +80,38 @@ func makeDBMetrics() db.EventListener {
 	}
 }
 
+func makeCompressionMetrics() compress.EventListener {
+	labels := []string{"bucket", "algorithm"}
+	sizes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "db",
+		Subsystem: "compression",
+		Name:      "bytes",
+	}, labels)
+	storedSizes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "db",
+		Subsystem: "compression",
+		Name:      "stored_bytes",
+	}, labels)
+	ratios := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "db",
+		Subsystem: "compression",
+		Name:      "ratio",
+		Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10), //nolint:gomnd
+	}, labels)
+
+	prometheus.MustRegister(sizes, storedSizes, ratios)
+	return &compress.SelectiveListener{
+		OnCompressCb: func(bucket db.Bucket, algorithm compress.Algorithm, size, storedSize int) {
+			labelValues := []string{bucket.String(), algorithm.String()}
+			sizes.WithLabelValues(labelValues...).Add(float64(size))
+			storedSizes.WithLabelValues(labelValues...).Add(float64(storedSize))
+			if size > 0 {
+				ratios.WithLabelValues(labelValues...).Observe(float64(storedSize) / float64(size))
+			}
+		},
+	}
+}
+
 func makeHTTPMetrics() jsonrpc.NewRequestListener {
 	reqCounter := prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "rpc",
@@ -161,6 +196,10 @@ func makeSyncMetrics(syncReader sync.Reader, bcReader blockchain.Reader) sync.Ev
 		Namespace: "sync",
 		Name:      "reorganisations",
 	})
+	reexecutionMismatchCount := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "sync",
+		Name:      "reexecution_mismatches",
+	})
 	chainHeightGauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Namespace: "sync",
 		Name:      "blockchain_height",
@@ -179,7 +218,7 @@ func makeSyncMetrics(syncReader sync.Reader, bcReader blockchain.Reader) sync.Ev
 		return 0
 	})
 
-	prometheus.MustRegister(opTimerHistogram, blockCount, chainHeightGauge, bestBlockGauge, reorgCount)
+	prometheus.MustRegister(opTimerHistogram, blockCount, chainHeightGauge, bestBlockGauge, reorgCount, reexecutionMismatchCount)
 
 	return &sync.SelectiveListener{
 		OnSyncStepDoneCb: func(op string, blockNum uint64, took time.Duration) {
@@ -191,6 +230,9 @@ func makeSyncMetrics(syncReader sync.Reader, bcReader blockchain.Reader) sync.Ev
 		OnReorgCb: func(blockNum uint64) {
 			reorgCount.Inc()
 		},
+		OnReexecutionMismatchCb: func(blockNum uint64, mismatches []string) {
+			reexecutionMismatchCount.Inc()
+		},
 	}
 }
 
@@ -245,3 +287,34 @@ func makeFeederMetrics() feeder.EventListener {
 		},
 	}
 }
+
+func makeP2PMetrics() starknet.EventListener {
+	peerScores := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "p2p",
+		Subsystem: "peer",
+		Name:      "score",
+		Help:      "The score of peers after their last protocol violation",
+	}, []string{"peer"})
+	penalties := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Subsystem: "peer",
+		Name:      "penalties",
+	}, []string{"violation"})
+	bans := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Subsystem: "peer",
+		Name:      "bans",
+	})
+	prometheus.MustRegister(peerScores, penalties, bans)
+	return &starknet.SelectiveListener{
+		OnPeerPenalisedCb: func(id peer.ID, violation starknet.Violation, score float64) {
+			peerScores.WithLabelValues(id.String()).Set(score)
+			penalties.WithLabelValues(violation.String()).Inc()
+		},
+		OnPeerBannedCb: func(id peer.ID) {
+			// banned peers are disconnected, so they are not kept around as labels
+			peerScores.DeleteLabelValues(id.String())
+			bans.Inc()
+		},
+	}
+}
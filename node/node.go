@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"time"
@@ -14,15 +15,22 @@ import (
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/clients/gateway"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
-	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/db/backends"
+	"github.com/NethermindEth/juno/db/compress"
+	"github.com/NethermindEth/juno/db/overlay"
 	"github.com/NethermindEth/juno/db/remote"
+	"github.com/NethermindEth/juno/feedergateway"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/l1"
 	"github.com/NethermindEth/juno/migration"
 	"github.com/NethermindEth/juno/p2p"
 	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/sequencer"
 	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/starknetdata"
+	"github.com/NethermindEth/juno/starknetdata/archive"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/upgrader"
@@ -41,6 +49,11 @@ const (
 	upgraderDelay    = 5 * time.Minute
 	githubAPIUrl     = "https://api.github.com/repos/NethermindEth/juno/releases/latest"
 	latestReleaseURL = "https://github.com/NethermindEth/juno/releases/latest"
+	// defaultSequencerAddress is where the sequencer collects the fees of the blocks it builds unless configured
+	// otherwise.
+	defaultSequencerAddress = "juno-sequencer"
+	// p2pKeyFile is where the private key of the p2p host is kept in the database directory by default.
+	p2pKeyFile = "p2p.key"
 )
 
 // Config is the top-level juno configuration.
@@ -63,21 +76,44 @@ type Config struct {
 	PprofPort           uint16         `mapstructure:"pprof-port"`
 	Colour              bool           `mapstructure:"colour"`
 	PendingPollInterval time.Duration  `mapstructure:"pending-poll-interval"`
+	SyncReexecution     string         `mapstructure:"sync-reexecution"`
 	RemoteDB            string         `mapstructure:"remote-db"`
+	FeederArchive       string         `mapstructure:"feeder-archive"`
+	FeederRecord        string         `mapstructure:"feeder-record"`
 
 	Metrics     bool   `mapstructure:"metrics"`
 	MetricsHost string `mapstructure:"metrics-host"`
 	MetricsPort uint16 `mapstructure:"metrics-port"`
 
-	P2P          bool   `mapstructure:"p2p"`
-	P2PAddr      string `mapstructure:"p2p-addr"`
-	P2PBootPeers string `mapstructure:"p2p-boot-peers"`
+	FeederGateway     bool   `mapstructure:"feeder-gateway"`
+	FeederGatewayHost string `mapstructure:"feeder-gateway-host"`
+	FeederGatewayPort uint16 `mapstructure:"feeder-gateway-port"`
+
+	Sequencer       bool          `mapstructure:"seq-enable"`
+	SeqBlockTime    time.Duration `mapstructure:"seq-block-time"`
+	SeqGenesisFile  string        `mapstructure:"seq-genesis-file"`
+	SeqForkDB       string        `mapstructure:"seq-fork-db"`
+	SeqForkRemoteDB string        `mapstructure:"seq-fork-remote-db"`
+	SeqForkHeight   *uint64       `mapstructure:"seq-fork-height"`
+	SeqAddress      string        `mapstructure:"seq-address"`
+
+	P2P           bool   `mapstructure:"p2p"`
+	P2PAddr       string `mapstructure:"p2p-addr"`
+	P2PBootPeers  string `mapstructure:"p2p-boot-peers"`
+	P2PPrivateKey string `mapstructure:"p2p-private-key"`
+	P2PKeyFile    string `mapstructure:"p2p-private-key-file"`
+	P2PForwardTxs bool   `mapstructure:"p2p-forward-transactions"`
+	P2PMDNS       bool   `mapstructure:"p2p-mdns"`
+	P2PAdminRPC   bool   `mapstructure:"p2p-admin-rpc"`
 
 	MaxVMs          uint `mapstructure:"max-vms"`
 	MaxVMQueue      uint `mapstructure:"max-vm-queue"`
 	RPCMaxBlockScan uint `mapstructure:"rpc-max-block-scan"`
 
-	DBCacheSize uint `mapstructure:"db-cache-size"`
+	DBBackend     string `mapstructure:"db-backend"`
+	DBCacheSize   uint   `mapstructure:"db-cache-size"`
+	DBCompression string `mapstructure:"db-compression"`
+	ArchiveTrie   bool   `mapstructure:"archive-trie"`
 }
 
 type Node struct {
@@ -99,6 +135,10 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		return nil, err
 	}
 
+	if cfg.FeederArchive != "" && cfg.FeederRecord != "" {
+		return nil, errors.New("cannot record feeder responses while syncing from a feeder archive")
+	}
+
 	dbLog, err := utils.NewZapLogger(utils.ERROR, cfg.Colour)
 	if err != nil {
 		return nil, fmt.Errorf("create DB logger: %w", err)
@@ -109,16 +149,35 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	if dbIsRemote {
 		database, err = remote.New(cfg.RemoteDB, context.TODO(), log, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	} else {
-		database, err = pebble.New(cfg.DatabasePath, cfg.DBCacheSize, dbLog)
+		database, err = backends.Open(cfg.DBBackend, cfg.DatabasePath, cfg.DBCacheSize, dbLog)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("open DB: %w", err)
 	}
+	if cfg.forking() {
+		if database, err = overlayForkDB(cfg, database, log, dbLog); err != nil {
+			return nil, err
+		}
+	} else if forked, markErr := overlay.IsOverlay(database); markErr != nil || forked {
+		if markErr == nil {
+			markErr = errors.New("the database holds the blocks built on top of a forked chain, which requires the fork options")
+		}
+		return nil, utils.RunAndWrapOnError(database.Close, fmt.Errorf("open DB: %w", markErr))
+	}
+	compressedDB, err := compressDB(cfg, database)
+	if err != nil {
+		return nil, err
+	}
+	database = compressedDB
+	// the layout of a database migrated by a newer version is unknown, so it must not be read
+	if err = migration.CheckSchemaVersion(database); err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, err)
+	}
 	ua := fmt.Sprintf("Juno/%s Starknet Client", version)
 
 	services := make([]service.Service, 0)
 
-	chain := blockchain.New(database, cfg.Network, log)
+	chain := blockchain.New(database, cfg.Network, log).WithArchive(cfg.ArchiveTrie)
 
 	// Verify that cfg.Network is compatible with the database.
 	head, err := chain.Head()
@@ -127,20 +186,63 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	}
 	if head != nil {
 		// We assume that there is at least one transaction in the block or that it is a pre-0.7 block.
-		if _, err = core.VerifyBlockHash(head, cfg.Network); err != nil {
+		stateUpdate, err := chain.StateUpdateByNumber(head.Number)
+		if err != nil {
+			return nil, fmt.Errorf("get head state update from database: %v", err)
+		}
+		if _, err = core.VerifyBlockHash(head, cfg.Network, stateUpdate.StateDiff); err != nil {
 			return nil, errors.New("unable to verify latest block hash; are the database and --network option compatible?")
 		}
 	}
 
 	feederClientTimeout := 5 * time.Second
 	client := feeder.NewClient(cfg.Network.FeederURL()).WithUserAgent(ua).WithLogger(log).WithTimeout(feederClientTimeout)
-	synchronizer := sync.New(chain, adaptfeeder.New(client), log, cfg.PendingPollInterval, dbIsRemote)
-	services = append(services, synchronizer)
-	gatewayClient := gateway.NewClient(cfg.Network.GatewayURL(), log).WithUserAgent(ua)
+	if cfg.FeederRecord != "" {
+		client = client.WithRecording(cfg.FeederRecord)
+	}
+
+	// In sequencer mode, blocks are built locally out of the transactions sent to the RPC server
+	// instead of being synced from the network.
+	var (
+		syncReader    sync.Reader
+		gatewayClient rpc.Gateway
+		synchronizer  *sync.Synchronizer
+		seq           *sequencer.Sequencer
+	)
+	if cfg.Sequencer {
+		if seq, err = newSequencer(cfg, chain, log); err != nil {
+			return nil, err
+		}
+		services = append(services, seq)
+		syncReader, gatewayClient = seq, seq
+	} else {
+		var starknetData starknetdata.StarknetData = adaptfeeder.New(client)
+		if cfg.FeederArchive != "" {
+			log.Infow("Syncing from feeder archive", "dir", cfg.FeederArchive)
+			starknetData = archive.New(cfg.FeederArchive)
+		}
+		synchronizer = sync.New(chain, starknetData, log, cfg.PendingPollInterval, dbIsRemote)
+		switch cfg.SyncReexecution {
+		case "":
+		case "warn":
+			synchronizer.WithReexecution(vm.New(log), sync.ReexecutionWarn)
+		case "halt":
+			synchronizer.WithReexecution(vm.New(log), sync.ReexecutionHalt)
+		default:
+			return nil, fmt.Errorf("unknown sync re-execution policy %q", cfg.SyncReexecution)
+		}
+		services = append(services, synchronizer)
+		syncReader = synchronizer
+		gatewayClient = gateway.NewClient(cfg.Network.GatewayURL(), log).WithUserAgent(ua)
+	}
 
 	throttledVM := NewThrottledVM(vm.New(log), cfg.MaxVMs, int32(cfg.MaxVMQueue))
-	rpcHandler := rpc.New(chain, synchronizer, cfg.Network, gatewayClient, client, throttledVM, version, log)
+	rpcHandler := rpc.New(chain, syncReader, cfg.Network, gatewayClient, client, throttledVM, version, log)
 	rpcHandler = rpcHandler.WithFilterLimit(cfg.RPCMaxBlockScan)
+	if !cfg.Sequencer {
+		// the sequencer compiles declared classes itself
+		rpcHandler = rpcHandler.WithCompiledClassHashCheck()
+	}
 	services = append(services, rpcHandler)
 	// to improve RPC throughput we double GOMAXPROCS
 	maxGoroutines := 2 * runtime.GOMAXPROCS(0)
@@ -149,6 +251,11 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	if err = jsonrpcServer.RegisterMethods(methods...); err != nil {
 		return nil, err
 	}
+	if seq != nil {
+		if err = jsonrpcServer.RegisterMethods(seq.Methods()...); err != nil {
+			return nil, err
+		}
+	}
 	jsonrpcServerLegacy := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
 	legacyMethods, legacyPath := rpcHandler.LegacyMethods()
 	if err = jsonrpcServerLegacy.RegisterMethods(legacyMethods...); err != nil {
@@ -172,13 +279,19 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		chain.WithListener(makeBlockchainMetrics())
 		makeJunoMetrics(version)
 		database.WithListener(makeDBMetrics())
+		compressedDB.WithCompressionListener(makeCompressionMetrics())
 		rpcMetrics, legacyRPCMetrics := makeRPCMetrics(path, legacyPath)
 		jsonrpcServer.WithListener(rpcMetrics)
 		jsonrpcServerLegacy.WithListener(legacyRPCMetrics)
-		synchronizer.WithListener(makeSyncMetrics(synchronizer, chain))
+		if synchronizer != nil {
+			synchronizer.WithListener(makeSyncMetrics(synchronizer, chain))
+		}
 		client.WithListener(makeFeederMetrics())
 		services = append(services, makeMetrics(cfg.MetricsHost, cfg.MetricsPort))
 	}
+	if cfg.FeederGateway {
+		services = append(services, makeHTTPService(cfg.FeederGatewayHost, cfg.FeederGatewayPort, feedergateway.New(chain, log)))
+	}
 	if cfg.GRPC {
 		services = append(services, makeGRPC(cfg.GRPCHost, cfg.GRPCPort, database, version))
 	}
@@ -195,9 +308,12 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		services:   services,
 	}
 
-	if n.cfg.EthNode == "" {
+	switch {
+	case cfg.Sequencer:
+		// Blocks built by the sequencer are never accepted on L1.
+	case n.cfg.EthNode == "":
 		n.log.Warnw("Ethereum node address not found; will not verify against L1")
-	} else {
+	default:
 		var ethNodeURL *url.URL
 		ethNodeURL, err = url.Parse(n.cfg.EthNode)
 		if err != nil {
@@ -218,12 +334,49 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	}
 
 	if cfg.P2P {
-		p2pService, err := p2p.New(cfg.P2PAddr, "juno", cfg.P2PBootPeers, "", cfg.Network, log)
+		// a remote database is read-only, so the peers of the node are not kept across restarts, and neither is
+		// its identity unless it has a key file
+		var p2pDB db.DB
+		keyFile := cfg.P2PKeyFile
+		if !dbIsRemote {
+			p2pDB = database
+			if keyFile == "" {
+				keyFile = filepath.Join(cfg.DatabasePath, p2pKeyFile)
+			}
+		}
+		p2pService, err := p2p.New(cfg.P2PAddr, "juno", cfg.P2PBootPeers, cfg.P2PPrivateKey, keyFile, cfg.Network,
+			log, p2pDB)
 		if err != nil {
 			return nil, fmt.Errorf("set up p2p service: %w", err)
 		}
 
-		n.services = append(n.services, p2pService)
+		p2pService.WithMDNS(cfg.P2PMDNS)
+		if err = jsonrpcServer.RegisterMethods(p2pService.Methods()...); err != nil {
+			return nil, err
+		}
+		if cfg.P2PAdminRPC {
+			if err = jsonrpcServer.RegisterMethods(p2pService.AdminMethods()...); err != nil {
+				return nil, err
+			}
+		}
+
+		if cfg.Metrics {
+			p2pService.PeerScores().WithListener(makeP2PMetrics())
+		}
+
+		// blocks are only handed to the synchronizer, the sequencer builds its own
+		var announcer p2p.BlockAnnouncer
+		if synchronizer != nil {
+			announcer = synchronizer
+		}
+		blockGossip := p2p.NewBlockGossip(p2pService, syncReader, announcer, cfg.Network, log)
+
+		txGossip := p2p.NewTransactionGossip(p2pService, cfg.Network, log)
+		if cfg.P2PForwardTxs {
+			txGossip.WithForwarder(gatewayClient)
+		}
+		rpcHandler.WithTransactionGossip(txGossip)
+		n.services = append(n.services, p2pService, blockGossip, txGossip)
 	}
 
 	if semversion, err := semver.NewVersion(version); err == nil {
@@ -236,6 +389,88 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	return n, nil
 }
 
+func (cfg *Config) forking() bool {
+	return cfg.SeqForkDB != "" || cfg.SeqForkRemoteDB != ""
+}
+
+// overlayForkDB layers the database the node was configured with on top of the database the sequencer forks from,
+// so that the forked chain is only read.
+func overlayForkDB(cfg *Config, database db.DB, log utils.SimpleLogger, dbLog *utils.ZapLogger) (db.DB, error) {
+	var err error
+	switch {
+	case !cfg.Sequencer:
+		err = errors.New("forking a chain requires the sequencer to be enabled")
+	case cfg.RemoteDB != "":
+		err = errors.New("cannot fork a chain into a remote database")
+	case cfg.SeqForkDB != "" && cfg.SeqForkRemoteDB != "":
+		err = errors.New("cannot fork both a local and a remote database")
+	}
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, err)
+	}
+
+	var base db.DB
+	if cfg.SeqForkRemoteDB != "" {
+		base, err = remote.New(cfg.SeqForkRemoteDB, context.TODO(), log, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		base, err = backends.OpenReadOnly(cfg.SeqForkDB, cfg.DBCacheSize, dbLog)
+	}
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, fmt.Errorf("open fork DB: %w", err))
+	}
+
+	forkDB, err := overlay.Open(base, database)
+	if err != nil {
+		closeErr := errors.Join(base.Close(), database.Close())
+		return nil, utils.RunAndWrapOnError(func() error { return closeErr }, fmt.Errorf("open DB on top of the fork DB: %w", err))
+	}
+	return forkDB, nil
+}
+
+// compressDB compresses the values of the buckets cfg configures compression for. Databases are wrapped even without
+// compression configured, so that the values compressed while it was are still read.
+func compressDB(cfg *Config, database db.DB) (*compress.DB, error) {
+	algorithms, err := compress.ParseConfig(cfg.DBCompression)
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, fmt.Errorf("configure DB compression: %w", err))
+	}
+	compressedDB, err := compress.New(database, algorithms)
+	if err != nil {
+		return nil, utils.RunAndWrapOnError(database.Close, fmt.Errorf("configure DB compression: %w", err))
+	}
+	return compressedDB, nil
+}
+
+func newSequencer(cfg *Config, chain *blockchain.Blockchain, log utils.SimpleLogger) (*sequencer.Sequencer, error) {
+	address := new(felt.Felt).SetBytes([]byte(defaultSequencerAddress))
+	if cfg.SeqAddress != "" {
+		if _, err := address.SetString(cfg.SeqAddress); err != nil {
+			return nil, fmt.Errorf("parse sequencer address: %w", err)
+		}
+	}
+
+	seq := sequencer.New(chain, vm.New(log), address, cfg.SeqBlockTime, log)
+	if cfg.forking() {
+		if cfg.SeqForkHeight != nil {
+			return seq.WithForkHeight(*cfg.SeqForkHeight), nil
+		}
+		height, err := chain.Height()
+		if err != nil {
+			return nil, fmt.Errorf("get height of the forked chain: %w", err)
+		}
+		return seq.WithForkHeight(height), nil
+	}
+	if cfg.SeqGenesisFile == "" {
+		return seq, nil
+	}
+
+	stateDiff, newClasses, err := sequencer.LoadGenesis(cfg.SeqGenesisFile)
+	if err != nil {
+		return nil, fmt.Errorf("load sequencer genesis: %w", err)
+	}
+	return seq.WithGenesis(stateDiff, newClasses), nil
+}
+
 func newL1Client(ethNode string, chain *blockchain.Blockchain, log utils.SimpleLogger) (*l1.Client, error) {
 	var coreContractAddress common.Address
 	coreContractAddress, err := chain.Network().CoreContractAddress()
@@ -274,7 +509,7 @@ func (n *Node) Run(ctx context.Context) {
 	}
 	n.log.Debugw(fmt.Sprintf("Running Juno with config:\n%s", string(yamlConfig)))
 
-	if err := migration.MigrateIfNeeded(ctx, n.db, n.cfg.Network, n.log); err != nil {
+	if err := migration.MigrateIfNeeded(ctx, n.db, n.cfg.Network, n.version, n.log); err != nil {
 		if errors.Is(err, context.Canceled) {
 			n.log.Infow("DB Migration cancelled")
 			return
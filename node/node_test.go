@@ -2,12 +2,16 @@ package node_test
 
 import (
 	"context"
+	"encoding/binary"
 	"testing"
 	"time"
 
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/overlay"
 	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/migration"
 	"github.com/NethermindEth/juno/node"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/sync"
@@ -88,3 +92,44 @@ func TestNetworkVerificationOnNonEmptyDB(t *testing.T) {
 		})
 	}
 }
+
+func TestSequencerAddress(t *testing.T) {
+	_, err := node.New(&node.Config{
+		DatabasePath: t.TempDir(),
+		Network:      utils.Sepolia,
+		Sequencer:    true,
+		SeqAddress:   "juno",
+	}, "v0.1")
+	require.ErrorContains(t, err, "parse sequencer address")
+}
+
+func TestForkDBRequiresForking(t *testing.T) {
+	dbPath := t.TempDir()
+	database, err := pebble.New(dbPath, 1, utils.NewNopZapLogger())
+	require.NoError(t, err)
+	_, err = overlay.Open(pebble.NewMemTest(t), database)
+	require.NoError(t, err)
+	require.NoError(t, database.Close())
+
+	_, err = node.New(&node.Config{
+		DatabasePath: dbPath,
+		Network:      utils.Sepolia,
+	}, "v0.1")
+	require.ErrorContains(t, err, "fork options")
+}
+
+func TestNewRefusesNewerSchema(t *testing.T) {
+	dbPath := t.TempDir()
+	database, err := pebble.New(dbPath, 1, utils.NewNopZapLogger())
+	require.NoError(t, err)
+	require.NoError(t, database.Update(func(txn db.Transaction) error {
+		return txn.Set(db.SchemaVersion.Key(), binary.BigEndian.AppendUint64(nil, 1_000_000))
+	}))
+	require.NoError(t, database.Close())
+
+	_, err = node.New(&node.Config{
+		DatabasePath: dbPath,
+		Network:      utils.Sepolia,
+	}, "v0.1")
+	require.ErrorIs(t, err, migration.ErrSchemaTooNew)
+}
@@ -43,3 +43,13 @@ func (tvm *ThrottledVM) Execute(txns []core.Transaction, declaredClasses []core.
 		return err
 	})
 }
+
+func (tvm *ThrottledVM) Compile(class *core.Cairo1Class) (json.RawMessage, error) {
+	var ret json.RawMessage
+	throttler := (*utils.Throttler[vm.VM])(tvm)
+	return ret, throttler.Do(func(vm *vm.VM) error {
+		var err error
+		ret, err = (*vm).Compile(class)
+		return err
+	})
+}
@@ -0,0 +1,128 @@
+package p2p
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// PeerInfo describes a peer the node is connected to
+type PeerInfo struct {
+	ID        string   `json:"id"`
+	Addresses []string `json:"addresses"`
+	Protocols []string `json:"protocols"`
+	UserAgent string   `json:"user_agent,omitempty"`
+	// Latency is the moving average of the round trip time to the peer in milliseconds, 0 until it was measured
+	Latency float64 `json:"latency"`
+}
+
+// Peers lists the peers the node is connected to
+func (s *Service) Peers() ([]PeerInfo, *jsonrpc.Error) {
+	peerstore := s.host.Peerstore()
+	ids := s.host.Network().Peers()
+	peers := make([]PeerInfo, 0, len(ids))
+	for _, id := range ids {
+		info := PeerInfo{
+			ID:        id.String(),
+			Addresses: []string{},
+			Protocols: []string{},
+			Latency:   float64(peerstore.LatencyEWMA(id).Microseconds()) / 1000,
+		}
+		for _, conn := range s.host.Network().ConnsToPeer(id) {
+			info.Addresses = append(info.Addresses, conn.RemoteMultiaddr().String())
+		}
+
+		protocols, err := peerstore.GetProtocols(id)
+		if err != nil {
+			return nil, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+		}
+		for _, p := range protocols {
+			info.Protocols = append(info.Protocols, string(p))
+		}
+
+		// the agent version is only known once the identify protocol completed
+		if agent, err := peerstore.Get(id, "AgentVersion"); err == nil {
+			info.UserAgent, _ = agent.(string)
+		}
+		peers = append(peers, info)
+	}
+	return peers, nil
+}
+
+// AddPeer connects to the peer with the given multiaddress, which must end with the ID of the peer, and
+// returns its ID
+func (s *Service) AddPeer(ctx context.Context, address string) (string, *jsonrpc.Error) {
+	info, err := peer.AddrInfoFromString(address)
+	if err != nil {
+		return "", jsonrpc.Err(jsonrpc.InvalidParams, err.Error())
+	}
+	if info.ID == s.host.ID() {
+		return "", jsonrpc.Err(jsonrpc.InvalidParams, "cannot connect to self")
+	}
+
+	if s.scores.Banned(info.ID) {
+		return "", jsonrpc.Err(jsonrpc.InvalidParams, "peer is banned")
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, reconnectTimeout)
+	defer cancel()
+	if err = s.host.Connect(connectCtx, *info); err != nil {
+		return "", jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+	return info.ID.String(), nil
+}
+
+// RemovePeer disconnects from the peer with the given ID and forgets its addresses, so that the node does not
+// reconnect to it. The peer may still connect to the node.
+func (s *Service) RemovePeer(id string) (bool, *jsonrpc.Error) {
+	peerID, err := peer.Decode(id)
+	if err != nil {
+		return false, jsonrpc.Err(jsonrpc.InvalidParams, err.Error())
+	}
+
+	connected := s.host.Network().Connectedness(peerID) == network.Connected
+	if err = s.host.Network().ClosePeer(peerID); err != nil {
+		return false, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+	s.host.Peerstore().ClearAddrs(peerID)
+
+	if s.database != nil {
+		err = s.database.Update(func(txn db.Transaction) error {
+			return txn.Delete(db.Peers.Key([]byte(peerID)))
+		})
+		if err != nil {
+			return false, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+		}
+	}
+	return connected, nil
+}
+
+// Methods returns the JSON-RPC methods that inspect the peers of the node.
+func (s *Service) Methods() []jsonrpc.Method {
+	return []jsonrpc.Method{
+		{
+			Name:    "juno_peers",
+			Handler: s.Peers,
+		},
+	}
+}
+
+// AdminMethods returns the JSON-RPC methods that connect and disconnect the peers of the node. Whoever can call
+// them controls who the node talks to, so they must not be served publicly.
+func (s *Service) AdminMethods() []jsonrpc.Method {
+	return []jsonrpc.Method{
+		{
+			Name:    "juno_addPeer",
+			Params:  []jsonrpc.Parameter{{Name: "address"}},
+			Handler: s.AddPeer,
+		},
+		{
+			Name:    "juno_removePeer",
+			Params:  []jsonrpc.Parameter{{Name: "id"}},
+			Handler: s.RemovePeer,
+		},
+	}
+}
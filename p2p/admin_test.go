@@ -0,0 +1,112 @@
+package p2p_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/p2p"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminMethods(t *testing.T) {
+	net, err := mocknet.FullMeshLinked(2)
+	require.NoError(t, err)
+	peerHosts := net.Hosts()
+
+	log := utils.NewNopZapLogger()
+	peerA, err := p2p.NewWithHost(peerHosts[0], "", utils.Integration, log)
+	require.NoError(t, err)
+	peerB, err := p2p.NewWithHost(peerHosts[1], "", utils.Integration, log)
+	require.NoError(t, err)
+
+	addrs, err := peerB.ListenAddrs()
+	require.NoError(t, err)
+	require.NotEmpty(t, addrs)
+	peerBID := peerHosts[1].ID().String()
+
+	t.Run("no peers", func(t *testing.T) {
+		peers, rpcErr := peerA.Peers()
+		require.Nil(t, rpcErr)
+		assert.Empty(t, peers)
+	})
+
+	t.Run("add peer", func(t *testing.T) {
+		id, rpcErr := peerA.AddPeer(context.Background(), addrs[0].String())
+		require.Nil(t, rpcErr)
+		assert.Equal(t, peerBID, id)
+
+		peers, rpcErr := peerA.Peers()
+		require.Nil(t, rpcErr)
+		require.Len(t, peers, 1)
+		assert.Equal(t, peerBID, peers[0].ID)
+		assert.NotEmpty(t, peers[0].Addresses)
+	})
+
+	t.Run("add peer with invalid address", func(t *testing.T) {
+		_, rpcErr := peerA.AddPeer(context.Background(), "/ip4/127.0.0.1/tcp/30301")
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		selfAddrs, err := peerA.ListenAddrs()
+		require.NoError(t, err)
+		_, rpcErr = peerA.AddPeer(context.Background(), selfAddrs[0].String())
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("remove peer", func(t *testing.T) {
+		removed, rpcErr := peerA.RemovePeer(peerBID)
+		require.Nil(t, rpcErr)
+		assert.True(t, removed)
+
+		peers, rpcErr := peerA.Peers()
+		require.Nil(t, rpcErr)
+		assert.Empty(t, peers)
+
+		// the peer is no longer connected
+		removed, rpcErr = peerA.RemovePeer(peerBID)
+		require.Nil(t, rpcErr)
+		assert.False(t, removed)
+	})
+
+	t.Run("remove peer with invalid id", func(t *testing.T) {
+		_, rpcErr := peerA.RemovePeer("notAPeer")
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		server := jsonrpc.NewServer(1, log)
+		require.NoError(t, server.RegisterMethods(peerA.Methods()...))
+		require.NoError(t, server.RegisterMethods(peerA.AdminMethods()...))
+	})
+}
+
+func TestRemovePeerForgetsStoredPeer(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	service, err := p2p.New("/ip4/127.0.0.1/tcp/0", "juno", "", "", "", utils.Integration, utils.NewNopZapLogger(), testDB)
+	require.NoError(t, err)
+
+	const peerID = "12D3KooWLdURCjbp1D7hkXWk6ZVfcMDPtsNnPHuxoTcWXFtvrxGG"
+	id, err := peer.Decode(peerID)
+	require.NoError(t, err)
+	key := db.Peers.Key([]byte(id))
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		return txn.Set(key, []byte{0})
+	}))
+
+	removed, rpcErr := service.RemovePeer(peerID)
+	require.Nil(t, rpcErr)
+	assert.False(t, removed)
+
+	require.ErrorIs(t, testDB.View(func(txn db.Transaction) error {
+		return txn.Get(key, func([]byte) error { return nil })
+	}), db.ErrKeyNotFound)
+}
@@ -0,0 +1,122 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/service"
+	junosync "github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ service.Service = (*BlockGossip)(nil)
+
+// BlockAnnouncer is handed the number and hash of the blocks announced by peers
+type BlockAnnouncer interface {
+	AnnounceBlock(number uint64, hash *felt.Felt)
+}
+
+// BlockGossip announces the blocks the node stores on the new blocks topic of its network, as spec.NewBlock
+// messages identifying the block, and hands the announcements of its peers to a BlockAnnouncer. Peers are not
+// trusted, so announcements are only hints that a block exists, which the BlockAnnouncer has to fetch from a
+// trusted source.
+type BlockGossip struct {
+	service   *Service
+	heads     junosync.Reader
+	announcer BlockAnnouncer
+	network   utils.Network
+	log       utils.SimpleLogger
+}
+
+// NewBlockGossip announces the heads of the given reader. If announcer is nil, the announcements of peers
+// are only validated and propagated.
+func NewBlockGossip(p2pService *Service, heads junosync.Reader, announcer BlockAnnouncer,
+	network utils.Network, log utils.SimpleLogger,
+) *BlockGossip {
+	return &BlockGossip{
+		service:   p2pService,
+		heads:     heads,
+		announcer: announcer,
+		network:   network,
+		log:       log,
+	}
+}
+
+func (g *BlockGossip) Run(ctx context.Context) error {
+	topic := starknet.NewBlocksTopic(g.network)
+	if err := g.service.RegisterTopicValidator(topic, g.validate); err != nil {
+		return err
+	}
+
+	announcements, unsubscribe, err := g.service.SubscribeToTopic(topic)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	headsSub := g.heads.SubscribeNewHeads()
+	defer headsSub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case head, ok := <-headsSub.Recv():
+			if !ok {
+				return nil
+			}
+			data, marshalErr := proto.Marshal(&spec.NewBlock{
+				MaybeFull: &spec.NewBlock_Id{Id: core2p2p.AdaptBlockID(head)},
+			})
+			if marshalErr == nil {
+				marshalErr = g.service.PublishOnTopic(topic, data)
+			}
+			if marshalErr != nil {
+				g.log.Debugw("Failed announcing block", "number", head.Number, "err", marshalErr)
+			}
+		case data, ok := <-announcements:
+			if !ok {
+				return nil
+			}
+			number, hash, decodeErr := decodeBlockAnnouncement(data)
+			if decodeErr != nil {
+				// validated messages always decode
+				g.log.Warnw("Failed decoding block announcement", "err", decodeErr)
+				continue
+			}
+			g.log.Debugw("Received block announcement", "number", number, "hash", hash.ShortString())
+			if g.announcer != nil {
+				g.announcer.AnnounceBlock(number, hash)
+			}
+		}
+	}
+}
+
+// validate accepts the announcements that identify a block
+func (g *BlockGossip) validate(data []byte) bool {
+	if _, _, err := decodeBlockAnnouncement(data); err != nil {
+		g.log.Debugw("Rejected malformed block announcement", "err", err)
+		return false
+	}
+	return true
+}
+
+func decodeBlockAnnouncement(data []byte) (uint64, *felt.Felt, error) {
+	var announcement spec.NewBlock
+	if err := proto.Unmarshal(data, &announcement); err != nil {
+		return 0, nil, err
+	}
+
+	id := announcement.GetId()
+	if id == nil || id.GetHeader() == nil {
+		return 0, nil, errors.New("block announcement does not identify a block")
+	}
+	number, hash := p2p2core.AdaptBlockID(id)
+	return number, hash, nil
+}
@@ -0,0 +1,47 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidateBlockAnnouncement(t *testing.T) {
+	gossip := NewBlockGossip(nil, nil, nil, utils.Mainnet, utils.NewNopZapLogger())
+
+	announce := func(t *testing.T, announcement *spec.NewBlock) []byte {
+		data, err := proto.Marshal(announcement)
+		require.NoError(t, err)
+		return data
+	}
+
+	hash := new(felt.Felt).SetUint64(42)
+	data := announce(t, &spec.NewBlock{
+		MaybeFull: &spec.NewBlock_Id{Id: core2p2p.AdaptBlockID(&core.Header{Number: 2, Hash: hash})},
+	})
+	assert.True(t, gossip.validate(data))
+	number, got, err := decodeBlockAnnouncement(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), number)
+	assert.Equal(t, hash, got)
+
+	tests := map[string]*spec.NewBlock{
+		"empty":        {},
+		"missing hash": {MaybeFull: &spec.NewBlock_Id{Id: &spec.BlockID{Number: 2}}},
+		"header":       {MaybeFull: &spec.NewBlock_Header{Header: &spec.BlockHeadersResponse{}}},
+	}
+	for name, announcement := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.False(t, gossip.validate(announce(t, announcement)))
+		})
+	}
+
+	assert.False(t, gossip.validate([]byte("not an announcement")))
+}
@@ -0,0 +1,42 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
+)
+
+var _ mdns.Notifee = (*Service)(nil)
+
+// mdnsServiceName keeps the nodes of different networks from discovering each other
+func mdnsServiceName(s *Service) string {
+	return fmt.Sprintf("_starknet-%s._udp", s.network.String())
+}
+
+// startMDNS advertises the Service on the local network and connects to the nodes it finds there
+func (s *Service) startMDNS() (mdns.Service, error) {
+	mdnsService := mdns.NewMdnsService(s.host, mdnsServiceName(s), s)
+	if err := mdnsService.Start(); err != nil {
+		return nil, err
+	}
+	return mdnsService, nil
+}
+
+// HandlePeerFound connects to the peers discovered with mDNS
+func (s *Service) HandlePeerFound(info peer.AddrInfo) {
+	if info.ID == s.host.ID() {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(s.runCtx, reconnectTimeout)
+		defer cancel()
+		if err := s.host.Connect(ctx, info); err != nil {
+			s.log.Debugw("Failed connecting to peer found with mDNS", "peer", info.ID, "err", err)
+			return
+		}
+		s.log.Debugw("Connected to peer found with mDNS", "peer", info.ID)
+	}()
+}
@@ -1,16 +1,21 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
 	cryptorand "crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/p2p/starknet"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/libp2p/go-libp2p"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
@@ -34,6 +39,14 @@ type Service struct {
 	bootPeers string
 	network   utils.Network
 	log       utils.SimpleLogger
+	scores    *starknet.PeerScores
+
+	// database keeps the identity and the peers of the Service across restarts, if it is set
+	database db.DB
+	// lastSeen is only accessed by the goroutine persisting peers
+	lastSeen map[peer.ID]int64
+	// mdns makes the Service discover peers on the local network
+	mdns bool
 
 	dht        *dht.IpfsDHT
 	pubsub     *pubsub.PubSub
@@ -44,7 +57,13 @@ type Service struct {
 	runLock sync.RWMutex
 }
 
-func New(addr, userAgent, bootPeers, privKeyStr string, snNetwork utils.Network, log utils.SimpleLogger) (*Service, error) {
+// New creates a Service listening on the given address. If privKeyStr is empty, the identity of the node is
+// loaded from privKeyFile, or generated and written to it on the first start. If privKeyFile is empty too, a new
+// identity is generated on every start. The peers of the Service are stored in the database, so that it
+// reconnects to them after a restart. The database may be nil, in which case peers are forgotten on every start.
+func New(addr, userAgent, bootPeers, privKeyStr, privKeyFile string, snNetwork utils.Network, log utils.SimpleLogger,
+	database db.DB,
+) (*Service, error) {
 	if addr == "" {
 		// 0.0.0.0/tcp/0 will listen on any interface device and assing a free port.
 		addr = "/ip4/0.0.0.0/tcp/0"
@@ -54,19 +73,34 @@ func New(addr, userAgent, bootPeers, privKeyStr string, snNetwork utils.Network,
 		return nil, err
 	}
 
-	prvKey, err := privateKey(privKeyStr)
+	prvKey, err := privateKey(privKeyStr, privKeyFile, database)
 	if err != nil {
 		return nil, err
 	}
 
-	p2pHost, err := libp2p.New(libp2p.ListenAddrs(sourceMultiAddr), libp2p.Identity(prvKey), libp2p.UserAgent(userAgent))
+	scores := starknet.NewPeerScores()
+	p2pHost, err := libp2p.New(libp2p.ListenAddrs(sourceMultiAddr), libp2p.Identity(prvKey), libp2p.UserAgent(userAgent),
+		libp2p.ConnectionGater(scores))
+	if err != nil {
+		return nil, err
+	}
+	s, err := newService(p2pHost, bootPeers, snNetwork, scores, log)
 	if err != nil {
 		return nil, err
 	}
-	return NewWithHost(p2pHost, bootPeers, snNetwork, log)
+	s.database = database
+	return s, nil
 }
 
+// NewWithHost creates a Service on a host built elsewhere. Banned peers are disconnected, but they are only
+// kept from reconnecting if the host was built with the PeerScores of the Service as its connection gater.
 func NewWithHost(p2phost host.Host, bootPeers string, snNetwork utils.Network, log utils.SimpleLogger) (*Service, error) {
+	return newService(p2phost, bootPeers, snNetwork, starknet.NewPeerScores(), log)
+}
+
+func newService(p2phost host.Host, bootPeers string, snNetwork utils.Network, scores *starknet.PeerScores,
+	log utils.SimpleLogger,
+) (*Service, error) {
 	p2pdht, err := makeDHT(p2phost, snNetwork, bootPeers)
 	if err != nil {
 		return nil, err
@@ -79,11 +113,57 @@ func NewWithHost(p2phost host.Host, bootPeers string, snNetwork utils.Network, l
 		network:   snNetwork,
 		dht:       p2pdht,
 		topics:    make(map[string]*pubsub.Topic),
+		lastSeen:  make(map[peer.ID]int64),
 	}
+	s.scores = scores.WithBanHandler(func(id peer.ID) {
+		s.log.Infow("Banning peer", "peer", id)
+		if err := s.host.Network().ClosePeer(id); err != nil {
+			s.log.Debugw("Failed disconnecting banned peer", "peer", id, "err", err)
+		}
+	})
 	s.runLock.Lock()
 	return s, nil
 }
 
+// WithMDNS makes the Service discover the nodes of its network on the local network with mDNS, so that
+// nodes on a LAN or a single machine connect without boot peers
+func (s *Service) WithMDNS(enabled bool) *Service {
+	s.mdns = enabled
+	return s
+}
+
+// PeerScores returns the reputation of the peers of the Service, which the starknet protocol handlers and
+// clients should score peers with
+func (s *Service) PeerScores() *starknet.PeerScores {
+	return s.scores
+}
+
+// NewClient returns a starknet Client requesting data from the peers of the Service. The Client scores peers with
+// the PeerScores of the Service and ends the responses a BlockVerifier rejects, including the block bodies
+// without proofs from peers advertising archive support. The node does not sync from its peers yet, so it
+// creates no Client itself.
+func (s *Service) NewClient() *starknet.Client {
+	return starknet.NewClient(s.NewStream, s.network, s.log).
+		WithPeerScores(s.scores).
+		WithVerifier(starknet.NewBlockVerifier(s.network)).
+		WithArchivePeers(s.archivePeer)
+}
+
+// AdvertiseArchive makes the Service advertise that the block bodies its peers request come with proofs, which
+// only nodes serving block bodies with archived tries should do
+func (s *Service) AdvertiseArchive() {
+	s.host.SetStreamHandler(starknet.ArchivePID(s.network), func(stream network.Stream) {
+		if err := stream.Close(); err != nil {
+			s.log.Debugw("Failed closing archive stream", "err", err)
+		}
+	})
+}
+
+func (s *Service) archivePeer(id peer.ID) bool {
+	protocols, err := s.host.Peerstore().SupportsProtocols(id, starknet.ArchivePID(s.network))
+	return err == nil && len(protocols) > 0
+}
+
 func makeDHT(p2phost host.Host, snNetwork utils.Network, cfgBootPeers string) (*dht.IpfsDHT, error) {
 	bootPeers := []peer.AddrInfo{}
 	if cfgBootPeers != "" {
@@ -107,14 +187,12 @@ func makeDHT(p2phost host.Host, snNetwork utils.Network, cfgBootPeers string) (*
 	)
 }
 
-func privateKey(privKeyStr string) (crypto.PrivKey, error) {
+func privateKey(privKeyStr, privKeyFile string, database db.DB) (crypto.PrivKey, error) {
 	if privKeyStr == "" {
-		// Creates a new key pair for this host.
-		prvKey, _, err := crypto.GenerateKeyPairWithReader(crypto.Ed25519, keyLength, cryptorand.Reader)
-		if err != nil {
-			return nil, err
+		if privKeyFile == "" {
+			return generatePrivateKey()
 		}
-		return prvKey, nil
+		return storedPrivateKey(privKeyFile, database)
 	}
 	privKeyBytes, err := hex.DecodeString(privKeyStr)
 	if err != nil {
@@ -129,6 +207,60 @@ func privateKey(privKeyStr string) (crypto.PrivKey, error) {
 	return prvKey, nil
 }
 
+func generatePrivateKey() (crypto.PrivKey, error) {
+	// Creates a new key pair for this host.
+	prvKey, _, err := crypto.GenerateKeyPairWithReader(crypto.Ed25519, keyLength, cryptorand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return prvKey, nil
+}
+
+// storedPrivateKey returns the private key stored in the file, which only its owner can read. If there is no
+// such file, the key older versions stored in the database is moved to it, or a new key is generated.
+func storedPrivateKey(privKeyFile string, database db.DB) (crypto.PrivKey, error) {
+	prvKeyBytes, err := os.ReadFile(privKeyFile)
+	if err == nil {
+		return crypto.UnmarshalPrivateKey(prvKeyBytes)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if database != nil {
+		err = database.View(func(txn db.Transaction) error {
+			return txn.Get(db.P2PIdentity.Key(), func(val []byte) error {
+				prvKeyBytes = bytes.Clone(val)
+				return nil
+			})
+		})
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	var prvKey crypto.PrivKey
+	if prvKeyBytes != nil {
+		prvKey, err = crypto.UnmarshalPrivateKey(prvKeyBytes)
+	} else if prvKey, err = generatePrivateKey(); err == nil {
+		prvKeyBytes, err = crypto.MarshalPrivateKey(prvKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.WriteFile(privKeyFile, prvKeyBytes, 0o600); err != nil { //nolint:gomnd
+		return nil, err
+	}
+	if database != nil {
+		if err = database.Update(func(txn db.Transaction) error {
+			return txn.Delete(db.P2PIdentity.Key())
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return prvKey, nil
+}
+
 func (s *Service) SubscribePeerConnectednessChanged(ctx context.Context) (<-chan event.EvtPeerConnectednessChanged, error) {
 	ch := make(chan event.EvtPeerConnectednessChanged)
 	sub, err := s.host.EventBus().Subscribe(&event.EvtPeerConnectednessChanged{})
@@ -186,8 +318,34 @@ func (s *Service) Run(ctx context.Context) error {
 	for _, addr := range listenAddrs {
 		s.log.Infow("Listening on", "addr", addr)
 	}
+	s.log.Infow("Peer ID", "id", s.host.ID())
+
+	var mdnsService io.Closer
+	if s.mdns {
+		if mdnsService, err = s.startMDNS(); err != nil {
+			return err
+		}
+	}
+
+	var peersPersisted chan struct{}
+	if s.database != nil {
+		peersPersisted = make(chan struct{})
+		go func() {
+			defer close(peersPersisted)
+			s.persistPeers(s.runCtx)
+		}()
+	}
 
 	<-s.runCtx.Done()
+	if peersPersisted != nil {
+		// the peers are saved one last time before the host is closed
+		<-peersPersisted
+	}
+	if mdnsService != nil {
+		if err := mdnsService.Close(); err != nil {
+			s.log.Warnw("Failed stopping mDNS", "err", err.Error())
+		}
+	}
 	if err := s.dht.Close(); err != nil {
 		s.log.Warnw("Failed stopping DHT", "err", err.Error())
 	}
@@ -308,6 +466,20 @@ func (s *Service) PublishOnTopic(topic string, data []byte) error {
 	return t.Publish(s.runCtx, data)
 }
 
+// RegisterTopicValidator makes the messages on the topic be delivered and propagated only if validate
+// accepts them
+func (s *Service) RegisterTopicValidator(topic string, validate func(data []byte) bool) error {
+	s.runLock.RLock()
+	defer s.runLock.RUnlock()
+	if s.runCtx == nil {
+		return errors.New("uninitialized p2p service")
+	}
+
+	return s.pubsub.RegisterTopicValidator(topic, func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+		return validate(msg.GetData())
+	})
+}
+
 func (s *Service) SetProtocolHandler(pid protocol.ID, handler func(network.Stream)) {
 	s.host.SetStreamHandler(pid, handler)
 }
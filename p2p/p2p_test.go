@@ -2,18 +2,29 @@ package p2p_test
 
 import (
 	"context"
+	"encoding/hex"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/p2p"
+	"github.com/NethermindEth/juno/p2p/starknet"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/event"
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
 )
 
 func TestService(t *testing.T) {
@@ -121,6 +132,27 @@ func TestService(t *testing.T) {
 		}
 	})
 
+	t.Run("client verifies responses", func(t *testing.T) {
+		peerA.SetProtocolHandler(starknet.BlockHeadersPID(utils.Integration), func(stream network.Stream) {
+			defer stream.Close()
+			// a header whose hash does not match its contents
+			res := &spec.BlockHeadersResponse{Part: []*spec.BlockHeadersResponsePart{
+				{HeaderMessage: &spec.BlockHeadersResponsePart_Header{Header: &spec.BlockHeader{Number: 1}}},
+				{HeaderMessage: &spec.BlockHeadersResponsePart_Signatures{Signatures: &spec.Signatures{
+					Block: &spec.BlockID{Number: 1, Header: &spec.Hash{Elements: []byte{1}}},
+				}}},
+			}}
+			_, err := protodelim.MarshalTo(stream, res)
+			require.NoError(t, err)
+		})
+
+		headers, err := peerB.NewClient().RequestBlockHeaders(testCtx, &spec.BlockHeadersRequest{})
+		require.NoError(t, err)
+		_, ok := headers()
+		assert.False(t, ok)
+		assert.Negative(t, peerB.PeerScores().Score(peerHosts[0].ID()))
+	})
+
 	cancel()
 	wg.Wait()
 }
@@ -131,8 +163,10 @@ func TestInvalidKey(t *testing.T) {
 		"peerA",
 		"",
 		"something",
+		"",
 		utils.Integration,
 		utils.NewNopZapLogger(),
+		nil,
 	)
 
 	require.Error(t, err)
@@ -144,9 +178,107 @@ func TestValidKey(t *testing.T) {
 		"peerA",
 		"",
 		"08011240333b4a433f16d7ca225c0e99d0d8c437b835cb74a98d9279c561977690c80f681b25ccf3fa45e2f2de260149c112fa516b69057dd3b0151a879416c0cb12d9b3",
+		"",
 		utils.Integration,
 		utils.NewNopZapLogger(),
+		nil,
 	)
 
 	require.NoError(t, err)
 }
+
+func TestPersistentIdentityAndPeers(t *testing.T) {
+	log := utils.NewNopZapLogger()
+	keyFile := filepath.Join(t.TempDir(), "p2p.key")
+	newService := func(t *testing.T, database db.DB, bootPeers string) (*p2p.Service, string) {
+		t.Helper()
+		var serviceKeyFile string
+		if database != nil {
+			serviceKeyFile = keyFile
+		}
+		service, err := p2p.New("/ip4/127.0.0.1/tcp/0", "juno", bootPeers, "", serviceKeyFile, utils.Integration, log, database)
+		require.NoError(t, err)
+
+		addrs, err := service.ListenAddrs()
+		require.NoError(t, err)
+		require.NotEmpty(t, addrs)
+		return service, addrs[0].String()
+	}
+	run := func(t *testing.T, service *p2p.Service) (context.CancelFunc, *sync.WaitGroup) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, service.Run(ctx))
+		}()
+		t.Cleanup(func() {
+			cancel()
+			wg.Wait()
+		})
+		return cancel, &wg
+	}
+	waitForConnection := func(t *testing.T, events <-chan event.EvtPeerConnectednessChanged) peer.ID {
+		t.Helper()
+		select {
+		case evt := <-events:
+			require.Equal(t, network.Connected, evt.Connectedness)
+			return evt.Peer
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "peers did not connect")
+			return ""
+		}
+	}
+
+	testDB := pebble.NewMemTest(t)
+	peerA, addrA := newService(t, nil, "")
+	run(t, peerA)
+
+	peerB, addrB := newService(t, testDB, addrA)
+	events, err := peerB.SubscribePeerConnectednessChanged(context.Background())
+	require.NoError(t, err)
+	cancelB, wgB := run(t, peerB)
+	waitForConnection(t, events)
+	cancelB()
+	wgB.Wait()
+
+	info, err := os.Stat(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// the restarted peer keeps its identity and reconnects to the stored peer without boot peers
+	restartedB, restartedAddrB := newService(t, testDB, "")
+	idOf := func(addr string) string {
+		return addr[strings.LastIndex(addr, "/"):]
+	}
+	assert.Equal(t, idOf(addrB), idOf(restartedAddrB))
+
+	events, err = restartedB.SubscribePeerConnectednessChanged(context.Background())
+	require.NoError(t, err)
+	run(t, restartedB)
+	assert.Equal(t, idOf(addrA), "/"+waitForConnection(t, events).String())
+}
+
+func TestIdentityMovedOutOfDB(t *testing.T) {
+	const key = "08011240333b4a433f16d7ca225c0e99d0d8c437b835cb74a98d9279c561977690c80f681b25ccf3fa45e2f2de260149c112fa516b69057dd3b0151a879416c0cb12d9b3"
+	keyBytes, err := hex.DecodeString(key)
+	require.NoError(t, err)
+
+	testDB := pebble.NewMemTest(t)
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		return txn.Set(db.P2PIdentity.Key(), keyBytes)
+	}))
+
+	keyFile := filepath.Join(t.TempDir(), "p2p.key")
+	_, err = p2p.New("/ip4/127.0.0.1/tcp/0", "juno", "", "", keyFile, utils.Integration, utils.NewNopZapLogger(), testDB)
+	require.NoError(t, err)
+
+	stored, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, keyBytes, stored)
+	err = testDB.View(func(txn db.Transaction) error {
+		return txn.Get(db.P2PIdentity.Key(), func([]byte) error { return nil })
+	})
+	assert.ErrorIs(t, err, db.ErrKeyNotFound)
+}
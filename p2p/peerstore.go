@@ -0,0 +1,186 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const (
+	peerstoreSaveInterval = time.Minute
+	// peers that were not seen for that long are forgotten
+	peerRetention = 7 * 24 * time.Hour
+	// how long the Service tries to reconnect to a stored peer on startup
+	reconnectTimeout = 10 * time.Second
+)
+
+// storedPeer is what the Service remembers of a peer across restarts
+type storedPeer struct {
+	Addrs     [][]byte
+	Protocols []string
+	LastSeen  int64 // unix seconds
+}
+
+// loadPeers adds the stored peers to the peerstore and returns their IDs, most recently seen first
+func (s *Service) loadPeers() ([]peer.ID, error) {
+	var ids []peer.ID
+	err := s.database.View(func(txn db.Transaction) error {
+		it, err := txn.NewIterator()
+		if err != nil {
+			return err
+		}
+
+		prefix := db.Peers.Key()
+		for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+			id, err := peer.IDFromBytes(it.Key()[len(prefix):])
+			if err != nil {
+				s.log.Debugw("Skipping stored peer with invalid ID", "err", err)
+				continue
+			}
+
+			val, err := it.Value()
+			if err != nil {
+				return errors.Join(err, it.Close())
+			}
+			var stored storedPeer
+			if err = encoder.Unmarshal(val, &stored); err != nil {
+				s.log.Debugw("Skipping malformed stored peer", "peer", id, "err", err)
+				continue
+			}
+
+			s.restorePeer(id, &stored)
+			ids = append(ids, id)
+		}
+		return it.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return s.lastSeen[ids[i]] > s.lastSeen[ids[j]]
+	})
+	return ids, nil
+}
+
+func (s *Service) restorePeer(id peer.ID, stored *storedPeer) {
+	peers := s.host.Peerstore()
+	for _, addrBytes := range stored.Addrs {
+		addr, err := multiaddr.NewMultiaddrBytes(addrBytes)
+		if err != nil {
+			continue
+		}
+		peers.AddAddr(id, addr, peerstore.AddressTTL)
+	}
+
+	protocols := make([]protocol.ID, 0, len(stored.Protocols))
+	for _, p := range stored.Protocols {
+		protocols = append(protocols, protocol.ID(p))
+	}
+	if err := peers.AddProtocols(id, protocols...); err != nil {
+		s.log.Debugw("Failed restoring peer protocols", "peer", id, "err", err)
+	}
+	s.lastSeen[id] = stored.LastSeen
+}
+
+// reconnect connects to the stored peers in the background
+func (s *Service) reconnect(ctx context.Context, ids []peer.ID) {
+	for _, id := range ids {
+		go func(id peer.ID) {
+			connectCtx, cancel := context.WithTimeout(ctx, reconnectTimeout)
+			defer cancel()
+			if err := s.host.Connect(connectCtx, s.host.Peerstore().PeerInfo(id)); err != nil {
+				s.log.Debugw("Failed reconnecting to stored peer", "peer", id, "err", err)
+			}
+		}(id)
+	}
+}
+
+// savePeers stores the peers with known addresses, and forgets the ones that were not seen for too long
+func (s *Service) savePeers() error {
+	now := time.Now()
+	peers := s.host.Peerstore()
+	return s.database.Update(func(txn db.Transaction) error {
+		for _, id := range peers.PeersWithAddrs() {
+			if id == s.host.ID() || s.scores.Banned(id) {
+				continue
+			}
+
+			if s.host.Network().Connectedness(id) == network.Connected {
+				s.lastSeen[id] = now.Unix()
+			}
+			lastSeen, seen := s.lastSeen[id]
+			if !seen {
+				continue
+			}
+
+			key := db.Peers.Key([]byte(id))
+			if now.Sub(time.Unix(lastSeen, 0)) > peerRetention {
+				delete(s.lastSeen, id)
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+				continue
+			}
+
+			stored := storedPeer{LastSeen: lastSeen}
+			for _, addr := range peers.Addrs(id) {
+				stored.Addrs = append(stored.Addrs, addr.Bytes())
+			}
+			protocols, err := peers.GetProtocols(id)
+			if err != nil {
+				return err
+			}
+			for _, p := range protocols {
+				stored.Protocols = append(stored.Protocols, string(p))
+			}
+
+			val, err := encoder.Marshal(stored)
+			if err != nil {
+				return err
+			}
+			if err = txn.Set(key, val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// persistPeers restores the stored peers and saves the peerstore periodically until ctx is done. It also
+// saves the peerstore when it returns.
+func (s *Service) persistPeers(ctx context.Context) {
+	ids, err := s.loadPeers()
+	if err != nil {
+		s.log.Warnw("Failed loading stored peers", "err", err)
+	} else if len(ids) > 0 {
+		s.log.Infow("Reconnecting to stored peers", "count", len(ids))
+		s.reconnect(ctx, ids)
+	}
+
+	ticker := time.NewTicker(peerstoreSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err = s.savePeers(); err != nil {
+				s.log.Warnw("Failed storing peers", "err", err)
+			}
+			return
+		case <-ticker.C:
+			if err = s.savePeers(); err != nil {
+				s.log.Warnw("Failed storing peers", "err", err)
+			}
+		}
+	}
+}
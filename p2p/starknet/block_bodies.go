@@ -1,7 +1,6 @@
 package starknet
 
 import (
-	"crypto/rand"
 	"slices"
 
 	"github.com/NethermindEth/juno/adapters/core2p2p"
@@ -27,6 +26,7 @@ const (
 
 type blockBodyIterator struct {
 	log         utils.Logger
+	bcReader    blockchain.Reader
 	stateReader core.StateReader
 	stateCloser func() error
 
@@ -50,6 +50,7 @@ func newBlockBodyIterator(bcReader blockchain.Reader, header *core.Header, log u
 		step:        sendDiff,
 		header:      header,
 		log:         log,
+		bcReader:    bcReader,
 		stateReader: stateReader,
 		stateCloser: closer,
 		stateUpdate: stateUpdate,
@@ -67,16 +68,17 @@ func (b *blockBodyIterator) hasNext() bool {
 
 // Either BlockBodiesResponse_Diff, *_Classes, *_Proof, *_Fin
 func (b *blockBodyIterator) next() (msg proto.Message, valid bool) {
+	// steps are advanced before the messages are built, as failing to build one ends the iteration with fin
 	switch b.step {
 	case sendDiff:
-		msg, valid = b.diff()
 		b.step = sendClasses
+		msg, valid = b.diff()
 	case sendClasses:
-		msg, valid = b.classes()
 		b.step = sendProof
+		msg, valid = b.classes()
 	case sendProof:
-		msg, valid = b.proof()
 		b.step = sendBlockFin
+		msg, valid = b.proof()
 	case sendBlockFin:
 		// fin changes step to terminal internally
 		msg, valid = b.fin()
@@ -100,7 +102,9 @@ func (b *blockBodyIterator) classes() (proto.Message, bool) {
 			return b.fin()
 		}
 
-		classesM[*hash] = core2p2p.AdaptClass(cls.Class, hash)
+		if classesM[*hash], err = core2p2p.AdaptClass(cls.Class, hash); err != nil {
+			return b.fin()
+		}
 	}
 	for classHash := range stateDiff.DeclaredV1Classes {
 		cls, err := b.stateReader.Class(&classHash)
@@ -112,7 +116,9 @@ func (b *blockBodyIterator) classes() (proto.Message, bool) {
 		if err != nil {
 			return b.fin()
 		}
-		classesM[classHash] = core2p2p.AdaptClass(cls.Class, hash)
+		if classesM[classHash], err = core2p2p.AdaptClass(cls.Class, hash); err != nil {
+			return b.fin()
+		}
 	}
 	for _, classHash := range stateDiff.DeployedContracts {
 		if _, ok := classesM[*classHash]; ok {
@@ -138,7 +144,9 @@ func (b *blockBodyIterator) classes() (proto.Message, bool) {
 			return b.fin()
 		}
 
-		classesM[*compiledHash] = core2p2p.AdaptClass(cls.Class, compiledHash)
+		if classesM[*compiledHash], err = core2p2p.AdaptClass(cls.Class, compiledHash); err != nil {
+			return b.fin()
+		}
 	}
 
 	var classes []*spec.Class
@@ -234,12 +242,19 @@ func (b *blockBodyIterator) fin() (proto.Message, bool) {
 	}, true
 }
 
+// proof proves the state diff against the state roots before and after the block, so that peers can verify
+// the body without executing the block. Proofs can only be built when the tries of both states are readable,
+// which takes archive mode for all but the head, so bodies may end without one.
 func (b *blockBodyIterator) proof() (proto.Message, bool) {
-	// proof size is currently 142K
-	proof := make([]byte, 142*1024) //nolint:gomnd
-	_, err := rand.Read(proof)
+	proof, err := b.stateDiffProof()
 	if err != nil {
-		b.log.Errorw("Failed to generate rand proof", "err", err)
+		b.log.Debugw("Failed to prove state diff", "number", b.header.Number, "err", err)
+		return b.fin()
+	}
+
+	proofBytes, err := proto.Marshal(core2p2p.AdaptStateDiffProof(proof))
+	if err != nil {
+		b.log.Errorw("Failed to encode state diff proof", "err", err)
 		return b.fin()
 	}
 
@@ -247,8 +262,40 @@ func (b *blockBodyIterator) proof() (proto.Message, bool) {
 		Id: core2p2p.AdaptBlockID(b.header),
 		BodyMessage: &spec.BlockBodiesResponse_Proof{
 			Proof: &spec.BlockProof{
-				Proof: proof,
+				Proof: proofBytes,
 			},
 		},
 	}, true
 }
+
+func (b *blockBodyIterator) stateDiffProof() (*core.StateDiffProof, error) {
+	newTries, newTriesCloser, err := b.bcReader.TriesAtBlockNumber(b.header.Number)
+	if err != nil {
+		return nil, err
+	}
+	defer b.close(newTriesCloser)
+
+	if b.header.Number == 0 {
+		return core.NewStateDiffProof(b.stateUpdate.StateDiff, nil, nil, b.stateReader, newTries)
+	}
+
+	oldTries, oldTriesCloser, err := b.bcReader.TriesAtBlockNumber(b.header.Number - 1)
+	if err != nil {
+		return nil, err
+	}
+	defer b.close(oldTriesCloser)
+
+	oldState, oldStateCloser, err := b.bcReader.StateAtBlockNumber(b.header.Number - 1)
+	if err != nil {
+		return nil, err
+	}
+	defer b.close(oldStateCloser)
+
+	return core.NewStateDiffProof(b.stateUpdate.StateDiff, oldState, oldTries, b.stateReader, newTries)
+}
+
+func (b *blockBodyIterator) close(closer func() error) {
+	if err := closer(); err != nil {
+		b.log.Errorw("Call to state closer failed", "err", err)
+	}
+}
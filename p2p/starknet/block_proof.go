@@ -0,0 +1,121 @@
+package starknet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ ResponseVerifier = (*BlockBodyVerifier)(nil)
+
+// ErrMissingProof is returned for the block bodies that end without a proof. Peers can only prove the blocks
+// they archived, so it is only the fault of the peers that advertise archive support.
+var ErrMissingProof = errors.New("block body without proof")
+
+// VerifyBlockProof checks that the proof sent in a block body proves the contract diffs sent in it against
+// the state roots before and after the block
+func VerifyBlockProof(proof *spec.BlockProof, diffs []*spec.StateDiff_ContractDiff, oldRoot, newRoot *felt.Felt) error {
+	var specProof spec.StateDiffProof
+	if err := proto.Unmarshal(proof.GetProof(), &specProof); err != nil {
+		return fmt.Errorf("decode proof: %v", err)
+	}
+	stateDiffProof, err := p2p2core.AdaptStateDiffProof(&specProof)
+	if err != nil {
+		return fmt.Errorf("decode proof: %v", err)
+	}
+
+	// the proof checks the class hashes of contract diffs like replaced classes
+	stateDiff, err := p2p2core.AdaptStateDiff(diffs)
+	if err != nil {
+		return err
+	}
+	return core.VerifyStateDiffProof(stateDiffProof, stateDiff, oldRoot, newRoot)
+}
+
+// bodyParts are what the BlockBodyVerifier received of the body of a block. The contract diffs are only kept
+// until the proof of the block is received.
+type bodyParts struct {
+	diffs []*spec.StateDiff_ContractDiff
+	// proof is whether the body came with a proof, which is only proven if the roots of the block are known
+	proof  bool
+	proven bool
+}
+
+// BlockBodyVerifier verifies the proofs in the block bodies a Client receives against the state roots of the
+// blocks, so that the bodies can be trusted without executing the blocks. Bodies without proofs are rejected
+// with ErrMissingProof when they end, and Proven tells the bodies that were verified from the ones that were
+// not.
+type BlockBodyVerifier struct {
+	roots func(blockNumber uint64) (oldRoot, newRoot *felt.Felt, err error)
+
+	mu     sync.Mutex
+	bodies *lru.Cache[uint64, *bodyParts]
+}
+
+// NewBlockBodyVerifier verifies proofs against the state roots the given function returns, which are the
+// roots before and after the block with the given number. The proofs of blocks whose roots cannot be
+// returned are not verified, and their bodies are not proven.
+func NewBlockBodyVerifier(roots func(blockNumber uint64) (oldRoot, newRoot *felt.Felt, err error)) *BlockBodyVerifier {
+	return &BlockBodyVerifier{
+		roots:  roots,
+		bodies: lru.NewCache[uint64, *bodyParts](verifiedBlocks),
+	}
+}
+
+func (v *BlockBodyVerifier) VerifyResponse(res proto.Message) error {
+	body, ok := res.(*spec.BlockBodiesResponse)
+	if !ok || body.Id == nil {
+		return nil
+	}
+	number := body.Id.Number
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts, ok := v.bodies.Get(number)
+	if !ok {
+		parts = new(bodyParts)
+		v.bodies.Add(number, parts)
+	}
+
+	switch message := body.BodyMessage.(type) {
+	case *spec.BlockBodiesResponse_Diff:
+		parts.diffs = append(parts.diffs, message.Diff.GetContractDiffs()...)
+		parts.proof, parts.proven = false, false
+	case *spec.BlockBodiesResponse_Proof:
+		diffs := parts.diffs
+		parts.diffs = nil
+		parts.proof = true
+
+		oldRoot, newRoot, err := v.roots(number)
+		if err != nil {
+			// not knowing the roots is not the fault of the peer
+			return nil //nolint:nilerr
+		}
+		if err = VerifyBlockProof(message.Proof, diffs, oldRoot, newRoot); err != nil {
+			return fmt.Errorf("block %d: %v", number, err)
+		}
+		parts.proven = true
+	case *spec.BlockBodiesResponse_Fin:
+		parts.diffs = nil
+		if !parts.proof {
+			return fmt.Errorf("block %d: %w", number, ErrMissingProof)
+		}
+	}
+	return nil
+}
+
+// Proven returns whether the last body received of the block with the given number was verified against a
+// proof
+func (v *BlockBodyVerifier) Proven(number uint64) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts, ok := v.bodies.Peek(number)
+	return ok && parts.proven
+}
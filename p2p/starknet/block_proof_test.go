@@ -0,0 +1,177 @@
+package starknet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/p2p/starknet"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockBodyProofs(t *testing.T) {
+	testNetwork := utils.Mainnet
+	chain := blockchain.New(pebble.NewMemTest(t), testNetwork, utils.NewNopZapLogger()).WithArchive(true)
+	unarchivedChain := blockchain.New(pebble.NewMemTest(t), testNetwork, utils.NewNopZapLogger())
+	gw := adaptfeeder.New(feeder.NewTestClient(t, testNetwork))
+
+	const blocks = 3
+	for number := uint64(0); number < blocks; number++ {
+		block, err := gw.BlockByNumber(context.Background(), number)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), number)
+		require.NoError(t, err)
+
+		newClasses := make(map[felt.Felt]core.Class)
+		for _, classHash := range su.StateDiff.DeployedContracts {
+			newClasses[*classHash], err = gw.Class(context.Background(), classHash)
+			require.NoError(t, err)
+		}
+		commitments, err := core.VerifyBlockHash(block, testNetwork, su.StateDiff)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(block, commitments, su, newClasses))
+		require.NoError(t, unarchivedChain.Store(block, commitments, su, newClasses))
+	}
+
+	mockNet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	peers := mockNet.Peers()
+	handlerID, clientID := peers[0], peers[1]
+
+	log := utils.NewNopZapLogger()
+	handler := starknet.NewHandler(chain, log)
+	// the handler keeps reading the chain after the client stopped reading, which must be done before the
+	// database closes
+	served := make(chan struct{})
+	serveBodies := func(handler *starknet.Handler) {
+		mockNet.Host(handlerID).SetStreamHandler(starknet.BlockBodiesPID(testNetwork), func(stream network.Stream) {
+			handler.BlockBodiesHandler(stream)
+			served <- struct{}{}
+		})
+	}
+	serveBodies(handler)
+
+	roots := func(number uint64) (*felt.Felt, *felt.Felt, error) {
+		header, err := chain.BlockHeaderByNumber(number)
+		if err != nil {
+			return nil, nil, err
+		}
+		if number == 0 {
+			return &felt.Zero, header.GlobalStateRoot, nil
+		}
+		parent, err := chain.BlockHeaderByNumber(number - 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return parent.GlobalStateRoot, header.GlobalStateRoot, nil
+	}
+
+	mockNet.Host(handlerID).SetStreamHandler(starknet.BlockHeadersPID(testNetwork), handler.BlockHeadersHandler)
+
+	iteration := &spec.Iteration{
+		Start:     &spec.Iteration_BlockNumber{BlockNumber: 0},
+		Direction: spec.Iteration_Forward,
+		Limit:     blocks,
+		Step:      1,
+	}
+	archivePeer := false
+	newClient := func(scores *starknet.PeerScores, verifier starknet.ResponseVerifier) *starknet.Client {
+		return starknet.NewClient(func(ctx context.Context, pids ...protocol.ID) (network.Stream, error) {
+			return mockNet.Host(clientID).NewStream(ctx, handlerID, pids...)
+		}, testNetwork, log).WithPeerScores(scores).WithVerifier(verifier).WithArchivePeers(func(id peer.ID) bool {
+			return id == handlerID && archivePeer
+		})
+	}
+
+	requestBodies := func(t *testing.T, verifier starknet.ResponseVerifier) (proofs, count int) {
+		scores := starknet.NewPeerScores()
+		res, err := newClient(scores, verifier).RequestBlockBodies(context.Background(), &spec.BlockBodiesRequest{
+			Iteration: iteration,
+		})
+		require.NoError(t, err)
+
+		for body, valid := res(); valid; body, valid = res() {
+			if _, ok := body.BodyMessage.(*spec.BlockBodiesResponse_Proof); ok {
+				proofs++
+			}
+			count++
+		}
+		<-served
+		// bodies without proofs are passed on, unless they come from archive peers
+		if count < blocks*3+1 {
+			assert.Less(t, scores.Score(handlerID), float64(0))
+		}
+		return proofs, count
+	}
+
+	t.Run("valid proofs", func(t *testing.T) {
+		verifier := starknet.NewBlockBodyVerifier(roots)
+		proofs, count := requestBodies(t, verifier)
+		assert.Equal(t, blocks, proofs)
+		// diff, classes, proof and fin of every block, and the final fin
+		assert.Equal(t, blocks*4+1, count)
+		for number := uint64(0); number < blocks; number++ {
+			assert.True(t, verifier.Proven(number))
+		}
+	})
+
+	t.Run("bodies without proofs", func(t *testing.T) {
+		serveBodies(starknet.NewHandler(unarchivedChain, log))
+		t.Cleanup(func() { serveBodies(handler) })
+
+		verifier := starknet.NewBlockBodyVerifier(roots)
+		proofs, count := requestBodies(t, verifier)
+		assert.Zero(t, proofs)
+		// diff, classes and fin of every block, and the final fin
+		assert.Equal(t, blocks*3+1, count)
+		for number := uint64(0); number < blocks; number++ {
+			assert.False(t, verifier.Proven(number))
+		}
+
+		archivePeer = true
+		t.Cleanup(func() { archivePeer = false })
+		proofs, count = requestBodies(t, starknet.NewBlockBodyVerifier(roots))
+		assert.Zero(t, proofs)
+		// the stream ends with the diff and classes of block 0
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("proofs against received headers", func(t *testing.T) {
+		verifier := starknet.NewBlockVerifier(testNetwork)
+		res, err := newClient(starknet.NewPeerScores(), verifier).RequestBlockHeaders(context.Background(),
+			&spec.BlockHeadersRequest{Iteration: iteration})
+		require.NoError(t, err)
+		var headers int
+		for _, valid := res(); valid; _, valid = res() {
+			headers++
+		}
+		// every header and the final fin
+		require.Equal(t, blocks+1, headers)
+
+		proofs, count := requestBodies(t, verifier)
+		assert.Equal(t, blocks, proofs)
+		assert.Equal(t, blocks*4+1, count)
+	})
+
+	t.Run("proofs against other roots", func(t *testing.T) {
+		proofs, count := requestBodies(t, starknet.NewBlockBodyVerifier(func(number uint64) (*felt.Felt, *felt.Felt, error) {
+			// the roots of the next block
+			return roots(number + 1)
+		}))
+		assert.Zero(t, proofs)
+		// the stream ends with the diff and classes of block 0
+		assert.Equal(t, 2, count)
+	})
+}
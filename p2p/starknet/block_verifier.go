@@ -0,0 +1,313 @@
+package starknet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ ResponseVerifier = (*BlockVerifier)(nil)
+
+// verifiedBlocks is how many blocks the BlockVerifier remembers the headers and unverified parts of
+const verifiedBlocks = 2 * MaxIterationLimit
+
+// blockParts are what the BlockVerifier received of a block. The transactions, receipts and events are only
+// kept until they are checked against the header.
+type blockParts struct {
+	header       *core.Header
+	commitments  *core.BlockCommitments
+	transactions []core.Transaction
+	receipts     []*core.TransactionReceipt
+	events       []*core.Event
+}
+
+// BlockVerifier verifies the headers, transactions, receipts, events and block bodies a Client receives, so
+// that inconsistent responses are rejected before their blocks are stored. The hashes of headers are rebuilt
+// from their commitments, and the transactions, receipts and events of a block are checked against the
+// commitments of its header once all of them were received, in any order. The classes of block bodies are
+// checked against their hashes, and their proofs against the state roots of the headers, as a BlockBodyVerifier
+// does.
+//
+// Headers of blocks following Starknet 0.13.2 are rejected, as their hashes commit to fields spec.BlockHeader
+// does not carry.
+type BlockVerifier struct {
+	network utils.Network
+	bodies  *BlockBodyVerifier
+
+	mu     sync.Mutex
+	blocks *lru.Cache[uint64, *blockParts]
+}
+
+func NewBlockVerifier(network utils.Network) *BlockVerifier {
+	v := &BlockVerifier{
+		network: network,
+		blocks:  lru.NewCache[uint64, *blockParts](verifiedBlocks),
+	}
+	v.bodies = NewBlockBodyVerifier(v.roots)
+	return v
+}
+
+func (v *BlockVerifier) VerifyResponse(res proto.Message) error {
+	switch res := res.(type) {
+	case *spec.BlockHeadersResponse:
+		return v.verifyHeaders(res)
+	case *spec.BlockBodiesResponse:
+		if classes, ok := res.BodyMessage.(*spec.BlockBodiesResponse_Classes); ok {
+			if err := verifyClasses(classes.Classes); err != nil {
+				return err
+			}
+		}
+		// proofs are verified against the roots of the headers, which takes the lock
+		return v.bodies.VerifyResponse(res)
+	case *spec.TransactionsResponse:
+		transactions, ok := res.Responses.(*spec.TransactionsResponse_Transactions)
+		if !ok || res.Id == nil {
+			return nil
+		}
+		return v.receiveTransactions(res.Id.Number, transactions.Transactions.GetItems())
+	case *spec.ReceiptsResponse:
+		receipts, ok := res.Responses.(*spec.ReceiptsResponse_Receipts)
+		if !ok || res.Id == nil {
+			return nil
+		}
+		return v.receiveReceipts(res.Id.Number, receipts.Receipts.GetItems())
+	case *spec.EventsResponse:
+		events, ok := res.Responses.(*spec.EventsResponse_Events)
+		if !ok || res.Id == nil {
+			return nil
+		}
+		return v.receiveEvents(res.Id.Number, events.Events.GetItems())
+	default:
+		return nil
+	}
+}
+
+// Proven returns whether the last body received of the block with the given number was verified against a
+// proof
+func (v *BlockVerifier) Proven(number uint64) bool {
+	return v.bodies.Proven(number)
+}
+
+// parts returns what was received of the block with the given number, and must be called with the lock held
+func (v *BlockVerifier) parts(number uint64) *blockParts {
+	parts, ok := v.blocks.Get(number)
+	if !ok {
+		parts = new(blockParts)
+		v.blocks.Add(number, parts)
+	}
+	return parts
+}
+
+func (v *BlockVerifier) verifyHeaders(res *spec.BlockHeadersResponse) error {
+	var (
+		header      *core.Header
+		commitments *core.BlockCommitments
+	)
+	for _, part := range res.Part {
+		switch message := part.HeaderMessage.(type) {
+		case *spec.BlockHeadersResponsePart_Header:
+			if header != nil {
+				return fmt.Errorf("header of block %d without hash", header.Number)
+			}
+			header, commitments = p2p2core.AdaptBlockHeader(message.Header)
+		case *spec.BlockHeadersResponsePart_Signatures:
+			number, hash := p2p2core.AdaptBlockID(message.Signatures.GetBlock())
+			if header == nil || header.Number != number {
+				return fmt.Errorf("signatures of block %d without header", number)
+			}
+			header.Hash = hash
+			header.Signatures = utils.Map(message.Signatures.Signatures, p2p2core.AdaptSignature)
+			if err := v.receiveHeader(header, commitments); err != nil {
+				return err
+			}
+			header = nil
+		}
+	}
+	if header != nil {
+		return fmt.Errorf("header of block %d without hash", header.Number)
+	}
+	return nil
+}
+
+func (v *BlockVerifier) receiveHeader(header *core.Header, commitments *core.BlockCommitments) error {
+	if err := core.VerifyHeaderHash(header, commitments, v.network); err != nil {
+		return fmt.Errorf("block %d: %v", header.Number, err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if header.Number > 0 {
+		if parent, ok := v.blocks.Peek(header.Number - 1); ok && parent.header != nil &&
+			!parent.header.Hash.Equal(header.ParentHash) {
+			return fmt.Errorf("block %d: parent hash %s does not match the hash of block %d", header.Number,
+				header.ParentHash, parent.header.Number)
+		}
+	}
+	if child, ok := v.blocks.Peek(header.Number + 1); ok && child.header != nil &&
+		!child.header.ParentHash.Equal(header.Hash) {
+		return fmt.Errorf("block %d: hash %s does not match the parent hash of block %d", header.Number,
+			header.Hash, child.header.Number)
+	}
+
+	parts := v.parts(header.Number)
+	parts.header, parts.commitments = header, commitments
+	return v.verifyParts(parts)
+}
+
+func (v *BlockVerifier) receiveTransactions(number uint64, items []*spec.Transaction) error {
+	transactions := make([]core.Transaction, 0, len(items))
+	for _, item := range items {
+		txn, err := p2p2core.AdaptTransaction(item)
+		if err != nil {
+			return fmt.Errorf("block %d: %v", number, err)
+		}
+		transactions = append(transactions, txn)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts := v.parts(number)
+	parts.transactions = transactions
+	return v.verifyParts(parts)
+}
+
+func (v *BlockVerifier) receiveReceipts(number uint64, items []*spec.Receipt) error {
+	receipts := make([]*core.TransactionReceipt, 0, len(items))
+	for _, item := range items {
+		receipt := p2p2core.AdaptReceipt(item)
+		if receipt == nil || receipt.TransactionHash == nil {
+			return fmt.Errorf("block %d: malformed receipt", number)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts := v.parts(number)
+	parts.receipts = receipts
+	return v.verifyParts(parts)
+}
+
+func (v *BlockVerifier) receiveEvents(number uint64, items []*spec.Event) error {
+	events := make([]*core.Event, 0, len(items))
+	for _, item := range items {
+		event := p2p2core.AdaptEvent(item)
+		if event == nil || event.From == nil {
+			return fmt.Errorf("block %d: malformed event", number)
+		}
+		events = append(events, event)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts := v.parts(number)
+	parts.events = events
+	return v.verifyParts(parts)
+}
+
+// verifyParts checks the parts of a block that were received along with its header, and must be called with
+// the lock held. Checked parts are dropped, whether they are valid or not.
+func (v *BlockVerifier) verifyParts(parts *blockParts) error {
+	if parts.header == nil {
+		return nil
+	}
+
+	var txErr, eventErr error
+	if parts.transactions != nil && parts.receipts != nil {
+		txErr = verifyTransactions(parts.header, parts.commitments, parts.transactions, parts.receipts)
+		parts.transactions, parts.receipts = nil, nil
+	}
+	if parts.events != nil {
+		eventErr = verifyEvents(parts.header, parts.commitments, parts.events)
+		parts.events = nil
+	}
+	if err := errors.Join(txErr, eventErr); err != nil {
+		return fmt.Errorf("block %d: %v", parts.header.Number, err)
+	}
+	return nil
+}
+
+// verifyTransactions checks the transactions of a block, with the hashes of their receipts, against the
+// transaction commitment of its header
+func verifyTransactions(header *core.Header, commitments *core.BlockCommitments, transactions []core.Transaction,
+	receipts []*core.TransactionReceipt,
+) error {
+	if uint64(len(transactions)) != header.TransactionCount || len(receipts) != len(transactions) {
+		return fmt.Errorf("received %d transactions and %d receipts, the header has %d", len(transactions),
+			len(receipts), header.TransactionCount)
+	}
+
+	// spec.Transaction does not carry the hashes of transactions
+	for i, txn := range transactions {
+		setTransactionHash(txn, receipts[i].TransactionHash)
+	}
+	return core.VerifyTransactionCommitment(transactions, commitments.TransactionCommitment)
+}
+
+func setTransactionHash(txn core.Transaction, hash *felt.Felt) {
+	switch t := txn.(type) {
+	case *core.DeclareTransaction:
+		t.TransactionHash = hash
+	case *core.DeployTransaction:
+		t.TransactionHash = hash
+	case *core.DeployAccountTransaction:
+		t.TransactionHash = hash
+	case *core.InvokeTransaction:
+		t.TransactionHash = hash
+	case *core.L1HandlerTransaction:
+		t.TransactionHash = hash
+	}
+}
+
+// verifyEvents checks the events of a block against the event commitment of its header
+func verifyEvents(header *core.Header, commitments *core.BlockCommitments, events []*core.Event) error {
+	if commitments.EventCommitment == nil {
+		// blocks preceding Cairo 0.7.0 do not commit to their events
+		return nil
+	}
+	if uint64(len(events)) != header.EventCount {
+		return fmt.Errorf("received %d events, the header has %d", len(events), header.EventCount)
+	}
+	return core.VerifyEventCommitment(events, commitments.EventCommitment)
+}
+
+// verifyClasses checks the classes of a block body against the hashes they are sent with
+func verifyClasses(classes *spec.Classes) error {
+	received := make(map[felt.Felt]core.Class, len(classes.GetClasses()))
+	for _, class := range classes.GetClasses() {
+		hash, coreClass, err := p2p2core.AdaptClass(class)
+		if err != nil {
+			return fmt.Errorf("malformed class: %v", err)
+		}
+		received[*hash] = coreClass
+	}
+	return core.VerifyClassHashes(received)
+}
+
+// roots returns the state roots before and after the block with the given number, from the headers received
+func (v *BlockVerifier) roots(number uint64) (oldRoot, newRoot *felt.Felt, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	parts, ok := v.blocks.Peek(number)
+	if !ok || parts.header == nil {
+		return nil, nil, fmt.Errorf("header of block %d not received", number)
+	}
+	if number == 0 {
+		return &felt.Zero, parts.header.GlobalStateRoot, nil
+	}
+
+	parent, ok := v.blocks.Peek(number - 1)
+	if !ok || parent.header == nil {
+		return nil, nil, fmt.Errorf("header of block %d not received", number-1)
+	}
+	return parent.header.GlobalStateRoot, parts.header.GlobalStateRoot, nil
+}
@@ -0,0 +1,222 @@
+package starknet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// blockResponses are the responses a Handler sends for a block
+type blockResponses struct {
+	header       *spec.BlockHeadersResponse
+	transactions *spec.TransactionsResponse
+	receipts     *spec.ReceiptsResponse
+	events       *spec.EventsResponse
+}
+
+func newBlockResponses(t *testing.T, block *core.Block, network utils.Network) *blockResponses {
+	t.Helper()
+	commitments, err := core.VerifyBlockHash(block, network, nil)
+	require.NoError(t, err)
+
+	id := core2p2p.AdaptBlockID(block.Header)
+	receipts := make([]*spec.Receipt, len(block.Receipts))
+	var events []*spec.Event
+	for i, receipt := range block.Receipts {
+		receipts[i] = core2p2p.AdaptReceipt(receipt, block.Transactions[i])
+		events = append(events, utils.Map(receipt.Events, core2p2p.AdaptEvent)...)
+	}
+
+	return &blockResponses{
+		header: &spec.BlockHeadersResponse{
+			Part: []*spec.BlockHeadersResponsePart{
+				{HeaderMessage: &spec.BlockHeadersResponsePart_Header{Header: core2p2p.AdaptHeader(block.Header, commitments)}},
+				{HeaderMessage: &spec.BlockHeadersResponsePart_Signatures{Signatures: &spec.Signatures{Block: id}}},
+			},
+		},
+		transactions: &spec.TransactionsResponse{
+			Id: id,
+			Responses: &spec.TransactionsResponse_Transactions{
+				Transactions: &spec.Transactions{Items: utils.Map(block.Transactions, core2p2p.AdaptTransaction)},
+			},
+		},
+		receipts: &spec.ReceiptsResponse{
+			Id:        id,
+			Responses: &spec.ReceiptsResponse_Receipts{Receipts: &spec.Receipts{Items: receipts}},
+		},
+		events: &spec.EventsResponse{
+			Id:        id,
+			Responses: &spec.EventsResponse_Events{Events: &spec.Events{Items: events}},
+		},
+	}
+}
+
+func TestBlockVerifier(t *testing.T) {
+	network := utils.Mainnet
+	gw := adaptfeeder.New(feeder.NewTestClient(t, network))
+
+	responses := func(t *testing.T, number uint64) *blockResponses {
+		t.Helper()
+		block, err := gw.BlockByNumber(context.Background(), number)
+		require.NoError(t, err)
+		return newBlockResponses(t, block, network)
+	}
+
+	// pre 0.7.0, post 0.7.0 without and with sequencer address
+	for _, number := range []uint64{2, 833, 16730} {
+		res := responses(t, number)
+
+		t.Run(fmt.Sprintf("block %d header first", number), func(t *testing.T) {
+			verifier := starknet.NewBlockVerifier(network)
+			for _, msg := range []proto.Message{res.header, res.transactions, res.receipts, res.events} {
+				assert.NoError(t, verifier.VerifyResponse(msg), "block %d", number)
+			}
+		})
+
+		t.Run(fmt.Sprintf("block %d header last", number), func(t *testing.T) {
+			verifier := starknet.NewBlockVerifier(network)
+			for _, msg := range []proto.Message{res.events, res.receipts, res.transactions, res.header} {
+				assert.NoError(t, verifier.VerifyResponse(msg), "block %d", number)
+			}
+		})
+	}
+
+	t.Run("tampered header", func(t *testing.T) {
+		res := responses(t, 833)
+		header := res.header.Part[0].HeaderMessage.(*spec.BlockHeadersResponsePart_Header).Header
+		header.State.Root = core2p2p.AdaptHash(new(felt.Felt).SetUint64(1))
+		assert.ErrorContains(t, starknet.NewBlockVerifier(network).VerifyResponse(res.header), "can not verify hash")
+	})
+
+	t.Run("header without hash", func(t *testing.T) {
+		res := responses(t, 833)
+		res.header.Part = res.header.Part[:1]
+		assert.ErrorContains(t, starknet.NewBlockVerifier(network).VerifyResponse(res.header), "without hash")
+	})
+
+	t.Run("unchained headers", func(t *testing.T) {
+		// the hashes of the first blocks of integration cannot be verified, so only their chaining is
+		integrationGw := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+		integrationHeaders := func(t *testing.T) (*spec.BlockHeadersResponse, *spec.BlockHeadersResponse) {
+			t.Helper()
+			var headers []*spec.BlockHeadersResponse
+			for number := uint64(0); number < 2; number++ {
+				block, err := integrationGw.BlockByNumber(context.Background(), number)
+				require.NoError(t, err)
+				headers = append(headers, newBlockResponses(t, block, utils.Integration).header)
+			}
+			headers[1].Part[0].HeaderMessage.(*spec.BlockHeadersResponsePart_Header).Header.ParentHeader =
+				core2p2p.AdaptHash(new(felt.Felt).SetUint64(1))
+			return headers[0], headers[1]
+		}
+
+		genesis, child := integrationHeaders(t)
+		verifier := starknet.NewBlockVerifier(utils.Integration)
+		require.NoError(t, verifier.VerifyResponse(genesis))
+		assert.ErrorContains(t, verifier.VerifyResponse(child), "parent hash")
+
+		genesis, child = integrationHeaders(t)
+		verifier = starknet.NewBlockVerifier(utils.Integration)
+		require.NoError(t, verifier.VerifyResponse(child))
+		assert.ErrorContains(t, verifier.VerifyResponse(genesis), "parent hash")
+
+		verifier = starknet.NewBlockVerifier(network)
+		require.NoError(t, verifier.VerifyResponse(responses(t, 0).header))
+		assert.NoError(t, verifier.VerifyResponse(responses(t, 1).header))
+		assert.NoError(t, verifier.VerifyResponse(responses(t, 2).header))
+	})
+
+	t.Run("tampered receipt", func(t *testing.T) {
+		res := responses(t, 833)
+		verifier := starknet.NewBlockVerifier(network)
+		require.NoError(t, verifier.VerifyResponse(res.header))
+		require.NoError(t, verifier.VerifyResponse(res.transactions))
+
+		receipts := res.receipts.Responses.(*spec.ReceiptsResponse_Receipts).Receipts.Items
+		receipts[0], receipts[1] = receipts[1], receipts[0]
+		assert.ErrorContains(t, verifier.VerifyResponse(res.receipts), "do not match transaction commitment")
+	})
+
+	t.Run("missing transaction", func(t *testing.T) {
+		res := responses(t, 833)
+		verifier := starknet.NewBlockVerifier(network)
+		require.NoError(t, verifier.VerifyResponse(res.header))
+		require.NoError(t, verifier.VerifyResponse(res.receipts))
+
+		transactions := res.transactions.Responses.(*spec.TransactionsResponse_Transactions).Transactions
+		transactions.Items = transactions.Items[1:]
+		assert.ErrorContains(t, verifier.VerifyResponse(res.transactions), "transactions")
+	})
+
+	t.Run("tampered event", func(t *testing.T) {
+		res := responses(t, 16730)
+		verifier := starknet.NewBlockVerifier(network)
+		require.NoError(t, verifier.VerifyResponse(res.header))
+
+		events := res.events.Responses.(*spec.EventsResponse_Events).Events.Items
+		require.NotEmpty(t, events)
+		events[0].Data = nil
+		assert.ErrorContains(t, verifier.VerifyResponse(res.events), "do not match event commitment")
+	})
+
+	t.Run("malformed event", func(t *testing.T) {
+		res := responses(t, 16730)
+		verifier := starknet.NewBlockVerifier(network)
+		require.NoError(t, verifier.VerifyResponse(res.header))
+
+		res.events.Responses.(*spec.EventsResponse_Events).Events.Items[0].FromAddress = nil
+		assert.ErrorContains(t, verifier.VerifyResponse(res.events), "malformed event")
+	})
+
+	t.Run("classes", func(t *testing.T) {
+		integrationGw := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+		cairo0Hash := utils.HexToFelt(t, "0x4631b6b3fa31e140524b7d21ba784cea223e618bffe60b5bbdca44a8b45be04")
+		cairo0Class, err := integrationGw.Class(context.Background(), cairo0Hash)
+		require.NoError(t, err)
+		specClass, err := core2p2p.AdaptClass(cairo0Class, cairo0Hash)
+		require.NoError(t, err)
+		assert.NoError(t, starknet.NewBlockVerifier(network).VerifyResponse(&spec.BlockBodiesResponse{
+			Id:          &spec.BlockID{Number: 1},
+			BodyMessage: &spec.BlockBodiesResponse_Classes{Classes: &spec.Classes{Classes: []*spec.Class{specClass}}},
+		}))
+
+		classHash := utils.HexToFelt(t, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+		class, err := integrationGw.Class(context.Background(), classHash)
+		require.NoError(t, err)
+
+		classesResponse := func(t *testing.T, hash *felt.Felt) *spec.BlockBodiesResponse {
+			specClass, err := core2p2p.AdaptClass(class, hash)
+			require.NoError(t, err)
+			return &spec.BlockBodiesResponse{
+				Id: &spec.BlockID{Number: 1},
+				BodyMessage: &spec.BlockBodiesResponse_Classes{
+					Classes: &spec.Classes{Classes: []*spec.Class{specClass}},
+				},
+			}
+		}
+
+		verifier := starknet.NewBlockVerifier(network)
+		assert.NoError(t, verifier.VerifyResponse(classesResponse(t, classHash)))
+		assert.ErrorContains(t, verifier.VerifyResponse(classesResponse(t, new(felt.Felt).SetUint64(1))),
+			"cannot verify class hash")
+
+		res := classesResponse(t, classHash)
+		res.BodyMessage.(*spec.BlockBodiesResponse_Classes).Classes.Classes[0].Definition = []byte("garbage")
+		assert.ErrorContains(t, verifier.VerifyResponse(res), "malformed class")
+
+		res.BodyMessage.(*spec.BlockBodiesResponse_Classes).Classes.Classes[0].Definition = []byte(`{"sierra_program":[null]}`)
+		assert.ErrorContains(t, verifier.VerifyResponse(res), "missing required fields")
+	})
+}
@@ -2,10 +2,13 @@ package starknet
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/NethermindEth/juno/p2p/starknet/spec"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/proto"
@@ -13,20 +16,51 @@ import (
 
 type NewStreamFunc func(ctx context.Context, pids ...protocol.ID) (network.Stream, error)
 
+// ResponseVerifier checks the data of a response, such as its hashes and commitments
+type ResponseVerifier interface {
+	VerifyResponse(res proto.Message) error
+}
+
 type Client struct {
 	newStream NewStreamFunc
 	network   utils.Network
-	log       utils.Logger
+	scores    *PeerScores
+	verifier  ResponseVerifier
+	// archivePeer returns whether a peer advertises archive support
+	archivePeer func(id peer.ID) bool
+	log         utils.SimpleLogger
 }
 
-func NewClient(newStream NewStreamFunc, snNetwork utils.Network, log utils.Logger) *Client {
+func NewClient(newStream NewStreamFunc, snNetwork utils.Network, log utils.SimpleLogger) *Client {
 	return &Client{
 		newStream: newStream,
 		network:   snNetwork,
+		scores:    NewPeerScores(),
 		log:       log,
 	}
 }
 
+// WithArchivePeers makes the Client penalise the peers advertising archive support that send block bodies
+// without proofs. The bodies other peers send without proofs are passed on, unproven.
+func (c *Client) WithArchivePeers(archivePeer func(id peer.ID) bool) *Client {
+	c.archivePeer = archivePeer
+	return c
+}
+
+// WithPeerScores makes the Client penalise the peers sending malformed or invalid responses in the given
+// PeerScores, which are usually shared with the Handler and the connection gater
+func (c *Client) WithPeerScores(scores *PeerScores) *Client {
+	c.scores = scores
+	return c
+}
+
+// WithVerifier makes the Client end the response streams at the first response the verifier rejects. The
+// clients the p2p Service creates verify responses with a BlockVerifier.
+func (c *Client) WithVerifier(verifier ResponseVerifier) *Client {
+	c.verifier = verifier
+	return c
+}
+
 func sendAndCloseWrite(stream network.Stream, req proto.Message) error {
 	reqBytes, err := proto.Marshal(req)
 	if err != nil {
@@ -44,9 +78,9 @@ func receiveInto(stream network.Stream, res proto.Message) error {
 }
 
 func requestAndReceiveStream[ReqT proto.Message, ResT proto.Message](ctx context.Context,
-	newStream NewStreamFunc, protocolID protocol.ID, req ReqT,
+	c *Client, protocolID protocol.ID, req ReqT,
 ) (Stream[ResT], error) {
-	stream, err := newStream(ctx, protocolID)
+	stream, err := c.newStream(ctx, protocolID)
 	if err != nil {
 		return nil, err
 	}
@@ -54,33 +88,54 @@ func requestAndReceiveStream[ReqT proto.Message, ResT proto.Message](ctx context
 		return nil, err
 	}
 
+	peerID := stream.Conn().RemotePeer()
 	return func() (ResT, bool) {
 		var zero ResT
 		res := zero.ProtoReflect().New().Interface()
 		if err := receiveInto(stream, res); err != nil {
+			// responses cut short by our side are not the fault of the peer
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				c.log.Debugw("Failed receiving response", "peer", peerID, "protocol", protocolID, "err", err)
+				c.scores.Penalise(peerID, MalformedResponse)
+			}
 			stream.Close() // todo: dont ignore close errors
 			return zero, false
 		}
+
+		if c.verifier != nil {
+			if err := c.verifier.VerifyResponse(res); err != nil && !c.unprovenBody(peerID, err) {
+				c.log.Debugw("Received invalid response", "peer", peerID, "protocol", protocolID, "err", err)
+				c.scores.Penalise(peerID, InvalidData)
+				stream.Close() // todo: dont ignore close errors
+				return zero, false
+			}
+		}
 		return res.(ResT), true
 	}, nil
 }
 
+// unprovenBody returns whether the error is only that of a block body without proof from a peer that does not
+// advertise archive support
+func (c *Client) unprovenBody(id peer.ID, err error) bool {
+	return errors.Is(err, ErrMissingProof) && (c.archivePeer == nil || !c.archivePeer(id))
+}
+
 func (c *Client) RequestBlockHeaders(ctx context.Context, req *spec.BlockHeadersRequest) (Stream[*spec.BlockHeadersResponse], error) {
-	return requestAndReceiveStream[*spec.BlockHeadersRequest, *spec.BlockHeadersResponse](ctx, c.newStream, BlockHeadersPID(c.network), req)
+	return requestAndReceiveStream[*spec.BlockHeadersRequest, *spec.BlockHeadersResponse](ctx, c, BlockHeadersPID(c.network), req)
 }
 
 func (c *Client) RequestBlockBodies(ctx context.Context, req *spec.BlockBodiesRequest) (Stream[*spec.BlockBodiesResponse], error) {
-	return requestAndReceiveStream[*spec.BlockBodiesRequest, *spec.BlockBodiesResponse](ctx, c.newStream, BlockBodiesPID(c.network), req)
+	return requestAndReceiveStream[*spec.BlockBodiesRequest, *spec.BlockBodiesResponse](ctx, c, BlockBodiesPID(c.network), req)
 }
 
 func (c *Client) RequestEvents(ctx context.Context, req *spec.EventsRequest) (Stream[*spec.EventsResponse], error) {
-	return requestAndReceiveStream[*spec.EventsRequest, *spec.EventsResponse](ctx, c.newStream, EventsPID(c.network), req)
+	return requestAndReceiveStream[*spec.EventsRequest, *spec.EventsResponse](ctx, c, EventsPID(c.network), req)
 }
 
 func (c *Client) RequestReceipts(ctx context.Context, req *spec.ReceiptsRequest) (Stream[*spec.ReceiptsResponse], error) {
-	return requestAndReceiveStream[*spec.ReceiptsRequest, *spec.ReceiptsResponse](ctx, c.newStream, ReceiptsPID(c.network), req)
+	return requestAndReceiveStream[*spec.ReceiptsRequest, *spec.ReceiptsResponse](ctx, c, ReceiptsPID(c.network), req)
 }
 
 func (c *Client) RequestTransactions(ctx context.Context, req *spec.TransactionsRequest) (Stream[*spec.TransactionsResponse], error) {
-	return requestAndReceiveStream[*spec.TransactionsRequest, *spec.TransactionsResponse](ctx, c.newStream, TransactionsPID(c.network), req)
+	return requestAndReceiveStream[*spec.TransactionsRequest, *spec.TransactionsResponse](ctx, c, TransactionsPID(c.network), req)
 }
@@ -1,9 +1,11 @@
-//go:generate protoc --go_out=./ --proto_path=./ --go_opt=Mp2p/proto/transaction.proto=./spec --go_opt=Mp2p/proto/state.proto=./spec --go_opt=Mp2p/proto/snapshot.proto=./spec --go_opt=Mp2p/proto/receipt.proto=./spec --go_opt=Mp2p/proto/mempool.proto=./spec --go_opt=Mp2p/proto/event.proto=./spec --go_opt=Mp2p/proto/block.proto=./spec --go_opt=Mp2p/proto/common.proto=./spec p2p/proto/transaction.proto p2p/proto/state.proto p2p/proto/snapshot.proto p2p/proto/common.proto p2p/proto/block.proto p2p/proto/event.proto p2p/proto/receipt.proto
+//go:generate protoc --go_out=./ --proto_path=./ --go_opt=Mp2p/proto/transaction.proto=./spec --go_opt=Mp2p/proto/state.proto=./spec --go_opt=Mp2p/proto/snapshot.proto=./spec --go_opt=Mp2p/proto/receipt.proto=./spec --go_opt=Mp2p/proto/mempool.proto=./spec --go_opt=Mp2p/proto/event.proto=./spec --go_opt=Mp2p/proto/block.proto=./spec --go_opt=Mp2p/proto/common.proto=./spec --go_opt=Mp2p/proto/gossip.proto=./spec --go_opt=Mp2p/proto/proof.proto=./spec p2p/proto/transaction.proto p2p/proto/state.proto p2p/proto/snapshot.proto p2p/proto/common.proto p2p/proto/block.proto p2p/proto/event.proto p2p/proto/receipt.proto p2p/proto/gossip.proto p2p/proto/proof.proto
 package starknet
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/NethermindEth/juno/adapters/core2p2p"
@@ -16,18 +18,36 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	// maxRequestSize caps the size of requests, which only describe an iteration
+	maxRequestSize = 1024
+	// MaxIterationLimit caps the number of blocks a request iterates over
+	MaxIterationLimit = 1024
+)
+
+var errMalformedRequest = errors.New("malformed request")
+
 type Handler struct {
 	bcReader blockchain.Reader
+	scores   *PeerScores
 	log      utils.Logger
 }
 
 func NewHandler(bcReader blockchain.Reader, log utils.Logger) *Handler {
 	return &Handler{
 		bcReader: bcReader,
+		scores:   NewPeerScores(),
 		log:      log,
 	}
 }
 
+// WithPeerScores makes the Handler rate limit and score peers with the given PeerScores, which are
+// usually shared with the Client and the connection gater
+func (h *Handler) WithPeerScores(scores *PeerScores) *Handler {
+	h.scores = scores
+	return h
+}
+
 // bufferPool caches unused buffer objects for later reuse.
 var bufferPool = sync.Pool{
 	New: func() any {
@@ -42,7 +62,7 @@ func getBuffer() *bytes.Buffer {
 }
 
 func streamHandler[ReqT proto.Message](stream network.Stream,
-	reqHandler func(req ReqT) (Stream[proto.Message], error), log utils.SimpleLogger,
+	reqHandler func(req ReqT) (Stream[proto.Message], error), scores *PeerScores, log utils.SimpleLogger,
 ) {
 	defer func() {
 		if err := stream.Close(); err != nil {
@@ -50,24 +70,39 @@ func streamHandler[ReqT proto.Message](stream network.Stream,
 		}
 	}()
 
+	peerID := stream.Conn().RemotePeer()
+	if !scores.AllowRequest(peerID) {
+		log.Debugw("Dropping request", "peer", peerID, "protocol", stream.Protocol(), "score", scores.Score(peerID))
+		return
+	}
+
 	buffer := getBuffer()
 	defer bufferPool.Put(buffer)
 
-	if _, err := buffer.ReadFrom(stream); err != nil {
+	if _, err := buffer.ReadFrom(io.LimitReader(stream, maxRequestSize+1)); err != nil {
 		log.Debugw("Error reading from stream", "peer", stream.ID(), "protocol", stream.Protocol(), "err", err)
 		return
 	}
+	if buffer.Len() > maxRequestSize {
+		log.Debugw("Request too large", "peer", stream.ID(), "protocol", stream.Protocol())
+		scores.Penalise(peerID, MalformedRequest)
+		return
+	}
 
 	var zero ReqT
 	req := zero.ProtoReflect().New().Interface()
 	if err := proto.Unmarshal(buffer.Bytes(), req); err != nil {
 		log.Debugw("Error unmarshalling message", "peer", stream.ID(), "protocol", stream.Protocol(), "err", err)
+		scores.Penalise(peerID, MalformedRequest)
 		return
 	}
 
 	response, err := reqHandler(req.(ReqT))
 	if err != nil {
 		log.Debugw("Error handling request", "peer", stream.ID(), "protocol", stream.Protocol(), "err", err)
+		if errors.Is(err, errMalformedRequest) {
+			scores.Penalise(peerID, MalformedRequest)
+		}
 		return
 	}
 
@@ -79,23 +114,23 @@ func streamHandler[ReqT proto.Message](stream network.Stream,
 }
 
 func (h *Handler) BlockHeadersHandler(stream network.Stream) {
-	streamHandler[*spec.BlockHeadersRequest](stream, h.onBlockHeadersRequest, h.log)
+	streamHandler[*spec.BlockHeadersRequest](stream, h.onBlockHeadersRequest, h.scores, h.log)
 }
 
 func (h *Handler) BlockBodiesHandler(stream network.Stream) {
-	streamHandler[*spec.BlockBodiesRequest](stream, h.onBlockBodiesRequest, h.log)
+	streamHandler[*spec.BlockBodiesRequest](stream, h.onBlockBodiesRequest, h.scores, h.log)
 }
 
 func (h *Handler) EventsHandler(stream network.Stream) {
-	streamHandler[*spec.EventsRequest](stream, h.onEventsRequest, h.log)
+	streamHandler[*spec.EventsRequest](stream, h.onEventsRequest, h.scores, h.log)
 }
 
 func (h *Handler) ReceiptsHandler(stream network.Stream) {
-	streamHandler[*spec.ReceiptsRequest](stream, h.onReceiptsRequest, h.log)
+	streamHandler[*spec.ReceiptsRequest](stream, h.onReceiptsRequest, h.scores, h.log)
 }
 
 func (h *Handler) TransactionsHandler(stream network.Stream) {
-	streamHandler[*spec.TransactionsRequest](stream, h.onTransactionsRequest, h.log)
+	streamHandler[*spec.TransactionsRequest](stream, h.onTransactionsRequest, h.scores, h.log)
 }
 
 func (h *Handler) onBlockHeadersRequest(req *spec.BlockHeadersRequest) (Stream[proto.Message], error) {
@@ -295,15 +330,21 @@ func (h *Handler) onTransactionsRequest(req *spec.TransactionsRequest) (Stream[p
 }
 
 func (h *Handler) newIterator(it *spec.Iteration) (*iterator, error) {
-	forward := it.Direction == spec.Iteration_Forward
+	if it == nil {
+		return nil, fmt.Errorf("%w: missing iteration", errMalformedRequest)
+	} else if it.Limit == 0 || it.Step == 0 {
+		return nil, fmt.Errorf("%w: zero limit or step", errMalformedRequest)
+	}
 
+	forward := it.Direction == spec.Iteration_Forward
+	limit := min(it.Limit, MaxIterationLimit)
 	switch v := it.Start.(type) {
 	case *spec.Iteration_BlockNumber:
-		return newIteratorByNumber(h.bcReader, v.BlockNumber, it.Limit, it.Step, forward)
+		return newIteratorByNumber(h.bcReader, v.BlockNumber, limit, it.Step, forward)
 	case *spec.Iteration_Header:
-		return newIteratorByHash(h.bcReader, p2p2core.AdaptHash(v.Header), it.Limit, it.Step, forward)
+		return newIteratorByHash(h.bcReader, p2p2core.AdaptHash(v.Header), limit, it.Step, forward)
 	default:
-		return nil, fmt.Errorf("unsupported iteration start type %T", v)
+		return nil, fmt.Errorf("%w: unsupported iteration start type %T", errMalformedRequest, v)
 	}
 }
 
@@ -24,3 +24,19 @@ func ReceiptsPID(n utils.Network) protocol.ID {
 func TransactionsPID(n utils.Network) protocol.ID {
 	return n.ProtocolID() + "/transactions/0"
 }
+
+// ArchivePID is the protocol the nodes that prove the block bodies they send advertise. It is only advertised,
+// and its streams are closed right away.
+func ArchivePID(n utils.Network) protocol.ID {
+	return n.ProtocolID() + "/archive/0"
+}
+
+// NewBlocksTopic is the pubsub topic blocks are announced on as soon as they are stored
+func NewBlocksTopic(n utils.Network) string {
+	return string(n.ProtocolID()) + "/new_blocks/0"
+}
+
+// NewTransactionsTopic is the pubsub topic the transactions sent to nodes are propagated on
+func NewTransactionsTopic(n utils.Network) string {
+	return string(n.ProtocolID()) + "/new_transactions/0"
+}
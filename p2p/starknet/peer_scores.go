@@ -0,0 +1,269 @@
+package starknet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+var _ connmgr.ConnectionGater = (*PeerScores)(nil)
+
+// Violation is a breach of the protocol by a peer, which lowers its score
+type Violation uint8
+
+const (
+	// MalformedRequest is a request that cannot be decoded, is too large or asks for an invalid iteration
+	MalformedRequest Violation = iota
+	// RateLimitExceeded is a request sent faster than peers are served
+	RateLimitExceeded
+	// MalformedResponse is a response that cannot be decoded
+	MalformedResponse
+	// InvalidData is a response whose data does not match its hashes or commitments
+	InvalidData
+)
+
+func (v Violation) String() string {
+	switch v {
+	case MalformedRequest:
+		return "malformed_request"
+	case RateLimitExceeded:
+		return "rate_limit_exceeded"
+	case MalformedResponse:
+		return "malformed_response"
+	case InvalidData:
+		return "invalid_data"
+	default:
+		return "unknown"
+	}
+}
+
+func (v Violation) penalty() float64 {
+	switch v {
+	case RateLimitExceeded:
+		return 5
+	case MalformedRequest:
+		return 10
+	case MalformedResponse:
+		return 20
+	default:
+		// sending data that does not verify is the only violation that cannot be a mistake
+		return 50
+	}
+}
+
+const (
+	// peers are banned once their score drops to banThreshold
+	banThreshold = -100
+	banDuration  = 10 * time.Minute
+	// scores recover towards zero, so that occasional violations are forgiven
+	scoreRecoveryPerSecond = 0.5
+
+	// every peer can send requestBurst requests at once, and requestsPerSecond after that
+	requestsPerSecond = 10
+	requestBurst      = 20
+
+	// peers that are not banned are forgotten once their scores recovered and there are more of them than that
+	maxTrackedPeers = 1024
+)
+
+type EventListener interface {
+	OnPeerPenalised(id peer.ID, violation Violation, score float64)
+	OnPeerBanned(id peer.ID)
+}
+
+type SelectiveListener struct {
+	OnPeerPenalisedCb func(id peer.ID, violation Violation, score float64)
+	OnPeerBannedCb    func(id peer.ID)
+}
+
+func (l *SelectiveListener) OnPeerPenalised(id peer.ID, violation Violation, score float64) {
+	if l.OnPeerPenalisedCb != nil {
+		l.OnPeerPenalisedCb(id, violation, score)
+	}
+}
+
+func (l *SelectiveListener) OnPeerBanned(id peer.ID) {
+	if l.OnPeerBannedCb != nil {
+		l.OnPeerBannedCb(id)
+	}
+}
+
+type peerState struct {
+	score      float64
+	scoredAt   time.Time
+	tokens     float64
+	refilledAt time.Time
+}
+
+// PeerScores keeps the reputation of peers. It rate limits their requests, lowers their scores for protocol
+// violations and temporarily bans the peers whose scores drop too low. As a connection gater, it keeps
+// banned peers from connecting.
+type PeerScores struct {
+	mu     sync.Mutex
+	peers  map[peer.ID]*peerState
+	banned map[peer.ID]time.Time
+
+	onBan    func(id peer.ID)
+	listener EventListener
+	now      func() time.Time
+}
+
+func NewPeerScores() *PeerScores {
+	return &PeerScores{
+		peers:    make(map[peer.ID]*peerState),
+		banned:   make(map[peer.ID]time.Time),
+		listener: &SelectiveListener{},
+		now:      time.Now,
+	}
+}
+
+// WithBanHandler registers a function that is called with every peer that gets banned, which should
+// disconnect it
+func (p *PeerScores) WithBanHandler(onBan func(id peer.ID)) *PeerScores {
+	p.onBan = onBan
+	return p
+}
+
+// WithListener registers an EventListener
+func (p *PeerScores) WithListener(listener EventListener) *PeerScores {
+	p.listener = listener
+	return p
+}
+
+// state returns the state of a peer that is not banned, with its score and tokens brought up to date.
+// It must be called with the lock held.
+func (p *PeerScores) state(id peer.ID) *peerState {
+	now := p.now()
+	s, found := p.peers[id]
+	if !found {
+		if len(p.peers) >= maxTrackedPeers {
+			p.forgetRecovered()
+		}
+		s = &peerState{
+			scoredAt:   now,
+			tokens:     requestBurst,
+			refilledAt: now,
+		}
+		p.peers[id] = s
+	}
+
+	s.score = min(0, s.score+now.Sub(s.scoredAt).Seconds()*scoreRecoveryPerSecond)
+	s.scoredAt = now
+	s.tokens = min(requestBurst, s.tokens+now.Sub(s.refilledAt).Seconds()*requestsPerSecond)
+	s.refilledAt = now
+	return s
+}
+
+// forgetRecovered drops the peers whose scores recovered. It must be called with the lock held.
+func (p *PeerScores) forgetRecovered() {
+	now := p.now()
+	for id, s := range p.peers {
+		if s.score+now.Sub(s.scoredAt).Seconds()*scoreRecoveryPerSecond >= 0 {
+			delete(p.peers, id)
+		}
+	}
+}
+
+// isBanned must be called with the lock held
+func (p *PeerScores) isBanned(id peer.ID) bool {
+	until, found := p.banned[id]
+	if !found {
+		return false
+	}
+	if p.now().Before(until) {
+		return true
+	}
+	delete(p.banned, id)
+	return false
+}
+
+// Score returns the score of a peer, which is zero for peers without recent violations and negative
+// otherwise
+func (p *PeerScores) Score(id peer.ID) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isBanned(id) {
+		return banThreshold
+	}
+	return p.state(id).score
+}
+
+// Banned returns whether the peer is banned
+func (p *PeerScores) Banned(id peer.ID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.isBanned(id)
+}
+
+// Penalise lowers the score of a peer for a violation, and bans it if its score drops too low
+func (p *PeerScores) Penalise(id peer.ID, violation Violation) {
+	p.mu.Lock()
+	if p.isBanned(id) {
+		p.mu.Unlock()
+		return
+	}
+	s := p.state(id)
+	s.score -= violation.penalty()
+	score, ban := s.score, s.score <= banThreshold
+	if ban {
+		delete(p.peers, id)
+		p.banned[id] = p.now().Add(banDuration)
+	}
+	p.mu.Unlock()
+
+	p.listener.OnPeerPenalised(id, violation, score)
+	if ban {
+		p.listener.OnPeerBanned(id)
+		if p.onBan != nil {
+			p.onBan(id)
+		}
+	}
+}
+
+// AllowRequest returns whether a request of the peer is served. Peers that send requests too fast are
+// penalised.
+func (p *PeerScores) AllowRequest(id peer.ID) bool {
+	p.mu.Lock()
+	if p.isBanned(id) {
+		p.mu.Unlock()
+		return false
+	}
+	s := p.state(id)
+	allowed := s.tokens >= 1
+	if allowed {
+		s.tokens--
+	}
+	p.mu.Unlock()
+
+	if !allowed {
+		p.Penalise(id, RateLimitExceeded)
+	}
+	return allowed
+}
+
+func (p *PeerScores) InterceptPeerDial(id peer.ID) bool {
+	return !p.Banned(id)
+}
+
+func (p *PeerScores) InterceptAddrDial(id peer.ID, _ multiaddr.Multiaddr) bool {
+	return !p.Banned(id)
+}
+
+func (p *PeerScores) InterceptAccept(network.ConnMultiaddrs) bool {
+	// the peer is only known once the connection is secured
+	return true
+}
+
+func (p *PeerScores) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return !p.Banned(id)
+}
+
+func (p *PeerScores) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
@@ -0,0 +1,62 @@
+package starknet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScores(t *testing.T) {
+	now := time.Unix(0, 0)
+	var banned []peer.ID
+	scores := NewPeerScores().WithBanHandler(func(id peer.ID) {
+		banned = append(banned, id)
+	})
+	scores.now = func() time.Time { return now }
+
+	const id, other = peer.ID("peer"), peer.ID("other")
+
+	t.Run("rate limit", func(t *testing.T) {
+		for i := 0; i < requestBurst; i++ {
+			require.True(t, scores.AllowRequest(id))
+		}
+		assert.False(t, scores.AllowRequest(id))
+		assert.Equal(t, -RateLimitExceeded.penalty(), scores.Score(id))
+		assert.True(t, scores.AllowRequest(other))
+
+		now = now.Add(time.Second / requestsPerSecond)
+		assert.True(t, scores.AllowRequest(id))
+		assert.False(t, scores.AllowRequest(id))
+	})
+
+	t.Run("recovery", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.Zero(t, scores.Score(id))
+	})
+
+	t.Run("ban", func(t *testing.T) {
+		scores.Penalise(id, InvalidData)
+		assert.Equal(t, -InvalidData.penalty(), scores.Score(id))
+		assert.False(t, scores.Banned(id))
+
+		scores.Penalise(id, InvalidData)
+		assert.True(t, scores.Banned(id))
+		assert.Equal(t, []peer.ID{id}, banned)
+		assert.False(t, scores.AllowRequest(id))
+		assert.False(t, scores.InterceptPeerDial(id))
+		assert.False(t, scores.InterceptSecured(0, id, nil))
+		assert.True(t, scores.InterceptPeerDial(other))
+
+		// banned peers are not penalised further
+		scores.Penalise(id, InvalidData)
+		assert.Len(t, banned, 1)
+
+		now = now.Add(banDuration)
+		assert.False(t, scores.Banned(id))
+		assert.Zero(t, scores.Score(id))
+		assert.True(t, scores.AllowRequest(id))
+	})
+}
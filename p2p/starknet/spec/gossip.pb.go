@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: p2p/proto/gossip.proto
+
+package spec
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Not part of the spec. Announces a transaction on the new transactions topic, along with the fields its
+// hash commits to that Transaction lacks.
+type TransactionAnnouncement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transaction *Transaction `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	Hash        *Hash        `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Nonce       *Felt252     `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"` // only set for invoke transactions, whose messages lack their nonces
+}
+
+func (x *TransactionAnnouncement) Reset() {
+	*x = TransactionAnnouncement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_gossip_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionAnnouncement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionAnnouncement) ProtoMessage() {}
+
+func (x *TransactionAnnouncement) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_gossip_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionAnnouncement.ProtoReflect.Descriptor instead.
+func (*TransactionAnnouncement) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_gossip_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TransactionAnnouncement) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *TransactionAnnouncement) GetHash() *Hash {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *TransactionAnnouncement) GetNonce() *Felt252 {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+var File_p2p_proto_gossip_proto protoreflect.FileDescriptor
+
+var file_p2p_proto_gossip_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x73, 0x73,
+	0x69, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1b, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x01,
+	0x0a, 0x17, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6e, 0x6e,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x0b, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x04, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x1e, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_p2p_proto_gossip_proto_rawDescOnce sync.Once
+	file_p2p_proto_gossip_proto_rawDescData = file_p2p_proto_gossip_proto_rawDesc
+)
+
+func file_p2p_proto_gossip_proto_rawDescGZIP() []byte {
+	file_p2p_proto_gossip_proto_rawDescOnce.Do(func() {
+		file_p2p_proto_gossip_proto_rawDescData = protoimpl.X.CompressGZIP(file_p2p_proto_gossip_proto_rawDescData)
+	})
+	return file_p2p_proto_gossip_proto_rawDescData
+}
+
+var file_p2p_proto_gossip_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_p2p_proto_gossip_proto_goTypes = []interface{}{
+	(*TransactionAnnouncement)(nil), // 0: TransactionAnnouncement
+	(*Transaction)(nil),             // 1: Transaction
+	(*Hash)(nil),                    // 2: Hash
+	(*Felt252)(nil),                 // 3: Felt252
+}
+var file_p2p_proto_gossip_proto_depIdxs = []int32{
+	1, // 0: TransactionAnnouncement.transaction:type_name -> Transaction
+	2, // 1: TransactionAnnouncement.hash:type_name -> Hash
+	3, // 2: TransactionAnnouncement.nonce:type_name -> Felt252
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_p2p_proto_gossip_proto_init() }
+func file_p2p_proto_gossip_proto_init() {
+	if File_p2p_proto_gossip_proto != nil {
+		return
+	}
+	file_p2p_proto_common_proto_init()
+	file_p2p_proto_transaction_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_p2p_proto_gossip_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionAnnouncement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_p2p_proto_gossip_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_p2p_proto_gossip_proto_goTypes,
+		DependencyIndexes: file_p2p_proto_gossip_proto_depIdxs,
+		MessageInfos:      file_p2p_proto_gossip_proto_msgTypes,
+	}.Build()
+	File_p2p_proto_gossip_proto = out.File
+	file_p2p_proto_gossip_proto_rawDesc = nil
+	file_p2p_proto_gossip_proto_goTypes = nil
+	file_p2p_proto_gossip_proto_depIdxs = nil
+}
@@ -0,0 +1,589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: p2p/proto/proof.proto
+
+package spec
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Not part of the spec. Proves the state diff of a block against the state roots before and after it, and is
+// sent encoded in BlockProof.
+type StateDiffProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OldContractsRoot *Hash                 `protobuf:"bytes,1,opt,name=old_contracts_root,json=oldContractsRoot,proto3" json:"old_contracts_root,omitempty"`
+	OldClassesRoot   *Hash                 `protobuf:"bytes,2,opt,name=old_classes_root,json=oldClassesRoot,proto3" json:"old_classes_root,omitempty"`
+	NewContractsRoot *Hash                 `protobuf:"bytes,3,opt,name=new_contracts_root,json=newContractsRoot,proto3" json:"new_contracts_root,omitempty"`
+	NewClassesRoot   *Hash                 `protobuf:"bytes,4,opt,name=new_classes_root,json=newClassesRoot,proto3" json:"new_classes_root,omitempty"`
+	Contracts        []*ContractDiffProof  `protobuf:"bytes,5,rep,name=contracts,proto3" json:"contracts,omitempty"`
+	Classes          []*DeclaredClassProof `protobuf:"bytes,6,rep,name=classes,proto3" json:"classes,omitempty"`
+}
+
+func (x *StateDiffProof) Reset() {
+	*x = StateDiffProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_proof_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateDiffProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateDiffProof) ProtoMessage() {}
+
+func (x *StateDiffProof) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_proof_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateDiffProof.ProtoReflect.Descriptor instead.
+func (*StateDiffProof) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_proof_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StateDiffProof) GetOldContractsRoot() *Hash {
+	if x != nil {
+		return x.OldContractsRoot
+	}
+	return nil
+}
+
+func (x *StateDiffProof) GetOldClassesRoot() *Hash {
+	if x != nil {
+		return x.OldClassesRoot
+	}
+	return nil
+}
+
+func (x *StateDiffProof) GetNewContractsRoot() *Hash {
+	if x != nil {
+		return x.NewContractsRoot
+	}
+	return nil
+}
+
+func (x *StateDiffProof) GetNewClassesRoot() *Hash {
+	if x != nil {
+		return x.NewClassesRoot
+	}
+	return nil
+}
+
+func (x *StateDiffProof) GetContracts() []*ContractDiffProof {
+	if x != nil {
+		return x.Contracts
+	}
+	return nil
+}
+
+func (x *StateDiffProof) GetClasses() []*DeclaredClassProof {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+type ContractDiffProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address *Address            `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Old     *ContractLeafProof  `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	New     *ContractLeafProof  `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
+	Storage []*StorageDiffProof `protobuf:"bytes,4,rep,name=storage,proto3" json:"storage,omitempty"`
+}
+
+func (x *ContractDiffProof) Reset() {
+	*x = ContractDiffProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_proof_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContractDiffProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractDiffProof) ProtoMessage() {}
+
+func (x *ContractDiffProof) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_proof_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractDiffProof.ProtoReflect.Descriptor instead.
+func (*ContractDiffProof) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_proof_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ContractDiffProof) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ContractDiffProof) GetOld() *ContractLeafProof {
+	if x != nil {
+		return x.Old
+	}
+	return nil
+}
+
+func (x *ContractDiffProof) GetNew() *ContractLeafProof {
+	if x != nil {
+		return x.New
+	}
+	return nil
+}
+
+func (x *ContractDiffProof) GetStorage() []*StorageDiffProof {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+// Proves the leaf of a contract, or its absence if it has no class hash
+type ContractLeafProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClassHash   *Hash           `protobuf:"bytes,1,opt,name=class_hash,json=classHash,proto3" json:"class_hash,omitempty"`
+	Nonce       *Felt252        `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	StorageRoot *Hash           `protobuf:"bytes,3,opt,name=storage_root,json=storageRoot,proto3" json:"storage_root,omitempty"`
+	Proof       []*PatriciaNode `protobuf:"bytes,4,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *ContractLeafProof) Reset() {
+	*x = ContractLeafProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_proof_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContractLeafProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractLeafProof) ProtoMessage() {}
+
+func (x *ContractLeafProof) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_proof_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractLeafProof.ProtoReflect.Descriptor instead.
+func (*ContractLeafProof) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_proof_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ContractLeafProof) GetClassHash() *Hash {
+	if x != nil {
+		return x.ClassHash
+	}
+	return nil
+}
+
+func (x *ContractLeafProof) GetNonce() *Felt252 {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *ContractLeafProof) GetStorageRoot() *Hash {
+	if x != nil {
+		return x.StorageRoot
+	}
+	return nil
+}
+
+func (x *ContractLeafProof) GetProof() []*PatriciaNode {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type StorageDiffProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      *Felt252        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	OldValue *Felt252        `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	OldProof []*PatriciaNode `protobuf:"bytes,3,rep,name=old_proof,json=oldProof,proto3" json:"old_proof,omitempty"`
+	NewProof []*PatriciaNode `protobuf:"bytes,4,rep,name=new_proof,json=newProof,proto3" json:"new_proof,omitempty"`
+}
+
+func (x *StorageDiffProof) Reset() {
+	*x = StorageDiffProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_proof_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorageDiffProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageDiffProof) ProtoMessage() {}
+
+func (x *StorageDiffProof) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_proof_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageDiffProof.ProtoReflect.Descriptor instead.
+func (*StorageDiffProof) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_proof_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StorageDiffProof) GetKey() *Felt252 {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *StorageDiffProof) GetOldValue() *Felt252 {
+	if x != nil {
+		return x.OldValue
+	}
+	return nil
+}
+
+func (x *StorageDiffProof) GetOldProof() []*PatriciaNode {
+	if x != nil {
+		return x.OldProof
+	}
+	return nil
+}
+
+func (x *StorageDiffProof) GetNewProof() []*PatriciaNode {
+	if x != nil {
+		return x.NewProof
+	}
+	return nil
+}
+
+type DeclaredClassProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClassHash *Hash           `protobuf:"bytes,1,opt,name=class_hash,json=classHash,proto3" json:"class_hash,omitempty"`
+	Proof     []*PatriciaNode `protobuf:"bytes,2,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *DeclaredClassProof) Reset() {
+	*x = DeclaredClassProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_proto_proof_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeclaredClassProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclaredClassProof) ProtoMessage() {}
+
+func (x *DeclaredClassProof) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_proto_proof_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclaredClassProof.ProtoReflect.Descriptor instead.
+func (*DeclaredClassProof) Descriptor() ([]byte, []int) {
+	return file_p2p_proto_proof_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeclaredClassProof) GetClassHash() *Hash {
+	if x != nil {
+		return x.ClassHash
+	}
+	return nil
+}
+
+func (x *DeclaredClassProof) GetProof() []*PatriciaNode {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+var File_p2p_proto_proof_proto protoreflect.FileDescriptor
+
+var file_p2p_proto_proof_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x18, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbd, 0x02, 0x0a, 0x0e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x33, 0x0a, 0x12,
+	0x6f, 0x6c, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x72, 0x6f,
+	0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52,
+	0x10, 0x6f, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x2f, 0x0a, 0x10, 0x6f, 0x6c, 0x64, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61,
+	0x73, 0x68, 0x52, 0x0e, 0x6f, 0x6c, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x6f,
+	0x6f, 0x74, 0x12, 0x33, 0x0a, 0x12, 0x6e, 0x65, 0x77, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x73, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05,
+	0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x10, 0x6e, 0x65, 0x77, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2f, 0x0a, 0x10, 0x6e, 0x65, 0x77, 0x5f, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0e, 0x6e, 0x65, 0x77, 0x43, 0x6c, 0x61,
+	0x73, 0x73, 0x65, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x30, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x44, 0x69, 0x66, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52,
+	0x09, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x44, 0x65,
+	0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x07, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x22, 0xb0, 0x01, 0x0a, 0x11, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x44, 0x69, 0x66, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12,
+	0x22, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x08, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x24, 0x0a, 0x03, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x03, 0x6f, 0x6c, 0x64, 0x12, 0x24, 0x0a, 0x03, 0x6e, 0x65, 0x77,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x74, 0x4c, 0x65, 0x61, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x03, 0x6e, 0x65, 0x77, 0x12,
+	0x2b, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x69, 0x66, 0x66, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x22, 0xa8, 0x01, 0x0a,
+	0x11, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x24, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x09, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1e, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35,
+	0x32, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0c, 0x73, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05,
+	0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x6f,
+	0x6f, 0x74, 0x12, 0x23, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64, 0x65,
+	0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xad, 0x01, 0x0a, 0x10, 0x53, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x44, 0x69, 0x66, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74,
+	0x32, 0x35, 0x32, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65,
+	0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x2a, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x2a, 0x0a, 0x09, 0x6e,
+	0x65, 0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x6e,
+	0x65, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x5f, 0x0a, 0x12, 0x44, 0x65, 0x63, 0x6c, 0x61,
+	0x72, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x24, 0x0a,
+	0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x23, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_p2p_proto_proof_proto_rawDescOnce sync.Once
+	file_p2p_proto_proof_proto_rawDescData = file_p2p_proto_proof_proto_rawDesc
+)
+
+func file_p2p_proto_proof_proto_rawDescGZIP() []byte {
+	file_p2p_proto_proof_proto_rawDescOnce.Do(func() {
+		file_p2p_proto_proof_proto_rawDescData = protoimpl.X.CompressGZIP(file_p2p_proto_proof_proto_rawDescData)
+	})
+	return file_p2p_proto_proof_proto_rawDescData
+}
+
+var file_p2p_proto_proof_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_p2p_proto_proof_proto_goTypes = []interface{}{
+	(*StateDiffProof)(nil),     // 0: StateDiffProof
+	(*ContractDiffProof)(nil),  // 1: ContractDiffProof
+	(*ContractLeafProof)(nil),  // 2: ContractLeafProof
+	(*StorageDiffProof)(nil),   // 3: StorageDiffProof
+	(*DeclaredClassProof)(nil), // 4: DeclaredClassProof
+	(*Hash)(nil),               // 5: Hash
+	(*Address)(nil),            // 6: Address
+	(*Felt252)(nil),            // 7: Felt252
+	(*PatriciaNode)(nil),       // 8: PatriciaNode
+}
+var file_p2p_proto_proof_proto_depIdxs = []int32{
+	5,  // 0: StateDiffProof.old_contracts_root:type_name -> Hash
+	5,  // 1: StateDiffProof.old_classes_root:type_name -> Hash
+	5,  // 2: StateDiffProof.new_contracts_root:type_name -> Hash
+	5,  // 3: StateDiffProof.new_classes_root:type_name -> Hash
+	1,  // 4: StateDiffProof.contracts:type_name -> ContractDiffProof
+	4,  // 5: StateDiffProof.classes:type_name -> DeclaredClassProof
+	6,  // 6: ContractDiffProof.address:type_name -> Address
+	2,  // 7: ContractDiffProof.old:type_name -> ContractLeafProof
+	2,  // 8: ContractDiffProof.new:type_name -> ContractLeafProof
+	3,  // 9: ContractDiffProof.storage:type_name -> StorageDiffProof
+	5,  // 10: ContractLeafProof.class_hash:type_name -> Hash
+	7,  // 11: ContractLeafProof.nonce:type_name -> Felt252
+	5,  // 12: ContractLeafProof.storage_root:type_name -> Hash
+	8,  // 13: ContractLeafProof.proof:type_name -> PatriciaNode
+	7,  // 14: StorageDiffProof.key:type_name -> Felt252
+	7,  // 15: StorageDiffProof.old_value:type_name -> Felt252
+	8,  // 16: StorageDiffProof.old_proof:type_name -> PatriciaNode
+	8,  // 17: StorageDiffProof.new_proof:type_name -> PatriciaNode
+	5,  // 18: DeclaredClassProof.class_hash:type_name -> Hash
+	8,  // 19: DeclaredClassProof.proof:type_name -> PatriciaNode
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_p2p_proto_proof_proto_init() }
+func file_p2p_proto_proof_proto_init() {
+	if File_p2p_proto_proof_proto != nil {
+		return
+	}
+	file_p2p_proto_common_proto_init()
+	file_p2p_proto_snapshot_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_p2p_proto_proof_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateDiffProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_p2p_proto_proof_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContractDiffProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_p2p_proto_proof_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContractLeafProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_p2p_proto_proof_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StorageDiffProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_p2p_proto_proof_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeclaredClassProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_p2p_proto_proof_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_p2p_proto_proof_proto_goTypes,
+		DependencyIndexes: file_p2p_proto_proof_proto_depIdxs,
+		MessageInfos:      file_p2p_proto_proof_proto_msgTypes,
+	}.Build()
+	File_p2p_proto_proof_proto = out.File
+	file_p2p_proto_proof_proto_rawDesc = nil
+	file_p2p_proto_proof_proto_goTypes = nil
+	file_p2p_proto_proof_proto_depIdxs = nil
+}
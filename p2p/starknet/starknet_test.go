@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"sort"
 	"testing"
@@ -270,6 +271,8 @@ func TestClientHandler(t *testing.T) { //nolint:gocyclo
 			stateHistory.EXPECT().ContractClassHash(replacedAddress).Return(replacedClassHash, nil).AnyTimes()
 
 			mockReader.EXPECT().StateAtBlockNumber(block.number).Return(stateHistory, nopCloser, nil)
+			// bodies of blocks that are not archived are sent without proofs
+			mockReader.EXPECT().TriesAtBlockNumber(block.number).Return(nil, nil, core.ErrStateNotArchived)
 		}
 
 		res, cErr := client.RequestBlockBodies(testCtx, &spec.BlockBodiesRequest{
@@ -343,16 +346,6 @@ func TestClientHandler(t *testing.T) { //nolint:gocyclo
 						},
 					},
 				},
-				{
-					Id: &spec.BlockID{
-						Number: b.number,
-					},
-					BodyMessage: &spec.BlockBodiesResponse_Proof{
-						Proof: &spec.BlockProof{
-							Proof: nil,
-						},
-					},
-				},
 				{
 					Id: &spec.BlockID{
 						Number: b.number,
@@ -369,12 +362,7 @@ func TestClientHandler(t *testing.T) { //nolint:gocyclo
 
 		var count int
 		for body, valid := res(); valid; body, valid = res() {
-			if bodyProof, ok := body.BodyMessage.(*spec.BlockBodiesResponse_Proof); ok {
-				// client generates random slice of bytes in proofs for now
-				bodyProof.Proof = nil
-			}
-
-			if count == 0 || count == 4 {
+			if count == 0 || count == 3 {
 				diff := body.BodyMessage.(*spec.BlockBodiesResponse_Diff).Diff.ContractDiffs
 				sortContractDiff(diff)
 
@@ -746,6 +734,52 @@ func TestClientHandler(t *testing.T) { //nolint:gocyclo
 		expectedCount := numOfBlocks + 1 // numOfBlocks messages with blocks + 1 fin message
 		require.Equal(t, expectedCount, count)
 	})
+
+	t.Run("peer scores", func(t *testing.T) {
+		handlerScores, clientScores := starknet.NewPeerScores(), starknet.NewPeerScores()
+		handler.WithPeerScores(handlerScores)
+		client.WithPeerScores(clientScores)
+
+		request := func(t *testing.T, step uint64) int {
+			res, cErr := client.RequestBlockHeaders(testCtx, &spec.BlockHeadersRequest{
+				Iteration: &spec.Iteration{
+					Start:     &spec.Iteration_BlockNumber{BlockNumber: 0},
+					Direction: spec.Iteration_Forward,
+					Limit:     1,
+					Step:      step,
+				},
+			})
+			require.NoError(t, cErr)
+
+			var count int
+			for _, valid := res(); valid; _, valid = res() {
+				count++
+			}
+			return count
+		}
+
+		t.Run("malformed request", func(t *testing.T) {
+			assert.Zero(t, request(t, 0))
+			assert.Negative(t, handlerScores.Score(clientID))
+			assert.Zero(t, clientScores.Score(handlerID))
+		})
+
+		t.Run("invalid response", func(t *testing.T) {
+			mockReader.EXPECT().BlockHeaderByNumber(uint64(0)).Return(fillFelts(t, &core.Header{}), nil)
+			mockReader.EXPECT().BlockCommitmentsByNumber(uint64(0)).Return(fillFelts(t, &core.BlockCommitments{}), nil)
+
+			client.WithVerifier(rejectingVerifier{})
+			t.Cleanup(func() { client.WithVerifier(nil) })
+			assert.Zero(t, request(t, 1))
+			assert.Negative(t, clientScores.Score(handlerID))
+		})
+	})
+}
+
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) VerifyResponse(proto.Message) error {
+	return errors.New("invalid")
 }
 
 func mapToExpectedTransactions(block *core.Block) *spec.Transactions {
@@ -0,0 +1,238 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/adapters/core2sn"
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ service.Service = (*TransactionGossip)(nil)
+
+// seenTransactions is how many transactions are remembered to de-duplicate gossip
+const seenTransactions = 8192
+
+// TransactionForwarder submits the transactions received from peers, usually to the gateway
+type TransactionForwarder interface {
+	AddTransaction(txn json.RawMessage) (json.RawMessage, error)
+}
+
+// TransactionGossip propagates the transactions sent to the node on the new transactions topic of its
+// network. Transactions with invalid hashes are not propagated, and the ones received from peers are handed
+// to a TransactionForwarder once.
+//
+// Only invoke and deploy account transactions of versions 1 and 3 are gossiped. Declare transactions are not,
+// as the gateway only takes them along with their classes, and neither are version 3 transactions with
+// paymaster or account deployment data, which spec.Transaction cannot express.
+type TransactionGossip struct {
+	service   *Service
+	forwarder TransactionForwarder
+	network   utils.Network
+	log       utils.SimpleLogger
+
+	// seen is keyed by the hashes of transactions along with their signatures, which the hashes do not commit
+	// to, so that a copy of a transaction with a bad signature does not keep the valid one from being forwarded
+	seen *lru.Cache[felt.Felt, struct{}]
+	// received holds the hashes of the transactions gossiped by the node and of the ones it forwarded
+	received *lru.Cache[felt.Felt, struct{}]
+}
+
+func NewTransactionGossip(p2pService *Service, network utils.Network, log utils.SimpleLogger) *TransactionGossip {
+	return &TransactionGossip{
+		service:  p2pService,
+		network:  network,
+		log:      log,
+		seen:     lru.NewCache[felt.Felt, struct{}](seenTransactions),
+		received: lru.NewCache[felt.Felt, struct{}](seenTransactions),
+	}
+}
+
+// WithForwarder makes the node forward the transactions it receives from peers
+func (g *TransactionGossip) WithForwarder(forwarder TransactionForwarder) *TransactionGossip {
+	g.forwarder = forwarder
+	return g
+}
+
+func (g *TransactionGossip) Run(ctx context.Context) error {
+	topic := starknet.NewTransactionsTopic(g.network)
+	if err := g.service.RegisterTopicValidator(topic, g.validate); err != nil {
+		return err
+	}
+
+	announcements, unsubscribe, err := g.service.SubscribeToTopic(topic)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data, ok := <-announcements:
+			if !ok {
+				return nil
+			}
+			txn, decodeErr := g.decodeTransaction(data)
+			if decodeErr != nil {
+				// validated messages always decode
+				g.log.Warnw("Failed decoding transaction announcement", "err", decodeErr)
+				continue
+			}
+			g.receive(txn)
+		}
+	}
+}
+
+func (g *TransactionGossip) receive(txn core.Transaction) {
+	key := seenKey(txn)
+	if g.seen.Contains(*key) {
+		return
+	}
+	g.seen.Add(*key, struct{}{})
+
+	g.log.Debugw("Received transaction", "hash", txn.Hash().ShortString())
+	if g.forwarder == nil {
+		g.received.Add(*txn.Hash(), struct{}{})
+		return
+	}
+	if err := g.forward(txn); err != nil {
+		g.log.Debugw("Failed forwarding transaction", "hash", txn.Hash().ShortString(), "err", err)
+		return
+	}
+	g.received.Add(*txn.Hash(), struct{}{})
+}
+
+// seenKey identifies a transaction by its hash and signature
+func seenKey(txn core.Transaction) *felt.Felt {
+	return crypto.PoseidonArray(append([]*felt.Felt{txn.Hash()}, txn.Signature()...)...)
+}
+
+// GossipTransaction announces a transaction sent to the node to its peers
+func (g *TransactionGossip) GossipTransaction(txn core.Transaction) error {
+	data, err := encodeTransaction(txn)
+	if err != nil {
+		return err
+	}
+
+	// the announcement may come back from peers, and the gateway has it already
+	g.seen.Add(*seenKey(txn), struct{}{})
+	g.received.Add(*txn.Hash(), struct{}{})
+	return g.service.PublishOnTopic(starknet.NewTransactionsTopic(g.network), data)
+}
+
+func encodeTransaction(txn core.Transaction) ([]byte, error) {
+	if txn.TxVersion().HasQueryBit() {
+		return nil, errors.New("query transactions are not gossiped")
+	}
+
+	announcement := &spec.TransactionAnnouncement{Hash: core2p2p.AdaptHash(txn.Hash())}
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		if !t.Version.Is(1) && !t.Version.Is(3) {
+			return nil, fmt.Errorf("invoke transactions of version %s are not gossiped", t.Version)
+		}
+		if len(t.PaymasterData) > 0 || len(t.AccountDeploymentData) > 0 {
+			return nil, errors.New("transactions with paymaster or account deployment data are not gossiped")
+		}
+		announcement.Nonce = core2p2p.AdaptFelt(t.Nonce)
+	case *core.DeployAccountTransaction:
+		if !t.Version.Is(1) && !t.Version.Is(3) {
+			return nil, fmt.Errorf("deploy account transactions of version %s are not gossiped", t.Version)
+		}
+		if len(t.PaymasterData) > 0 {
+			return nil, errors.New("transactions with paymaster data are not gossiped")
+		}
+	default:
+		return nil, fmt.Errorf("%T is not gossiped", txn)
+	}
+
+	announcement.Transaction = core2p2p.AdaptTransaction(txn)
+	return proto.Marshal(announcement)
+}
+
+// Received returns whether the transaction with the given hash was gossiped recently, by the node or its peers
+func (g *TransactionGossip) Received(hash *felt.Felt) bool {
+	return g.received.Contains(*hash)
+}
+
+// validate accepts the announcements of transactions whose hashes match their contents
+func (g *TransactionGossip) validate(data []byte) bool {
+	if _, err := g.decodeTransaction(data); err != nil {
+		g.log.Debugw("Rejected transaction announcement", "err", err)
+		return false
+	}
+	return true
+}
+
+// decodeTransaction returns the announced transaction, with its hash verified
+func (g *TransactionGossip) decodeTransaction(data []byte) (core.Transaction, error) {
+	var announcement spec.TransactionAnnouncement
+	if err := proto.Unmarshal(data, &announcement); err != nil {
+		return nil, err
+	}
+	hash := p2p2core.AdaptHash(announcement.Hash)
+	if hash == nil {
+		return nil, errors.New("transaction announcement without hash")
+	}
+
+	txn, err := p2p2core.AdaptTransaction(announcement.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		if announcement.Nonce == nil {
+			return nil, errors.New("invoke transaction announcement without nonce")
+		}
+		t.Nonce = p2p2core.AdaptFelt(announcement.Nonce)
+		t.TransactionHash = hash
+	case *core.DeployAccountTransaction:
+		t.TransactionHash = hash
+	default:
+		return nil, fmt.Errorf("%T is not gossiped", txn)
+	}
+	if !txn.TxVersion().Is(1) && !txn.TxVersion().Is(3) {
+		return nil, fmt.Errorf("transactions of version %s are not gossiped", txn.TxVersion())
+	}
+
+	computed, err := core.TransactionHash(txn, g.network)
+	if err != nil {
+		return nil, err
+	}
+	if !computed.Equal(hash) {
+		return nil, fmt.Errorf("transaction hash %s does not match the announced hash %s", computed, hash)
+	}
+	return txn, nil
+}
+
+// forward submits a transaction received from peers the way the RPC server submits the ones sent to it
+func (g *TransactionGossip) forward(txn core.Transaction) error {
+	snTxn, err := core2sn.AdaptTransaction(txn)
+	if err != nil {
+		return err
+	}
+	// transactions are submitted without their hashes
+	snTxn.Hash = nil
+
+	txnJSON, err := json.Marshal(snTxn)
+	if err != nil {
+		return err
+	}
+	_, err = g.forwarder.AddTransaction(txnJSON)
+	return err
+}
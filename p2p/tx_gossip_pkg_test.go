@@ -0,0 +1,127 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/adapters/core2sn"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/p2p/starknet/spec"
+	"github.com/NethermindEth/juno/starknet"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type recordingForwarder struct {
+	txns []json.RawMessage
+}
+
+func (f *recordingForwarder) AddTransaction(txn json.RawMessage) (json.RawMessage, error) {
+	f.txns = append(f.txns, txn)
+	return nil, nil
+}
+
+func TestTransactionAnnouncement(t *testing.T) {
+	forwarder := new(recordingForwarder)
+	gossips := map[utils.Network]*TransactionGossip{}
+	gateways := map[utils.Network]*adaptfeeder.Feeder{}
+	for _, network := range []utils.Network{utils.Mainnet, utils.Integration} {
+		gossips[network] = NewTransactionGossip(nil, network, utils.NewNopZapLogger()).WithForwarder(forwarder)
+		gateways[network] = adaptfeeder.New(feeder.NewTestClient(t, network))
+	}
+
+	type networkTxn struct {
+		network utils.Network
+		hash    string
+	}
+	transaction := func(t *testing.T, txn networkTxn) core.Transaction {
+		coreTxn, err := gateways[txn.network].Transaction(context.Background(), utils.HexToFelt(t, txn.hash))
+		require.NoError(t, err)
+		return coreTxn
+	}
+
+	gossiped := map[string]networkTxn{
+		"invoke v1":         {utils.Mainnet, "0x2897e3cec3e24e4d341df26b8cf1ab84ea1c01a051021836b36c6639145b497"},
+		"deploy account v1": {utils.Mainnet, "0x32b272b6d0d584305a460197aa849b5c7a9a85903b66e9d3e1afa2427ef093e"},
+		"invoke v3":         {utils.Integration, "0x49728601e0bb2f48ce506b0cbd9c0e2a9e50d95858aa41463f46386dca489fd"},
+		"deploy account v3": {utils.Integration, "0x29fd7881f14380842414cdfdd8d6c0b1f2174f8916edcfeb1ede1eb26ac3ef0"},
+	}
+	for name, gossipedTxn := range gossiped {
+		t.Run(name, func(t *testing.T) {
+			gossip := gossips[gossipedTxn.network]
+			txn := transaction(t, gossipedTxn)
+			data, err := encodeTransaction(txn)
+			require.NoError(t, err)
+			require.True(t, gossip.validate(data))
+
+			decoded, err := gossip.decodeTransaction(data)
+			require.NoError(t, err)
+			assert.Equal(t, txn.Hash(), decoded.Hash())
+
+			// peers submit transactions the way the RPC server does, without their hashes
+			forwarder.txns = nil
+			require.NoError(t, gossip.forward(decoded))
+			require.Len(t, forwarder.txns, 1)
+			var forwarded, expected starknet.Transaction
+			require.NoError(t, json.Unmarshal(forwarder.txns[0], &forwarded))
+			snTxn, err := core2sn.AdaptTransaction(txn)
+			require.NoError(t, err)
+			expected = *snTxn
+			expected.Hash = nil
+			assert.Equal(t, expected, forwarded)
+		})
+	}
+
+	notGossiped := map[string]networkTxn{
+		"declare":    {utils.Mainnet, "0x1b4d9f09276629d496af1af8ff00173c11ff146affacb1b5c858d7aa89001ae"},
+		"declare v3": {utils.Integration, "0x41d1f5206ef58a443e7d3d1ca073171ec25fa75313394318fc83a074a6631c3"},
+		"invoke v0":  {utils.Mainnet, "0x631333277e88053336d8c302630b4420dc3ff24018a1c464da37d5e36ea19df"},
+	}
+	for name, txn := range notGossiped {
+		t.Run(name, func(t *testing.T) {
+			_, err := encodeTransaction(transaction(t, txn))
+			assert.ErrorContains(t, err, "not gossiped")
+		})
+	}
+
+	gossip := gossips[utils.Mainnet]
+	t.Run("tampered transaction", func(t *testing.T) {
+		txn := transaction(t, gossiped["invoke v1"]).(*core.InvokeTransaction)
+		txn.Nonce = new(felt.Felt).Add(txn.Nonce, new(felt.Felt).SetUint64(1))
+		data, err := encodeTransaction(txn)
+		require.NoError(t, err)
+		assert.False(t, gossip.validate(data))
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		txn := transaction(t, gossiped["deploy account v1"]).(*core.DeployAccountTransaction)
+		txn.ClassHash = nil
+		data, err := encodeTransaction(txn)
+		require.NoError(t, err)
+		assert.False(t, gossip.validate(data))
+	})
+
+	t.Run("bad signature does not shadow the transaction", func(t *testing.T) {
+		txn := transaction(t, gossiped["invoke v1"]).(*core.InvokeTransaction)
+		forged := *txn
+		forged.TransactionSignature = []*felt.Felt{new(felt.Felt).SetUint64(1)}
+
+		forwarder.txns = nil
+		gossip.receive(&forged)
+		gossip.receive(txn)
+		gossip.receive(txn)
+		assert.Len(t, forwarder.txns, 2)
+		assert.True(t, gossip.Received(txn.Hash()))
+	})
+
+	assert.False(t, gossip.validate([]byte("not an announcement")))
+	invalid, err := proto.Marshal(&spec.TransactionAnnouncement{Hash: &spec.Hash{Elements: []byte{1}}})
+	require.NoError(t, err)
+	assert.False(t, gossip.validate(invalid))
+}
@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"github.com/NethermindEth/juno/abi"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+type DecodedEmittedEvent struct {
+	*EmittedEvent
+	Decoded *abi.Event `json:"decoded,omitempty"`
+}
+
+type DecodedEventsChunk struct {
+	Events            []*DecodedEmittedEvent `json:"events"`
+	ContinuationToken string                 `json:"continuation_token,omitempty"`
+}
+
+// DecodedCall is a call made by a transaction. Function and Arguments are set if the ABI of the called
+// contract's class declares the entry point.
+type DecodedCall struct {
+	ContractAddress    *felt.Felt   `json:"contract_address"`
+	EntryPointSelector *felt.Felt   `json:"entry_point_selector"`
+	Calldata           []*felt.Felt `json:"calldata"`
+	Function           string       `json:"function,omitempty"`
+	Arguments          abi.Struct   `json:"arguments,omitempty"`
+}
+
+type DecodedTransaction struct {
+	*Transaction
+	Calls []DecodedCall `json:"decoded_calls,omitempty"`
+}
+
+type DecodedCallResult struct {
+	Result  []*felt.Felt `json:"result"`
+	Decoded any          `json:"decoded,omitempty"`
+}
+
+// transactionCalls returns the calls made by invoke and L1 handler transactions. The calldata of accounts
+// is split into the calls of the multicall, if it follows one of the known layouts.
+func transactionCalls(txn core.Transaction) []abi.AccountCall {
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		if t.Version.Is(0) {
+			return []abi.AccountCall{{To: t.ContractAddress, Selector: t.EntryPointSelector, Calldata: t.CallData}}
+		}
+
+		calls, err := abi.DecodeMulticall(t.CallData)
+		if err != nil {
+			return []abi.AccountCall{{To: t.SenderAddress, Selector: abi.Selector("__execute__"), Calldata: t.CallData}}
+		}
+		return calls
+	case *core.L1HandlerTransaction:
+		return []abi.AccountCall{{To: t.ContractAddress, Selector: t.EntryPointSelector, Calldata: t.CallData}}
+	default:
+		return nil
+	}
+}
+
+// abiResolver finds the ABIs of the classes of contracts at a given block. It keeps the state of the last
+// block it was asked about open and caches the parsed ABIs by class hash.
+type abiResolver struct {
+	handler *Handler
+
+	id     *BlockID
+	state  core.StateReader
+	closer blockchain.StateCloser
+
+	abis map[felt.Felt]*abi.ABI
+}
+
+func newABIResolver(h *Handler) *abiResolver {
+	return &abiResolver{
+		handler: h,
+		abis:    make(map[felt.Felt]*abi.ABI),
+	}
+}
+
+// contractABI returns the ABI of the class of the contract at the given address as of the given block
+func (r *abiResolver) contractABI(id *BlockID, address *felt.Felt) (*abi.ABI, error) {
+	if r.id == nil || *r.id != *id {
+		if err := r.close(); err != nil {
+			return nil, err
+		}
+
+		state, closer, err := r.handler.stateByBlockID(id)
+		if err != nil {
+			return nil, err
+		}
+		r.id, r.state, r.closer = id, state, closer
+	}
+
+	classHash, err := r.state.ContractClassHash(address)
+	if err != nil {
+		return nil, err
+	}
+	if classABI, ok := r.abis[*classHash]; ok {
+		return classABI, nil
+	}
+
+	class, err := r.state.Class(classHash)
+	if err != nil {
+		return nil, err
+	}
+	classABI, err := abi.New(class.Class)
+	if err != nil {
+		return nil, err
+	}
+	r.abis[*classHash] = classABI
+	return classABI, nil
+}
+
+func (r *abiResolver) close() error {
+	if r.closer == nil {
+		return nil
+	}
+	closer := r.closer
+	r.id, r.state, r.closer = nil, nil, nil
+	return closer()
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
 )
 
 // https://github.com/starkware-libs/starknet-specs/blob/fbf8710c2d2dcdb70a95776f257d080392ad0816/api/starknet_api_openrpc.json#L2353-L2363
@@ -42,8 +44,10 @@ type BlockHashAndNumber struct {
 type BlockID struct {
 	Pending bool
 	Latest  bool
-	Hash    *felt.Felt
-	Number  uint64
+	// L1Accepted refers to the latest block accepted on L1
+	L1Accepted bool
+	Hash       *felt.Felt
+	Number     uint64
 }
 
 func (b *BlockID) UnmarshalJSON(data []byte) error {
@@ -51,6 +55,8 @@ func (b *BlockID) UnmarshalJSON(data []byte) error {
 		b.Latest = true
 	} else if string(data) == `"pending"` {
 		b.Pending = true
+	} else if string(data) == `"l1_accepted"` {
+		b.L1Accepted = true
 	} else {
 		jsonObject := make(map[string]json.RawMessage)
 		if err := json.Unmarshal(data, &jsonObject); err != nil {
@@ -103,3 +109,77 @@ type BlockWithTxHashes struct {
 	BlockHeader
 	TxnHashes []*felt.Felt `json:"transactions"`
 }
+
+// CommitmentProof is the Merkle proof that a transaction or event is included in the commitment of a block
+type CommitmentProof struct {
+	BlockHash   *felt.Felt `json:"block_hash"`
+	BlockNumber uint64     `json:"block_number"`
+	// Commitment is the root of the transaction or event commitment trie
+	Commitment *felt.Felt `json:"commitment"`
+	// Index of the leaf in the commitment trie. Events are indexed by their position in the block.
+	Index uint64      `json:"index"`
+	Leaf  *felt.Felt  `json:"leaf"`
+	Proof []ProofNode `json:"proof"`
+}
+
+// ProofNode is a node on the path from the root of a commitment trie to a leaf, either binary or an edge
+type ProofNode struct {
+	Binary *BinaryNode `json:"binary,omitempty"`
+	Edge   *EdgeNode   `json:"edge,omitempty"`
+}
+
+type BinaryNode struct {
+	Left  *felt.Felt `json:"left"`
+	Right *felt.Felt `json:"right"`
+}
+
+type EdgeNode struct {
+	Child  *felt.Felt `json:"child"`
+	Path   *felt.Felt `json:"path"`
+	Length uint8      `json:"length"`
+}
+
+// StorageProof is the Merkle proof of a contract against the contracts root of a block, and of values in its
+// storage against its storage root. The leaf of the contract commits to its class hash, storage root and nonce.
+type StorageProof struct {
+	BlockHash     *felt.Felt          `json:"block_hash"`
+	BlockNumber   uint64              `json:"block_number"`
+	ContractsRoot *felt.Felt          `json:"contracts_root"`
+	ClassesRoot   *felt.Felt          `json:"classes_root"`
+	ClassHash     *felt.Felt          `json:"class_hash"`
+	Nonce         *felt.Felt          `json:"nonce"`
+	StorageRoot   *felt.Felt          `json:"storage_root"`
+	ContractProof []ProofNode         `json:"contract_proof"`
+	Storage       []StorageValueProof `json:"storage"`
+}
+
+// StorageValueProof is the Merkle proof of the value of a storage location against the storage root of its contract
+type StorageValueProof struct {
+	Key   *felt.Felt  `json:"key"`
+	Value *felt.Felt  `json:"value"`
+	Proof []ProofNode `json:"proof"`
+}
+
+func adaptCommitmentProof(header *core.Header, commitment *felt.Felt, proof *core.CommitmentProof) *CommitmentProof {
+	return &CommitmentProof{
+		BlockHash:   header.Hash,
+		BlockNumber: header.Number,
+		Commitment:  commitment,
+		Index:       proof.Index,
+		Leaf:        proof.Leaf,
+		Proof:       adaptProofNodes(proof.Nodes),
+	}
+}
+
+func adaptProofNodes(proof []trie.ProofNode) []ProofNode {
+	nodes := make([]ProofNode, 0, len(proof))
+	for _, node := range proof {
+		if node.Binary != nil {
+			nodes = append(nodes, ProofNode{Binary: &BinaryNode{Left: node.Binary.LeftHash, Right: node.Binary.RightHash}})
+			continue
+		}
+		path := node.Edge.Path.Felt()
+		nodes = append(nodes, ProofNode{Edge: &EdgeNode{Child: node.Edge.Child, Path: &path, Length: node.Edge.Path.Len()}})
+	}
+	return nodes
+}
@@ -27,6 +27,12 @@ func TestBlockId(t *testing.T) {
 				Pending: true,
 			},
 		},
+		"l1_accepted": {
+			blockIDJSON: `"l1_accepted"`,
+			expectedBlockID: rpc.BlockID{
+				L1Accepted: true,
+			},
+		},
 		"number": {
 			blockIDJSON: `{ "block_number" : 123123 }`,
 			expectedBlockID: rpc.BlockID{
@@ -32,6 +32,16 @@ type EntryPoint struct {
 	Selector *felt.Felt `json:"selector"`
 }
 
+type ContractAddressesChunk struct {
+	ContractAddresses []*felt.Felt `json:"contract_addresses"`
+	ContinuationToken string       `json:"continuation_token,omitempty"`
+}
+
+type ClassHashesChunk struct {
+	ClassHashes       []*felt.Felt `json:"class_hashes"`
+	ContinuationToken string       `json:"continuation_token,omitempty"`
+}
+
 // https://github.com/starkware-libs/starknet-specs/blob/v0.3.0/api/starknet_api_openrpc.json#L2344
 type FunctionCall struct {
 	ContractAddress    felt.Felt   `json:"contract_address"`
@@ -29,4 +29,6 @@ type EmittedEvent struct {
 	BlockNumber     *uint64    `json:"block_number,omitempty"`
 	BlockHash       *felt.Felt `json:"block_hash,omitempty"`
 	TransactionHash *felt.Felt `json:"transaction_hash"`
+	// FinalityStatus tells whether the block the event was emitted in is accepted on L1
+	FinalityStatus TxnFinalityStatus `json:"finality_status,omitempty"`
 }
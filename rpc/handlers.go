@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"math"
 	"slices"
+	"strconv"
+	"strings"
 	stdsync "sync"
 
 	"github.com/Masterminds/semver/v3"
@@ -34,6 +36,13 @@ type Gateway interface {
 	AddTransaction(json.RawMessage) (json.RawMessage, error)
 }
 
+// TransactionGossip propagates the transactions sent to the node to its peers
+type TransactionGossip interface {
+	GossipTransaction(txn core.Transaction) error
+	// Received returns whether the transaction with the given hash was gossiped recently
+	Received(hash *felt.Felt) bool
+}
+
 var (
 	ErrContractNotFound                = &jsonrpc.Error{Code: 20, Message: "Contract not found"}
 	ErrBlockNotFound                   = &jsonrpc.Error{Code: 24, Message: "Block not found"}
@@ -66,12 +75,18 @@ var (
 
 	// These errors can be only be returned by Juno-specific methods.
 	ErrSubscriptionNotFound = &jsonrpc.Error{Code: 100, Message: "Subscription not found"}
+	ErrInvalidEventIndex    = &jsonrpc.Error{Code: 101, Message: "Invalid event index in a transaction"}
+	ErrStateNotArchived     = &jsonrpc.Error{Code: 102, Message: "State of the block is not archived"}
 )
 
 const (
-	maxEventChunkSize  = 10240
-	maxEventFilterKeys = 1024
-	traceCacheSize     = 128
+	maxEventChunkSize              = 10240
+	maxEventFilterKeys             = 1024
+	maxAccountTransactionChunkSize = 1024
+	maxValueChangesChunkSize       = 1024
+	maxRangeChunkSize              = 1024
+	maxStorageProofKeys            = 1024
+	traceCacheSize                 = 128
 )
 
 type traceCacheKey struct {
@@ -84,6 +99,7 @@ type Handler struct {
 	syncReader    sync.Reader
 	network       utils.Network
 	gatewayClient Gateway
+	txGossip      TransactionGossip
 	feederClient  *feeder.Client
 	vm            vm.VM
 	log           utils.Logger
@@ -98,6 +114,8 @@ type Handler struct {
 	blockTraceCache *lru.Cache[traceCacheKey, []TracedBlockTransaction]
 
 	filterLimit uint
+
+	checkCompiledClassHash bool
 }
 
 type subscription struct {
@@ -138,6 +156,20 @@ func (h *Handler) WithFilterLimit(limit uint) *Handler {
 	return h
 }
 
+// WithTransactionGossip makes the handler gossip the transactions it submits, and report the ones gossiped by
+// peers as received
+func (h *Handler) WithTransactionGossip(txGossip TransactionGossip) *Handler {
+	h.txGossip = txGossip
+	return h
+}
+
+// WithCompiledClassHashCheck makes the handler compile the classes of declare transactions and reject the ones
+// whose compiled class hash does not match, instead of leaving the check to the gateway
+func (h *Handler) WithCompiledClassHashCheck() *Handler {
+	h.checkCompiledClassHash = true
+	return h
+}
+
 func (h *Handler) WithIDGen(idgen func() uint64) *Handler {
 	h.idgen = idgen
 	return h
@@ -241,6 +273,15 @@ func (h *Handler) l1Head() (*core.L1Head, *jsonrpc.Error) {
 	return l1Head, nil
 }
 
+// l1AcceptedBlockNumber returns the number of the latest block accepted on L1, or db.ErrKeyNotFound if there is none
+func (h *Handler) l1AcceptedBlockNumber() (uint64, error) {
+	l1Head, err := h.bcReader.L1Head()
+	if err != nil {
+		return 0, err
+	}
+	return l1Head.BlockNumber, nil
+}
+
 func isL1Verified(n uint64, l1 *core.L1Head) bool {
 	if l1 != nil && l1.BlockNumber >= n {
 		return true
@@ -454,6 +495,11 @@ func (h *Handler) blockByID(id *BlockID) (*core.Block, *jsonrpc.Error) {
 	switch {
 	case id.Latest:
 		block, err = h.bcReader.Head()
+	case id.L1Accepted:
+		var number uint64
+		if number, err = h.l1AcceptedBlockNumber(); err == nil {
+			block, err = h.bcReader.BlockByNumber(number)
+		}
 	case id.Hash != nil:
 		block, err = h.bcReader.BlockByHash(id.Hash)
 	case id.Pending:
@@ -480,6 +526,12 @@ func (h *Handler) blockHeaderByID(id *BlockID) (*core.Header, error) {
 	switch {
 	case id.Latest:
 		return h.bcReader.HeadsHeader()
+	case id.L1Accepted:
+		number, err := h.l1AcceptedBlockNumber()
+		if err != nil {
+			return nil, err
+		}
+		return h.bcReader.BlockHeaderByNumber(number)
 	case id.Hash != nil:
 		return h.bcReader.BlockHeaderByHash(id.Hash)
 	case id.Pending:
@@ -517,6 +569,101 @@ func (h *Handler) LegacyTransactionByHash(hash felt.Felt) (*Transaction, *jsonrp
 	return txn, nil
 }
 
+// DecodedTransactionByHash returns the transaction with the given hash along with the calls it makes. The
+// calldata of each call is decoded using the ABI of the called contract's class at the block the
+// transaction is included in.
+func (h *Handler) DecodedTransactionByHash(hash felt.Felt) (*DecodedTransaction, *jsonrpc.Error) {
+	txn, err := h.bcReader.TransactionByHash(&hash)
+	if err != nil {
+		return nil, ErrTxnHashNotFound
+	}
+
+	_, blockHash, blockNumber, err := h.bcReader.Receipt(&hash)
+	if err != nil {
+		return nil, ErrTxnHashNotFound
+	}
+	id := BlockID{Pending: true}
+	if blockHash != nil {
+		id = BlockID{Number: blockNumber}
+	}
+
+	abis := newABIResolver(h)
+	defer h.callAndLogErr(abis.close, "Failed to close state in juno_getDecodedTransactionByHash")
+
+	calls := transactionCalls(txn)
+	decodedCalls := make([]DecodedCall, 0, len(calls))
+	for _, call := range calls {
+		decodedCall := DecodedCall{
+			ContractAddress:    call.To,
+			EntryPointSelector: call.Selector,
+			Calldata:           call.Calldata,
+		}
+		if contractABI, abiErr := abis.contractABI(&id, call.To); abiErr == nil {
+			if decoded, decodeErr := contractABI.DecodeCall(call.Selector, call.Calldata); decodeErr == nil {
+				decodedCall.Function, decodedCall.Arguments = decoded.Function, decoded.Arguments
+			}
+		}
+		decodedCalls = append(decodedCalls, decodedCall)
+	}
+	return &DecodedTransaction{Transaction: AdaptTransaction(txn), Calls: decodedCalls}, nil
+}
+
+// TransactionsByAddress returns the transactions sent by, or deploying, the given address in the order
+// they were included in the chain. Results are paginated with a continuation token.
+func (h *Handler) TransactionsByAddress(address felt.Felt, chunkSize uint64,
+	continuationToken string,
+) (*AccountTransactionsChunk, *jsonrpc.Error) {
+	if chunkSize == 0 {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "chunk_size must be positive")
+	} else if chunkSize > maxAccountTransactionChunkSize {
+		return nil, ErrPageSizeTooBig
+	}
+
+	var cToken *blockchain.AccountTransactionsToken
+	if continuationToken != "" {
+		cToken = new(blockchain.AccountTransactionsToken)
+		if err := cToken.FromString(continuationToken); err != nil {
+			return nil, ErrInvalidContinuationToken
+		}
+	}
+
+	txs, cToken, err := h.bcReader.TransactionsByAddress(&address, cToken, chunkSize)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	chunk := &AccountTransactionsChunk{
+		Transactions: make([]*AccountTransaction, 0, len(txs)),
+	}
+	for _, tx := range txs {
+		chunk.Transactions = append(chunk.Transactions, adaptAccountTransaction(tx))
+	}
+	if cToken != nil {
+		chunk.ContinuationToken = cToken.String()
+	}
+	return chunk, nil
+}
+
+// TransactionBySenderAndNonce returns the transaction sent by the given address with the given nonce
+func (h *Handler) TransactionBySenderAndNonce(sender, nonce felt.Felt) (*AccountTransaction, *jsonrpc.Error) {
+	tx, err := h.bcReader.TransactionBySenderAndNonce(&sender, &nonce)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrTxnHashNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	return adaptAccountTransaction(tx), nil
+}
+
+func adaptAccountTransaction(tx *blockchain.AccountTransaction) *AccountTransaction {
+	return &AccountTransaction{
+		BlockNumber:      tx.BlockNumber,
+		TransactionIndex: tx.Index,
+		Transaction:      AdaptTransaction(tx.Transaction),
+	}
+}
+
 // BlockTransactionCount returns the number of transactions in a block
 // identified by the given BlockID.
 //
@@ -720,6 +867,11 @@ func (h *Handler) StateUpdate(id BlockID) (*StateUpdate, *jsonrpc.Error) {
 		if err == nil {
 			update = pending.StateUpdate
 		}
+	} else if id.L1Accepted {
+		var number uint64
+		if number, err = h.l1AcceptedBlockNumber(); err == nil {
+			update, err = h.bcReader.StateUpdateByNumber(number)
+		}
 	} else if id.Hash != nil {
 		update, err = h.bcReader.StateUpdateByHash(id.Hash)
 	} else {
@@ -833,6 +985,12 @@ func (h *Handler) stateByBlockID(id *BlockID) (core.StateReader, blockchain.Stat
 	switch {
 	case id.Latest:
 		return h.bcReader.HeadState()
+	case id.L1Accepted:
+		number, err := h.l1AcceptedBlockNumber()
+		if err != nil {
+			return nil, nil, err
+		}
+		return h.bcReader.StateAtBlockNumber(number)
 	case id.Hash != nil:
 		return h.bcReader.StateAtBlockHash(id.Hash)
 	case id.Pending:
@@ -899,6 +1057,439 @@ func (h *Handler) ClassHashAt(id BlockID, address felt.Felt) (*felt.Felt, *jsonr
 	return classHash, nil
 }
 
+// StorageHistory returns the changes to a storage location of the given contract between two blocks
+// (inclusive) along with the value before and after each change. Results are paginated with a
+// continuation token.
+func (h *Handler) StorageHistory(address, key felt.Felt, from, to BlockID, chunkSize uint64,
+	continuationToken string,
+) (*ValueChangesChunk, *jsonrpc.Error) {
+	return h.valueChanges(&from, &to, chunkSize, continuationToken, func(from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+		return h.bcReader.ContractStorageChanges(&address, &key, from, to, limit)
+	})
+}
+
+// NonceHistory returns the changes to the nonce of the given contract between two blocks (inclusive)
+// along with the value before and after each change. Results are paginated with a continuation token.
+func (h *Handler) NonceHistory(address felt.Felt, from, to BlockID, chunkSize uint64,
+	continuationToken string,
+) (*ValueChangesChunk, *jsonrpc.Error) {
+	return h.valueChanges(&from, &to, chunkSize, continuationToken, func(from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+		return h.bcReader.ContractNonceChanges(&address, from, to, limit)
+	})
+}
+
+// ClassHashHistory returns the deployment and upgrades of the given contract between two blocks
+// (inclusive) along with the class hash before and after each change. Results are paginated with a
+// continuation token.
+func (h *Handler) ClassHashHistory(address felt.Felt, from, to BlockID, chunkSize uint64,
+	continuationToken string,
+) (*ValueChangesChunk, *jsonrpc.Error) {
+	return h.valueChanges(&from, &to, chunkSize, continuationToken, func(from, to, limit uint64) ([]core.ValueChange, *uint64, error) {
+		return h.bcReader.ContractClassHashChanges(&address, from, to, limit)
+	})
+}
+
+func (h *Handler) valueChanges(fromID, toID *BlockID, chunkSize uint64, continuationToken string,
+	changes func(from, to, limit uint64) ([]core.ValueChange, *uint64, error),
+) (*ValueChangesChunk, *jsonrpc.Error) {
+	if chunkSize == 0 {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "chunk_size must be positive")
+	} else if chunkSize > maxValueChangesChunkSize {
+		return nil, ErrPageSizeTooBig
+	}
+
+	from, rpcErr := h.committedBlockNumber(fromID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	to, rpcErr := h.committedBlockNumber(toID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// changes already returned from the block the page starts at
+	var skip uint64
+	if continuationToken != "" {
+		next, index, ok := parseValueChangesToken(continuationToken)
+		if !ok || next < from || index > maxValueChangesChunkSize {
+			return nil, ErrInvalidContinuationToken
+		}
+		from, skip = next, index
+	}
+
+	values, next, err := changes(from, to, chunkSize+skip)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	var skipped uint64
+	for skipped < skip && len(values) > 0 && values[0].BlockNumber == from {
+		values, skipped = values[1:], skipped+1
+	}
+	if uint64(len(values)) > chunkSize {
+		next, values = &values[chunkSize].BlockNumber, values[:chunkSize]
+	}
+
+	chunk := &ValueChangesChunk{
+		Changes: make([]*ValueChange, 0, len(values)),
+	}
+	for _, value := range values {
+		chunk.Changes = append(chunk.Changes, &ValueChange{
+			BlockNumber: value.BlockNumber,
+			OldValue:    value.OldValue,
+			NewValue:    value.NewValue,
+		})
+	}
+	if next != nil {
+		// a block can have more than one change, such as a contract deployed and upgraded in it
+		var index uint64
+		if *next == from {
+			index = skipped
+		}
+		for _, value := range values {
+			if value.BlockNumber == *next {
+				index++
+			}
+		}
+		chunk.ContinuationToken = strconv.FormatUint(*next, 10)
+		if index > 0 {
+			chunk.ContinuationToken += ":" + strconv.FormatUint(index, 10)
+		}
+	}
+	return chunk, nil
+}
+
+// parseValueChangesToken parses a continuation token of value changes, which is the number of the block to
+// continue from, followed by how many of its changes were returned if any were
+func parseValueChangesToken(token string) (blockNumber, index uint64, ok bool) {
+	blockToken, indexToken, hasIndex := strings.Cut(token, ":")
+	blockNumber, err := strconv.ParseUint(blockToken, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if hasIndex {
+		if index, err = strconv.ParseUint(indexToken, 10, 64); err != nil || index == 0 {
+			return 0, 0, false
+		}
+	}
+	return blockNumber, index, true
+}
+
+// committedBlockNumber resolves the number of a block for queries that walk the committed state, which
+// the pending block is not part of.
+func (h *Handler) committedBlockNumber(id *BlockID) (uint64, *jsonrpc.Error) {
+	if id.Pending {
+		return 0, jsonrpc.Err(jsonrpc.InvalidParams, "pending block is not supported")
+	}
+
+	header, err := h.blockHeaderByID(id)
+	if err != nil {
+		return 0, ErrBlockNotFound
+	}
+	return header.Number, nil
+}
+
+// StorageRange returns the non-zero storage locations of the given contract and their values in the given
+// block, in ascending key order. Results are paginated with a continuation token.
+func (h *Handler) StorageRange(address felt.Felt, id BlockID, chunkSize uint64,
+	continuationToken string,
+) (*StorageRangeChunk, *jsonrpc.Error) {
+	blockNumber, start, rpcErr := h.rangeArgs(&id, chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	entries, next, err := h.bcReader.ContractStorageRange(&address, start, blockNumber, chunkSize)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	chunk := &StorageRangeChunk{
+		Storage: make([]Entry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		chunk.Storage = append(chunk.Storage, Entry{Key: *entry.Key, Value: *entry.Value})
+	}
+	if next != nil {
+		chunk.ContinuationToken = next.String()
+	}
+	return chunk, nil
+}
+
+// ContractsRange returns the contracts deployed as of the given block along with their class hashes and
+// nonces, in ascending address order. Results are paginated with a continuation token.
+func (h *Handler) ContractsRange(id BlockID, chunkSize uint64, continuationToken string) (*ContractsRangeChunk, *jsonrpc.Error) {
+	blockNumber, start, rpcErr := h.rangeArgs(&id, chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	entries, next, err := h.bcReader.ContractsRange(start, blockNumber, chunkSize)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrBlockNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	chunk := &ContractsRangeChunk{
+		Contracts: make([]ContractEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		chunk.Contracts = append(chunk.Contracts, ContractEntry{
+			Address:   *entry.Address,
+			ClassHash: *entry.ClassHash,
+			Nonce:     *entry.Nonce,
+		})
+	}
+	if next != nil {
+		chunk.ContinuationToken = next.String()
+	}
+	return chunk, nil
+}
+
+// TransactionProof rebuilds the transaction commitment trie of the given block and returns the Merkle proof
+// that the transaction at the given index is included in the block's transaction commitment
+func (h *Handler) TransactionProof(id BlockID, txIndex int) (*CommitmentProof, *jsonrpc.Error) {
+	if txIndex < 0 {
+		return nil, ErrInvalidTxIndex
+	}
+
+	block, commitments, rpcErr := h.committedBlock(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if txIndex >= len(block.Transactions) {
+		return nil, ErrInvalidTxIndex
+	}
+
+	proof, err := core.TransactionProof(block, uint64(txIndex))
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	return adaptCommitmentProof(block.Header, commitments.TransactionCommitment, proof), nil
+}
+
+// EventProof rebuilds the event commitment trie of the given block and returns the Merkle proof that the
+// event at the given index in the events emitted by the transaction at the given index is included in the
+// block's event commitment
+func (h *Handler) EventProof(id BlockID, txIndex, eventIndex int) (*CommitmentProof, *jsonrpc.Error) {
+	if txIndex < 0 {
+		return nil, ErrInvalidTxIndex
+	} else if eventIndex < 0 {
+		return nil, ErrInvalidEventIndex
+	}
+
+	block, commitments, rpcErr := h.committedBlock(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if txIndex >= len(block.Receipts) {
+		return nil, ErrInvalidTxIndex
+	} else if eventIndex >= len(block.Receipts[txIndex].Events) {
+		return nil, ErrInvalidEventIndex
+	}
+
+	proof, err := core.EventProof(block, uint64(txIndex), uint64(eventIndex))
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	return adaptCommitmentProof(block.Header, commitments.EventCommitment, proof), nil
+}
+
+// StorageProof returns the Merkle proof of the given contract against the contracts root of the given block,
+// and of the values of the given storage locations against the storage root of the contract. Only the state at
+// the head, or at the blocks stored in archive mode, can be proven.
+func (h *Handler) StorageProof(id BlockID, address felt.Felt, keys []felt.Felt) (*StorageProof, *jsonrpc.Error) {
+	if id.Pending {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "pending block is not supported")
+	} else if len(keys) > maxStorageProofKeys {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, fmt.Sprintf("at most %d keys can be proven", maxStorageProofKeys))
+	}
+
+	header, err := h.blockHeaderByID(&id)
+	if err != nil {
+		return nil, ErrBlockNotFound
+	}
+
+	tries, triesCloser, err := h.bcReader.TriesAtBlockNumber(header.Number)
+	if err != nil {
+		if errors.Is(err, core.ErrStateNotArchived) {
+			return nil, ErrStateNotArchived
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	defer h.callAndLogErr(triesCloser, "Error closing tries in juno_getStorageProof")
+
+	state, stateCloser, err := h.bcReader.StateAtBlockNumber(header.Number)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	defer h.callAndLogErr(stateCloser, "Error closing state reader in juno_getStorageProof")
+
+	proof, err := storageProof(state, tries, &address, keys)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	proof.BlockHash, proof.BlockNumber = header.Hash, header.Number
+	return proof, nil
+}
+
+func storageProof(state core.StateReader, tries core.TrieReader, addr *felt.Felt, keys []felt.Felt) (*StorageProof, error) {
+	classHash, err := state.ContractClassHash(addr)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := state.ContractNonce(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	contractsRoot, classesRoot, err := tries.GlobalRoots()
+	if err != nil {
+		return nil, err
+	}
+	storageRoot, err := tries.ContractStorageRoot(addr)
+	if err != nil {
+		return nil, err
+	}
+	contractProof, err := tries.ContractProof(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := make([]StorageValueProof, 0, len(keys))
+	for i := range keys {
+		key := &keys[i]
+		value, err := state.ContractStorage(addr, key)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := tries.ContractStorageProof(addr, key)
+		if err != nil {
+			return nil, err
+		}
+		storage = append(storage, StorageValueProof{Key: key, Value: value, Proof: adaptProofNodes(proof)})
+	}
+
+	return &StorageProof{
+		ContractsRoot: contractsRoot,
+		ClassesRoot:   classesRoot,
+		ClassHash:     classHash,
+		Nonce:         nonce,
+		StorageRoot:   storageRoot,
+		ContractProof: adaptProofNodes(contractProof),
+		Storage:       storage,
+	}, nil
+}
+
+// committedBlock returns the block with the given id along with its commitments
+func (h *Handler) committedBlock(id *BlockID) (*core.Block, *core.BlockCommitments, *jsonrpc.Error) {
+	blockNumber, rpcErr := h.committedBlockNumber(id)
+	if rpcErr != nil {
+		return nil, nil, rpcErr
+	}
+
+	block, err := h.bcReader.BlockByNumber(blockNumber)
+	if err != nil {
+		return nil, nil, ErrBlockNotFound
+	}
+	commitments, err := h.bcReader.BlockCommitmentsByNumber(blockNumber)
+	if err != nil {
+		return nil, nil, ErrInternal.CloneWithData(err.Error())
+	}
+	return block, commitments, nil
+}
+
+// rangeArgs validates the arguments of a trie range query and returns the block number and the key to
+// start from
+func (h *Handler) rangeArgs(id *BlockID, chunkSize uint64, continuationToken string) (uint64, *felt.Felt, *jsonrpc.Error) {
+	start, rpcErr := rangeStart(chunkSize, continuationToken)
+	if rpcErr != nil {
+		return 0, nil, rpcErr
+	}
+
+	blockNumber, rpcErr := h.committedBlockNumber(id)
+	if rpcErr != nil {
+		return 0, nil, rpcErr
+	}
+	return blockNumber, start, nil
+}
+
+// rangeStart validates the chunk size of a query over felt keys and returns the key to start from
+func rangeStart(chunkSize uint64, continuationToken string) (*felt.Felt, *jsonrpc.Error) {
+	if chunkSize == 0 {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "chunk_size must be positive")
+	} else if chunkSize > maxRangeChunkSize {
+		return nil, ErrPageSizeTooBig
+	}
+
+	if continuationToken == "" {
+		return &felt.Zero, nil
+	}
+
+	start, err := new(felt.Felt).SetString(continuationToken)
+	if err != nil {
+		return nil, ErrInvalidContinuationToken
+	}
+	return start, nil
+}
+
+// ContractsByClassHash returns the addresses of the contracts currently using the given class, in ascending
+// order. Results are paginated with a continuation token.
+func (h *Handler) ContractsByClassHash(classHash felt.Felt, chunkSize uint64,
+	continuationToken string,
+) (*ContractAddressesChunk, *jsonrpc.Error) {
+	start, rpcErr := rangeStart(chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	addresses, next, err := h.bcReader.ContractsByClassHash(&classHash, start, chunkSize)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	chunk := &ContractAddressesChunk{ContractAddresses: make([]*felt.Felt, 0, len(addresses))}
+	chunk.ContractAddresses = append(chunk.ContractAddresses, addresses...)
+	if next != nil {
+		chunk.ContinuationToken = next.String()
+	}
+	return chunk, nil
+}
+
+// ClassesBySelector returns the hashes of the declared classes that define an external, L1 handler or
+// constructor entry point with the given selector, in ascending order. Results are paginated with a
+// continuation token.
+func (h *Handler) ClassesBySelector(selector felt.Felt, chunkSize uint64,
+	continuationToken string,
+) (*ClassHashesChunk, *jsonrpc.Error) {
+	start, rpcErr := rangeStart(chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	classHashes, next, err := h.bcReader.ClassHashesBySelector(&selector, start, chunkSize)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	chunk := &ClassHashesChunk{ClassHashes: make([]*felt.Felt, 0, len(classHashes))}
+	chunk.ClassHashes = append(chunk.ClassHashes, classHashes...)
+	if next != nil {
+		chunk.ContinuationToken = next.String()
+	}
+	return chunk, nil
+}
+
 // Class gets the contract class definition in the given block associated with the given hash
 //
 // It follows the specification defined here:
@@ -1039,7 +1630,12 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 		}
 	}
 
-	if err = setEventFilterRange(filter, args.EventFilter.FromBlock, args.EventFilter.ToBlock, height); err != nil {
+	l1H, jsonErr := h.l1Head()
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	if err = setEventFilterRange(filter, args.EventFilter.FromBlock, args.EventFilter.ToBlock, height, l1H); err != nil {
 		return nil, ErrBlockNotFound
 	}
 
@@ -1051,13 +1647,18 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 	emittedEvents := make([]*EmittedEvent, 0, len(filteredEvents))
 	for _, fEvent := range filteredEvents {
 		var blockNumber *uint64
+		status := TxnAcceptedOnL2
 		if fEvent.BlockHash != nil {
 			blockNumber = &(fEvent.BlockNumber)
+			if isL1Verified(fEvent.BlockNumber, l1H) {
+				status = TxnAcceptedOnL1
+			}
 		}
 		emittedEvents = append(emittedEvents, &EmittedEvent{
 			BlockNumber:     blockNumber,
 			BlockHash:       fEvent.BlockHash,
 			TransactionHash: fEvent.TransactionHash,
+			FinalityStatus:  status,
 			Event: &Event{
 				From: fEvent.From,
 				Keys: fEvent.Keys,
@@ -1073,7 +1674,35 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 	return &EventsChunk{Events: emittedEvents, ContinuationToken: cTokenStr}, nil
 }
 
-func setEventFilterRange(filter *blockchain.EventFilter, fromID, toID *BlockID, latestHeight uint64) error {
+// DecodedEvents gets the events matching a filter, like starknet_getEvents, and decodes them using the ABI
+// of the emitting contract's class at the block the event was emitted in. Events that can not be decoded
+// are returned without decoded fields.
+func (h *Handler) DecodedEvents(args EventsArg) (*DecodedEventsChunk, *jsonrpc.Error) {
+	chunk, rpcErr := h.Events(args)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	abis := newABIResolver(h)
+	defer h.callAndLogErr(abis.close, "Failed to close state in juno_getDecodedEvents")
+
+	decodedEvents := make([]*DecodedEmittedEvent, 0, len(chunk.Events))
+	for _, event := range chunk.Events {
+		id := BlockID{Pending: true}
+		if event.BlockNumber != nil {
+			id = BlockID{Number: *event.BlockNumber}
+		}
+
+		decodedEvent := &DecodedEmittedEvent{EmittedEvent: event}
+		if contractABI, err := abis.contractABI(&id, event.From); err == nil {
+			decodedEvent.Decoded, _ = contractABI.DecodeEvent(event.Keys, event.Data)
+		}
+		decodedEvents = append(decodedEvents, decodedEvent)
+	}
+	return &DecodedEventsChunk{Events: decodedEvents, ContinuationToken: chunk.ContinuationToken}, nil
+}
+
+func setEventFilterRange(filter *blockchain.EventFilter, fromID, toID *BlockID, latestHeight uint64, l1Head *core.L1Head) error {
 	set := func(filterRange blockchain.EventFilterRange, id *BlockID) error {
 		if id == nil {
 			return nil
@@ -1082,6 +1711,11 @@ func setEventFilterRange(filter *blockchain.EventFilter, fromID, toID *BlockID,
 		switch {
 		case id.Latest:
 			return filter.SetRangeEndBlockByNumber(filterRange, latestHeight)
+		case id.L1Accepted:
+			if l1Head == nil {
+				return db.ErrKeyNotFound
+			}
+			return filter.SetRangeEndBlockByNumber(filterRange, l1Head.BlockNumber)
 		case id.Hash != nil:
 			return filter.SetRangeEndBlockByHash(filterRange, id.Hash)
 		case id.Pending:
@@ -1099,6 +1733,12 @@ func setEventFilterRange(filter *blockchain.EventFilter, fromID, toID *BlockID,
 // AddTransaction relays a transaction to the gateway.
 func (h *Handler) AddTransaction(tx BroadcastedTransaction) (*AddTxResponse, *jsonrpc.Error) { //nolint:gocritic
 	if tx.Type == TxnDeclare && tx.Version.Cmp(new(felt.Felt).SetUint64(2)) != -1 {
+		if h.checkCompiledClassHash {
+			if rpcErr := h.checkCompiledClass(tx.ContractClass, tx.CompiledClassHash); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+
 		contractClass := make(map[string]any)
 		if err := json.Unmarshal(tx.ContractClass, &contractClass); err != nil {
 			return nil, ErrInternal.CloneWithData(fmt.Sprintf("unmarshal contract class: %v", err))
@@ -1149,6 +1789,9 @@ func (h *Handler) AddTransaction(tx BroadcastedTransaction) (*AddTxResponse, *js
 	if err = json.Unmarshal(respJSON, &gatewayResponse); err != nil {
 		return nil, jsonrpc.Err(jsonrpc.InternalError, fmt.Sprintf("unmarshal gateway response: %v", err))
 	}
+	if h.txGossip != nil && tx.Type != TxnDeclare {
+		h.gossipTransaction(&tx)
+	}
 
 	return &AddTxResponse{
 		TransactionHash: gatewayResponse.TransactionHash,
@@ -1157,6 +1800,45 @@ func (h *Handler) AddTransaction(tx BroadcastedTransaction) (*AddTxResponse, *js
 	}, nil
 }
 
+// checkCompiledClass compiles the Sierra class of a declare transaction and checks that it hashes to the
+// compiled class hash of the transaction
+func (h *Handler) checkCompiledClass(contractClass json.RawMessage, compiledClassHash *felt.Felt) *jsonrpc.Error {
+	class, err := adaptDeclaredClass(contractClass)
+	if err != nil {
+		return ErrInvalidContractClass.CloneWithData(err.Error())
+	}
+	cairo1Class, ok := class.(*core.Cairo1Class)
+	if !ok {
+		return ErrInvalidContractClass.CloneWithData("declare v2 and later require a Sierra class")
+	}
+
+	compiled, err := h.vm.Compile(cairo1Class)
+	if err != nil {
+		return ErrCompilationFailed.CloneWithData(err.Error())
+	}
+	cairo1Class.Compiled = compiled
+
+	hash, err := cairo1Class.CompiledClassHash()
+	if err != nil {
+		return ErrCompilationFailed.CloneWithData(err.Error())
+	}
+	if !hash.Equal(compiledClassHash) {
+		return ErrCompiledClassHashMismatch
+	}
+	return nil
+}
+
+// gossipTransaction propagates a transaction the gateway accepted to the peers of the node
+func (h *Handler) gossipTransaction(tx *BroadcastedTransaction) {
+	txn, _, _, err := adaptBroadcastedTransaction(tx, h.network)
+	if err == nil {
+		err = h.txGossip.GossipTransaction(txn)
+	}
+	if err != nil {
+		h.log.Debugw("Failed gossiping transaction", "err", err)
+	}
+}
+
 func makeJSONErrorFromGatewayError(err error) *jsonrpc.Error {
 	gatewayErr, ok := err.(*gateway.Error)
 	if !ok {
@@ -1237,6 +1919,25 @@ func (h *Handler) Call(call FunctionCall, id BlockID) ([]*felt.Felt, *jsonrpc.Er
 	return res, nil
 }
 
+// DecodedCall calls a function like starknet_call and decodes its result using the ABI of the called
+// contract's class at the given block. The result is returned without decoded values if it can not be
+// decoded.
+func (h *Handler) DecodedCall(call FunctionCall, id BlockID) (*DecodedCallResult, *jsonrpc.Error) { //nolint:gocritic
+	result, rpcErr := h.Call(call, id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	abis := newABIResolver(h)
+	defer h.callAndLogErr(abis.close, "Failed to close state in juno_decodedCall")
+
+	callResult := &DecodedCallResult{Result: result}
+	if contractABI, err := abis.contractABI(&id, &call.ContractAddress); err == nil {
+		callResult.Decoded, _ = contractABI.DecodeResult(&call.EntryPointSelector, result)
+	}
+	return callResult, nil
+}
+
 type ContractErrorData struct {
 	RevertError string `json:"revert_error"`
 }
@@ -1270,6 +1971,9 @@ func (h *Handler) TransactionStatus(ctx context.Context, hash felt.Felt) (*Trans
 	case ErrTxnHashNotFound:
 		txStatus, err := h.feederClient.Transaction(ctx, &hash)
 		if err != nil {
+			if h.gossipReceived(&hash) {
+				return &TransactionStatus{Finality: TxnStatusReceived}, nil
+			}
 			return nil, jsonrpc.Err(jsonrpc.InternalError, err.Error())
 		}
 
@@ -1282,6 +1986,10 @@ func (h *Handler) TransactionStatus(ctx context.Context, hash felt.Felt) (*Trans
 		case starknet.Received:
 			status.Finality = TxnStatusReceived
 		default:
+			// the gateway may not have seen a transaction gossiped by peers yet
+			if h.gossipReceived(&hash) {
+				return &TransactionStatus{Finality: TxnStatusReceived}, nil
+			}
 			return nil, ErrTxnHashNotFound
 		}
 
@@ -1300,6 +2008,10 @@ func (h *Handler) TransactionStatus(ctx context.Context, hash felt.Felt) (*Trans
 	}
 }
 
+func (h *Handler) gossipReceived(hash *felt.Felt) bool {
+	return h.txGossip != nil && h.txGossip.Received(hash)
+}
+
 func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []SimulationFlag, id BlockID,
 ) ([]FeeEstimate, *jsonrpc.Error) {
@@ -1864,6 +2576,95 @@ func (h *Handler) Methods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Name:    "juno_version",
 			Handler: h.Version,
 		},
+		{
+			Name: "juno_getTransactionsByAddress",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.TransactionsByAddress,
+		},
+		{
+			Name:    "juno_getTransactionBySenderAndNonce",
+			Params:  []jsonrpc.Parameter{{Name: "sender_address"}, {Name: "nonce"}},
+			Handler: h.TransactionBySenderAndNonce,
+		},
+		{
+			Name: "juno_getStorageHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "key"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.StorageHistory,
+		},
+		{
+			Name: "juno_getNonceHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.NonceHistory,
+		},
+		{
+			Name: "juno_getClassHashHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.ClassHashHistory,
+		},
+		{
+			Name: "juno_getStorageRange",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "block_id"}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.StorageRange,
+		},
+		{
+			Name:    "juno_getContractsRange",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsRange,
+		},
+		{
+			Name:    "juno_getContractsByClassHash",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsByClassHash,
+		},
+		{
+			Name:    "juno_getClassesBySelector",
+			Params:  []jsonrpc.Parameter{{Name: "selector"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ClassesBySelector,
+		},
+		{
+			Name:    "juno_getDecodedEvents",
+			Params:  []jsonrpc.Parameter{{Name: "filter"}},
+			Handler: h.DecodedEvents,
+		},
+		{
+			Name:    "juno_getDecodedTransactionByHash",
+			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
+			Handler: h.DecodedTransactionByHash,
+		},
+		{
+			Name:    "juno_decodedCall",
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}},
+			Handler: h.DecodedCall,
+		},
+		{
+			Name:    "juno_getTransactionProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "index"}},
+			Handler: h.TransactionProof,
+		},
+		{
+			Name:    "juno_getEventProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "transaction_index"}, {Name: "event_index"}},
+			Handler: h.EventProof,
+		},
+		{
+			Name:    "juno_getStorageProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "contract_address"}, {Name: "keys"}},
+			Handler: h.StorageProof,
+		},
 		{
 			Name:    "starknet_getTransactionStatus",
 			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
@@ -2017,6 +2818,95 @@ func (h *Handler) LegacyMethods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Name:    "juno_version",
 			Handler: h.Version,
 		},
+		{
+			Name: "juno_getTransactionsByAddress",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.TransactionsByAddress,
+		},
+		{
+			Name:    "juno_getTransactionBySenderAndNonce",
+			Params:  []jsonrpc.Parameter{{Name: "sender_address"}, {Name: "nonce"}},
+			Handler: h.TransactionBySenderAndNonce,
+		},
+		{
+			Name: "juno_getStorageHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "key"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.StorageHistory,
+		},
+		{
+			Name: "juno_getNonceHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.NonceHistory,
+		},
+		{
+			Name: "juno_getClassHashHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "from_block"}, {Name: "to_block"},
+				{Name: "chunk_size"}, {Name: "continuation_token", Optional: true},
+			},
+			Handler: h.ClassHashHistory,
+		},
+		{
+			Name: "juno_getStorageRange",
+			Params: []jsonrpc.Parameter{
+				{Name: "contract_address"}, {Name: "block_id"}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.StorageRange,
+		},
+		{
+			Name:    "juno_getContractsRange",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsRange,
+		},
+		{
+			Name:    "juno_getContractsByClassHash",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsByClassHash,
+		},
+		{
+			Name:    "juno_getClassesBySelector",
+			Params:  []jsonrpc.Parameter{{Name: "selector"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ClassesBySelector,
+		},
+		{
+			Name:    "juno_getDecodedEvents",
+			Params:  []jsonrpc.Parameter{{Name: "filter"}},
+			Handler: h.DecodedEvents,
+		},
+		{
+			Name:    "juno_getDecodedTransactionByHash",
+			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
+			Handler: h.DecodedTransactionByHash,
+		},
+		{
+			Name:    "juno_decodedCall",
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}},
+			Handler: h.DecodedCall,
+		},
+		{
+			Name:    "juno_getTransactionProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "index"}},
+			Handler: h.TransactionProof,
+		},
+		{
+			Name:    "juno_getEventProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "transaction_index"}, {Name: "event_index"}},
+			Handler: h.EventProof,
+		},
+		{
+			Name:    "juno_getStorageProof",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "contract_address"}, {Name: "keys"}},
+			Handler: h.StorageProof,
+		},
 		{
 			Name:    "starknet_getTransactionStatus",
 			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
@@ -9,13 +9,18 @@ import (
 	"math/rand"
 	"net"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/NethermindEth/juno/abi"
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/jsonrpc"
@@ -512,6 +517,66 @@ func TestBlockWithTxHashesV013(t *testing.T) {
 	}, got)
 }
 
+func TestTransactionsByAddress(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	address := utils.HexToFelt(t, "0xDEADBEEF")
+	tx := &core.InvokeTransaction{
+		TransactionHash: utils.HexToFelt(t, "0x1"),
+		SenderAddress:   address,
+		Nonce:           utils.HexToFelt(t, "0x2"),
+		Version:         new(core.TransactionVersion).SetUint64(1),
+	}
+	accountTx := &blockchain.AccountTransaction{BlockNumber: 3, Index: 4, Transaction: tx}
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		_, rpcErr := handler.TransactionsByAddress(*address, 0, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.TransactionsByAddress(*address, 1025, "")
+		require.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+	})
+
+	t.Run("invalid continuation token", func(t *testing.T) {
+		_, rpcErr := handler.TransactionsByAddress(*address, 1, "not a token")
+		require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		cToken := new(blockchain.AccountTransactionsToken)
+		require.NoError(t, cToken.FromString("3-4"))
+		nextToken := new(blockchain.AccountTransactionsToken)
+		require.NoError(t, nextToken.FromString("5-0"))
+		mockReader.EXPECT().TransactionsByAddress(address, cToken, uint64(1)).
+			Return([]*blockchain.AccountTransaction{accountTx}, nextToken, nil)
+
+		chunk, rpcErr := handler.TransactionsByAddress(*address, 1, "3-4")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "5-0", chunk.ContinuationToken)
+		require.Len(t, chunk.Transactions, 1)
+		assert.Equal(t, &rpc.AccountTransaction{
+			BlockNumber:      3,
+			TransactionIndex: 4,
+			Transaction:      rpc.AdaptTransaction(tx),
+		}, chunk.Transactions[0])
+	})
+
+	t.Run("by sender and nonce", func(t *testing.T) {
+		mockReader.EXPECT().TransactionBySenderAndNonce(address, tx.Nonce).Return(accountTx, nil)
+		got, rpcErr := handler.TransactionBySenderAndNonce(*address, *tx.Nonce)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, uint64(3), got.BlockNumber)
+		assert.Equal(t, tx.Hash(), got.Transaction.Hash)
+
+		mockReader.EXPECT().TransactionBySenderAndNonce(address, address).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr = handler.TransactionBySenderAndNonce(*address, *address)
+		assert.Equal(t, rpc.ErrTxnHashNotFound, rpcErr)
+	})
+}
+
 func TestTransactionByHashNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	t.Cleanup(mockCtrl.Finish)
@@ -2085,6 +2150,371 @@ func TestStorageAt(t *testing.T) {
 	})
 }
 
+func TestValueHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	address := utils.HexToFelt(t, "0xDEADBEEF")
+	key := utils.HexToFelt(t, "0x5")
+	from := rpc.BlockID{Number: 2}
+	latest := rpc.BlockID{Latest: true}
+	mockReader.EXPECT().BlockHeaderByNumber(uint64(2)).Return(&core.Header{Number: 2}, nil).AnyTimes()
+	mockReader.EXPECT().HeadsHeader().Return(&core.Header{Number: 10}, nil).AnyTimes()
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, rpcErr := handler.StorageHistory(*address, *key, rpc.BlockID{Pending: true}, latest, 10, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		mockReader.EXPECT().BlockHeaderByNumber(uint64(11)).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr = handler.StorageHistory(*address, *key, from, rpc.BlockID{Number: 11}, 10, "")
+		require.Equal(t, rpc.ErrBlockNotFound, rpcErr)
+
+		_, rpcErr = handler.StorageHistory(*address, *key, from, latest, 10, "1")
+		require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+
+		_, rpcErr = handler.StorageHistory(*address, *key, from, latest, 0, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("contract not found", func(t *testing.T) {
+		mockReader.EXPECT().ContractNonceChanges(address, uint64(2), uint64(10), uint64(10)).Return(nil, nil, db.ErrKeyNotFound)
+		_, rpcErr := handler.NonceHistory(*address, from, latest, 10, "")
+		require.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		change := core.ValueChange{BlockNumber: 4, OldValue: &felt.Zero, NewValue: key}
+		next := uint64(7)
+		mockReader.EXPECT().ContractStorageChanges(address, key, uint64(4), uint64(10), uint64(1)).
+			Return([]core.ValueChange{change}, &next, nil)
+
+		chunk, rpcErr := handler.StorageHistory(*address, *key, from, latest, 1, "4")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ValueChangesChunk{
+			Changes: []*rpc.ValueChange{{
+				BlockNumber: 4,
+				OldValue:    &felt.Zero,
+				NewValue:    key,
+			}},
+			ContinuationToken: "7",
+		}, chunk)
+	})
+
+	t.Run("class hash", func(t *testing.T) {
+		mockReader.EXPECT().ContractClassHashChanges(address, uint64(2), uint64(10), uint64(10)).Return(nil, nil, nil)
+		chunk, rpcErr := handler.ClassHashHistory(*address, from, latest, 10, "")
+		require.Nil(t, rpcErr)
+		assert.Empty(t, chunk.Changes)
+		assert.Empty(t, chunk.ContinuationToken)
+	})
+
+	t.Run("deployed and upgraded in the same block", func(t *testing.T) {
+		deployed, upgraded := utils.HexToFelt(t, "0xC1"), utils.HexToFelt(t, "0xC2")
+		deploy := core.ValueChange{BlockNumber: 4, OldValue: &felt.Zero, NewValue: deployed}
+		upgrade := core.ValueChange{BlockNumber: 4, OldValue: deployed, NewValue: upgraded}
+		next := uint64(4)
+
+		mockReader.EXPECT().ContractClassHashChanges(address, uint64(2), uint64(10), uint64(1)).
+			Return([]core.ValueChange{deploy}, &next, nil)
+		chunk, rpcErr := handler.ClassHashHistory(*address, from, latest, 1, "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ValueChangesChunk{
+			Changes:           []*rpc.ValueChange{{BlockNumber: 4, OldValue: &felt.Zero, NewValue: deployed}},
+			ContinuationToken: "4:1",
+		}, chunk)
+
+		mockReader.EXPECT().ContractClassHashChanges(address, uint64(4), uint64(10), uint64(2)).
+			Return([]core.ValueChange{deploy, upgrade}, nil, nil)
+		chunk, rpcErr = handler.ClassHashHistory(*address, from, latest, 1, chunk.ContinuationToken)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ValueChangesChunk{
+			Changes: []*rpc.ValueChange{{BlockNumber: 4, OldValue: deployed, NewValue: upgraded}},
+		}, chunk)
+
+		for _, token := range []string{"4:0", "4:x", ":1"} {
+			_, rpcErr = handler.ClassHashHistory(*address, from, latest, 1, token)
+			require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr, token)
+		}
+	})
+}
+
+func TestStorageRange(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	address := utils.HexToFelt(t, "0xDEADBEEF")
+	latest := rpc.BlockID{Latest: true}
+	mockReader.EXPECT().HeadsHeader().Return(&core.Header{Number: 10}, nil).AnyTimes()
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, rpcErr := handler.StorageRange(*address, rpc.BlockID{Pending: true}, 10, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.StorageRange(*address, latest, 1025, "")
+		require.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+
+		_, rpcErr = handler.ContractsRange(latest, 10, "not a felt")
+		require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+	})
+
+	t.Run("contract not found", func(t *testing.T) {
+		mockReader.EXPECT().ContractStorageRange(address, &felt.Zero, uint64(10), uint64(10)).Return(nil, nil, db.ErrKeyNotFound)
+		_, rpcErr := handler.StorageRange(*address, latest, 10, "")
+		require.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("storage", func(t *testing.T) {
+		start, next := utils.HexToFelt(t, "0x5"), utils.HexToFelt(t, "0x7")
+		mockReader.EXPECT().ContractStorageRange(address, start, uint64(10), uint64(1)).
+			Return([]core.StorageEntry{{Key: start, Value: address}}, next, nil)
+
+		chunk, rpcErr := handler.StorageRange(*address, latest, 1, "0x5")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.StorageRangeChunk{
+			Storage:           []rpc.Entry{{Key: *start, Value: *address}},
+			ContinuationToken: "0x7",
+		}, chunk)
+	})
+
+	t.Run("contracts", func(t *testing.T) {
+		classHash := utils.HexToFelt(t, "0x1337")
+		mockReader.EXPECT().ContractsRange(&felt.Zero, uint64(10), uint64(10)).
+			Return([]core.ContractEntry{{Address: address, ClassHash: classHash, Nonce: &felt.Zero}}, nil, nil)
+
+		chunk, rpcErr := handler.ContractsRange(latest, 10, "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractsRangeChunk{
+			Contracts: []rpc.ContractEntry{{Address: *address, ClassHash: *classHash, Nonce: felt.Zero}},
+		}, chunk)
+	})
+}
+
+func TestClassIndexes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	classHash := utils.HexToFelt(t, "0x1337")
+	selector := utils.HexToFelt(t, "0x5")
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, rpcErr := handler.ContractsByClassHash(*classHash, 0, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.ClassesBySelector(*selector, 1025, "")
+		require.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+
+		_, rpcErr = handler.ContractsByClassHash(*classHash, 10, "not a felt")
+		require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+	})
+
+	t.Run("contracts by class hash", func(t *testing.T) {
+		start, next := utils.HexToFelt(t, "0xA"), utils.HexToFelt(t, "0xC")
+		address := utils.HexToFelt(t, "0xB")
+		mockReader.EXPECT().ContractsByClassHash(classHash, start, uint64(1)).Return([]*felt.Felt{address}, next, nil)
+
+		chunk, rpcErr := handler.ContractsByClassHash(*classHash, 1, "0xa")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractAddressesChunk{
+			ContractAddresses: []*felt.Felt{address},
+			ContinuationToken: "0xc",
+		}, chunk)
+	})
+
+	t.Run("classes by selector", func(t *testing.T) {
+		mockReader.EXPECT().ClassHashesBySelector(selector, &felt.Zero, uint64(10)).Return(nil, nil, nil)
+
+		chunk, rpcErr := handler.ClassesBySelector(*selector, 10, "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ClassHashesChunk{ClassHashes: []*felt.Felt{}}, chunk)
+
+		mockReader.EXPECT().ClassHashesBySelector(selector, &felt.Zero, uint64(10)).Return(nil, nil, errors.New("some error"))
+		_, rpcErr = handler.ClassesBySelector(*selector, 10, "")
+		require.Equal(t, rpc.ErrInternal.Code, rpcErr.Code)
+	})
+}
+
+func TestCommitmentProofs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	block, err := gw.BlockByNumber(context.Background(), 16789)
+	require.NoError(t, err)
+	commitments, err := core.VerifyBlockHash(block, utils.Mainnet, nil)
+	require.NoError(t, err)
+
+	expectBlock := func() {
+		mockReader.EXPECT().BlockHeaderByNumber(block.Number).Return(block.Header, nil)
+		mockReader.EXPECT().BlockByNumber(block.Number).Return(block, nil)
+		mockReader.EXPECT().BlockCommitmentsByNumber(block.Number).Return(commitments, nil)
+	}
+	id := rpc.BlockID{Number: block.Number}
+
+	adaptProof := func(proof *rpc.CommitmentProof) *core.CommitmentProof {
+		return &core.CommitmentProof{Index: proof.Index, Leaf: proof.Leaf, Nodes: adaptProofNodes(proof.Proof)}
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, rpcErr := handler.TransactionProof(rpc.BlockID{Pending: true}, 0)
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.TransactionProof(id, -1)
+		require.Equal(t, rpc.ErrInvalidTxIndex, rpcErr)
+
+		_, rpcErr = handler.EventProof(id, 0, -1)
+		require.Equal(t, rpc.ErrInvalidEventIndex, rpcErr)
+
+		mockReader.EXPECT().BlockHeaderByNumber(uint64(1)).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr = handler.TransactionProof(rpc.BlockID{Number: 1}, 0)
+		require.Equal(t, rpc.ErrBlockNotFound, rpcErr)
+
+		expectBlock()
+		_, rpcErr = handler.TransactionProof(id, len(block.Transactions))
+		require.Equal(t, rpc.ErrInvalidTxIndex, rpcErr)
+
+		expectBlock()
+		_, rpcErr = handler.EventProof(id, 0, len(block.Receipts[0].Events))
+		require.Equal(t, rpc.ErrInvalidEventIndex, rpcErr)
+	})
+
+	t.Run("transaction proof", func(t *testing.T) {
+		index := len(block.Transactions) - 1
+		expectBlock()
+		proof, rpcErr := handler.TransactionProof(id, index)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, block.Hash, proof.BlockHash)
+		assert.Equal(t, block.Number, proof.BlockNumber)
+		assert.Equal(t, commitments.TransactionCommitment, proof.Commitment)
+		assert.Equal(t, uint64(index), proof.Index)
+
+		require.NoError(t, core.VerifyTransactionProof(commitments, block.ProtocolVersion, block.Transactions[index], adaptProof(proof)))
+	})
+
+	t.Run("event proof", func(t *testing.T) {
+		var txIndex int
+		for i, receipt := range block.Receipts {
+			if len(receipt.Events) > 0 {
+				txIndex = i
+				break
+			}
+		}
+		receipt := block.Receipts[txIndex]
+		eventIndex := len(receipt.Events) - 1
+
+		expectBlock()
+		proof, rpcErr := handler.EventProof(id, txIndex, eventIndex)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, commitments.EventCommitment, proof.Commitment)
+
+		require.NoError(t, core.VerifyEventProof(commitments, block.ProtocolVersion, receipt.Events[eventIndex],
+			receipt.TransactionHash, adaptProof(proof)))
+	})
+}
+
+func adaptProofNodes(proof []rpc.ProofNode) []trie.ProofNode {
+	nodes := make([]trie.ProofNode, 0, len(proof))
+	for _, node := range proof {
+		if node.Binary != nil {
+			nodes = append(nodes, trie.ProofNode{Binary: &trie.Binary{LeftHash: node.Binary.Left, RightHash: node.Binary.Right}})
+			continue
+		}
+		pathBytes := node.Edge.Path.Bytes()
+		path := trie.NewKey(node.Edge.Length, pathBytes[:])
+		nodes = append(nodes, trie.ProofNode{Edge: &trie.Edge{Child: node.Edge.Child, Path: &path}})
+	}
+	return nodes
+}
+
+func TestStorageProof(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	archive := blockchain.New(pebble.NewMemTest(t), utils.Mainnet, utils.NewNopZapLogger()).WithArchive(true)
+	var su0 *core.StateUpdate
+	for i := uint64(0); i < 2; i++ {
+		block, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, archive.Store(block, &core.BlockCommitments{}, su, nil))
+		if i == 0 {
+			su0 = su
+		}
+	}
+	handler := rpc.New(archive, nil, utils.Mainnet, nil, nil, nil, "", nil)
+
+	var (
+		address felt.Felt
+		keys    []felt.Felt
+	)
+	for addr, diff := range su0.StateDiff.StorageDiffs {
+		address = addr
+		for key := range diff {
+			keys = append(keys, key)
+		}
+		break
+	}
+	keys = append(keys, *new(felt.Felt).SetUint64(0xdead))
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, rpcErr := handler.StorageProof(rpc.BlockID{Pending: true}, address, keys)
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.StorageProof(rpc.BlockID{Number: 2}, address, keys)
+		require.Equal(t, rpc.ErrBlockNotFound, rpcErr)
+
+		_, rpcErr = handler.StorageProof(rpc.BlockID{Number: 0}, *new(felt.Felt).SetUint64(0xdead), keys)
+		require.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("not archived", func(t *testing.T) {
+		chain := blockchain.New(pebble.NewMemTest(t), utils.Mainnet, utils.NewNopZapLogger())
+		for i := uint64(0); i < 2; i++ {
+			block, err := gw.BlockByNumber(context.Background(), i)
+			require.NoError(t, err)
+			su, err := gw.StateUpdate(context.Background(), i)
+			require.NoError(t, err)
+			require.NoError(t, chain.Store(block, &core.BlockCommitments{}, su, nil))
+		}
+
+		_, rpcErr := rpc.New(chain, nil, utils.Mainnet, nil, nil, nil, "", nil).StorageProof(rpc.BlockID{Number: 0}, address, keys)
+		require.Equal(t, rpc.ErrStateNotArchived, rpcErr)
+	})
+
+	for _, number := range []uint64{0, 1} {
+		t.Run(fmt.Sprintf("block %d", number), func(t *testing.T) {
+			proof, rpcErr := handler.StorageProof(rpc.BlockID{Number: number}, address, keys)
+			require.Nil(t, rpcErr)
+			assert.Equal(t, number, proof.BlockNumber)
+			if number == 0 {
+				assert.Equal(t, su0.NewRoot, proof.ContractsRoot)
+			}
+
+			leaf := crypto.Pedersen(crypto.Pedersen(crypto.Pedersen(proof.ClassHash, proof.StorageRoot), proof.Nonce), &felt.Zero)
+			assert.True(t, trie.VerifyProof(proof.ContractsRoot, &address, leaf, 251, adaptProofNodes(proof.ContractProof),
+				crypto.Pedersen))
+
+			require.Len(t, proof.Storage, len(keys))
+			for i, value := range proof.Storage {
+				assert.Equal(t, keys[i], *value.Key)
+				if i == len(keys)-1 {
+					assert.True(t, value.Value.IsZero())
+				} else {
+					assert.Equal(t, su0.StateDiff.StorageDiffs[address][keys[i]], value.Value)
+				}
+				assert.True(t, trie.VerifyProof(proof.StorageRoot, value.Key, value.Value, 251, adaptProofNodes(value.Proof),
+					crypto.Pedersen))
+			}
+		})
+	}
+}
+
 func TestClassHashAt(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	t.Cleanup(mockCtrl.Finish)
@@ -2436,7 +2866,32 @@ func TestEvents(t *testing.T) {
 			require.Equal(t, uint64(5), *events.Events[0].BlockNumber)
 			require.Equal(t, utils.HexToFelt(t, "0x3b43b334f46b921938854ba85ffc890c1b1321f8fd69e7b2961b18b4260de14"), events.Events[0].BlockHash)
 			require.Equal(t, utils.HexToFelt(t, "0x6d1431d875ba082365b888c1651e026012a94172b04589c91c2adeb6c1b7ace"), events.Events[0].TransactionHash)
+			require.Equal(t, rpc.TxnAcceptedOnL2, events.Events[0].FinalityStatus)
+		})
+	})
+
+	t.Run("l1 accepted", func(t *testing.T) {
+		t.Run("no l1 head", func(t *testing.T) {
+			args.ToBlock = &rpc.BlockID{L1Accepted: true}
+			_, err := handler.Events(args)
+			require.Equal(t, rpc.ErrBlockNotFound, err)
+		})
+
+		require.NoError(t, chain.SetL1Head(&core.L1Head{BlockNumber: 4}))
+		t.Run("events up to the l1 head", func(t *testing.T) {
+			events, err := handler.Events(args)
+			require.Nil(t, err)
+			require.Empty(t, events.Events)
 		})
+
+		require.NoError(t, chain.SetL1Head(&core.L1Head{BlockNumber: 5}))
+		t.Run("finality status", func(t *testing.T) {
+			events, err := handler.Events(args)
+			require.Nil(t, err)
+			require.Len(t, events.Events, 1)
+			require.Equal(t, rpc.TxnAcceptedOnL1, events.Events[0].FinalityStatus)
+		})
+		args.ToBlock = &rpc.BlockID{Latest: true}
 	})
 
 	t.Run("large page size", func(t *testing.T) {
@@ -2816,6 +3271,70 @@ func TestAddTransaction(t *testing.T) {
 	}
 }
 
+func TestAddTransactionCompiledClassHash(t *testing.T) {
+	const classHash = "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5"
+	testdata := filepath.Join("..", "clients", "feeder", "testdata", "integration")
+	contractClass, err := os.ReadFile(filepath.Join(testdata, "class", classHash+".json"))
+	require.NoError(t, err)
+	compiledClass, err := os.ReadFile(filepath.Join(testdata, "compiled_class", classHash+".json"))
+	require.NoError(t, err)
+	compiledClassHash, err := (&core.Cairo1Class{Compiled: compiledClass}).CompiledClassHash()
+	require.NoError(t, err)
+
+	declare := func(compiledClassHash *felt.Felt) rpc.BroadcastedTransaction {
+		return rpc.BroadcastedTransaction{
+			Transaction: rpc.Transaction{
+				Type:              rpc.TxnDeclare,
+				Version:           new(felt.Felt).SetUint64(2),
+				Nonce:             new(felt.Felt),
+				MaxFee:            new(felt.Felt).SetUint64(1_000_000),
+				SenderAddress:     new(felt.Felt).SetUint64(0x101),
+				Signature:         &[]*felt.Felt{},
+				CompiledClassHash: compiledClassHash,
+			},
+			ContractClass: contractClass,
+		}
+	}
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	mockVM.EXPECT().Compile(gomock.Any()).Return(json.RawMessage(compiledClass), nil).AnyTimes()
+
+	t.Run("matching class is forwarded to the gateway", func(t *testing.T) {
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		mockGateway.EXPECT().AddTransaction(gomock.Any()).Return(json.RawMessage(`{
+			"transaction_hash": "0x1",
+			"class_hash": "0x3"
+		}`), nil).Times(1)
+
+		handler := rpc.New(nil, nil, utils.Integration, mockGateway, nil, mockVM, "", utils.NewNopZapLogger()).
+			WithCompiledClassHashCheck()
+		got, rpcErr := handler.AddTransaction(declare(compiledClassHash))
+		require.Nil(t, rpcErr)
+		assert.Equal(t, utils.HexToFelt(t, "0x3"), got.ClassHash)
+	})
+
+	t.Run("mismatching class is rejected", func(t *testing.T) {
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		handler := rpc.New(nil, nil, utils.Integration, mockGateway, nil, mockVM, "", utils.NewNopZapLogger()).
+			WithCompiledClassHashCheck()
+		_, rpcErr := handler.AddTransaction(declare(new(felt.Felt).SetUint64(1)))
+		assert.Equal(t, rpc.ErrCompiledClassHashMismatch, rpcErr)
+	})
+
+	t.Run("class that does not compile is rejected", func(t *testing.T) {
+		failingVM := mocks.NewMockVM(mockCtrl)
+		failingVM.EXPECT().Compile(gomock.Any()).Return(nil, errors.New("unsupported sierra version"))
+
+		handler := rpc.New(nil, nil, utils.Integration, mocks.NewMockGateway(mockCtrl), nil, failingVM, "",
+			utils.NewNopZapLogger()).WithCompiledClassHashCheck()
+		_, rpcErr := handler.AddTransaction(declare(compiledClassHash))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, rpc.ErrCompilationFailed.Code, rpcErr.Code)
+	})
+}
+
 func TestVersion(t *testing.T) {
 	const version = "1.2.3-rc1"
 
@@ -2940,10 +3459,32 @@ func TestTransactionStatus(t *testing.T) {
 				require.NotNil(t, err)
 				require.Equal(t, err, rpc.ErrTxnHashNotFound)
 			})
+
+			t.Run("transaction gossiped by peers", func(t *testing.T) {
+				mockReader := mocks.NewMockReader(mockCtrl)
+				mockReader.EXPECT().TransactionByHash(test.notFoundTxHash).Return(nil, db.ErrKeyNotFound)
+				handler := rpc.New(mockReader, nil, test.network, nil, client, nil, "", nil)
+				handler.WithTransactionGossip(receivedTransactions{*test.notFoundTxHash: {}})
+
+				status, err := handler.TransactionStatus(ctx, *test.notFoundTxHash)
+				require.Nil(t, err)
+				require.Equal(t, &rpc.TransactionStatus{Finality: rpc.TxnStatusReceived}, status)
+			})
 		})
 	}
 }
 
+type receivedTransactions map[felt.Felt]struct{}
+
+func (r receivedTransactions) GossipTransaction(core.Transaction) error {
+	return nil
+}
+
+func (r receivedTransactions) Received(hash *felt.Felt) bool {
+	_, found := r[*hash]
+	return found
+}
+
 func TestCall(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	t.Cleanup(mockCtrl.Finish)
@@ -2989,6 +3530,80 @@ func TestCall(t *testing.T) {
 	})
 }
 
+func TestDecodedTransactionAndCall(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	handler := rpc.New(mockReader, nil, utils.Mainnet, nil, nil, mockVM, "", utils.NewNopZapLogger())
+
+	classHash := utils.HexToFelt(t, "0xC1A55")
+	counter, unknown := utils.HexToFelt(t, "0xC0DE"), utils.HexToFelt(t, "0xDEAD")
+	class := &core.Cairo1Class{Abi: `[{"type": "function", "name": "increase", "inputs": [{"name": "amount", "type": "core::integer::u64"}],
+		"outputs": [{"type": "core::integer::u64"}], "state_mutability": "external"}]`}
+	mockState.EXPECT().ContractClassHash(counter).Return(classHash, nil).AnyTimes()
+	mockState.EXPECT().ContractClassHash(unknown).Return(nil, db.ErrKeyNotFound).AnyTimes()
+	mockState.EXPECT().Class(classHash).Return(&core.DeclaredClass{Class: class}, nil).AnyTimes()
+
+	t.Run("transaction", func(t *testing.T) {
+		hash := utils.HexToFelt(t, "0x1")
+		// a multicall to increase(5) on the counter and to an unknown contract
+		calldata := []*felt.Felt{
+			new(felt.Felt).SetUint64(2),
+			counter, abi.Selector("increase"), new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(5),
+			unknown, abi.Selector("increase"), &felt.Zero,
+		}
+		mockReader.EXPECT().TransactionByHash(hash).Return(&core.InvokeTransaction{
+			TransactionHash: hash,
+			Version:         new(core.TransactionVersion).SetUint64(1),
+			SenderAddress:   utils.HexToFelt(t, "0xACC"),
+			CallData:        calldata,
+		}, nil)
+		mockReader.EXPECT().Receipt(hash).Return(nil, utils.HexToFelt(t, "0xB"), uint64(7), nil)
+		mockReader.EXPECT().StateAtBlockNumber(uint64(7)).Return(mockState, nopCloser, nil)
+
+		txn, rpcErr := handler.DecodedTransactionByHash(*hash)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, hash, txn.Hash)
+		assert.Equal(t, []rpc.DecodedCall{
+			{
+				ContractAddress:    counter,
+				EntryPointSelector: abi.Selector("increase"),
+				Calldata:           calldata[4:5],
+				Function:           "increase",
+				Arguments:          abi.Struct{{Name: "amount", Value: calldata[4]}},
+			},
+			{
+				ContractAddress:    unknown,
+				EntryPointSelector: abi.Selector("increase"),
+				Calldata:           []*felt.Felt{},
+			},
+		}, txn.Calls)
+
+		mockReader.EXPECT().TransactionByHash(hash).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr = handler.DecodedTransactionByHash(*hash)
+		require.Equal(t, rpc.ErrTxnHashNotFound, rpcErr)
+	})
+
+	t.Run("call", func(t *testing.T) {
+		result := []*felt.Felt{new(felt.Felt).SetUint64(6)}
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil).Times(2)
+		mockReader.EXPECT().HeadsHeader().Return(&core.Header{Number: 7}, nil)
+		mockVM.EXPECT().Call(counter, classHash, abi.Selector("increase"), gomock.Any(), uint64(7), gomock.Any(),
+			mockState, utils.Mainnet).Return(result, nil)
+
+		callResult, rpcErr := handler.DecodedCall(rpc.FunctionCall{
+			ContractAddress:    *counter,
+			EntryPointSelector: *abi.Selector("increase"),
+			Calldata:           []felt.Felt{*new(felt.Felt).SetUint64(1)},
+		}, rpc.BlockID{Latest: true})
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.DecodedCallResult{Result: result, Decoded: []any{result[0]}}, callResult)
+	})
+}
+
 func TestEstimateMessageFee(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	t.Cleanup(mockCtrl.Finish)
@@ -48,3 +48,30 @@ type DeclaredClass struct {
 	ClassHash         felt.Felt `json:"class_hash"`
 	CompiledClassHash felt.Felt `json:"compiled_class_hash"`
 }
+
+type ValueChange struct {
+	BlockNumber uint64     `json:"block_number"`
+	OldValue    *felt.Felt `json:"old_value"`
+	NewValue    *felt.Felt `json:"new_value"`
+}
+
+type ValueChangesChunk struct {
+	Changes           []*ValueChange `json:"changes"`
+	ContinuationToken string         `json:"continuation_token,omitempty"`
+}
+
+type StorageRangeChunk struct {
+	Storage           []Entry `json:"storage"`
+	ContinuationToken string  `json:"continuation_token,omitempty"`
+}
+
+type ContractEntry struct {
+	Address   felt.Felt `json:"address"`
+	ClassHash felt.Felt `json:"class_hash"`
+	Nonce     felt.Felt `json:"nonce"`
+}
+
+type ContractsRangeChunk struct {
+	Contracts         []ContractEntry `json:"contracts"`
+	ContinuationToken string          `json:"continuation_token,omitempty"`
+}
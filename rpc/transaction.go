@@ -341,6 +341,18 @@ func (r *ExecutionResources) MarshalJSON() ([]byte, error) {
 	return json.Marshal(resources(*r))
 }
 
+// AccountTransaction is a transaction sent by, or deploying, an account along with its position in the chain
+type AccountTransaction struct {
+	BlockNumber      uint64       `json:"block_number"`
+	TransactionIndex uint64       `json:"transaction_index"`
+	Transaction      *Transaction `json:"transaction"`
+}
+
+type AccountTransactionsChunk struct {
+	Transactions      []*AccountTransaction `json:"transactions"`
+	ContinuationToken string                `json:"continuation_token,omitempty"`
+}
+
 // https://github.com/starkware-libs/starknet-specs/blob/master/api/starknet_api_openrpc.json#L1871
 type TransactionReceipt struct {
 	Type               TransactionType     `json:"type"`
@@ -0,0 +1,145 @@
+package sequencer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+)
+
+// Fee token addresses are the same on every public network.
+const (
+	ethFeeTokenAddress  = "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
+	strkFeeTokenAddress = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+)
+
+// The cheat methods below change the pending state directly, without a transaction. The changes are
+// visible to transactions and queries on the pending block straight away and are stored with the next block.
+
+// SetStorageAt sets the value of a storage slot of a deployed contract.
+func (s *Sequencer) SetStorageAt(address, key, value felt.Felt) (bool, *jsonrpc.Error) {
+	return s.cheat(func(state core.StateReader, stateDiff *core.StateDiff) *jsonrpc.Error {
+		if _, err := state.ContractClassHash(&address); err != nil {
+			return rpc.ErrContractNotFound
+		}
+		setStorage(stateDiff, &address, &key, &value)
+		return nil
+	})
+}
+
+// SetNonce sets the nonce of a deployed contract.
+func (s *Sequencer) SetNonce(address, nonce felt.Felt) (bool, *jsonrpc.Error) {
+	return s.cheat(func(state core.StateReader, stateDiff *core.StateDiff) *jsonrpc.Error {
+		if _, err := state.ContractClassHash(&address); err != nil {
+			return rpc.ErrContractNotFound
+		}
+		stateDiff.Nonces[address] = nonce.Clone()
+		return nil
+	})
+}
+
+// SetClassHash replaces the class of a contract, or deploys a contract of the given class at the address if there is
+// none. The class must already be declared.
+func (s *Sequencer) SetClassHash(address, classHash felt.Felt) (bool, *jsonrpc.Error) {
+	return s.cheat(func(state core.StateReader, stateDiff *core.StateDiff) *jsonrpc.Error {
+		if _, err := state.Class(&classHash); err != nil {
+			return rpc.ErrClassHashNotFound
+		}
+
+		_, err := state.ContractClassHash(&address)
+		switch {
+		case err == nil:
+			if _, ok := stateDiff.DeployedContracts[address]; ok {
+				stateDiff.DeployedContracts[address] = classHash.Clone()
+			} else {
+				stateDiff.ReplacedClasses[address] = classHash.Clone()
+			}
+		case errors.Is(err, db.ErrKeyNotFound):
+			stateDiff.DeployedContracts[address] = classHash.Clone()
+		default:
+			return jsonrpc.Err(jsonrpc.InternalError, err.Error())
+		}
+		return nil
+	})
+}
+
+// SetBalance sets the balance of an address in the fee token of the given unit, WEI for ETH and FRI for STRK.
+// The unit defaults to WEI.
+func (s *Sequencer) SetBalance(address, amount felt.Felt, unit string) (bool, *jsonrpc.Error) {
+	var tokenAddress string
+	switch unit {
+	case "", "WEI":
+		tokenAddress = ethFeeTokenAddress
+	case "FRI":
+		tokenAddress = strkFeeTokenAddress
+	default:
+		return false, jsonrpc.Err(jsonrpc.InvalidParams, fmt.Sprintf("unknown unit %q", unit))
+	}
+	token, err := new(felt.Felt).SetString(tokenAddress)
+	if err != nil {
+		return false, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+
+	return s.cheat(func(state core.StateReader, stateDiff *core.StateDiff) *jsonrpc.Error {
+		if _, err := state.ContractClassHash(token); err != nil {
+			return rpc.ErrContractNotFound
+		}
+
+		// Balances are stored as u256 values in the ERC20_balances mapping, low 128 bits first.
+		varAddress, err := crypto.StarknetKeccak([]byte("ERC20_balances"))
+		if err != nil {
+			return jsonrpc.Err(jsonrpc.InternalError, err.Error())
+		}
+		lowKey := crypto.Pedersen(varAddress, &address)
+		highKey := new(felt.Felt).Add(lowKey, new(felt.Felt).SetUint64(1))
+
+		amountBytes := amount.Bytes()
+		setStorage(stateDiff, token, lowKey, new(felt.Felt).SetBytes(amountBytes[16:]))
+		setStorage(stateDiff, token, highKey, new(felt.Felt).SetBytes(amountBytes[:16]))
+		return nil
+	})
+}
+
+// cheat applies a change to the pending state diff and publishes the updated pending block.
+func (s *Sequencer) cheat(apply func(state core.StateReader, stateDiff *core.StateDiff) *jsonrpc.Error) (bool, *jsonrpc.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		return false, jsonrpc.Err(jsonrpc.InternalError, errNotRunning.Error())
+	}
+
+	headState, headCloser, err := s.chain.HeadState()
+	if err != nil {
+		return false, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+	defer func() {
+		if closeErr := headCloser(); closeErr != nil {
+			s.log.Warnw("Failed to close head state", "err", closeErr)
+		}
+	}()
+
+	stateDiff := s.pending.StateUpdate.StateDiff
+	if rpcErr := apply(blockchain.NewPendingState(stateDiff, s.pending.NewClasses, headState), stateDiff); rpcErr != nil {
+		return false, rpcErr
+	}
+	if err = s.storePending(); err != nil {
+		return false, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+	return true, nil
+}
+
+func setStorage(stateDiff *core.StateDiff, address, key, value *felt.Felt) {
+	storage, ok := stateDiff.StorageDiffs[*address]
+	if !ok {
+		storage = make(map[felt.Felt]*felt.Felt)
+		stateDiff.StorageDiffs[*address] = storage
+	}
+	storage[*key] = value.Clone()
+}
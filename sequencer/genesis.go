@@ -0,0 +1,154 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+)
+
+// GenesisConfig describes the state of a devnet at block 0: the classes it declares and the
+// contracts, such as accounts and fee tokens, it deploys with their storage already set.
+//
+// Relative class paths are resolved against the directory of the genesis file. Classes are stored in the
+// format returned by the feeder gateway's get_class_by_hash endpoint.
+type GenesisConfig struct {
+	Classes []GenesisClass `json:"classes"`
+	// Contracts maps the address of each predeployed contract to its class and state.
+	Contracts map[string]GenesisContract `json:"contracts"`
+}
+
+type GenesisClass struct {
+	Path string `json:"path"`
+	// Optional hash of the class a Sierra class compiles to, which its compiled class must hash to.
+	CompiledClassHash *felt.Felt `json:"compiled_class_hash,omitempty"`
+	// Path to the compiled class of a Sierra class, in the format returned by the feeder gateway's
+	// get_compiled_class_by_class_hash endpoint. Sierra classes must have one.
+	CompiledPath string `json:"compiled_path,omitempty"`
+}
+
+type GenesisContract struct {
+	ClassHash *felt.Felt            `json:"class_hash"`
+	Nonce     *felt.Felt            `json:"nonce,omitempty"`
+	Storage   map[string]*felt.Felt `json:"storage,omitempty"`
+}
+
+// LoadGenesis reads a genesis config file and the classes it refers to, and returns the
+// state diff and classes of the genesis block.
+func LoadGenesis(path string) (*core.StateDiff, map[felt.Felt]core.Class, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config GenesisConfig
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("decode genesis config: %v", err)
+	}
+	return config.build(filepath.Dir(path))
+}
+
+func (c *GenesisConfig) build(dir string) (*core.StateDiff, map[felt.Felt]core.Class, error) {
+	stateDiff := core.EmptyStateDiff()
+	newClasses := make(map[felt.Felt]core.Class, len(c.Classes))
+
+	for _, genesisClass := range c.Classes {
+		class, err := genesisClass.load(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load class %s: %v", genesisClass.Path, err)
+		}
+
+		classHash, err := class.Hash()
+		if err != nil {
+			return nil, nil, err
+		}
+		newClasses[*classHash] = class
+
+		if cairo1Class, ok := class.(*core.Cairo1Class); ok {
+			compiledClassHash, err := cairo1Class.CompiledClassHash()
+			if err != nil {
+				return nil, nil, fmt.Errorf("class %s: %v", genesisClass.Path, err)
+			}
+			if genesisClass.CompiledClassHash != nil && !genesisClass.CompiledClassHash.Equal(compiledClassHash) {
+				return nil, nil, fmt.Errorf("class %s: compiled class hash %s does not match the class, which compiles to %s",
+					genesisClass.Path, genesisClass.CompiledClassHash, compiledClassHash)
+			}
+			stateDiff.DeclaredV1Classes[*classHash] = compiledClassHash
+		} else {
+			stateDiff.DeclaredV0Classes = append(stateDiff.DeclaredV0Classes, classHash)
+		}
+	}
+
+	for addrStr, contract := range c.Contracts {
+		addr, err := new(felt.Felt).SetString(addrStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("contract address %q: %v", addrStr, err)
+		}
+		if contract.ClassHash == nil {
+			return nil, nil, fmt.Errorf("contract %s: missing class hash", addrStr)
+		}
+		if _, ok := newClasses[*contract.ClassHash]; !ok {
+			return nil, nil, fmt.Errorf("contract %s: class %s is not declared in genesis", addrStr, contract.ClassHash)
+		}
+
+		stateDiff.DeployedContracts[*addr] = contract.ClassHash
+		if contract.Nonce != nil {
+			stateDiff.Nonces[*addr] = contract.Nonce
+		}
+		if len(contract.Storage) == 0 {
+			continue
+		}
+
+		storage := make(map[felt.Felt]*felt.Felt, len(contract.Storage))
+		for keyStr, value := range contract.Storage {
+			key, err := new(felt.Felt).SetString(keyStr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("contract %s: storage key %q: %v", addrStr, keyStr, err)
+			}
+			storage[*key] = value
+		}
+		stateDiff.StorageDiffs[*addr] = storage
+	}
+	return stateDiff, newClasses, nil
+}
+
+func (c *GenesisClass) load(dir string) (core.Class, error) {
+	data, err := os.ReadFile(resolve(dir, c.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	var definition starknet.ClassDefinition
+	if err = json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case definition.V1 != nil:
+		if c.CompiledPath == "" {
+			return nil, errors.New("missing compiled class")
+		}
+		compiledClass, err := os.ReadFile(resolve(dir, c.CompiledPath))
+		if err != nil {
+			return nil, err
+		}
+		return sn2core.AdaptCairo1Class(definition.V1, compiledClass)
+	case definition.V0 != nil:
+		return sn2core.AdaptCairo0Class(definition.V0)
+	default:
+		return nil, errors.New("empty class")
+	}
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,181 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/rpc"
+)
+
+// makeReceipt builds the receipt of a transaction from the fee it paid and its execution trace.
+func makeReceipt(txn core.Transaction, fee *felt.Felt, trace *rpc.TransactionTrace) *core.TransactionReceipt {
+	receipt := &core.TransactionReceipt{
+		Fee:                fee,
+		FeeUnit:            core.WEI,
+		Events:             []*core.Event{},
+		ExecutionResources: new(core.ExecutionResources),
+		L2ToL1Message:      []*core.L2ToL1Message{},
+		TransactionHash:    txn.Hash(),
+	}
+	if txn.TxVersion().Is(3) {
+		receipt.FeeUnit = core.STRK
+	}
+
+	var executeInvocation *rpc.FunctionInvocation
+	if trace.ExecuteInvocation != nil {
+		receipt.RevertReason = trace.ExecuteInvocation.RevertReason
+		receipt.Reverted = receipt.RevertReason != ""
+		executeInvocation = trace.ExecuteInvocation.FunctionInvocation
+	}
+
+	// Events and messages are listed in the order their calls are executed. The constructor of a deployed
+	// account runs before its validation.
+	for _, invocation := range []*rpc.FunctionInvocation{
+		trace.ConstructorInvocation,
+		trace.ValidateInvocation,
+		executeInvocation,
+		trace.FunctionInvocation,
+		trace.FeeTransferInvocation,
+	} {
+		if invocation == nil {
+			continue
+		}
+
+		receipt.Events = append(receipt.Events, invocationEvents(invocation)...)
+		receipt.L2ToL1Message = append(receipt.L2ToL1Message, invocationMessages(invocation)...)
+		addExecutionResources(receipt.ExecutionResources, invocation.ExecutionResources)
+	}
+	return receipt
+}
+
+type orderedEvent struct {
+	order uint64
+	event *core.Event
+}
+
+// invocationEvents returns the events emitted by a call and its inner calls in emission order.
+func invocationEvents(invocation *rpc.FunctionInvocation) []*core.Event {
+	var ordered []orderedEvent
+	var collect func(invocation *rpc.FunctionInvocation)
+	collect = func(invocation *rpc.FunctionInvocation) {
+		for _, event := range invocation.Events {
+			ordered = append(ordered, orderedEvent{
+				order: event.Order,
+				event: &core.Event{
+					From: invocation.ContractAddress.Clone(),
+					Keys: event.Keys,
+					Data: event.Data,
+				},
+			})
+		}
+		for i := range invocation.Calls {
+			collect(&invocation.Calls[i])
+		}
+	}
+	collect(invocation)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].order < ordered[j].order
+	})
+
+	events := make([]*core.Event, len(ordered))
+	for i := range ordered {
+		events[i] = ordered[i].event
+	}
+	return events
+}
+
+type orderedMessage struct {
+	order   uint64
+	message *core.L2ToL1Message
+}
+
+// invocationMessages returns the messages sent to L1 by a call and its inner calls in the order they were sent.
+func invocationMessages(invocation *rpc.FunctionInvocation) []*core.L2ToL1Message {
+	var ordered []orderedMessage
+	var collect func(invocation *rpc.FunctionInvocation)
+	collect = func(invocation *rpc.FunctionInvocation) {
+		for _, msg := range invocation.Messages {
+			ordered = append(ordered, orderedMessage{
+				order: msg.Order,
+				message: &core.L2ToL1Message{
+					From:    invocation.ContractAddress.Clone(),
+					Payload: msg.Payload,
+					To:      msg.To,
+				},
+			})
+		}
+		for i := range invocation.Calls {
+			collect(&invocation.Calls[i])
+		}
+	}
+	collect(invocation)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].order < ordered[j].order
+	})
+
+	messages := make([]*core.L2ToL1Message, len(ordered))
+	for i := range ordered {
+		messages[i] = ordered[i].message
+	}
+	return messages
+}
+
+func addExecutionResources(total *core.ExecutionResources, resources *rpc.ExecutionResources) {
+	if resources == nil {
+		return
+	}
+
+	total.Steps += resources.Steps
+	total.MemoryHoles += resources.MemoryHoles
+	total.BuiltinInstanceCounter.Pedersen += resources.Pedersen
+	total.BuiltinInstanceCounter.RangeCheck += resources.RangeCheck
+	total.BuiltinInstanceCounter.Bitwise += resources.Bitwise
+	total.BuiltinInstanceCounter.Ecsda += resources.Ecsda
+	total.BuiltinInstanceCounter.EcOp += resources.EcOp
+	total.BuiltinInstanceCounter.Keccak += resources.Keccak
+	total.BuiltinInstanceCounter.Poseidon += resources.Poseidon
+	total.BuiltinInstanceCounter.SegmentArena += resources.SegmentArena
+}
+
+// mergeStateDiff applies the state changes of a single transaction to the state diff of the block being built.
+func mergeStateDiff(stateDiff *core.StateDiff, txDiff *rpc.StateDiff) {
+	if txDiff == nil {
+		return
+	}
+
+	for _, diff := range txDiff.StorageDiffs {
+		storage, ok := stateDiff.StorageDiffs[diff.Address]
+		if !ok {
+			storage = make(map[felt.Felt]*felt.Felt, len(diff.StorageEntries))
+			stateDiff.StorageDiffs[diff.Address] = storage
+		}
+		for _, entry := range diff.StorageEntries {
+			storage[entry.Key] = entry.Value.Clone()
+		}
+	}
+	for _, nonce := range txDiff.Nonces {
+		stateDiff.Nonces[nonce.ContractAddress] = nonce.Nonce.Clone()
+	}
+	for _, deployed := range txDiff.DeployedContracts {
+		stateDiff.DeployedContracts[deployed.Address] = deployed.ClassHash.Clone()
+	}
+	stateDiff.DeclaredV0Classes = append(stateDiff.DeclaredV0Classes, txDiff.DeprecatedDeclaredClasses...)
+	for _, declared := range txDiff.DeclaredClasses {
+		stateDiff.DeclaredV1Classes[declared.ClassHash] = declared.CompiledClassHash.Clone()
+	}
+	for _, replaced := range txDiff.ReplacedClasses {
+		stateDiff.ReplacedClasses[replaced.ContractAddress] = replaced.ClassHash.Clone()
+	}
+}
+
+func decodeTrace(traceJSON json.RawMessage) (*rpc.TransactionTrace, error) {
+	trace := new(rpc.TransactionTrace)
+	if err := json.Unmarshal(traceJSON, trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
@@ -0,0 +1,592 @@
+package sequencer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	stdsync "sync"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/gateway"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/feed"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+)
+
+var (
+	_ service.Service = (*Sequencer)(nil)
+	_ sync.Reader     = (*Sequencer)(nil)
+	_ rpc.Gateway     = (*Sequencer)(nil)
+)
+
+// defaultGasPrice is the price of gas and data gas, in both WEI and FRI, that the sequencer charges.
+var defaultGasPrice = new(felt.Felt).SetUint64(1_000_000_000)
+
+var errNotRunning = errors.New("sequencer is not running")
+
+// Sequencer runs a local devnet. Transactions received through AddTransaction are executed
+// against the head state as they arrive and collected in a pending block, which is stored as
+// a new block on a fixed interval or on demand.
+type Sequencer struct {
+	address   *felt.Felt
+	blockTime time.Duration
+	chain     *blockchain.Blockchain
+	vm        vm.VM
+	log       utils.SimpleLogger
+	newHeads  *feed.Feed[*core.Header]
+
+	genesis        *core.StateDiff
+	genesisClasses map[felt.Felt]core.Class
+	forkHeight     *uint64
+
+	mu      stdsync.Mutex
+	pending *blockchain.Pending
+}
+
+// New creates a sequencer that builds blocks on top of chain. If blockTime is zero, blocks
+// are only built on demand.
+func New(chain *blockchain.Blockchain, virtualMachine vm.VM, address *felt.Felt, blockTime time.Duration,
+	log utils.SimpleLogger,
+) *Sequencer {
+	return &Sequencer{
+		address:   address,
+		blockTime: blockTime,
+		chain:     chain,
+		vm:        virtualMachine,
+		log:       log,
+		newHeads:  feed.New[*core.Header](),
+	}
+}
+
+// WithGenesis sets the state the sequencer stores in the genesis block when it starts on an empty database.
+func (s *Sequencer) WithGenesis(stateDiff *core.StateDiff, newClasses map[felt.Felt]core.Class) *Sequencer {
+	s.genesis = stateDiff
+	s.genesisClasses = newClasses
+	return s
+}
+
+// WithForkHeight makes the sequencer build on top of an existing chain, such as a mainnet database
+// layered under an overlay database, from the given block onwards. Blocks of the existing chain
+// above the fork height are reverted when the sequencer starts.
+func (s *Sequencer) WithForkHeight(height uint64) *Sequencer {
+	s.forkHeight = &height
+	return s
+}
+
+// Run stores the genesis block if the chain is empty and then builds a block every block time
+// until ctx is cancelled. Blocks are only built on an interval if they contain transactions.
+func (s *Sequencer) Run(ctx context.Context) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	if s.blockTime == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(s.blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := s.buildBlock(false); err != nil {
+				s.log.Errorw("Failed to build block", "err", err)
+			}
+		}
+	}
+}
+
+func (s *Sequencer) init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.chain.Height()
+	if err == nil {
+		if s.forkHeight != nil {
+			if err = s.fork(*s.forkHeight); err != nil {
+				return err
+			}
+		}
+		return s.resetPending()
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	} else if s.forkHeight != nil {
+		return errors.New("cannot fork an empty chain")
+	}
+
+	if err = s.resetPending(); err != nil {
+		return err
+	}
+	if s.genesis != nil {
+		s.pending.StateUpdate.StateDiff = s.genesis
+		s.pending.NewClasses = s.genesisClasses
+	}
+	_, err = s.finalise()
+	return err
+}
+
+// fork reverts the blocks of the base chain above the fork height. Blocks built by the sequencer are
+// kept, so that a restarted sequencer continues the chain it built before. The caller must hold s.mu.
+func (s *Sequencer) fork(height uint64) error {
+	for {
+		head, err := s.chain.HeadsHeader()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		// early blocks of the networks have no sequencer address
+		case head.SequencerAddress != nil && head.SequencerAddress.Equal(s.address) && head.Number > height:
+			return nil
+		case head.Number < height:
+			return fmt.Errorf("cannot fork at block %d, the chain is at block %d", height, head.Number)
+		case head.Number == height:
+			s.log.Infow("Forked chain", "number", head.Number, "hash", head.Hash.ShortString())
+			return nil
+		}
+
+		if err = s.chain.RevertHead(); err != nil {
+			return err
+		}
+	}
+}
+
+// BuildBlock stores the pending block, even if it is empty, and starts a new one on top of it.
+func (s *Sequencer) BuildBlock() (*core.Header, error) {
+	return s.buildBlock(true)
+}
+
+func (s *Sequencer) buildBlock(allowEmpty bool) (*core.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		return nil, errNotRunning
+	}
+	if !allowEmpty && len(s.pending.Block.Transactions) == 0 {
+		return nil, nil
+	}
+	return s.finalise()
+}
+
+// finalise stores the pending block and starts a new one. The caller must hold s.mu.
+func (s *Sequencer) finalise() (*core.Header, error) {
+	if err := s.chain.Finalise(s.pending); err != nil {
+		return nil, err
+	}
+
+	header := s.pending.Block.Header
+	s.newHeads.Send(header)
+	s.log.Infow("Built block", "number", header.Number, "hash", header.Hash.ShortString(),
+		"transactions", header.TransactionCount)
+	return header, s.resetPending()
+}
+
+// resetPending starts a new, empty pending block on top of the head. The caller must hold s.mu.
+func (s *Sequencer) resetPending() error {
+	header := &core.Header{
+		ParentHash:       &felt.Zero,
+		SequencerAddress: s.address,
+		Timestamp:        uint64(time.Now().Unix()),
+		ProtocolVersion:  blockchain.SupportedStarknetVersion.String(),
+		GasPrice:         defaultGasPrice,
+		GasPriceSTRK:     defaultGasPrice,
+		L1DataGasPrice:   &core.GasPrice{PriceInWei: defaultGasPrice, PriceInFri: defaultGasPrice},
+	}
+	oldRoot := &felt.Zero
+
+	head, err := s.chain.HeadsHeader()
+	if err == nil {
+		header.ParentHash = head.Hash
+		header.Number = head.Number + 1
+		header.Timestamp = max(header.Timestamp, head.Timestamp)
+		oldRoot = head.GlobalStateRoot
+		// Keep charging the gas prices of a forked chain.
+		if head.GasPrice != nil {
+			header.GasPrice = head.GasPrice
+		}
+		if head.GasPriceSTRK != nil {
+			header.GasPriceSTRK = head.GasPriceSTRK
+		}
+		if head.L1DataGasPrice != nil {
+			header.L1DataGasPrice = head.L1DataGasPrice
+		}
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
+	stateDiff, err := blockchain.MakeStateDiffForEmptyBlock(s.chain, header.Number)
+	if err != nil {
+		return err
+	}
+
+	s.pending = &blockchain.Pending{
+		Block: &core.Block{
+			Header:       header,
+			Transactions: []core.Transaction{},
+			Receipts:     []*core.TransactionReceipt{},
+		},
+		StateUpdate: &core.StateUpdate{
+			OldRoot:   oldRoot,
+			StateDiff: stateDiff,
+		},
+		NewClasses: make(map[felt.Felt]core.Class),
+	}
+	return nil
+}
+
+// AddTransaction executes a transaction in the gateway's format and adds it to the pending block.
+// Transactions that fail validation are rejected with a gateway error.
+func (s *Sequencer) AddTransaction(txnJSON json.RawMessage) (json.RawMessage, error) {
+	txn, class, err := s.adaptTransaction(txnJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		return nil, errNotRunning
+	}
+	if err = s.checkDuplicate(txn); err != nil {
+		return nil, err
+	}
+	if err = s.execute(txn, class); err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		Code            string     `json:"code"`
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+		ContractAddress *felt.Felt `json:"address,omitempty"`
+		ClassHash       *felt.Felt `json:"class_hash,omitempty"`
+	}{
+		Code:            "TRANSACTION_RECEIVED",
+		TransactionHash: txn.Hash(),
+	}
+	switch t := txn.(type) {
+	case *core.DeployAccountTransaction:
+		response.ContractAddress = t.ContractAddress
+	case *core.DeclareTransaction:
+		response.ClassHash = t.ClassHash
+	}
+	return json.Marshal(response)
+}
+
+// adaptTransaction decodes a transaction in the gateway's format and computes its hash.
+func (s *Sequencer) adaptTransaction(txnJSON json.RawMessage) (core.Transaction, core.Class, error) {
+	var request struct {
+		*starknet.Transaction
+		ContractClass json.RawMessage `json:"contract_class,omitempty"`
+	}
+	if err := json.Unmarshal(txnJSON, &request); err != nil {
+		return nil, nil, err
+	}
+	if request.Transaction == nil {
+		return nil, nil, errors.New("empty transaction")
+	}
+
+	txn, err := sn2core.AdaptTransaction(request.Transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var class core.Class
+	if declare, ok := txn.(*core.DeclareTransaction); ok {
+		if class, err = adaptDeclaredClass(request.ContractClass); err != nil {
+			return nil, nil, &gateway.Error{Code: gateway.InvalidContractClass, Message: err.Error()}
+		}
+		if declare.ClassHash, err = class.Hash(); err != nil {
+			return nil, nil, err
+		}
+		if cairo1Class, ok := class.(*core.Cairo1Class); ok {
+			if err = s.compile(cairo1Class, declare.CompiledClassHash); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	txnHash, err := core.TransactionHash(txn, s.chain.Network())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch t := txn.(type) {
+	case *core.DeclareTransaction:
+		t.TransactionHash = txnHash
+	case *core.InvokeTransaction:
+		t.TransactionHash = txnHash
+	case *core.DeployAccountTransaction:
+		t.TransactionHash = txnHash
+	default:
+		return nil, nil, &gateway.Error{
+			Code:    gateway.InvalidTransactionVersion,
+			Message: fmt.Sprintf("unsupported transaction type %T", txn),
+		}
+	}
+	return txn, class, nil
+}
+
+// adaptDeclaredClass decodes a class in the gateway's format, where the programs of both Cairo 0
+// and Sierra classes are gzip compressed and base64 encoded.
+func adaptDeclaredClass(classJSON json.RawMessage) (core.Class, error) {
+	if len(classJSON) == 0 {
+		return nil, errors.New("declare without a class definition")
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(classJSON, &fields); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"program", "sierra_program"} {
+		encoded, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		var program string
+		if err := json.Unmarshal(encoded, &program); err != nil {
+			return nil, fmt.Errorf("%s is not an encoded program: %v", key, err)
+		}
+		decoded, err := utils.Gzip64Decode(program)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = decoded
+	}
+
+	decodedJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition starknet.ClassDefinition
+	if err = json.Unmarshal(decodedJSON, &definition); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case definition.V1 != nil:
+		return sn2core.AdaptCairo1Class(definition.V1, nil)
+	case definition.V0 != nil:
+		return sn2core.AdaptCairo0Class(definition.V0)
+	default:
+		return nil, errors.New("empty class")
+	}
+}
+
+// compile compiles a declared Sierra class to CASM, which must hash to the compiled class hash of the declare.
+func (s *Sequencer) compile(class *core.Cairo1Class, compiledClassHash *felt.Felt) error {
+	if compiledClassHash == nil {
+		return &gateway.Error{Code: gateway.InvalidCompiledClassHash, Message: "missing compiled class hash"}
+	}
+
+	compiled, err := s.vm.Compile(class)
+	if err != nil {
+		return &gateway.Error{Code: gateway.CompilationFailed, Message: err.Error()}
+	}
+	class.Compiled = compiled
+
+	hash, err := class.CompiledClassHash()
+	if err != nil {
+		return &gateway.Error{Code: gateway.CompilationFailed, Message: err.Error()}
+	}
+	if !hash.Equal(compiledClassHash) {
+		return &gateway.Error{
+			Code:    gateway.InvalidCompiledClassHash,
+			Message: fmt.Sprintf("compiled class hash %s does not match the class, which compiles to %s", compiledClassHash, hash),
+		}
+	}
+	return nil
+}
+
+// checkDuplicate rejects transactions that are already in the pending block or the chain. The caller must hold s.mu.
+func (s *Sequencer) checkDuplicate(txn core.Transaction) error {
+	duplicate := &gateway.Error{
+		Code:    gateway.DuplicatedTransaction,
+		Message: fmt.Sprintf("transaction %s already exists", txn.Hash()),
+	}
+
+	for _, pendingTxn := range s.pending.Block.Transactions {
+		if pendingTxn.Hash().Equal(txn.Hash()) {
+			return duplicate
+		}
+	}
+
+	_, err := s.chain.TransactionByHash(txn.Hash())
+	if err == nil {
+		return duplicate
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	return nil
+}
+
+// execute runs a transaction on top of the pending state and adds it, its receipt and its state
+// changes to the pending block. The caller must hold s.mu.
+func (s *Sequencer) execute(txn core.Transaction, class core.Class) error {
+	headState, headCloser, err := s.chain.HeadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := headCloser(); closeErr != nil {
+			s.log.Warnw("Failed to close head state", "err", closeErr)
+		}
+	}()
+
+	state := blockchain.NewPendingState(s.pending.StateUpdate.StateDiff, s.pending.NewClasses, headState)
+
+	var declaredClasses []core.Class
+	if declare, ok := txn.(*core.DeclareTransaction); ok {
+		if _, err = state.Class(declare.ClassHash); err == nil {
+			return &gateway.Error{
+				Code:    gateway.ClassAlreadyDeclared,
+				Message: fmt.Sprintf("class %s is already declared", declare.ClassHash),
+			}
+		} else if !errors.Is(err, db.ErrKeyNotFound) {
+			return err
+		}
+		declaredClasses = []core.Class{class}
+	}
+
+	header := s.pending.Block.Header
+	fees, traces, err := s.vm.Execute([]core.Transaction{txn}, declaredClasses, header.Number, header.Timestamp,
+		s.address, state, s.chain.Network(), nil, false, false, false, header.GasPrice, header.GasPriceSTRK, false)
+	if err != nil {
+		var executionErr vm.TransactionExecutionError
+		if errors.As(err, &executionErr) {
+			return &gateway.Error{Code: gateway.ValidateFailure, Message: executionErr.Cause.Error()}
+		}
+		return err
+	}
+	if len(fees) != 1 || len(traces) != 1 {
+		return fmt.Errorf("expected 1 fee and trace, got %d and %d", len(fees), len(traces))
+	}
+
+	trace, err := decodeTrace(traces[0])
+	if err != nil {
+		return fmt.Errorf("decode trace: %v", err)
+	}
+
+	mergeStateDiff(s.pending.StateUpdate.StateDiff, trace.StateDiff)
+	if class != nil {
+		classHash, hashErr := class.Hash()
+		if hashErr != nil {
+			return hashErr
+		}
+		s.pending.NewClasses[*classHash] = class
+	}
+
+	block := s.pending.Block
+	block.Transactions = append(block.Transactions, txn)
+	block.Receipts = append(block.Receipts, makeReceipt(txn, fees[0], trace))
+	block.TransactionCount = uint64(len(block.Transactions))
+	return s.storePending()
+}
+
+// storePending makes the transactions executed so far visible to pending block and pending state
+// queries. The blockchain keeps a copy, since the sequencer keeps adding to its pending block.
+// The caller must hold s.mu.
+func (s *Sequencer) storePending() error {
+	header := *s.pending.Block.Header
+	stateDiff := *s.pending.StateUpdate.StateDiff
+	stateDiff.StorageDiffs = make(map[felt.Felt]map[felt.Felt]*felt.Felt, len(s.pending.StateUpdate.StateDiff.StorageDiffs))
+	for addr, storage := range s.pending.StateUpdate.StateDiff.StorageDiffs {
+		stateDiff.StorageDiffs[addr] = maps.Clone(storage)
+	}
+	stateDiff.Nonces = maps.Clone(stateDiff.Nonces)
+	stateDiff.DeployedContracts = maps.Clone(stateDiff.DeployedContracts)
+	stateDiff.DeclaredV0Classes = slices.Clone(stateDiff.DeclaredV0Classes)
+	stateDiff.DeclaredV1Classes = maps.Clone(stateDiff.DeclaredV1Classes)
+	stateDiff.ReplacedClasses = maps.Clone(stateDiff.ReplacedClasses)
+
+	return s.chain.ReplacePending(&blockchain.Pending{
+		Block: &core.Block{
+			Header:       &header,
+			Transactions: slices.Clone(s.pending.Block.Transactions),
+			Receipts:     slices.Clone(s.pending.Block.Receipts),
+		},
+		StateUpdate: &core.StateUpdate{
+			OldRoot:   s.pending.StateUpdate.OldRoot,
+			StateDiff: &stateDiff,
+		},
+		NewClasses: maps.Clone(s.pending.NewClasses),
+	})
+}
+
+// StartingBlockNumber returns the number of the genesis block, which is where the sequencer starts building.
+func (s *Sequencer) StartingBlockNumber() (uint64, error) {
+	return 0, nil
+}
+
+// HighestBlockHeader returns the header of the latest block built by the sequencer.
+func (s *Sequencer) HighestBlockHeader() *core.Header {
+	header, err := s.chain.HeadsHeader()
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (s *Sequencer) SubscribeNewHeads() sync.HeaderSubscription {
+	return sync.HeaderSubscription{
+		Subscription: s.newHeads.Subscribe(),
+	}
+}
+
+// CreateBlock builds a block out of the pending transactions on demand.
+func (s *Sequencer) CreateBlock() (*rpc.BlockHashAndNumber, *jsonrpc.Error) {
+	header, err := s.BuildBlock()
+	if err != nil {
+		return nil, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+	return &rpc.BlockHashAndNumber{Hash: header.Hash, Number: header.Number}, nil
+}
+
+// Methods returns the JSON-RPC methods that control the sequencer.
+func (s *Sequencer) Methods() []jsonrpc.Method {
+	return []jsonrpc.Method{
+		{
+			Name:    "juno_createBlock",
+			Handler: s.CreateBlock,
+		},
+		{
+			Name:    "juno_setStorageAt",
+			Params:  []jsonrpc.Parameter{{Name: "contract_address"}, {Name: "key"}, {Name: "value"}},
+			Handler: s.SetStorageAt,
+		},
+		{
+			Name:    "juno_setNonce",
+			Params:  []jsonrpc.Parameter{{Name: "contract_address"}, {Name: "nonce"}},
+			Handler: s.SetNonce,
+		},
+		{
+			Name:    "juno_setClassHash",
+			Params:  []jsonrpc.Parameter{{Name: "contract_address"}, {Name: "class_hash"}},
+			Handler: s.SetClassHash,
+		},
+		{
+			Name:    "juno_setBalance",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "amount"}, {Name: "unit", Optional: true}},
+			Handler: s.SetBalance,
+		},
+	}
+}
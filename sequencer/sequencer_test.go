@@ -0,0 +1,604 @@
+package sequencer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/clients/gateway"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/overlay"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/sequencer"
+	"github.com/NethermindEth/juno/starknet"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	accountClassHash = "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5"
+	accountAddress   = "0x101"
+)
+
+var seqAddress = new(felt.Felt).SetUint64(0xabc)
+
+// writeGenesis writes a genesis file that predeploys an account with some storage set.
+func writeGenesis(t *testing.T) string {
+	t.Helper()
+
+	testdata, err := filepath.Abs(filepath.Join("..", "clients", "feeder", "testdata", "integration"))
+	require.NoError(t, err)
+
+	genesis := map[string]any{
+		"classes": []map[string]any{{
+			"path":                filepath.Join(testdata, "class", accountClassHash+".json"),
+			"compiled_path":       filepath.Join(testdata, "compiled_class", accountClassHash+".json"),
+			"compiled_class_hash": "0x20a6110c6e226e18145a0474173b18280a842b994192b542ccb62be544d944",
+		}},
+		"contracts": map[string]any{
+			accountAddress: map[string]any{
+				"class_hash": accountClassHash,
+				"storage":    map[string]string{"0x5": "0x1234"},
+			},
+		},
+	}
+	data, err := json.Marshal(genesis)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// start runs a sequencer with the test genesis and waits for the genesis block to be stored.
+func start(t *testing.T, virtualMachine vm.VM, blockTime time.Duration) (*sequencer.Sequencer, *blockchain.Blockchain) {
+	t.Helper()
+
+	network := utils.Sepolia
+	chain := blockchain.New(pebble.NewMemTest(t), network, utils.NewNopZapLogger())
+
+	stateDiff, newClasses, err := sequencer.LoadGenesis(writeGenesis(t))
+	require.NoError(t, err)
+	seq := sequencer.New(chain, virtualMachine, seqAddress, blockTime, utils.NewNopZapLogger()).
+		WithGenesis(stateDiff, newClasses)
+	run(t, seq, chain)
+	return seq, chain
+}
+
+// run runs a sequencer until the end of the test and waits for its chain to have a head.
+func run(t *testing.T, seq *sequencer.Sequencer, chain *blockchain.Blockchain) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, seq.Run(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	require.Eventually(t, func() bool {
+		_, err := chain.Height()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func invokeJSON(t *testing.T, nonce uint64) json.RawMessage {
+	t.Helper()
+
+	txn, err := json.Marshal(&starknet.Transaction{
+		Type:          starknet.TxnInvoke,
+		Version:       new(felt.Felt).SetUint64(1),
+		SenderAddress: utils.HexToFelt(t, accountAddress),
+		CallData:      &[]*felt.Felt{new(felt.Felt).SetUint64(1)},
+		Signature:     &[]*felt.Felt{},
+		Nonce:         new(felt.Felt).SetUint64(nonce),
+		MaxFee:        new(felt.Felt).SetUint64(1_000_000),
+	})
+	require.NoError(t, err)
+	return txn
+}
+
+// invokeTrace is the trace of an invoke that emits an event and writes to the account's storage.
+const invokeTrace = `{
+	"type": "INVOKE",
+	"execute_invocation": {
+		"contract_address": "0x101",
+		"calldata": [],
+		"caller_address": "0x0",
+		"result": [],
+		"calls": [],
+		"events": [{"order": 0, "keys": ["0x7"], "data": ["0x8"]}],
+		"messages": []
+	},
+	"state_diff": {
+		"storage_diffs": [{"address": "0x101", "storage_entries": [{"key": "0x6", "value": "0x9"}]}],
+		"nonces": [{"contract_address": "0x101", "nonce": "0x1"}],
+		"deployed_contracts": [],
+		"deprecated_declared_classes": [],
+		"declared_classes": [],
+		"replaced_classes": []
+	}
+}`
+
+func TestGenesis(t *testing.T) {
+	_, chain := start(t, nil, 0)
+
+	genesis, err := chain.BlockByNumber(0)
+	require.NoError(t, err)
+	assert.Equal(t, seqAddress, genesis.SequencerAddress)
+	assert.Equal(t, blockchain.SupportedStarknetVersion.String(), genesis.ProtocolVersion)
+	assert.Empty(t, genesis.Transactions)
+	stateUpdate, err := chain.StateUpdateByNumber(0)
+	require.NoError(t, err)
+	_, err = core.VerifyBlockHash(genesis, chain.Network(), stateUpdate.StateDiff)
+	require.NoError(t, err)
+
+	state, closer, err := chain.HeadState()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, closer())
+	})
+
+	classHash, err := state.ContractClassHash(utils.HexToFelt(t, accountAddress))
+	require.NoError(t, err)
+	assert.Equal(t, utils.HexToFelt(t, accountClassHash), classHash)
+
+	value, err := state.ContractStorage(utils.HexToFelt(t, accountAddress), new(felt.Felt).SetUint64(5))
+	require.NoError(t, err)
+	assert.Equal(t, utils.HexToFelt(t, "0x1234"), value)
+}
+
+func TestGenesisVerifiesCompiledClasses(t *testing.T) {
+	testdata, err := filepath.Abs(filepath.Join("..", "clients", "feeder", "testdata", "integration"))
+	require.NoError(t, err)
+	classPath := filepath.Join(testdata, "class", accountClassHash+".json")
+	compiledPath := filepath.Join(testdata, "compiled_class", accountClassHash+".json")
+
+	tests := map[string]map[string]any{
+		"missing compiled class":          {"path": classPath},
+		"mismatching compiled class hash": {"path": classPath, "compiled_path": compiledPath, "compiled_class_hash": "0x1"},
+	}
+	for name, class := range tests {
+		t.Run(name, func(t *testing.T) {
+			genesis, err := json.Marshal(map[string]any{"classes": []map[string]any{class}})
+			require.NoError(t, err)
+			genesisPath := filepath.Join(t.TempDir(), "genesis.json")
+			require.NoError(t, os.WriteFile(genesisPath, genesis, 0o600))
+
+			_, _, err = sequencer.LoadGenesis(genesisPath)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAddTransactionAndBuildBlock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	seq, chain := start(t, mockVM, 0)
+
+	fee := new(felt.Felt).SetUint64(42)
+	mockVM.EXPECT().Execute(gomock.Len(1), nil, uint64(1), gomock.Any(), seqAddress, gomock.Any(), utils.Sepolia,
+		nil, false, false, false, gomock.Any(), gomock.Any(), false).
+		Return([]*felt.Felt{fee}, []json.RawMessage{json.RawMessage(invokeTrace)}, nil)
+
+	respJSON, err := seq.AddTransaction(invokeJSON(t, 0))
+	require.NoError(t, err)
+
+	var resp struct {
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+	}
+	require.NoError(t, json.Unmarshal(respJSON, &resp))
+	require.NotNil(t, resp.TransactionHash)
+
+	t.Run("pending block contains the transaction", func(t *testing.T) {
+		pending, err := chain.Pending()
+		require.NoError(t, err)
+		require.Len(t, pending.Block.Transactions, 1)
+		assert.Equal(t, resp.TransactionHash, pending.Block.Transactions[0].Hash())
+
+		state, closer, err := chain.PendingState()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, closer())
+		}()
+		nonce, err := state.ContractNonce(utils.HexToFelt(t, accountAddress))
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(1), nonce)
+	})
+
+	t.Run("duplicate transaction is rejected", func(t *testing.T) {
+		_, err := seq.AddTransaction(invokeJSON(t, 0))
+		var gatewayErr *gateway.Error
+		require.ErrorAs(t, err, &gatewayErr)
+		assert.Equal(t, gateway.DuplicatedTransaction, gatewayErr.Code)
+	})
+
+	header, err := seq.BuildBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), header.Number)
+
+	block, err := chain.BlockByNumber(1)
+	require.NoError(t, err)
+	stateUpdate, err := chain.StateUpdateByNumber(1)
+	require.NoError(t, err)
+	_, err = core.VerifyBlockHash(block, chain.Network(), stateUpdate.StateDiff)
+	require.NoError(t, err)
+
+	require.Len(t, block.Receipts, 1)
+	receipt := block.Receipts[0]
+	assert.Equal(t, fee, receipt.Fee)
+	assert.Equal(t, []*core.Event{{
+		From: utils.HexToFelt(t, accountAddress),
+		Keys: []*felt.Felt{new(felt.Felt).SetUint64(7)},
+		Data: []*felt.Felt{new(felt.Felt).SetUint64(8)},
+	}}, receipt.Events)
+	assert.Equal(t, uint64(1), block.EventCount)
+
+	state, closer, err := chain.HeadState()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, closer())
+	})
+	value, err := state.ContractStorage(utils.HexToFelt(t, accountAddress), new(felt.Felt).SetUint64(6))
+	require.NoError(t, err)
+	assert.Equal(t, new(felt.Felt).SetUint64(9), value)
+
+	t.Run("empty block on demand", func(t *testing.T) {
+		header, err := seq.BuildBlock()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), header.Number)
+		assert.Equal(t, block.Hash, header.ParentHash)
+	})
+}
+
+func TestValidationFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	seq, chain := start(t, mockVM, 0)
+
+	mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil, vm.TransactionExecutionError{Cause: errors.New("invalid signature")})
+
+	_, err := seq.AddTransaction(invokeJSON(t, 0))
+	var gatewayErr *gateway.Error
+	require.ErrorAs(t, err, &gatewayErr)
+	assert.Equal(t, gateway.ValidateFailure, gatewayErr.Code)
+	assert.Equal(t, "invalid signature", gatewayErr.Message)
+
+	pending, err := chain.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending.Block.Transactions)
+}
+
+func TestDeclareCompilesClass(t *testing.T) {
+	const classHash = "0x4e70b19333ae94bd958625f7b61ce9eec631653597e68645e13780061b2136c"
+	testdata := filepath.Join("..", "clients", "feeder", "testdata", "integration")
+	classJSON, err := os.ReadFile(filepath.Join(testdata, "class", classHash+".json"))
+	require.NoError(t, err)
+	compiledClass, err := os.ReadFile(filepath.Join(testdata, "compiled_class", classHash+".json"))
+	require.NoError(t, err)
+
+	// the gateway takes the sierra program gzip compressed and base64 encoded
+	var contractClass map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(classJSON, &contractClass))
+	program, err := utils.Gzip64Encode(contractClass["sierra_program"])
+	require.NoError(t, err)
+	contractClass["sierra_program"], err = json.Marshal(program)
+	require.NoError(t, err)
+
+	var definition starknet.ClassDefinition
+	require.NoError(t, json.Unmarshal(classJSON, &definition))
+	class, err := sn2core.AdaptCairo1Class(definition.V1, compiledClass)
+	require.NoError(t, err)
+	compiledClassHash, err := class.(*core.Cairo1Class).CompiledClassHash()
+	require.NoError(t, err)
+
+	declareJSON := func(t *testing.T, compiledClassHash *felt.Felt) json.RawMessage {
+		t.Helper()
+		declare, err := json.Marshal(struct {
+			*starknet.Transaction
+			ContractClass map[string]json.RawMessage `json:"contract_class"`
+		}{
+			Transaction: &starknet.Transaction{
+				Type:              starknet.TxnDeclare,
+				Version:           new(felt.Felt).SetUint64(2),
+				SenderAddress:     utils.HexToFelt(t, accountAddress),
+				CompiledClassHash: compiledClassHash,
+				Signature:         &[]*felt.Felt{},
+				Nonce:             new(felt.Felt),
+				MaxFee:            new(felt.Felt).SetUint64(1_000_000),
+			},
+			ContractClass: contractClass,
+		})
+		require.NoError(t, err)
+		return declare
+	}
+
+	t.Run("compiled class hash must match", func(t *testing.T) {
+		mockVM := mocks.NewMockVM(gomock.NewController(t))
+		seq, _ := start(t, mockVM, 0)
+		mockVM.EXPECT().Compile(gomock.Any()).Return(json.RawMessage(compiledClass), nil)
+
+		_, err := seq.AddTransaction(declareJSON(t, new(felt.Felt).SetUint64(1)))
+		var gatewayErr *gateway.Error
+		require.ErrorAs(t, err, &gatewayErr)
+		assert.Equal(t, gateway.InvalidCompiledClassHash, gatewayErr.Code)
+	})
+
+	t.Run("compilation failure", func(t *testing.T) {
+		mockVM := mocks.NewMockVM(gomock.NewController(t))
+		seq, _ := start(t, mockVM, 0)
+		mockVM.EXPECT().Compile(gomock.Any()).Return(nil, errors.New("invalid sierra"))
+
+		_, err := seq.AddTransaction(declareJSON(t, compiledClassHash))
+		var gatewayErr *gateway.Error
+		require.ErrorAs(t, err, &gatewayErr)
+		assert.Equal(t, gateway.CompilationFailed, gatewayErr.Code)
+	})
+
+	t.Run("compiled class is stored", func(t *testing.T) {
+		mockVM := mocks.NewMockVM(gomock.NewController(t))
+		seq, chain := start(t, mockVM, 0)
+		mockVM.EXPECT().Compile(gomock.Any()).Return(json.RawMessage(compiledClass), nil)
+		mockVM.EXPECT().Execute(gomock.Any(), gomock.Len(1), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*felt.Felt{new(felt.Felt)}, []json.RawMessage{json.RawMessage(`{
+				"type": "DECLARE",
+				"state_diff": {
+					"storage_diffs": [],
+					"nonces": [{"contract_address": "0x101", "nonce": "0x1"}],
+					"deployed_contracts": [],
+					"deprecated_declared_classes": [],
+					"declared_classes": [{"class_hash": "` + classHash + `", "compiled_class_hash": "` +
+				compiledClassHash.String() + `"}],
+					"replaced_classes": []
+				}
+			}`)}, nil)
+
+		_, err := seq.AddTransaction(declareJSON(t, compiledClassHash))
+		require.NoError(t, err)
+		_, err = seq.BuildBlock()
+		require.NoError(t, err)
+
+		state, closer, err := chain.HeadState()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, closer()) })
+		declared, err := state.Class(utils.HexToFelt(t, classHash))
+		require.NoError(t, err)
+		stored, ok := declared.Class.(*core.Cairo1Class)
+		require.True(t, ok)
+		assert.JSONEq(t, string(compiledClass), string(stored.Compiled))
+	})
+}
+
+func TestBlockTime(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	seq, chain := start(t, mockVM, 10*time.Millisecond)
+
+	mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*felt.Felt{new(felt.Felt)}, []json.RawMessage{json.RawMessage(invokeTrace)}, nil)
+
+	sub := seq.SubscribeNewHeads()
+	t.Cleanup(sub.Unsubscribe)
+
+	_, err := seq.AddTransaction(invokeJSON(t, 0))
+	require.NoError(t, err)
+
+	// The genesis block may still be announced after subscribing.
+	timeout := time.After(time.Second)
+	for built := false; !built; {
+		select {
+		case header := <-sub.Recv():
+			built = header.Number == 1
+		case <-timeout:
+			require.FailNow(t, "block was not built")
+		}
+	}
+
+	// Blocks are not built on an interval when there are no transactions.
+	time.Sleep(50 * time.Millisecond)
+	height, err := chain.Height()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+}
+
+func TestFork(t *testing.T) {
+	baseDB := pebble.NewMemTest(t)
+	baseChain := blockchain.New(baseDB, utils.Sepolia, utils.NewNopZapLogger())
+	stateDiff, newClasses, err := sequencer.LoadGenesis(writeGenesis(t))
+	require.NoError(t, err)
+	baseSeq := sequencer.New(baseChain, nil, seqAddress, 0, utils.NewNopZapLogger()).
+		WithGenesis(stateDiff, newClasses)
+	run(t, baseSeq, baseChain)
+	for i := 0; i < 2; i++ {
+		_, err = baseSeq.BuildBlock()
+		require.NoError(t, err)
+	}
+	forkedBlock, err := baseChain.BlockHeaderByNumber(1)
+	require.NoError(t, err)
+
+	forkAddress := new(felt.Felt).SetUint64(0xdef)
+	overlayDB := pebble.NewMemTest(t)
+	chain := blockchain.New(overlay.New(baseDB, overlayDB), utils.Sepolia, utils.NewNopZapLogger())
+	seq := sequencer.New(chain, nil, forkAddress, 0, utils.NewNopZapLogger()).WithForkHeight(1)
+	run(t, seq, chain)
+
+	header, err := seq.BuildBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), header.Number)
+	assert.Equal(t, forkedBlock.Hash, header.ParentHash)
+	assert.Equal(t, forkAddress, header.SequencerAddress)
+
+	t.Run("base chain is not modified", func(t *testing.T) {
+		height, err := baseChain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), height)
+
+		block, err := baseChain.BlockHeaderByNumber(2)
+		require.NoError(t, err)
+		assert.Equal(t, seqAddress, block.SequencerAddress)
+	})
+
+	t.Run("restart keeps the blocks built on the fork", func(t *testing.T) {
+		restarted := sequencer.New(chain, nil, forkAddress, 0, utils.NewNopZapLogger()).WithForkHeight(1)
+		run(t, restarted, chain)
+
+		head, err := chain.HeadsHeader()
+		require.NoError(t, err)
+		assert.Equal(t, header.Hash, head.Hash)
+	})
+
+	t.Run("fork above the head of the base chain", func(t *testing.T) {
+		chain := blockchain.New(overlay.New(baseDB, pebble.NewMemTest(t)), utils.Sepolia, utils.NewNopZapLogger())
+		seq := sequencer.New(chain, nil, forkAddress, 0, utils.NewNopZapLogger()).WithForkHeight(3)
+		require.Error(t, seq.Run(context.Background()))
+	})
+}
+
+func TestForkMainnetGenesis(t *testing.T) {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	baseDB := pebble.NewMemTest(t)
+	baseChain := blockchain.New(baseDB, utils.Mainnet, utils.NewNopZapLogger())
+	for i := uint64(0); i < 3; i++ {
+		block, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		require.Nil(t, block.SequencerAddress)
+		stateUpdate, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, baseChain.Store(block, &core.BlockCommitments{}, stateUpdate, nil))
+	}
+	genesis, err := baseChain.BlockHeaderByNumber(0)
+	require.NoError(t, err)
+
+	chain := blockchain.New(overlay.New(baseDB, pebble.NewMemTest(t)), utils.Mainnet, utils.NewNopZapLogger())
+	seq := sequencer.New(chain, nil, seqAddress, 0, utils.NewNopZapLogger()).WithForkHeight(0)
+	run(t, seq, chain)
+
+	header, err := seq.BuildBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), header.Number)
+	assert.Equal(t, genesis.Hash, header.ParentHash)
+
+	height, err := baseChain.Height()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+}
+
+func TestCheats(t *testing.T) {
+	seq, chain := start(t, nil, 0)
+	account := utils.HexToFelt(t, accountAddress)
+
+	pendingState := func(t *testing.T) core.StateReader {
+		t.Helper()
+
+		state, closer, err := chain.PendingState()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, closer())
+		})
+		return state
+	}
+
+	t.Run("set storage", func(t *testing.T) {
+		key := new(felt.Felt).SetUint64(5)
+		value := new(felt.Felt).SetUint64(0x99)
+		ok, rpcErr := seq.SetStorageAt(*account, *key, *value)
+		require.Nil(t, rpcErr)
+		assert.True(t, ok)
+
+		got, err := pendingState(t).ContractStorage(account, key)
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+
+		_, rpcErr = seq.SetStorageAt(*new(felt.Felt).SetUint64(0x999), *key, *value)
+		assert.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("set nonce", func(t *testing.T) {
+		nonce := new(felt.Felt).SetUint64(7)
+		_, rpcErr := seq.SetNonce(*account, *nonce)
+		require.Nil(t, rpcErr)
+
+		got, err := pendingState(t).ContractNonce(account)
+		require.NoError(t, err)
+		assert.Equal(t, nonce, got)
+	})
+
+	ethAddress := utils.HexToFelt(t, "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	classHash := utils.HexToFelt(t, accountClassHash)
+
+	t.Run("set class hash", func(t *testing.T) {
+		_, rpcErr := seq.SetClassHash(*ethAddress, *new(felt.Felt).SetUint64(0x123))
+		assert.Equal(t, rpc.ErrClassHashNotFound, rpcErr)
+
+		_, rpcErr = seq.SetClassHash(*ethAddress, *classHash)
+		require.Nil(t, rpcErr)
+
+		got, err := pendingState(t).ContractClassHash(ethAddress)
+		require.NoError(t, err)
+		assert.Equal(t, classHash, got)
+	})
+
+	t.Run("set balance", func(t *testing.T) {
+		_, rpcErr := seq.SetBalance(*account, *new(felt.Felt).SetUint64(1), "FRI")
+		assert.Equal(t, rpc.ErrContractNotFound, rpcErr)
+		_, rpcErr = seq.SetBalance(*account, *new(felt.Felt).SetUint64(1), "GWEI")
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		amount := utils.HexToFelt(t, "0x200000000000000000000000000000005")
+		_, rpcErr = seq.SetBalance(*account, *amount, "WEI")
+		require.Nil(t, rpcErr)
+
+		varAddress, err := crypto.StarknetKeccak([]byte("ERC20_balances"))
+		require.NoError(t, err)
+		lowKey := crypto.Pedersen(varAddress, account)
+		highKey := new(felt.Felt).Add(lowKey, new(felt.Felt).SetUint64(1))
+
+		state := pendingState(t)
+		low, err := state.ContractStorage(ethAddress, lowKey)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(5), low)
+		high, err := state.ContractStorage(ethAddress, highKey)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(2), high)
+	})
+
+	_, err := seq.BuildBlock()
+	require.NoError(t, err)
+
+	state, closer, err := chain.HeadState()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, closer())
+	})
+	nonce, err := state.ContractNonce(account)
+	require.NoError(t, err)
+	assert.Equal(t, new(felt.Felt).SetUint64(7), nonce)
+	ethClassHash, err := state.ContractClassHash(ethAddress)
+	require.NoError(t, err)
+	assert.Equal(t, classHash, ethClassHash)
+}
@@ -1,6 +1,11 @@
 package starknet
 
-import "github.com/NethermindEth/juno/core/felt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
 
 // Block object returned by the feeder in JSON format for "get_block" endpoint
 type Block struct {
@@ -15,6 +20,8 @@ type Block struct {
 	Receipts         []*TransactionReceipt `json:"transaction_receipts"`
 	SequencerAddress *felt.Felt            `json:"sequencer_address"`
 	GasPriceSTRK     *felt.Felt            `json:"strk_l1_gas_price"`
+	L1DataGasPrice   *GasPrice             `json:"l1_data_gas_price,omitempty"`
+	L1DAMode         L1DAMode              `json:"l1_da_mode"`
 
 	// TODO we can remove the GasPrice method and the GasPriceLegacy field
 	// once v0.13 lands on mainnet. In the meantime, we include both to support
@@ -29,3 +36,38 @@ func (b *Block) GasPriceETH() *felt.Felt {
 	}
 	return b.GasPriceLegacy
 }
+
+type GasPrice struct {
+	PriceInWei *felt.Felt `json:"price_in_wei"`
+	PriceInFri *felt.Felt `json:"price_in_fri"`
+}
+
+type L1DAMode uint
+
+const (
+	Calldata L1DAMode = iota
+	Blob
+)
+
+func (m L1DAMode) MarshalJSON() ([]byte, error) {
+	switch m {
+	case Calldata:
+		return []byte(`"CALLDATA"`), nil
+	case Blob:
+		return []byte(`"BLOB"`), nil
+	default:
+		return nil, errors.New("unknown L1DAMode")
+	}
+}
+
+func (m *L1DAMode) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"CALLDATA"`:
+		*m = Calldata
+	case `"BLOB"`:
+		*m = Blob
+	default:
+		return fmt.Errorf("unknown L1DAMode: %s", string(data))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package starknet_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalPost0131Block(t *testing.T) {
+	var block starknet.Block
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"starknet_version": "0.13.2",
+		"l1_da_mode": "BLOB",
+		"l1_data_gas_price": {"price_in_wei": "0x1", "price_in_fri": "0x2"},
+		"transaction_receipts": [{
+			"execution_resources": {"n_steps": 10, "total_gas_consumed": {"l1_gas": 3, "l1_data_gas": 4}}
+		}]
+	}`), &block))
+
+	assert.Equal(t, starknet.Blob, block.L1DAMode)
+	assert.Equal(t, &starknet.GasPrice{
+		PriceInWei: new(felt.Felt).SetUint64(1),
+		PriceInFri: new(felt.Felt).SetUint64(2),
+	}, block.L1DataGasPrice)
+	assert.Equal(t, &starknet.GasConsumed{L1Gas: 3, L1DataGas: 4}, block.Receipts[0].ExecutionResources.TotalGasConsumed)
+
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"l1_da_mode": "ABC"}`), &block), "unknown L1DAMode")
+}
+
+func TestMarshalL1DAMode(t *testing.T) {
+	for _, mode := range []starknet.L1DAMode{starknet.Calldata, starknet.Blob} {
+		data, err := json.Marshal(mode)
+		require.NoError(t, err)
+
+		unmarshalled := new(starknet.L1DAMode)
+		require.NoError(t, json.Unmarshal(data, unmarshalled))
+		assert.Equal(t, mode, *unmarshalled)
+	}
+
+	_, err := json.Marshal(starknet.L1DAMode(2))
+	require.Error(t, err)
+}
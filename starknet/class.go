@@ -59,3 +59,47 @@ func (c *ClassDefinition) UnmarshalJSON(data []byte) error {
 	c.V0 = new(Cairo0Definition)
 	return json.Unmarshal(data, c.V0)
 }
+
+// CompiledClass is the CASM of a Cairo 1 class, as returned by get_compiled_class_by_class_hash
+type CompiledClass struct {
+	Prime           string          `json:"prime"`
+	CompilerVersion string          `json:"compiler_version"`
+	Bytecode        []*felt.Felt    `json:"bytecode"`
+	Hints           json.RawMessage `json:"hints"`
+	PythonicHints   json.RawMessage `json:"pythonic_hints,omitempty"`
+	// BytecodeSegmentLengths is only set by compilers from 2.6.0 onwards
+	BytecodeSegmentLengths *SegmentLengths     `json:"bytecode_segment_lengths,omitempty"`
+	EntryPoints            CompiledEntryPoints `json:"entry_points_by_type"`
+}
+
+type CompiledEntryPoints struct {
+	Constructor []CompiledEntryPoint `json:"CONSTRUCTOR"`
+	External    []CompiledEntryPoint `json:"EXTERNAL"`
+	L1Handler   []CompiledEntryPoint `json:"L1_HANDLER"`
+}
+
+type CompiledEntryPoint struct {
+	Selector *felt.Felt `json:"selector"`
+	Offset   *felt.Felt `json:"offset"`
+	Builtins []string   `json:"builtins"`
+}
+
+// SegmentLengths is either the length of a leaf segment of the bytecode or a list of nested segments
+type SegmentLengths struct {
+	Length   uint64
+	Children []SegmentLengths
+}
+
+func (s *SegmentLengths) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &s.Children)
+	}
+	return json.Unmarshal(data, &s.Length)
+}
+
+func (s SegmentLengths) MarshalJSON() ([]byte, error) {
+	if s.Children != nil {
+		return json.Marshal(s.Children)
+	}
+	return json.Marshal(s.Length)
+}
@@ -16,6 +16,19 @@ const (
 	Rejected
 )
 
+func (es ExecutionStatus) MarshalJSON() ([]byte, error) {
+	switch es {
+	case Succeeded:
+		return []byte(`"SUCCEEDED"`), nil
+	case Reverted:
+		return []byte(`"REVERTED"`), nil
+	case Rejected:
+		return []byte(`"REJECTED"`), nil
+	default:
+		return nil, errors.New("unknown ExecutionStatus")
+	}
+}
+
 func (es *ExecutionStatus) UnmarshalJSON(data []byte) error {
 	switch string(data) {
 	case `"SUCCEEDED"`:
@@ -39,6 +52,21 @@ const (
 	Received
 )
 
+func (fs FinalityStatus) MarshalJSON() ([]byte, error) {
+	switch fs {
+	case AcceptedOnL2:
+		return []byte(`"ACCEPTED_ON_L2"`), nil
+	case AcceptedOnL1:
+		return []byte(`"ACCEPTED_ON_L1"`), nil
+	case NotReceived:
+		return []byte(`"NOT_RECEIVED"`), nil
+	case Received:
+		return []byte(`"RECEIVED"`), nil
+	default:
+		return nil, errors.New("unknown FinalityStatus")
+	}
+}
+
 func (fs *FinalityStatus) UnmarshalJSON(data []byte) error {
 	switch string(data) {
 	case `"ACCEPTED_ON_L2"`:
@@ -185,8 +213,8 @@ type Transaction struct {
 
 type TransactionStatus struct {
 	Status           string          `json:"status"`
-	FinalityStatus   FinalityStatus  `json:"finality_status"`
-	ExecutionStatus  ExecutionStatus `json:"execution_status"`
+	FinalityStatus   FinalityStatus  `json:"finality_status,omitempty"`
+	ExecutionStatus  ExecutionStatus `json:"execution_status,omitempty"`
 	BlockHash        *felt.Felt      `json:"block_hash"`
 	BlockNumber      uint64          `json:"block_number"`
 	TransactionIndex uint64          `json:"transaction_index"`
@@ -218,6 +246,12 @@ type ExecutionResources struct {
 	Steps                  uint64                 `json:"n_steps"`
 	BuiltinInstanceCounter BuiltinInstanceCounter `json:"builtin_instance_counter"`
 	MemoryHoles            uint64                 `json:"n_memory_holes"`
+	TotalGasConsumed       *GasConsumed           `json:"total_gas_consumed,omitempty"`
+}
+
+type GasConsumed struct {
+	L1Gas     uint64 `json:"l1_gas"`
+	L1DataGas uint64 `json:"l1_data_gas"`
 }
 
 type BuiltinInstanceCounter struct {
@@ -240,7 +274,7 @@ type TransactionReceipt struct {
 
 	ActualFee          *felt.Felt          `json:"actual_fee"`
 	Events             []*Event            `json:"events"`
-	ExecutionStatus    ExecutionStatus     `json:"execution_status"`
+	ExecutionStatus    ExecutionStatus     `json:"execution_status,omitempty"`
 	ExecutionResources *ExecutionResources `json:"execution_resources"`
 	L1ToL2Message      *L1ToL2Message      `json:"l1_to_l2_consumed_message"`
 	L2ToL1Message      []*L2ToL1Message    `json:"l2_to_l1_messages"`
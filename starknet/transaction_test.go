@@ -1,6 +1,7 @@
 package starknet_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/NethermindEth/juno/starknet"
@@ -29,3 +30,31 @@ func TestUnmarshalFinalityStatus(t *testing.T) {
 
 	require.ErrorContains(t, fs.UnmarshalJSON([]byte("ABC")), "unknown FinalityStatus")
 }
+
+func TestMarshalExecutionStatus(t *testing.T) {
+	for _, es := range []starknet.ExecutionStatus{starknet.Succeeded, starknet.Reverted, starknet.Rejected} {
+		data, err := json.Marshal(es)
+		require.NoError(t, err)
+
+		unmarshalled := new(starknet.ExecutionStatus)
+		require.NoError(t, json.Unmarshal(data, unmarshalled))
+		assert.Equal(t, es, *unmarshalled)
+	}
+
+	_, err := json.Marshal(starknet.ExecutionStatus(0))
+	require.Error(t, err)
+}
+
+func TestMarshalFinalityStatus(t *testing.T) {
+	for _, fs := range []starknet.FinalityStatus{starknet.AcceptedOnL2, starknet.AcceptedOnL1, starknet.NotReceived, starknet.Received} {
+		data, err := json.Marshal(fs)
+		require.NoError(t, err)
+
+		unmarshalled := new(starknet.FinalityStatus)
+		require.NoError(t, json.Unmarshal(data, unmarshalled))
+		assert.Equal(t, fs, *unmarshalled)
+	}
+
+	_, err := json.Marshal(starknet.FinalityStatus(0))
+	require.Error(t, err)
+}
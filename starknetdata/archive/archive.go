@@ -0,0 +1,235 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/starknetdata"
+)
+
+var _ starknetdata.StarknetData = (*Archive)(nil)
+
+const (
+	latestID  = "latest"
+	pendingID = "pending"
+
+	blockDir                = "block"
+	signatureDir            = "signature"
+	stateUpdateDir          = "state_update"
+	stateUpdateWithBlockDir = "state_update_with_block"
+	transactionDir          = "transaction"
+	classDir                = "class"
+	compiledClassDir        = "compiled_class"
+)
+
+// ErrNotArchived is returned when the archive does not contain the requested data.
+var ErrNotArchived = errors.New("not found in archive")
+
+// Archive serves Starknet data from a directory of feeder gateway responses, as recorded by
+// feeder.Client.WithRecording. Responses are stored as <dir>/<endpoint>/<id>.json, for example
+// block/42.json or class/0x1234.json.
+type Archive struct {
+	dir string
+}
+
+func New(dir string) *Archive {
+	return &Archive{
+		dir: dir,
+	}
+}
+
+// load decodes the archived response of the given endpoint and id into v.
+func (a *Archive) load(ctx context.Context, endpointDir, id string, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(a.dir, endpointDir, id+".json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s %q: %w", endpointDir, id, ErrNotArchived)
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// signature returns the archived signature for a block, or nil if the signature was not recorded.
+func (a *Archive) signature(ctx context.Context, blockID string) (*starknet.Signature, error) {
+	if blockID == pendingID {
+		return nil, nil
+	}
+
+	sig := new(starknet.Signature)
+	if err := a.load(ctx, signatureDir, blockID, sig); err != nil {
+		if errors.Is(err, ErrNotArchived) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sig, nil
+}
+
+// BlockByNumber reads the block for a given block number from the archive,
+// then adapts it to the core.Block type.
+func (a *Archive) BlockByNumber(ctx context.Context, blockNumber uint64) (*core.Block, error) {
+	return a.block(ctx, strconv.FormatUint(blockNumber, 10))
+}
+
+// BlockLatest reads the latest recorded block from the archive,
+// then adapts it to the core.Block type.
+func (a *Archive) BlockLatest(ctx context.Context) (*core.Block, error) {
+	return a.block(ctx, latestID)
+}
+
+// BlockPending reads the recorded pending block from the archive,
+// then adapts it to the core.Block type.
+func (a *Archive) BlockPending(ctx context.Context) (*core.Block, error) {
+	return a.block(ctx, pendingID)
+}
+
+func (a *Archive) block(ctx context.Context, blockID string) (*core.Block, error) {
+	response := new(starknet.Block)
+	if err := a.load(ctx, blockDir, blockID, response); err != nil {
+		return nil, err
+	}
+
+	if blockID == pendingID && response.Status != "PENDING" {
+		return nil, errors.New("no pending block")
+	}
+
+	sig, err := a.signature(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("get signature for block %q: %v", blockID, err)
+	}
+
+	return sn2core.AdaptBlock(response, sig)
+}
+
+// Transaction reads the transaction for a given transaction hash from the archive,
+// then adapts it to the appropriate core.Transaction types.
+func (a *Archive) Transaction(ctx context.Context, transactionHash *felt.Felt) (core.Transaction, error) {
+	response := new(starknet.TransactionStatus)
+	if err := a.load(ctx, transactionDir, transactionHash.String(), response); err != nil {
+		return nil, err
+	}
+
+	if response.Transaction == nil {
+		return nil, fmt.Errorf("%s %q: %w", transactionDir, transactionHash, ErrNotArchived)
+	}
+	return sn2core.AdaptTransaction(response.Transaction)
+}
+
+// Class reads the class for a given class hash from the archive,
+// then adapts it to the core.Class type.
+func (a *Archive) Class(ctx context.Context, classHash *felt.Felt) (core.Class, error) {
+	response := new(starknet.ClassDefinition)
+	if err := a.load(ctx, classDir, classHash.String(), response); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case response.V1 != nil:
+		var compiledClass json.RawMessage
+		if err := a.load(ctx, compiledClassDir, classHash.String(), &compiledClass); err != nil {
+			return nil, err
+		}
+
+		return sn2core.AdaptCairo1Class(response.V1, compiledClass)
+	case response.V0 != nil:
+		return sn2core.AdaptCairo0Class(response.V0)
+	default:
+		return nil, errors.New("empty class")
+	}
+}
+
+func (a *Archive) stateUpdate(ctx context.Context, blockID string) (*core.StateUpdate, error) {
+	response := new(starknet.StateUpdate)
+	if err := a.load(ctx, stateUpdateDir, blockID, response); err != nil {
+		return nil, err
+	}
+
+	return sn2core.AdaptStateUpdate(response)
+}
+
+// StateUpdate reads the state update for a given block number from the archive,
+// then adapts it to the core.StateUpdate type.
+func (a *Archive) StateUpdate(ctx context.Context, blockNumber uint64) (*core.StateUpdate, error) {
+	return a.stateUpdate(ctx, strconv.FormatUint(blockNumber, 10))
+}
+
+// StateUpdatePending reads the state update for the recorded pending block from the archive,
+// then adapts it to the core.StateUpdate type.
+func (a *Archive) StateUpdatePending(ctx context.Context) (*core.StateUpdate, error) {
+	return a.stateUpdate(ctx, pendingID)
+}
+
+// stateUpdateWithBlock reads the combined state update and block response from the archive.
+// Archives recorded by clients that fetch blocks and state updates separately are supported
+// by falling back to the individual responses.
+func (a *Archive) stateUpdateWithBlock(ctx context.Context, blockID string) (*core.StateUpdate, *core.Block, error) {
+	response := new(starknet.StateUpdateWithBlock)
+	err := a.load(ctx, stateUpdateWithBlockDir, blockID, response)
+	if errors.Is(err, ErrNotArchived) {
+		return a.stateUpdateAndBlock(ctx, blockID)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if blockID == pendingID && response.Block.Status != "PENDING" {
+		return nil, nil, errors.New("no pending block")
+	}
+
+	sig, err := a.signature(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var adaptedState *core.StateUpdate
+	var adaptedBlock *core.Block
+
+	if adaptedState, err = sn2core.AdaptStateUpdate(response.StateUpdate); err != nil {
+		return nil, nil, err
+	}
+
+	if adaptedBlock, err = sn2core.AdaptBlock(response.Block, sig); err != nil {
+		return nil, nil, err
+	}
+
+	return adaptedState, adaptedBlock, nil
+}
+
+func (a *Archive) stateUpdateAndBlock(ctx context.Context, blockID string) (*core.StateUpdate, *core.Block, error) {
+	stateUpdate, err := a.stateUpdate(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := a.block(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stateUpdate, block, nil
+}
+
+// StateUpdatePendingWithBlock reads both the recorded pending state update and pending block from the archive,
+// then adapts them to the core.StateUpdate and core.Block types respectively
+func (a *Archive) StateUpdatePendingWithBlock(ctx context.Context) (*core.StateUpdate, *core.Block, error) {
+	return a.stateUpdateWithBlock(ctx, pendingID)
+}
+
+// StateUpdateWithBlock reads both state update and block for a given block number from the archive,
+// then adapts them to the core.StateUpdate and core.Block types respectively
+func (a *Archive) StateUpdateWithBlock(ctx context.Context, blockNumber uint64) (*core.StateUpdate, *core.Block, error) {
+	return a.stateUpdateWithBlock(ctx, strconv.FormatUint(blockNumber, 10))
+}
@@ -0,0 +1,163 @@
+package archive_test
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/starknetdata/archive"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArchive(network utils.Network) *archive.Archive {
+	return archive.New(filepath.Join("..", "..", "clients", "feeder", "testdata", network.String()))
+}
+
+func TestBlockByNumber(t *testing.T) {
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	a := testArchive(utils.Mainnet)
+	ctx := context.Background()
+
+	for _, number := range []uint64{0, 147, 11817} {
+		t.Run("mainnet block number "+strconv.FormatUint(number, 10), func(t *testing.T) {
+			expected, err := adapter.BlockByNumber(ctx, number)
+			require.NoError(t, err)
+			block, err := a.BlockByNumber(ctx, number)
+			require.NoError(t, err)
+			assert.Equal(t, expected, block)
+		})
+	}
+
+	t.Run("block not in archive", func(t *testing.T) {
+		_, err := a.BlockByNumber(ctx, 1000000)
+		assert.ErrorIs(t, err, archive.ErrNotArchived)
+	})
+}
+
+func TestBlockLatest(t *testing.T) {
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	ctx := context.Background()
+
+	expected, err := adapter.BlockLatest(ctx)
+	require.NoError(t, err)
+	block, err := testArchive(utils.Mainnet).BlockLatest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, block)
+}
+
+func TestStateUpdate(t *testing.T) {
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	a := testArchive(utils.Mainnet)
+	ctx := context.Background()
+
+	for _, number := range []uint64{0, 1, 2, 21656} {
+		t.Run("number "+strconv.FormatUint(number, 10), func(t *testing.T) {
+			expected, err := adapter.StateUpdate(ctx, number)
+			require.NoError(t, err)
+			update, err := a.StateUpdate(ctx, number)
+			require.NoError(t, err)
+			assert.Equal(t, expected, update)
+		})
+	}
+}
+
+func TestStateUpdateWithBlock(t *testing.T) {
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+	a := testArchive(utils.Integration)
+	ctx := context.Background()
+
+	for _, number := range []uint64{0, 78541} {
+		t.Run("number "+strconv.FormatUint(number, 10), func(t *testing.T) {
+			expectedUpdate, expectedBlock, err := adapter.StateUpdateWithBlock(ctx, number)
+			require.NoError(t, err)
+			update, block, err := a.StateUpdateWithBlock(ctx, number)
+			require.NoError(t, err)
+			assert.Equal(t, expectedUpdate, update)
+			assert.Equal(t, expectedBlock, block)
+		})
+	}
+
+	t.Run("pending", func(t *testing.T) {
+		expectedUpdate, expectedBlock, err := adapter.StateUpdatePendingWithBlock(ctx)
+		require.NoError(t, err)
+		update, block, err := a.StateUpdatePendingWithBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedUpdate, update)
+		assert.Equal(t, expectedBlock, block)
+	})
+}
+
+func TestClass(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cairo 0", func(t *testing.T) {
+		adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Goerli))
+		hash := utils.HexToFelt(t, "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8")
+
+		expected, err := adapter.Class(ctx, hash)
+		require.NoError(t, err)
+		class, err := testArchive(utils.Goerli).Class(ctx, hash)
+		require.NoError(t, err)
+		assert.Equal(t, expected, class)
+	})
+
+	t.Run("cairo 1", func(t *testing.T) {
+		adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Integration))
+		hash := utils.HexToFelt(t, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+
+		expected, err := adapter.Class(ctx, hash)
+		require.NoError(t, err)
+		class, err := testArchive(utils.Integration).Class(ctx, hash)
+		require.NoError(t, err)
+		assert.Equal(t, expected, class)
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	a := testArchive(utils.Mainnet)
+	ctx := context.Background()
+
+	hash := utils.HexToFelt(t, "0x537eacfd3c49166eec905daff61ff7feef9c133a049ea2135cb94eec840a4a8")
+	expected, err := adapter.Transaction(ctx, hash)
+	require.NoError(t, err)
+	txn, err := a.Transaction(ctx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, expected, txn)
+
+	_, err = a.Transaction(ctx, utils.HexToFelt(t, "0xffff"))
+	assert.ErrorIs(t, err, archive.ErrNotArchived)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	adapter := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet).WithRecording(dir))
+	a := archive.New(dir)
+	ctx := context.Background()
+
+	expectedBlock, err := adapter.BlockByNumber(ctx, 2)
+	require.NoError(t, err)
+	expectedUpdate, err := adapter.StateUpdate(ctx, 2)
+	require.NoError(t, err)
+
+	block, err := a.BlockByNumber(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, expectedBlock, block)
+
+	t.Run("state update with block falls back to separate responses", func(t *testing.T) {
+		update, block, err := a.StateUpdateWithBlock(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, expectedUpdate, update)
+		assert.Equal(t, expectedBlock, block)
+	})
+
+	t.Run("unrecorded data", func(t *testing.T) {
+		_, err := a.BlockByNumber(ctx, 1)
+		assert.ErrorIs(t, err, archive.ErrNotArchived)
+	})
+}
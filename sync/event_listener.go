@@ -5,11 +5,14 @@ import "time"
 type EventListener interface {
 	OnSyncStepDone(op string, blockNum uint64, took time.Duration)
 	OnReorg(blockNum uint64)
+	OnReexecutionMismatch(blockNum uint64, mismatches []string)
 }
 
 type SelectiveListener struct {
 	OnSyncStepDoneCb func(op string, blockNum uint64, took time.Duration)
 	OnReorgCb        func(blockNum uint64)
+
+	OnReexecutionMismatchCb func(blockNum uint64, mismatches []string)
 }
 
 func (l *SelectiveListener) OnSyncStepDone(op string, blockNum uint64, took time.Duration) {
@@ -23,3 +26,9 @@ func (l *SelectiveListener) OnReorg(blockNum uint64) {
 		l.OnReorgCb(blockNum)
 	}
 }
+
+func (l *SelectiveListener) OnReexecutionMismatch(blockNum uint64, mismatches []string) {
+	if l.OnReexecutionMismatchCb != nil {
+		l.OnReexecutionMismatchCb(blockNum, mismatches)
+	}
+}
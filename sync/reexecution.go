@@ -0,0 +1,352 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ReexecutionPolicy decides what the synchronizer does when re-executing a block does not reproduce
+// the receipts and state diff it was synced with.
+type ReexecutionPolicy uint8
+
+const (
+	// ReexecutionWarn logs the mismatches and stores the block anyway.
+	ReexecutionWarn ReexecutionPolicy = iota + 1
+	// ReexecutionHalt stops syncing without storing the block.
+	ReexecutionHalt
+)
+
+// Blocks up to this version cannot be re-executed faithfully by the VM.
+var reexecutionMinVersion = semver.MustParse("0.12.2")
+
+// invocation holds the parts of a call in a VM trace that are compared to the synced receipts.
+type invocation struct {
+	ContractAddress felt.Felt    `json:"contract_address"`
+	Calls           []invocation `json:"calls"`
+	Events          []struct {
+		Order uint64       `json:"order"`
+		Keys  []*felt.Felt `json:"keys"`
+		Data  []*felt.Felt `json:"data"`
+	} `json:"events"`
+	// Only set on execute invocations.
+	RevertReason string `json:"revert_reason"`
+}
+
+type executionTrace struct {
+	ValidateInvocation    *invocation `json:"validate_invocation"`
+	ExecuteInvocation     *invocation `json:"execute_invocation"`
+	FeeTransferInvocation *invocation `json:"fee_transfer_invocation"`
+	ConstructorInvocation *invocation `json:"constructor_invocation"`
+	FunctionInvocation    *invocation `json:"function_invocation"`
+	StateDiff             *struct {
+		StorageDiffs []struct {
+			Address        felt.Felt `json:"address"`
+			StorageEntries []struct {
+				Key   felt.Felt  `json:"key"`
+				Value *felt.Felt `json:"value"`
+			} `json:"storage_entries"`
+		} `json:"storage_diffs"`
+		Nonces []struct {
+			ContractAddress felt.Felt  `json:"contract_address"`
+			Nonce           *felt.Felt `json:"nonce"`
+		} `json:"nonces"`
+		DeployedContracts []struct {
+			Address   felt.Felt  `json:"address"`
+			ClassHash *felt.Felt `json:"class_hash"`
+		} `json:"deployed_contracts"`
+		DeprecatedDeclaredClasses []*felt.Felt `json:"deprecated_declared_classes"`
+		DeclaredClasses           []struct {
+			ClassHash         felt.Felt  `json:"class_hash"`
+			CompiledClassHash *felt.Felt `json:"compiled_class_hash"`
+		} `json:"declared_classes"`
+		ReplacedClasses []struct {
+			ContractAddress felt.Felt  `json:"contract_address"`
+			ClassHash       *felt.Felt `json:"class_hash"`
+		} `json:"replaced_classes"`
+	} `json:"state_diff"`
+}
+
+// reexecute executes the transactions of a block on top of the head state, which must be the state of its parent,
+// and returns every way in which the result differs from the receipts and state update the block was synced with.
+func (s *Synchronizer) reexecute(block *core.Block, stateUpdate *core.StateUpdate,
+	newClasses map[felt.Felt]core.Class,
+) ([]string, error) {
+	if block.Number == 0 {
+		return nil, nil
+	}
+	if version, err := core.ParseBlockVersion(block.ProtocolVersion); err != nil {
+		return nil, err
+	} else if version.Compare(reexecutionMinVersion) != 1 {
+		return nil, nil
+	}
+
+	headState, closer, err := s.blockchain.HeadState()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := closer(); closeErr != nil {
+			s.log.Warnw("Failed to close head state", "err", closeErr)
+		}
+	}()
+
+	// The block hash written to the block hash contract at the start of the block is not part of any trace.
+	blockHashDiff, err := blockchain.MakeStateDiffForEmptyBlock(s.blockchain, block.Number)
+	if err != nil {
+		return nil, err
+	}
+	state := blockchain.NewPendingState(blockHashDiff, nil, headState)
+
+	var declaredClasses []core.Class
+	var paidFeesOnL1 []*felt.Felt
+	for _, txn := range block.Transactions {
+		switch t := txn.(type) {
+		case *core.DeclareTransaction:
+			class, ok := newClasses[*t.ClassHash]
+			if !ok {
+				declared, classErr := headState.Class(t.ClassHash)
+				if classErr != nil {
+					return nil, classErr
+				}
+				class = declared.Class
+			}
+			declaredClasses = append(declaredClasses, class)
+		case *core.L1HandlerTransaction:
+			paidFeesOnL1 = append(paidFeesOnL1, new(felt.Felt).SetUint64(1))
+		}
+	}
+
+	sequencerAddress := block.SequencerAddress
+	if sequencerAddress == nil {
+		sequencerAddress = core.NetworkBlockHashMetaInfo(s.blockchain.Network()).FallBackSequencerAddress
+	}
+	fees, traces, err := s.vm.Execute(block.Transactions, declaredClasses, block.Number, block.Timestamp, sequencerAddress,
+		state, s.blockchain.Network(), paidFeesOnL1, false, false, false, block.GasPrice, block.GasPriceSTRK, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(fees) != len(block.Transactions) || len(traces) != len(block.Transactions) {
+		return nil, fmt.Errorf("expected %d fees and traces, got %d and %d", len(block.Transactions), len(fees), len(traces))
+	}
+
+	actualDiff := core.EmptyStateDiff()
+	mergeStorage(actualDiff, blockHashDiff.StorageDiffs)
+
+	// The VM has no L1 data gas price, so it prices the state diffs of blocks published as blobs as calldata.
+	compareFees := block.L1DAMode != core.Blob
+
+	var mismatches []string
+	for i, traceJSON := range traces {
+		var trace executionTrace
+		if err = json.Unmarshal(traceJSON, &trace); err != nil {
+			return nil, fmt.Errorf("decode trace: %v", err)
+		}
+
+		mismatches = append(mismatches, compareReceipt(block.Receipts[i], fees[i], compareFees, &trace)...)
+		trace.mergeStateDiff(actualDiff)
+	}
+	return append(mismatches, compareStateDiffs(stateUpdate.StateDiff, actualDiff, headState)...), nil
+}
+
+func compareReceipt(receipt *core.TransactionReceipt, fee *felt.Felt, compareFee bool, trace *executionTrace) []string {
+	var mismatches []string
+	mismatch := func(format string, args ...any) {
+		mismatches = append(mismatches, fmt.Sprintf("transaction %s: ", receipt.TransactionHash)+fmt.Sprintf(format, args...))
+	}
+
+	if compareFee && !feltsEqual(receipt.Fee, fee) {
+		mismatch("fee %s, expected %s", fee, receipt.Fee)
+	}
+
+	var revertReason string
+	if trace.ExecuteInvocation != nil {
+		revertReason = trace.ExecuteInvocation.RevertReason
+	}
+	if reverted := revertReason != ""; reverted != receipt.Reverted {
+		mismatch("reverted %t (%q), expected %t", reverted, revertReason, receipt.Reverted)
+	}
+
+	events := trace.events()
+	if len(events) != len(receipt.Events) {
+		mismatch("%d events, expected %d", len(events), len(receipt.Events))
+		return mismatches
+	}
+	for i, event := range events {
+		if !eventsEqual(event, receipt.Events[i]) {
+			mismatch("event %d differs", i)
+		}
+	}
+	return mismatches
+}
+
+// events returns the events of a transaction in the order they are listed in its receipt. The constructor
+// of a deployed account runs before its validation.
+func (t *executionTrace) events() []*core.Event {
+	var events []*core.Event
+	for _, root := range []*invocation{
+		t.ConstructorInvocation,
+		t.ValidateInvocation,
+		t.ExecuteInvocation,
+		t.FunctionInvocation,
+		t.FeeTransferInvocation,
+	} {
+		if root == nil {
+			continue
+		}
+
+		type orderedEvent struct {
+			order uint64
+			event *core.Event
+		}
+		var ordered []orderedEvent
+		var collect func(call *invocation)
+		collect = func(call *invocation) {
+			for _, event := range call.Events {
+				ordered = append(ordered, orderedEvent{
+					order: event.Order,
+					event: &core.Event{From: call.ContractAddress.Clone(), Keys: event.Keys, Data: event.Data},
+				})
+			}
+			for i := range call.Calls {
+				collect(&call.Calls[i])
+			}
+		}
+		collect(root)
+
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].order < ordered[j].order
+		})
+		for _, e := range ordered {
+			events = append(events, e.event)
+		}
+	}
+	return events
+}
+
+func eventsEqual(a, b *core.Event) bool {
+	return feltsEqual(a.From, b.From) && slices.EqualFunc(a.Keys, b.Keys, feltsEqual) && slices.EqualFunc(a.Data, b.Data, feltsEqual)
+}
+
+func feltsEqual(a, b *felt.Felt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
+
+func (t *executionTrace) mergeStateDiff(stateDiff *core.StateDiff) {
+	if t.StateDiff == nil {
+		return
+	}
+
+	for _, diff := range t.StateDiff.StorageDiffs {
+		storage := make(map[felt.Felt]*felt.Felt, len(diff.StorageEntries))
+		for _, entry := range diff.StorageEntries {
+			storage[entry.Key] = entry.Value
+		}
+		mergeStorage(stateDiff, map[felt.Felt]map[felt.Felt]*felt.Felt{diff.Address: storage})
+	}
+	for _, nonce := range t.StateDiff.Nonces {
+		stateDiff.Nonces[nonce.ContractAddress] = nonce.Nonce
+	}
+	for _, deployed := range t.StateDiff.DeployedContracts {
+		stateDiff.DeployedContracts[deployed.Address] = deployed.ClassHash
+	}
+	stateDiff.DeclaredV0Classes = append(stateDiff.DeclaredV0Classes, t.StateDiff.DeprecatedDeclaredClasses...)
+	for _, declared := range t.StateDiff.DeclaredClasses {
+		stateDiff.DeclaredV1Classes[declared.ClassHash] = declared.CompiledClassHash
+	}
+	for _, replaced := range t.StateDiff.ReplacedClasses {
+		stateDiff.ReplacedClasses[replaced.ContractAddress] = replaced.ClassHash
+	}
+}
+
+func mergeStorage(stateDiff *core.StateDiff, storageDiffs map[felt.Felt]map[felt.Felt]*felt.Felt) {
+	for addr, diff := range storageDiffs {
+		storage, ok := stateDiff.StorageDiffs[addr]
+		if !ok {
+			storage = make(map[felt.Felt]*felt.Felt, len(diff))
+			stateDiff.StorageDiffs[addr] = storage
+		}
+		for key, value := range diff {
+			storage[key] = value
+		}
+	}
+}
+
+// compareStateDiffs compares the state diff of a block to the one produced by re-executing it. Writes that leave
+// a value unchanged are left out of synced state diffs, so those are ignored.
+func compareStateDiffs(expected, actual *core.StateDiff, parent core.StateReader) []string {
+	var mismatches []string
+	mismatch := func(format string, args ...any) {
+		mismatches = append(mismatches, fmt.Sprintf(format, args...))
+	}
+
+	for addr, diff := range expected.StorageDiffs {
+		for key, value := range diff {
+			if actualValue := actual.StorageDiffs[addr][key]; !feltsEqual(value, actualValue) {
+				mismatch("storage %s at %s: %v, expected %s", key.String(), addr.String(), actualValue, value)
+			}
+		}
+	}
+	for addr, diff := range actual.StorageDiffs {
+		for key, value := range diff {
+			if _, ok := expected.StorageDiffs[addr][key]; ok {
+				continue
+			}
+			if old, err := parent.ContractStorage(&addr, &key); err != nil || !feltsEqual(old, value) {
+				mismatch("storage %s at %s: unexpected write of %s", key.String(), addr.String(), value)
+			}
+		}
+	}
+
+	for addr, nonce := range expected.Nonces {
+		if !feltsEqual(nonce, actual.Nonces[addr]) {
+			mismatch("nonce of %s: %v, expected %s", addr.String(), actual.Nonces[addr], nonce)
+		}
+	}
+	for addr, nonce := range actual.Nonces {
+		if _, ok := expected.Nonces[addr]; ok {
+			continue
+		}
+		if old, err := parent.ContractNonce(&addr); err != nil || !feltsEqual(old, nonce) {
+			mismatch("nonce of %s: unexpected update to %s", addr.String(), nonce)
+		}
+	}
+
+	mismatches = append(mismatches, compareMaps("deployed contract", expected.DeployedContracts, actual.DeployedContracts)...)
+	mismatches = append(mismatches, compareMaps("replaced class", expected.ReplacedClasses, actual.ReplacedClasses)...)
+	mismatches = append(mismatches, compareMaps("declared class", expected.DeclaredV1Classes, actual.DeclaredV1Classes)...)
+
+	expectedV0 := make(map[felt.Felt]*felt.Felt, len(expected.DeclaredV0Classes))
+	for _, classHash := range expected.DeclaredV0Classes {
+		expectedV0[*classHash] = classHash
+	}
+	actualV0 := make(map[felt.Felt]*felt.Felt, len(actual.DeclaredV0Classes))
+	for _, classHash := range actual.DeclaredV0Classes {
+		actualV0[*classHash] = classHash
+	}
+	return append(mismatches, compareMaps("deprecated declared class", expectedV0, actualV0)...)
+}
+
+func compareMaps(name string, expected, actual map[felt.Felt]*felt.Felt) []string {
+	var mismatches []string
+	for key, value := range expected {
+		if !feltsEqual(value, actual[key]) {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: %v, expected %s", name, key.String(), actual[key], value))
+		}
+	}
+	for key, value := range actual {
+		if _, ok := expected[key]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: unexpected %s", name, key.String(), value))
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,304 @@
+package sync_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/sequencer"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	accountClassHash = "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5"
+	accountAddress   = "0x101"
+)
+
+// invokeTrace is the trace of an invoke that emits an event and writes to the account's storage.
+const invokeTrace = `{
+	"type": "INVOKE",
+	"execute_invocation": {
+		"contract_address": "0x101",
+		"calldata": [],
+		"caller_address": "0x0",
+		"result": [],
+		"calls": [],
+		"events": [{"order": 0, "keys": ["0x7"], "data": ["0x8"]}],
+		"messages": []
+	},
+	"state_diff": {
+		"storage_diffs": [{"address": "0x101", "storage_entries": [{"key": "0x6", "value": "0x9"}]}],
+		"nonces": [{"contract_address": "0x101", "nonce": "0x1"}],
+		"deployed_contracts": [],
+		"deprecated_declared_classes": [],
+		"declared_classes": [],
+		"replaced_classes": []
+	}
+}`
+
+// deployAccountTrace is the trace of a deploy account whose constructor and validation both emit an event.
+const deployAccountTrace = `{
+	"type": "DEPLOY_ACCOUNT",
+	"validate_invocation": {
+		"contract_address": "%[1]s",
+		"calldata": [],
+		"caller_address": "0x0",
+		"result": [],
+		"calls": [],
+		"events": [{"order": 0, "keys": ["0x2"], "data": []}],
+		"messages": []
+	},
+	"constructor_invocation": {
+		"contract_address": "%[1]s",
+		"calldata": [],
+		"caller_address": "0x0",
+		"result": [],
+		"calls": [],
+		"events": [{"order": 0, "keys": ["0x1"], "data": []}],
+		"messages": []
+	},
+	"state_diff": {
+		"storage_diffs": [],
+		"nonces": [{"contract_address": "%[1]s", "nonce": "0x1"}],
+		"deployed_contracts": [{"address": "%[1]s", "class_hash": "%[2]s"}],
+		"deprecated_declared_classes": [],
+		"declared_classes": [],
+		"replaced_classes": []
+	}
+}`
+
+// sequencedChain builds a chain with a genesis block that deploys an account and a block with the given
+// transaction, which unlike the blocks in the feeder test data are recent enough to be re-executed.
+func sequencedChain(t *testing.T, txn *starknet.Transaction, trace string) (*blockchain.Blockchain, *felt.Felt) {
+	t.Helper()
+
+	testdata, err := filepath.Abs(filepath.Join("..", "clients", "feeder", "testdata", "integration"))
+	require.NoError(t, err)
+	genesis, err := json.Marshal(map[string]any{
+		"classes": []map[string]any{{
+			"path":                filepath.Join(testdata, "class", accountClassHash+".json"),
+			"compiled_path":       filepath.Join(testdata, "compiled_class", accountClassHash+".json"),
+			"compiled_class_hash": "0x20a6110c6e226e18145a0474173b18280a842b994192b542ccb62be544d944",
+		}},
+		"contracts": map[string]any{
+			accountAddress: map[string]any{"class_hash": accountClassHash},
+		},
+	})
+	require.NoError(t, err)
+	genesisPath := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesisPath, genesis, 0o600))
+	stateDiff, newClasses, err := sequencer.LoadGenesis(genesisPath)
+	require.NoError(t, err)
+
+	mockVM := mocks.NewMockVM(gomock.NewController(t))
+	fee := new(felt.Felt).SetUint64(42)
+	mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*felt.Felt{fee}, []json.RawMessage{json.RawMessage(trace)}, nil)
+
+	chain := blockchain.New(pebble.NewMemTest(t), utils.Sepolia, utils.NewNopZapLogger())
+	seq := sequencer.New(chain, mockVM, new(felt.Felt).SetUint64(0xabc), 0, utils.NewNopZapLogger()).
+		WithGenesis(stateDiff, newClasses)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, seq.Run(ctx))
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	require.Eventually(t, func() bool {
+		_, hErr := chain.Height()
+		return hErr == nil
+	}, time.Second, 10*time.Millisecond)
+
+	txnJSON, err := json.Marshal(txn)
+	require.NoError(t, err)
+	_, err = seq.AddTransaction(txnJSON)
+	require.NoError(t, err)
+	_, err = seq.BuildBlock()
+	require.NoError(t, err)
+	return chain, fee
+}
+
+// reexecutingSync syncs the blocks of source, published after genesis in the given data availability mode, with
+// a VM that executes every transaction with the given fee and trace, and returns the synced chain, the blocks
+// whose re-execution did not match and the halting error.
+func reexecutingSync(t *testing.T, source *blockchain.Blockchain, daMode core.L1DAMode, executedFee *felt.Felt,
+	trace string, policy sync.ReexecutionPolicy,
+) (*blockchain.Blockchain, []uint64, error) {
+	t.Helper()
+
+	stateUpdateWithBlock := func(number uint64) (*core.StateUpdate, *core.Block, error) {
+		block, err := source.BlockByNumber(number)
+		if err != nil {
+			return nil, nil, err
+		}
+		stateUpdate, err := source.StateUpdateByNumber(number)
+		if err != nil || number == 0 || block.L1DAMode == daMode {
+			return stateUpdate, block, err
+		}
+
+		block.L1DAMode = daMode
+		if block.Hash, _, err = core.BlockHash(block, utils.Sepolia, stateUpdate.StateDiff); err != nil {
+			return nil, nil, err
+		}
+		stateUpdate.BlockHash = block.Hash
+		return stateUpdate, block, nil
+	}
+
+	mockCtrl := gomock.NewController(t)
+	data := mocks.NewMockStarknetData(mockCtrl)
+	data.EXPECT().StateUpdateWithBlock(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, number uint64) (*core.StateUpdate, *core.Block, error) {
+			return stateUpdateWithBlock(number)
+		}).AnyTimes()
+	data.EXPECT().Class(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, classHash *felt.Felt) (core.Class, error) {
+			state, closer, err := source.HeadState()
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = closer() }()
+			declared, err := state.Class(classHash)
+			if err != nil {
+				return nil, err
+			}
+			return declared.Class, nil
+		}).AnyTimes()
+	data.EXPECT().BlockLatest(gomock.Any()).DoAndReturn(func(context.Context) (*core.Block, error) {
+		height, err := source.Height()
+		if err != nil {
+			return nil, err
+		}
+		_, block, err := stateUpdateWithBlock(height)
+		return block, err
+	}).AnyTimes()
+
+	mockVM := mocks.NewMockVM(mockCtrl)
+	mockVM.EXPECT().Execute(gomock.Len(1), nil, uint64(1), gomock.Any(), gomock.Any(), gomock.Any(), utils.Sepolia,
+		nil, false, false, false, gomock.Any(), gomock.Any(), false).
+		Return([]*felt.Felt{executedFee}, []json.RawMessage{json.RawMessage(trace)}, nil).MinTimes(1)
+
+	var mismatched []uint64
+	chain := blockchain.New(pebble.NewMemTest(t), utils.Sepolia, utils.NewNopZapLogger())
+	synchronizer := sync.New(chain, data, utils.NewNopZapLogger(), 0, false).
+		WithReexecution(mockVM, policy).
+		WithListener(&sync.SelectiveListener{
+			OnReexecutionMismatchCb: func(blockNum uint64, mismatches []string) {
+				assert.NotEmpty(t, mismatches)
+				mismatched = append(mismatched, blockNum)
+			},
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	require.NoError(t, synchronizer.Run(ctx))
+	return chain, mismatched, synchronizer.Halted()
+}
+
+func TestReexecution(t *testing.T) {
+	source, fee := sequencedChain(t, &starknet.Transaction{
+		Type:          starknet.TxnInvoke,
+		Version:       new(felt.Felt).SetUint64(1),
+		SenderAddress: utils.HexToFelt(t, accountAddress),
+		CallData:      &[]*felt.Felt{},
+		Signature:     &[]*felt.Felt{},
+		Nonce:         new(felt.Felt),
+		MaxFee:        new(felt.Felt).SetUint64(1_000_000),
+	}, invokeTrace)
+	run := func(t *testing.T, executedFee *felt.Felt, policy sync.ReexecutionPolicy) (*blockchain.Blockchain, []uint64, error) {
+		t.Helper()
+		return reexecutingSync(t, source, core.Calldata, executedFee, invokeTrace, policy)
+	}
+
+	t.Run("matching block is stored", func(t *testing.T) {
+		chain, mismatched, err := run(t, fee, sync.ReexecutionHalt)
+		require.NoError(t, err)
+		assert.Empty(t, mismatched)
+
+		height, err := chain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+
+	t.Run("mismatch is stored with the warn policy", func(t *testing.T) {
+		chain, mismatched, err := run(t, new(felt.Felt).SetUint64(43), sync.ReexecutionWarn)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1}, mismatched)
+
+		height, err := chain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+
+	t.Run("mismatch halts the sync with the halt policy", func(t *testing.T) {
+		chain, mismatched, err := run(t, new(felt.Felt).SetUint64(43), sync.ReexecutionHalt)
+		require.Error(t, err)
+		assert.Equal(t, []uint64{1}, mismatched)
+
+		height, err := chain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), height)
+	})
+
+	t.Run("fees of blocks published as blobs are not compared", func(t *testing.T) {
+		chain, mismatched, err := reexecutingSync(t, source, core.Blob, new(felt.Felt).SetUint64(43), invokeTrace,
+			sync.ReexecutionHalt)
+		require.NoError(t, err)
+		assert.Empty(t, mismatched)
+
+		height, err := chain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+}
+
+func TestReexecutionDeployAccount(t *testing.T) {
+	classHash := utils.HexToFelt(t, accountClassHash)
+	salt := new(felt.Felt).SetUint64(1)
+	address := core.ContractAddress(&felt.Zero, classHash, salt, []*felt.Felt{})
+	trace := fmt.Sprintf(deployAccountTrace, address, classHash)
+	source, fee := sequencedChain(t, &starknet.Transaction{
+		Type:                starknet.TxnDeployAccount,
+		Version:             new(felt.Felt).SetUint64(1),
+		ClassHash:           classHash,
+		ContractAddressSalt: salt,
+		ConstructorCallData: &[]*felt.Felt{},
+		Signature:           &[]*felt.Felt{},
+		Nonce:               new(felt.Felt),
+		MaxFee:              new(felt.Felt).SetUint64(1_000_000),
+	}, trace)
+
+	block, err := source.BlockByNumber(1)
+	require.NoError(t, err)
+	require.Len(t, block.Receipts, 1)
+	events := block.Receipts[0].Events
+	require.Len(t, events, 2)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(1)}, events[0].Keys, "constructor event comes first")
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(2)}, events[1].Keys)
+
+	chain, mismatched, err := reexecutingSync(t, source, core.Calldata, fee, trace, sync.ReexecutionHalt)
+	require.NoError(t, err)
+	assert.Empty(t, mismatched)
+
+	height, err := chain.Height()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+}
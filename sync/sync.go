@@ -3,7 +3,9 @@ package sync
 import (
 	"context"
 	"errors"
+	"fmt"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/NethermindEth/juno/service"
 	"github.com/NethermindEth/juno/starknetdata"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
 	"github.com/sourcegraph/conc/stream"
 )
 
@@ -27,8 +30,13 @@ const (
 	OpVerify = "verify"
 	OpStore  = "store"
 	OpFetch  = "fetch"
+	// OpReexecute is only reported when re-execution is enabled with WithReexecution.
+	OpReexecute = "reexecute"
 )
 
+// announcementWindow is how far ahead of the head the hashes of the blocks announced by peers are kept
+const announcementWindow = 4
+
 // This is a work-around. mockgen chokes when the instantiated generic type is in the interface.
 type HeaderSubscription struct {
 	*feed.Subscription[*core.Header]
@@ -55,6 +63,15 @@ type Synchronizer struct {
 
 	pendingPollInterval time.Duration
 	catchUpMode         bool
+
+	vm                vm.VM
+	reexecutionPolicy ReexecutionPolicy
+	halt              context.CancelCauseFunc
+	haltCause         atomic.Pointer[error]
+
+	announcements     map[uint64]*felt.Felt
+	announcementsLock sync.Mutex
+	pollLatestNow     chan struct{}
 }
 
 func New(bc *blockchain.Blockchain, starkNetData starknetdata.StarknetData,
@@ -68,6 +85,8 @@ func New(bc *blockchain.Blockchain, starkNetData starknetdata.StarknetData,
 		pendingPollInterval: pendingPollInterval,
 		listener:            &SelectiveListener{},
 		readOnlyBlockchain:  readOnlyBlockchain,
+		announcements:       make(map[uint64]*felt.Felt),
+		pollLatestNow:       make(chan struct{}, 1),
 	}
 	return s
 }
@@ -78,9 +97,38 @@ func (s *Synchronizer) WithListener(listener EventListener) *Synchronizer {
 	return s
 }
 
-// Run starts the Synchronizer, returns an error if the loop is already running
+// WithReexecution makes the Synchronizer re-execute the transactions of every block before storing it and
+// compare the fees, revert statuses, events and state diff to the ones it was synced with. The policy decides
+// whether mismatches only produce warnings or halt the sync.
+func (s *Synchronizer) WithReexecution(virtualMachine vm.VM, policy ReexecutionPolicy) *Synchronizer {
+	s.vm = virtualMachine
+	s.reexecutionPolicy = policy
+	return s
+}
+
+// Run starts the Synchronizer, returns an error if the loop is already running. A halted sync stops fetching
+// blocks but Run only returns once ctx is done, so that the node keeps serving the blocks synced so far.
 func (s *Synchronizer) Run(ctx context.Context) error {
-	s.syncBlocks(ctx)
+	syncCtx, halt := context.WithCancelCause(ctx)
+	defer halt(nil)
+	s.halt = func(cause error) {
+		s.haltCause.CompareAndSwap(nil, &cause)
+		halt(cause)
+	}
+
+	s.syncBlocks(syncCtx)
+	if err := s.Halted(); err != nil && ctx.Err() == nil {
+		s.log.Errorw("Sync halted, serving the blocks synced so far", "err", err)
+		<-ctx.Done()
+	}
+	return nil
+}
+
+// Halted returns why the sync was halted, or nil if it was not
+func (s *Synchronizer) Halted() error {
+	if cause := s.haltCause.Load(); cause != nil {
+		return *cause
+	}
 	return nil
 }
 
@@ -96,6 +144,7 @@ func (s *Synchronizer) fetcherTask(ctx context.Context, height uint64, verifiers
 			if err != nil {
 				continue
 			}
+			s.checkAnnouncement(block)
 
 			newClasses, err := s.fetchUnknownClasses(ctx, stateUpdate)
 			if err != nil {
@@ -181,6 +230,11 @@ func (s *Synchronizer) verifierTask(ctx context.Context, block *core.Block, stat
 				resetStreams()
 				return
 			}
+			if s.vm != nil && !s.verifyReexecution(block, stateUpdate, newClasses) {
+				resetStreams()
+				return
+			}
+
 			storeTimer := time.Now()
 			err = s.blockchain.Store(block, commitments, stateUpdate, newClasses)
 
@@ -219,6 +273,41 @@ func (s *Synchronizer) verifierTask(ctx context.Context, block *core.Block, stat
 	}
 }
 
+// verifyReexecution re-executes a block on top of its parent and reports any mismatch. It returns whether the block
+// should be stored.
+func (s *Synchronizer) verifyReexecution(block *core.Block, stateUpdate *core.StateUpdate,
+	newClasses map[felt.Felt]core.Class,
+) bool {
+	// If the block does not extend the head, storing it fails and the head is reverted.
+	if head, err := s.blockchain.HeadsHeader(); err != nil || !head.Hash.Equal(block.ParentHash) {
+		return true
+	}
+
+	reexecuteTimer := time.Now()
+	mismatches, err := s.reexecute(block, stateUpdate, newClasses)
+	if err != nil {
+		// Re-execution errors are not proof that the block is wrong, so they never halt the sync.
+		s.log.Warnw("Failed to re-execute block", "number", block.Number, "hash", block.Hash.ShortString(), "err", err)
+		return true
+	}
+	s.listener.OnSyncStepDone(OpReexecute, block.Number, time.Since(reexecuteTimer))
+	if len(mismatches) == 0 {
+		return true
+	}
+
+	s.listener.OnReexecutionMismatch(block.Number, mismatches)
+	if s.reexecutionPolicy != ReexecutionHalt {
+		s.log.Warnw("Re-executed block does not match", "number", block.Number, "hash", block.Hash.ShortString(),
+			"mismatches", mismatches)
+		return true
+	}
+
+	s.log.Errorw("Re-executed block does not match, halting sync", "number", block.Number,
+		"hash", block.Hash.ShortString(), "mismatches", mismatches)
+	s.halt(fmt.Errorf("re-execution of block %d does not match: %d mismatches", block.Number, len(mismatches)))
+	return false
+}
+
 func (s *Synchronizer) nextHeight() uint64 {
 	nextHeight := uint64(0)
 	if h, err := s.blockchain.Height(); err == nil {
@@ -373,6 +462,8 @@ func (s *Synchronizer) pollLatest(ctx context.Context, sem chan struct{}) {
 			return
 		case <-ticker.C:
 			poll()
+		case <-s.pollLatestNow:
+			poll()
 		}
 	}
 }
@@ -411,6 +502,45 @@ func (s *Synchronizer) fetchAndStorePending(ctx context.Context) error {
 	})
 }
 
+// AnnounceBlock hands the Synchronizer the number and hash of a block announced by a peer. Peers are not
+// trusted, so the announcement only makes the Synchronizer poll the latest block right away when it is ahead of
+// the highest block known, and its hash is checked against the block fetched at its height, which is the one synced.
+func (s *Synchronizer) AnnounceBlock(number uint64, hash *felt.Felt) {
+	nextHeight := s.nextHeight()
+	if number < nextHeight || number >= nextHeight+announcementWindow {
+		return
+	}
+
+	s.announcementsLock.Lock()
+	for height := range s.announcements {
+		if height < nextHeight {
+			delete(s.announcements, height)
+		}
+	}
+	s.announcements[number] = hash
+	s.announcementsLock.Unlock()
+
+	if highestBlockHeader := s.highestBlockHeader.Load(); highestBlockHeader == nil || highestBlockHeader.Number < number {
+		select {
+		case s.pollLatestNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// checkAnnouncement removes the hash announced at the height of the block, and warns if the block does not match it
+func (s *Synchronizer) checkAnnouncement(block *core.Block) {
+	s.announcementsLock.Lock()
+	hash, found := s.announcements[block.Number]
+	delete(s.announcements, block.Number)
+	s.announcementsLock.Unlock()
+
+	if found && !hash.Equal(block.Hash) {
+		s.log.Warnw("Block does not match the one announced by peers", "number", block.Number,
+			"hash", block.Hash.ShortString(), "announced", hash.ShortString())
+	}
+}
+
 func (s *Synchronizer) StartingBlockNumber() (uint64, error) {
 	if s.startingBlockNumber == nil {
 		return 0, errors.New("not running")
@@ -225,3 +225,68 @@ func TestSubscribeNewHeads(t *testing.T) {
 	require.Equal(t, want.Header, got)
 	sub.Unsubscribe()
 }
+
+func TestAnnounceBlock(t *testing.T) {
+	t.Parallel()
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, utils.Mainnet))
+	log := utils.NewNopZapLogger()
+	bc := blockchain.New(pebble.NewMemTest(t), utils.Mainnet, log)
+
+	// the feeder does not have the last block until it is announced
+	const lastHeight = 2
+	var announced, latestPolls atomic.Int32
+	mockSNData := mocks.NewMockStarknetData(mockCtrl)
+	mockSNData.EXPECT().StateUpdateWithBlock(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, height uint64) (*core.StateUpdate, *core.Block, error) {
+			if height > lastHeight || (height == lastHeight && announced.Load() == 0) {
+				return nil, nil, errors.New("not found")
+			}
+			return gw.StateUpdateWithBlock(ctx, height)
+		}).AnyTimes()
+	mockSNData.EXPECT().Class(gomock.Any(), gomock.Any()).DoAndReturn(gw.Class).AnyTimes()
+	mockSNData.EXPECT().BlockLatest(gomock.Any()).DoAndReturn(func(ctx context.Context) (*core.Block, error) {
+		latestPolls.Add(1)
+		return gw.BlockByNumber(ctx, lastHeight-1+uint64(announced.Load()))
+	}).AnyTimes()
+
+	synchronizer := sync.New(bc, mockSNData, log, time.Duration(0), false)
+	sub := synchronizer.SubscribeNewHeads()
+	t.Cleanup(sub.Unsubscribe)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	t.Cleanup(cancel)
+	go func() {
+		assert.NoError(t, synchronizer.Run(ctx))
+	}()
+
+	for {
+		head := <-sub.Recv()
+		require.NotNil(t, head)
+		if head.Number == lastHeight-1 {
+			break
+		}
+	}
+	require.Eventually(t, func() bool { return latestPolls.Load() > 0 }, timeout, 10*time.Millisecond)
+	polls := latestPolls.Load()
+
+	want, err := gw.BlockByNumber(context.Background(), lastHeight)
+	require.NoError(t, err)
+	announced.Store(1)
+	// the announced hash is only checked against the block the feeder serves
+	synchronizer.AnnounceBlock(lastHeight, new(felt.Felt).SetUint64(1))
+
+	select {
+	case head := <-sub.Recv():
+		assert.Equal(t, want.Header, head)
+	case <-ctx.Done():
+		require.Fail(t, "announced block was not synced")
+	}
+	got, err := bc.BlockByNumber(lastHeight)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Greater(t, latestPolls.Load(), polls, "the announcement polls the latest block")
+}
@@ -15,7 +15,7 @@ func marshalCompiledClass(class core.Class) (json.RawMessage, error) {
 	switch c := class.(type) {
 	case *core.Cairo0Class:
 		var err error
-		compiledClass, err = makeDeprecatedVMClass(c)
+		compiledClass, err = core2sn.AdaptCairo0Class(c)
 		if err != nil {
 			return nil, err
 		}
@@ -34,7 +34,7 @@ func marshalDeclaredClass(class core.Class) (json.RawMessage, error) {
 
 	switch c := class.(type) {
 	case *core.Cairo0Class:
-		declaredClass, err = makeDeprecatedVMClass(c)
+		declaredClass, err = core2sn.AdaptCairo0Class(c)
 		if err != nil {
 			return nil, err
 		}
@@ -47,34 +47,6 @@ func marshalDeclaredClass(class core.Class) (json.RawMessage, error) {
 	return json.Marshal(declaredClass)
 }
 
-func makeDeprecatedVMClass(class *core.Cairo0Class) (*starknet.Cairo0Definition, error) {
-	adaptEntryPoint := func(ep core.EntryPoint) starknet.EntryPoint {
-		return starknet.EntryPoint{
-			Selector: ep.Selector,
-			Offset:   ep.Offset,
-		}
-	}
-
-	constructors := utils.Map(utils.NonNilSlice(class.Constructors), adaptEntryPoint)
-	external := utils.Map(utils.NonNilSlice(class.Externals), adaptEntryPoint)
-	handlers := utils.Map(utils.NonNilSlice(class.L1Handlers), adaptEntryPoint)
-
-	decompressedProgram, err := utils.Gzip64Decode(class.Program)
-	if err != nil {
-		return nil, err
-	}
-
-	return &starknet.Cairo0Definition{
-		Program: decompressedProgram,
-		Abi:     class.Abi,
-		EntryPoints: starknet.EntryPoints{
-			Constructor: constructors,
-			External:    external,
-			L1Handler:   handlers,
-		},
-	}, nil
-}
-
 func makeSierraClass(class *core.Cairo1Class) *starknet.SierraDefinition {
 	constructors := utils.Map(utils.NonNilSlice(class.EntryPoints.Constructor), core2sn.AdaptSierraEntryPoint)
 	external := utils.Map(utils.NonNilSlice(class.EntryPoints.External), core2sn.AdaptSierraEntryPoint)
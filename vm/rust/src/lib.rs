@@ -56,6 +56,7 @@ extern "C" {
     fn JunoAppendTrace(reader_handle: usize, json_trace: *const c_void, len: usize);
     fn JunoAppendResponse(reader_handle: usize, ptr: *const c_uchar);
     fn JunoAppendActualFee(reader_handle: usize, ptr: *const c_uchar);
+    fn JunoSetCompiledClass(reader_handle: usize, json_class: *const c_void, len: usize);
 }
 
 const N_STEPS_FEE_WEIGHT: f64 = 0.005;
@@ -447,11 +448,28 @@ fn build_block_context(
 }
 
 fn contract_class_from_sierra_json(sierra_json: &str) -> Result<ContractClass, String> {
-    let sierra_class: SierraContractClass =
-        serde_json::from_str(sierra_json).map_err(|err| err.to_string())?;
-    let casm_class = CasmContractClass::from_contract_class(sierra_class, true)
-        .map_err(|err| err.to_string())?;
+    let casm_class = compile_sierra_json(sierra_json)?;
     let contract_class_v1 = ContractClassV1::try_from(casm_class).map_err(|err| err.to_string())?;
 
     Ok(contract_class_v1.into())
 }
+
+fn compile_sierra_json(sierra_json: &str) -> Result<CasmContractClass, String> {
+    let sierra_class: SierraContractClass =
+        serde_json::from_str(sierra_json).map_err(|err| err.to_string())?;
+    CasmContractClass::from_contract_class(sierra_class, true).map_err(|err| err.to_string())
+}
+
+#[no_mangle]
+pub extern "C" fn cairoCompileSierra(sierra_json: *const c_char, reader_handle: usize) {
+    let sierra_json_str = unsafe { CStr::from_ptr(sierra_json) }.to_str().unwrap();
+    let casm_json = compile_sierra_json(sierra_json_str)
+        .and_then(|casm_class| serde_json::to_vec(&casm_class).map_err(|err| err.to_string()));
+
+    match casm_json {
+        Err(e) => report_error(reader_handle, e.as_str(), -1),
+        Ok(casm_json) => unsafe {
+            JunoSetCompiledClass(reader_handle, casm_json.as_ptr() as *const c_void, casm_json.len());
+        },
+    }
+}
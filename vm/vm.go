@@ -12,6 +12,8 @@ package vm
 //					unsigned char skip_charge_fee, unsigned char skip_validate, unsigned char err_on_revert, char* gas_price_wei,
 //					char* gas_price_strk, unsigned char legacy_json);
 //
+// extern void cairoCompileSierra(char* sierra_json, uintptr_t readerHandle);
+//
 // #cgo vm_debug  LDFLAGS: -L./rust/target/debug   -ljuno_starknet_rs -ldl -lm
 // #cgo !vm_debug LDFLAGS: -L./rust/target/release -ljuno_starknet_rs -ldl -lm
 import "C"
@@ -36,6 +38,8 @@ type VM interface {
 		sequencerAddress *felt.Felt, state core.StateReader, network utils.Network, paidFeesOnL1 []*felt.Felt,
 		skipChargeFee, skipValidate, errOnRevert bool, gasPriceWEI *felt.Felt, gasPriceSTRK *felt.Felt, legacyTraceJSON bool,
 	) ([]*felt.Felt, []json.RawMessage, error)
+	// Compile compiles a Sierra class to CASM, in the format returned by get_compiled_class_by_class_hash
+	Compile(class *core.Cairo1Class) (json.RawMessage, error)
 }
 
 type vm struct {
@@ -62,6 +66,8 @@ type callContext struct {
 	// fee amount taken per transaction during VM execution
 	actualFees []*felt.Felt
 	traces     []json.RawMessage
+	// CASM of the compiled class
+	compiledClass json.RawMessage
 }
 
 func unwrapContext(readerHandle C.uintptr_t) *callContext {
@@ -99,6 +105,12 @@ func JunoAppendActualFee(readerHandle C.uintptr_t, ptr unsafe.Pointer) {
 	context.actualFees = append(context.actualFees, makeFeltFromPtr(ptr))
 }
 
+//export JunoSetCompiledClass
+func JunoSetCompiledClass(readerHandle C.uintptr_t, jsonBytes *C.void, bytesLen C.size_t) {
+	context := unwrapContext(readerHandle)
+	context.compiledClass = C.GoBytes(unsafe.Pointer(jsonBytes), C.int(bytesLen))
+}
+
 func makeFeltFromPtr(ptr unsafe.Pointer) *felt.Felt {
 	return new(felt.Felt).SetBytes(C.GoBytes(ptr, felt.Bytes))
 }
@@ -279,3 +291,25 @@ func marshalTxnsAndDeclaredClasses(txns []core.Transaction, declaredClasses []co
 
 	return txnsJSON, classesJSON, nil
 }
+
+func (v *vm) Compile(class *core.Cairo1Class) (json.RawMessage, error) {
+	context := &callContext{
+		log: v.log,
+	}
+	handle := cgo.NewHandle(context)
+	defer handle.Delete()
+
+	sierraJSON, err := json.Marshal(makeSierraClass(class))
+	if err != nil {
+		return nil, err
+	}
+
+	sierraJSONCStr := cstring(sierraJSON)
+	C.cairoCompileSierra(sierraJSONCStr, C.uintptr_t(handle))
+	C.free(unsafe.Pointer(sierraJSONCStr))
+
+	if len(context.err) > 0 {
+		return nil, errors.New(context.err)
+	}
+	return context.compiledClass, nil
+}